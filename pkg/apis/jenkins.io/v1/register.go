@@ -60,6 +60,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&Plugin{},
 		&PipelineActivity{},
 		&PipelineActivityList{},
+		&PromotionApproval{},
+		&PromotionApprovalList{},
 		&Scheduler{},
 		&SchedulerList{},
 		&PipelineStructure{},
@@ -50,11 +50,71 @@ type EnvironmentSpec struct {
 
 	// RemoteCluster flag indicates if the Environment is deployed in a separate cluster to the Development Environment
 	RemoteCluster bool `json:"remoteCluster,omitempty" protobuf:"bytes,12,opt,name=remoteCluster"`
+
+	// Approval if specified requires promotions to this Environment to be approved before they proceed
+	Approval *EnvironmentApproval `json:"approval,omitempty" protobuf:"bytes,13,opt,name=approval"`
+
+	// FreezeWindows are the periods during which promotions to this Environment are blocked
+	FreezeWindows []FreezeWindow `json:"freezeWindows,omitempty" protobuf:"bytes,14,opt,name=freezeWindows"`
+
+	// PreviewPolicy is the time to live and idle hibernation policy of a Preview Environment
+	PreviewPolicy *PreviewPolicy `json:"previewPolicy,omitempty" protobuf:"bytes,15,opt,name=previewPolicy"`
+}
+
+// PreviewPolicy is the lifecycle policy of a Preview Environment which is copied from the previewEnvironments
+// configuration of the project when it is deployed and enforced by 'jx gc previews'
+type PreviewPolicy struct {
+	// TTL is how long the Preview Environment lives after it was last deployed such as '72h'
+	TTL string `json:"ttl,omitempty" protobuf:"bytes,1,opt,name=ttl"`
+	// HibernateAfter is how long the Preview Environment can be idle before its Deployments are scaled to zero such as '8h'.
+	// It is idle if it has not been deployed or woken and, only if 'jx gc previews' is given a Prometheus URL, has not
+	// served any requests
+	HibernateAfter string `json:"hibernateAfter,omitempty" protobuf:"bytes,2,opt,name=hibernateAfter"`
+}
+
+// FreezeWindow is a period during which promotions to an Environment are blocked. It is either recurring, using a
+// cron like schedule, or ad hoc using a start and optional end time such as during an incident
+type FreezeWindow struct {
+	Name   string `json:"name" protobuf:"bytes,1,opt,name=name"`
+	Reason string `json:"reason,omitempty" protobuf:"bytes,2,opt,name=reason"`
+	// Schedule is a cron expression of the form 'minute hour day-of-month month day-of-week'.
+	// Without a Duration the Environment is frozen during every minute that matches the schedule
+	// such as '* * * * 0,6' for weekends. With a Duration the schedule is when each freeze starts
+	Schedule string `json:"schedule,omitempty" protobuf:"bytes,3,opt,name=schedule"`
+	// Duration is how long each scheduled freeze lasts such as '62h'
+	Duration string `json:"duration,omitempty" protobuf:"bytes,4,opt,name=duration"`
+	// TimeZone is the location the schedule is evaluated in such as 'Europe/London'. Defaults to UTC
+	TimeZone string `json:"timeZone,omitempty" protobuf:"bytes,5,opt,name=timeZone"`
+	// Start is the start of an ad hoc freeze
+	Start *metav1.Time `json:"start,omitempty" protobuf:"bytes,6,opt,name=start"`
+	// End is the end of an ad hoc freeze. If not specified the freeze lasts until it is deleted
+	End       *metav1.Time `json:"end,omitempty" protobuf:"bytes,7,opt,name=end"`
+	CreatedBy string       `json:"createdBy,omitempty" protobuf:"bytes,8,opt,name=createdBy"`
+}
+
+// EnvironmentApproval specifies who needs to approve promotions to an Environment
+type EnvironmentApproval struct {
+	// RequiredApprovals is the number of approvers who need to approve a promotion. Defaults to 1
+	RequiredApprovals int32 `json:"requiredApprovals,omitempty" protobuf:"bytes,1,opt,name=requiredApprovals"`
+	// RoleBinding is the name of the EnvironmentRoleBinding whose subjects can approve or reject promotions.
+	// If not specified any user who can update PromotionApprovals in the team can approve
+	RoleBinding string `json:"roleBinding,omitempty" protobuf:"bytes,2,opt,name=roleBinding"`
+	// Timeout is the maximum time to wait for approval such as '24h'. Defaults to the timeout of the promotion
+	Timeout string `json:"timeout,omitempty" protobuf:"bytes,3,opt,name=timeout"`
 }
 
 // EnvironmentStatus is the status for an Environment resource
 type EnvironmentStatus struct {
 	Version string `json:"version,omitempty"`
+
+	// LastDeployed is when a Preview Environment was last deployed
+	LastDeployed *metav1.Time `json:"lastDeployed,omitempty"`
+	// LastActive is when a Preview Environment was last woken or seen serving traffic
+	LastActive *metav1.Time `json:"lastActive,omitempty"`
+	// Hibernated is when the Deployments of an idle Preview Environment were scaled to zero
+	Hibernated *metav1.Time `json:"hibernated,omitempty"`
+	// HibernatedReplicas are the replicas of each Deployment before it was hibernated which are restored when it wakes
+	HibernatedReplicas map[string]int32 `json:"hibernatedReplicas,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -228,6 +288,85 @@ type TeamSettings struct {
 
 	// ProwConfig is the way we manage prow configurations
 	ProwConfig ProwConfigType `json:"prowConfig,omitempty" protobuf:"bytes,29,opt,name=prowConfig"`
+
+	// Notifications are the rules used by the notify controller to route pipeline notifications
+	Notifications []NotificationRule `json:"notifications,omitempty" protobuf:"bytes,30,opt,name=notifications"`
+
+	// CVE configures the CVE provider used to scan images and the severity of vulnerabilities which block promotions
+	CVE *CVESettings `json:"cve,omitempty" protobuf:"bytes,31,opt,name=cve"`
+}
+
+// CVESettings the CVE provider settings of a team
+type CVESettings struct {
+	// Kind is the kind of CVE provider such as anchore, trivy or clair
+	Kind string `json:"kind,omitempty" protobuf:"bytes,1,opt,name=kind"`
+	// URL is the URL of the CVE provider; for trivy it is the location of the JSON reports
+	URL string `json:"url,omitempty" protobuf:"bytes,2,opt,name=url"`
+	// PromotionSeverityThreshold fails promotions of images with vulnerabilities of this severity or above such as High
+	PromotionSeverityThreshold string `json:"promotionSeverityThreshold,omitempty" protobuf:"bytes,3,opt,name=promotionSeverityThreshold"`
+}
+
+// NotificationEventType is the kind of pipeline event a notification is sent for
+type NotificationEventType string
+
+const (
+	// NotificationEventTypeFailed a pipeline failed
+	NotificationEventTypeFailed NotificationEventType = "Failed"
+	// NotificationEventTypeRecovered a pipeline succeeded after its previous build failed
+	NotificationEventTypeRecovered NotificationEventType = "Recovered"
+	// NotificationEventTypePromoted a version was promoted to an environment
+	NotificationEventTypePromoted NotificationEventType = "Promoted"
+	// NotificationEventTypeWaitingForApproval a pipeline is waiting for approval
+	NotificationEventTypeWaitingForApproval NotificationEventType = "WaitingForApproval"
+)
+
+// NotificationEventTypes the kinds of events that notifications can be sent for
+var NotificationEventTypes = []NotificationEventType{
+	NotificationEventTypeFailed,
+	NotificationEventTypeRecovered,
+	NotificationEventTypePromoted,
+	NotificationEventTypeWaitingForApproval,
+}
+
+// NotificationRule routes the matching pipeline events to one or more notification channels.
+// Empty match fields match everything
+type NotificationRule struct {
+	Name string `json:"name,omitempty" protobuf:"bytes,1,opt,name=name"`
+	// Events the kinds of events to notify on
+	Events []NotificationEventType `json:"events,omitempty" protobuf:"bytes,2,opt,name=events"`
+	// Repositories the owner/repository glob patterns to match such as myorg/*
+	Repositories []string `json:"repositories,omitempty" protobuf:"bytes,3,opt,name=repositories"`
+	// Branches the branch glob patterns to match such as master or PR-*
+	Branches []string `json:"branches,omitempty" protobuf:"bytes,4,opt,name=branches"`
+	// Environments the environments to match for promotion and approval events
+	Environments []string `json:"environments,omitempty" protobuf:"bytes,5,opt,name=environments"`
+	// Channels where the notifications are sent
+	Channels []NotificationChannel `json:"channels,omitempty" protobuf:"bytes,6,opt,name=channels"`
+}
+
+// NotificationChannelKind is the kind of a notification channel
+type NotificationChannelKind string
+
+const (
+	// NotificationChannelKindChat posts to a chat channel
+	NotificationChannelKindChat NotificationChannelKind = "chat"
+	// NotificationChannelKindEmail sends an email
+	NotificationChannelKindEmail NotificationChannelKind = "email"
+	// NotificationChannelKindWebhook posts a JSON payload to a URL
+	NotificationChannelKindWebhook NotificationChannelKind = "webhook"
+)
+
+// NotificationChannel is a destination for notifications
+type NotificationChannel struct {
+	Kind NotificationChannelKind `json:"kind,omitempty" protobuf:"bytes,1,opt,name=kind"`
+	// Chat the chat channel for chat notifications
+	Chat string `json:"chat,omitempty" protobuf:"bytes,2,opt,name=chat"`
+	// Email the recipients of email notifications
+	Email []string `json:"email,omitempty" protobuf:"bytes,3,opt,name=email"`
+	// WebhookURL the URL that webhook notifications are posted to
+	WebhookURL string `json:"webhookUrl,omitempty" protobuf:"bytes,4,opt,name=webhookUrl"`
+	// Template an optional go template overriding the default notification text
+	Template string `json:"template,omitempty" protobuf:"bytes,5,opt,name=template"`
 }
 
 // StorageLocation
@@ -418,6 +557,19 @@ func (t *TeamSettings) IsProw() bool {
 	return t.PromotionEngine == PromotionEngineProw
 }
 
+// RequiresApproval returns true if promotions to the environment need to be approved
+func (e *EnvironmentSpec) RequiresApproval() bool {
+	return e.Approval != nil
+}
+
+// RequiredApprovalCount returns the number of approvals required which defaults to 1
+func (a *EnvironmentApproval) RequiredApprovalCount() int32 {
+	if a.RequiredApprovals < 1 {
+		return 1
+	}
+	return a.RequiredApprovals
+}
+
 // IsProwOrLighthouse returns true if either Prow or Lighthouse is being used.
 // e.g. using the Prow based configuration model
 func (e *EnvironmentSpec) IsProwOrLighthouse() bool {
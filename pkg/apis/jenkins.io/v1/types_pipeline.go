@@ -3,6 +3,7 @@ package v1
 import (
 	"strings"
 
+	"github.com/jenkins-x/jx/pkg/kube/naming"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -60,6 +61,13 @@ type PipelineActivitySpec struct {
 	PostExtensions        []ExtensionExecution   `json:"postExtensions,omitempty" protobuf:"bytes,23,opt,name=postExtensions"`
 	Attachments           []Attachment           `json:"attachments,omitempty" protobuf:"bytes,24,opt,name=attachments"`
 	BatchPipelineActivity BatchPipelineActivity  `json:"batchPipelineActivity,omitempty" protobuf:"bytes,25,opt,name=batchPipelineActivity"`
+	Parameters            []PipelineParameter    `json:"parameters,omitempty" protobuf:"bytes,26,opt,name=parameters"`
+}
+
+// PipelineParameter is the value of a parameter the pipeline was triggered with
+type PipelineParameter struct {
+	Name  string `json:"name" protobuf:"bytes,1,opt,name=name"`
+	Value string `json:"value,omitempty" protobuf:"bytes,2,opt,name=value"`
 }
 
 // BatchPipelineActivity contains information about a batch build, used by both the batch build and its comprising PRs for linking them together
@@ -97,6 +105,21 @@ type StageActivityStep struct {
 	CoreActivityStep `json:",inline"`
 
 	Steps []CoreActivityStep `json:"steps,omitempty" protobuf:"bytes,1,opt,name=steps"`
+	Input *StageInputStep    `json:"input,omitempty" protobuf:"bytes,2,opt,name=input"`
+}
+
+// StageInputStep is an input stage which pauses the pipeline until a user approves or rejects it continuing
+type StageInputStep struct {
+	// Status is WaitingForApproval until the input is either approved, which is Succeeded, or rejected or timed out,
+	// which is Aborted
+	CoreActivityStep `json:",inline"`
+
+	Message string `json:"message,omitempty" protobuf:"bytes,1,opt,name=message"`
+	// Approvers are the users who can approve or reject the input, if empty any user can
+	Approvers []string `json:"approvers,omitempty" protobuf:"bytes,2,opt,name=approvers"`
+	// Deadline is when the input times out
+	Deadline *metav1.Time      `json:"deadline,omitempty" protobuf:"bytes,3,opt,name=deadline"`
+	Decision *ApprovalDecision `json:"decision,omitempty" protobuf:"bytes,4,opt,name=decision"`
 }
 
 // PreviewActivityStep is the step of creating a preview environment as part of a Pull Request pipeline
@@ -116,6 +139,78 @@ type PromoteActivityStep struct {
 	PullRequest    *PromotePullRequestStep `json:"pullRequest,omitempty" protobuf:"bytes,2,opt,name=pullRequest"`
 	Update         *PromoteUpdateStep      `json:"update,omitempty" protobuf:"bytes,3,opt,name=update"`
 	ApplicationURL string                  `json:"applicationURL,omitempty" protobuf:"bytes,4,opt,name=environment"`
+	Approval       *PromoteApprovalStep    `json:"approval,omitempty" protobuf:"bytes,5,opt,name=approval"`
+	FreezeOverride *FreezeOverride         `json:"freezeOverride,omitempty" protobuf:"bytes,6,opt,name=freezeOverride"`
+	Rollback       *PromoteRollback        `json:"rollback,omitempty" protobuf:"bytes,7,opt,name=rollback"`
+	Canary         *PromoteCanaryStep      `json:"canary,omitempty" protobuf:"bytes,8,opt,name=canary"`
+	Verify         *PromoteVerifyStep      `json:"verify,omitempty" protobuf:"bytes,9,opt,name=verify"`
+	Applications   []PromoteApplication    `json:"applications,omitempty" protobuf:"bytes,10,opt,name=applications"`
+}
+
+// PromoteApplication is the version of one of the applications promoted together to an environment by a single
+// Pull Request
+type PromoteApplication struct {
+	Name    string `json:"name,omitempty" protobuf:"bytes,1,opt,name=name"`
+	Version string `json:"version,omitempty" protobuf:"bytes,2,opt,name=version"`
+}
+
+// PromoteVerifyCheckKind is the kind of check used to verify an application is healthy after a promotion
+type PromoteVerifyCheckKind string
+
+const (
+	// PromoteVerifyCheckKindRollout checks the rollout of the Deployment of the application completed
+	PromoteVerifyCheckKindRollout PromoteVerifyCheckKind = "Rollout"
+	// PromoteVerifyCheckKindHTTP checks a URL of the application responds successfully
+	PromoteVerifyCheckKindHTTP PromoteVerifyCheckKind = "HTTP"
+	// PromoteVerifyCheckKindPrometheus checks the error rate of the application reported by Prometheus is below a threshold
+	PromoteVerifyCheckKindPrometheus PromoteVerifyCheckKind = "Prometheus"
+)
+
+// PromoteVerifyStep is the step for verifying an application is healthy after it has been promoted
+type PromoteVerifyStep struct {
+	CoreActivityStep `json:",inline"`
+
+	Checks []PromoteVerifyCheck `json:"checks,omitempty" protobuf:"bytes,1,opt,name=checks"`
+}
+
+// PromoteVerifyCheck is the outcome of a single check of a PromoteVerifyStep
+type PromoteVerifyCheck struct {
+	Name    string                 `json:"name,omitempty" protobuf:"bytes,1,opt,name=name"`
+	Kind    PromoteVerifyCheckKind `json:"kind,omitempty" protobuf:"bytes,2,opt,name=kind"`
+	Status  ActivityStatusType     `json:"status,omitempty" protobuf:"bytes,3,opt,name=status"`
+	Message string                 `json:"message,omitempty" protobuf:"bytes,4,opt,name=message"`
+}
+
+// PromoteCanaryStep is the step for waiting for the progressive delivery of a promotion by a Flagger Canary to
+// complete, recording the traffic weight and failed metric checks of the canary analysis
+type PromoteCanaryStep struct {
+	CoreActivityStep `json:",inline"`
+
+	Canary       string   `json:"canary,omitempty" protobuf:"bytes,1,opt,name=canary"`
+	Phase        string   `json:"phase,omitempty" protobuf:"bytes,2,opt,name=phase"`
+	CanaryWeight int32    `json:"canaryWeight,omitempty" protobuf:"bytes,3,opt,name=canaryWeight"`
+	MaxWeight    int32    `json:"maxWeight,omitempty" protobuf:"bytes,4,opt,name=maxWeight"`
+	FailedChecks int32    `json:"failedChecks,omitempty" protobuf:"bytes,5,opt,name=failedChecks"`
+	Threshold    int32    `json:"threshold,omitempty" protobuf:"bytes,6,opt,name=threshold"`
+	Iterations   int32    `json:"iterations,omitempty" protobuf:"bytes,7,opt,name=iterations"`
+	Metrics      []string `json:"metrics,omitempty" protobuf:"bytes,8,opt,name=metrics"`
+	Message      string   `json:"message,omitempty" protobuf:"bytes,9,opt,name=message"`
+}
+
+// PromoteRollback marks a promotion as a rollback of an application to an earlier version
+type PromoteRollback struct {
+	FromVersion string `json:"fromVersion,omitempty" protobuf:"bytes,1,opt,name=fromVersion"`
+	ToVersion   string `json:"toVersion,omitempty" protobuf:"bytes,2,opt,name=toVersion"`
+	User        string `json:"user,omitempty" protobuf:"bytes,3,opt,name=user"`
+	Reason      string `json:"reason,omitempty" protobuf:"bytes,4,opt,name=reason"`
+}
+
+// FreezeOverride records who promoted to an environment during a freeze window and why
+type FreezeOverride struct {
+	FreezeWindow string      `json:"freezeWindow,omitempty" protobuf:"bytes,1,opt,name=freezeWindow"`
+	User         string      `json:"user,omitempty" protobuf:"bytes,2,opt,name=user"`
+	Reason       string      `json:"reason,omitempty" protobuf:"bytes,3,opt,name=reason"`
+	Timestamp    metav1.Time `json:"timestamp,omitempty" protobuf:"bytes,4,opt,name=timestamp"`
 }
 
 // GitStatus the status of a git commit in terms of CI/CD
@@ -133,6 +228,16 @@ type PromotePullRequestStep struct {
 	MergeCommitSHA string `json:"mergeCommitSHA,omitempty" protobuf:"bytes,2,opt,name=mergeCommitSHA"`
 }
 
+// PromoteApprovalStep is the step for waiting for a promotion to an environment which requires approval to be
+// approved or rejected
+type PromoteApprovalStep struct {
+	CoreActivityStep `json:",inline"`
+
+	PromotionApproval string             `json:"promotionApproval,omitempty" protobuf:"bytes,1,opt,name=promotionApproval"`
+	RequiredApprovals int32              `json:"requiredApprovals,omitempty" protobuf:"bytes,2,opt,name=requiredApprovals"`
+	Decisions         []ApprovalDecision `json:"decisions,omitempty" protobuf:"bytes,3,opt,name=decisions"`
+}
+
 // PromoteUpdateStep is the step for updating a promotion after the Pull Request merges to master
 type PromoteUpdateStep struct {
 	CoreActivityStep `json:",inline"`
@@ -244,3 +349,43 @@ func (p *PipelineActivity) BranchName() string {
 	p.Spec.GitBranch = branch
 	return branch
 }
+
+// IsPending returns true if the input has not yet been approved, rejected or timed out
+func (s *StageInputStep) IsPending() bool {
+	return s.Status == ActivityStatusTypeNone || s.Status == ActivityStatusTypeWaitingForApproval
+}
+
+// IsApprover returns true if the user can approve or reject the input. The user and approvers are compared as
+// valid Kubernetes names, which is how 'jx approve pipeline' names the current user, so case and dots are ignored
+func (s *StageInputStep) IsApprover(user string) bool {
+	if len(s.Approvers) == 0 {
+		return true
+	}
+	if strings.TrimSpace(user) == "" {
+		return false
+	}
+	name := naming.ToValidNameTruncated(user, 63)
+	for _, approver := range s.Approvers {
+		if naming.ToValidNameTruncated(approver, 63) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// AddDecision records the decision of the user which completes the input
+func (s *StageInputStep) AddDecision(user string, approved bool, comment string) {
+	now := metav1.Now()
+	s.Decision = &ApprovalDecision{
+		User:      user,
+		Approved:  approved,
+		Comment:   comment,
+		Timestamp: now,
+	}
+	if approved {
+		s.Status = ActivityStatusTypeSucceeded
+	} else {
+		s.Status = ActivityStatusTypeAborted
+	}
+	s.CompletedTimestamp = &now
+}
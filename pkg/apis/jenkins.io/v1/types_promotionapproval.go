@@ -0,0 +1,121 @@
+package v1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:openapi-gen=true
+
+// PromotionApproval represents a request to approve the promotion of a version of an application to an
+// Environment which requires approval, along with the decisions of the approvers
+type PromotionApproval struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Spec   PromotionApprovalSpec   `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+	Status PromotionApprovalStatus `json:"status,omitempty" protobuf:"bytes,3,opt,name=status"`
+}
+
+// PromotionApprovalSpec is the specification of a PromotionApproval
+type PromotionApprovalSpec struct {
+	Environment string `json:"environment,omitempty" protobuf:"bytes,1,opt,name=environment"`
+	Application string `json:"application,omitempty" protobuf:"bytes,2,opt,name=application"`
+	Version     string `json:"version,omitempty" protobuf:"bytes,3,opt,name=version"`
+	// PipelineActivity is the name of the PipelineActivity performing the promotion
+	PipelineActivity string `json:"pipelineActivity,omitempty" protobuf:"bytes,4,opt,name=pipelineActivity"`
+	Pipeline         string `json:"pipeline,omitempty" protobuf:"bytes,5,opt,name=pipeline"`
+	Build            string `json:"build,omitempty" protobuf:"bytes,6,opt,name=build"`
+	// RequiredApprovals is the number of approvals required before the promotion can continue
+	RequiredApprovals int32 `json:"requiredApprovals,omitempty" protobuf:"bytes,7,opt,name=requiredApprovals"`
+	// RoleBinding is the name of the EnvironmentRoleBinding whose subjects can approve or reject the promotion
+	RoleBinding string `json:"roleBinding,omitempty" protobuf:"bytes,8,opt,name=roleBinding"`
+}
+
+// PromotionApprovalStatus is the status of a PromotionApproval
+type PromotionApprovalStatus struct {
+	// Status is WaitingForApproval until the promotion is either approved, which is Succeeded, or rejected, which is Aborted
+	Status    ActivityStatusType `json:"status,omitempty" protobuf:"bytes,1,opt,name=status"`
+	Decisions []ApprovalDecision `json:"decisions,omitempty" protobuf:"bytes,2,opt,name=decisions"`
+}
+
+// ApprovalDecision is the decision of an approver to approve or reject a promotion
+type ApprovalDecision struct {
+	User      string      `json:"user,omitempty" protobuf:"bytes,1,opt,name=user"`
+	Approved  bool        `json:"approved,omitempty" protobuf:"bytes,2,opt,name=approved"`
+	Comment   string      `json:"comment,omitempty" protobuf:"bytes,3,opt,name=comment"`
+	Timestamp metav1.Time `json:"timestamp,omitempty" protobuf:"bytes,4,opt,name=timestamp"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PromotionApprovalList is a list of PromotionApproval resources
+type PromotionApprovalList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []PromotionApproval `json:"items"`
+}
+
+// IsPending returns true if the promotion has not yet been approved or rejected
+func (a *PromotionApproval) IsPending() bool {
+	status := a.Status.Status
+	return status == ActivityStatusTypeNone || status == ActivityStatusTypeWaitingForApproval
+}
+
+// Approvals returns the number of approvers who have approved the promotion
+func (a *PromotionApproval) Approvals() int32 {
+	var count int32
+	for _, d := range a.Status.Decisions {
+		if d.Approved {
+			count++
+		}
+	}
+	return count
+}
+
+// AddDecision records the decision of the user replacing any previous decision they made, then
+// updates the status: a single rejection rejects the promotion otherwise it is approved once the
+// required number of approvals have been made
+func (a *PromotionApproval) AddDecision(user string, approved bool, comment string) {
+	decision := ApprovalDecision{
+		User:     user,
+		Approved: approved,
+		Comment:  comment,
+		Timestamp: metav1.Time{
+			Time: time.Now(),
+		},
+	}
+	found := false
+	for i, d := range a.Status.Decisions {
+		if d.User == user {
+			a.Status.Decisions[i] = decision
+			found = true
+		}
+	}
+	if !found {
+		a.Status.Decisions = append(a.Status.Decisions, decision)
+	}
+
+	required := a.Spec.RequiredApprovals
+	if required < 1 {
+		required = 1
+	}
+	a.Status.Status = ActivityStatusTypeWaitingForApproval
+	for _, d := range a.Status.Decisions {
+		if !d.Approved {
+			a.Status.Status = ActivityStatusTypeAborted
+			return
+		}
+	}
+	if a.Approvals() >= required {
+		a.Status.Status = ActivityStatusTypeSucceeded
+	}
+}
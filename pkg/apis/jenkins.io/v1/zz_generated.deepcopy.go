@@ -132,6 +132,23 @@ func (in *AppSpec) DeepCopy() *AppSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApprovalDecision) DeepCopyInto(out *ApprovalDecision) {
+	*out = *in
+	in.Timestamp.DeepCopyInto(&out.Timestamp)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApprovalDecision.
+func (in *ApprovalDecision) DeepCopy() *ApprovalDecision {
+	if in == nil {
+		return nil
+	}
+	out := new(ApprovalDecision)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Approve) DeepCopyInto(out *Approve) {
 	*out = *in
@@ -409,6 +426,22 @@ func (in *ChartRef) DeepCopy() *ChartRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CVESettings) DeepCopyInto(out *CVESettings) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CVESettings.
+func (in *CVESettings) DeepCopy() *CVESettings {
+	if in == nil {
+		return nil
+	}
+	out := new(CVESettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CommitStatus) DeepCopyInto(out *CommitStatus) {
 	*out = *in
@@ -801,7 +834,7 @@ func (in *Environment) DeepCopyInto(out *Environment) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -823,6 +856,22 @@ func (in *Environment) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvironmentApproval) DeepCopyInto(out *EnvironmentApproval) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentApproval.
+func (in *EnvironmentApproval) DeepCopy() *EnvironmentApproval {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvironmentApproval)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EnvironmentFilter) DeepCopyInto(out *EnvironmentFilter) {
 	*out = *in
@@ -1010,6 +1059,31 @@ func (in *EnvironmentSpec) DeepCopyInto(out *EnvironmentSpec) {
 	out.Source = in.Source
 	in.TeamSettings.DeepCopyInto(&out.TeamSettings)
 	out.PreviewGitSpec = in.PreviewGitSpec
+	if in.Approval != nil {
+		in, out := &in.Approval, &out.Approval
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(EnvironmentApproval)
+			**out = **in
+		}
+	}
+	if in.FreezeWindows != nil {
+		in, out := &in.FreezeWindows, &out.FreezeWindows
+		*out = make([]FreezeWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PreviewPolicy != nil {
+		in, out := &in.PreviewPolicy, &out.PreviewPolicy
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(PreviewPolicy)
+			**out = **in
+		}
+	}
 	return
 }
 
@@ -1026,6 +1100,37 @@ func (in *EnvironmentSpec) DeepCopy() *EnvironmentSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EnvironmentStatus) DeepCopyInto(out *EnvironmentStatus) {
 	*out = *in
+	if in.LastDeployed != nil {
+		in, out := &in.LastDeployed, &out.LastDeployed
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = (*in).DeepCopy()
+		}
+	}
+	if in.LastActive != nil {
+		in, out := &in.LastActive, &out.LastActive
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = (*in).DeepCopy()
+		}
+	}
+	if in.Hibernated != nil {
+		in, out := &in.Hibernated, &out.Hibernated
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = (*in).DeepCopy()
+		}
+	}
+	if in.HibernatedReplicas != nil {
+		in, out := &in.HibernatedReplicas, &out.HibernatedReplicas
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
@@ -1568,6 +1673,55 @@ func (in *FactStatus) DeepCopy() *FactStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FreezeOverride) DeepCopyInto(out *FreezeOverride) {
+	*out = *in
+	in.Timestamp.DeepCopyInto(&out.Timestamp)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FreezeOverride.
+func (in *FreezeOverride) DeepCopy() *FreezeOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(FreezeOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FreezeWindow) DeepCopyInto(out *FreezeWindow) {
+	*out = *in
+	if in.Start != nil {
+		in, out := &in.Start, &out.Start
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = (*in).DeepCopy()
+		}
+	}
+	if in.End != nil {
+		in, out := &in.End, &out.End
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = (*in).DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FreezeWindow.
+func (in *FreezeWindow) DeepCopy() *FreezeWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(FreezeWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GitService) DeepCopyInto(out *GitService) {
 	*out = *in
@@ -2004,6 +2158,70 @@ func (in *Merger) DeepCopy() *Merger {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationChannel) DeepCopyInto(out *NotificationChannel) {
+	*out = *in
+	if in.Email != nil {
+		in, out := &in.Email, &out.Email
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationChannel.
+func (in *NotificationChannel) DeepCopy() *NotificationChannel {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationChannel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationRule) DeepCopyInto(out *NotificationRule) {
+	*out = *in
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]NotificationEventType, len(*in))
+		copy(*out, *in)
+	}
+	if in.Repositories != nil {
+		in, out := &in.Repositories, &out.Repositories
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Branches != nil {
+		in, out := &in.Branches, &out.Branches
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Environments != nil {
+		in, out := &in.Environments, &out.Environments
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Channels != nil {
+		in, out := &in.Channels, &out.Channels
+		*out = make([]NotificationChannel, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationRule.
+func (in *NotificationRule) DeepCopy() *NotificationRule {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Original) DeepCopyInto(out *Original) {
 	*out = *in
@@ -2207,6 +2425,11 @@ func (in *PipelineActivitySpec) DeepCopyInto(out *PipelineActivitySpec) {
 		}
 	}
 	in.BatchPipelineActivity.DeepCopyInto(&out.BatchPipelineActivity)
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]PipelineParameter, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -2300,6 +2523,22 @@ func (in *PipelineExtension) DeepCopy() *PipelineExtension {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineParameter) DeepCopyInto(out *PipelineParameter) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineParameter.
+func (in *PipelineParameter) DeepCopy() *PipelineParameter {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineParameter)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PipelineStageAndChildren) DeepCopyInto(out *PipelineStageAndChildren) {
 	*out = *in
@@ -2854,6 +3093,22 @@ func (in *PreviewGitSpec) DeepCopy() *PreviewGitSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreviewPolicy) DeepCopyInto(out *PreviewPolicy) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreviewPolicy.
+func (in *PreviewPolicy) DeepCopy() *PreviewPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(PreviewPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PromoteActivityStep) DeepCopyInto(out *PromoteActivityStep) {
 	*out = *in
@@ -2876,6 +3131,56 @@ func (in *PromoteActivityStep) DeepCopyInto(out *PromoteActivityStep) {
 			(*in).DeepCopyInto(*out)
 		}
 	}
+	if in.Approval != nil {
+		in, out := &in.Approval, &out.Approval
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(PromoteApprovalStep)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.FreezeOverride != nil {
+		in, out := &in.FreezeOverride, &out.FreezeOverride
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(FreezeOverride)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.Rollback != nil {
+		in, out := &in.Rollback, &out.Rollback
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(PromoteRollback)
+			**out = **in
+		}
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(PromoteCanaryStep)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.Verify != nil {
+		in, out := &in.Verify, &out.Verify
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(PromoteVerifyStep)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.Applications != nil {
+		in, out := &in.Applications, &out.Applications
+		*out = make([]PromoteApplication, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -2889,6 +3194,68 @@ func (in *PromoteActivityStep) DeepCopy() *PromoteActivityStep {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromoteApplication) DeepCopyInto(out *PromoteApplication) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromoteApplication.
+func (in *PromoteApplication) DeepCopy() *PromoteApplication {
+	if in == nil {
+		return nil
+	}
+	out := new(PromoteApplication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromoteApprovalStep) DeepCopyInto(out *PromoteApprovalStep) {
+	*out = *in
+	in.CoreActivityStep.DeepCopyInto(&out.CoreActivityStep)
+	if in.Decisions != nil {
+		in, out := &in.Decisions, &out.Decisions
+		*out = make([]ApprovalDecision, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromoteApprovalStep.
+func (in *PromoteApprovalStep) DeepCopy() *PromoteApprovalStep {
+	if in == nil {
+		return nil
+	}
+	out := new(PromoteApprovalStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromoteCanaryStep) DeepCopyInto(out *PromoteCanaryStep) {
+	*out = *in
+	in.CoreActivityStep.DeepCopyInto(&out.CoreActivityStep)
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromoteCanaryStep.
+func (in *PromoteCanaryStep) DeepCopy() *PromoteCanaryStep {
+	if in == nil {
+		return nil
+	}
+	out := new(PromoteCanaryStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PromotePullRequestStep) DeepCopyInto(out *PromotePullRequestStep) {
 	*out = *in
@@ -2906,6 +3273,22 @@ func (in *PromotePullRequestStep) DeepCopy() *PromotePullRequestStep {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromoteRollback) DeepCopyInto(out *PromoteRollback) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromoteRollback.
+func (in *PromoteRollback) DeepCopy() *PromoteRollback {
+	if in == nil {
+		return nil
+	}
+	out := new(PromoteRollback)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PromoteUpdateStep) DeepCopyInto(out *PromoteUpdateStep) {
 	*out = *in
@@ -2928,6 +3311,44 @@ func (in *PromoteUpdateStep) DeepCopy() *PromoteUpdateStep {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromoteVerifyCheck) DeepCopyInto(out *PromoteVerifyCheck) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromoteVerifyCheck.
+func (in *PromoteVerifyCheck) DeepCopy() *PromoteVerifyCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(PromoteVerifyCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromoteVerifyStep) DeepCopyInto(out *PromoteVerifyStep) {
+	*out = *in
+	in.CoreActivityStep.DeepCopyInto(&out.CoreActivityStep)
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]PromoteVerifyCheck, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromoteVerifyStep.
+func (in *PromoteVerifyStep) DeepCopy() *PromoteVerifyStep {
+	if in == nil {
+		return nil
+	}
+	out := new(PromoteVerifyStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PromoteWorkflowStep) DeepCopyInto(out *PromoteWorkflowStep) {
 	*out = *in
@@ -2944,6 +3365,106 @@ func (in *PromoteWorkflowStep) DeepCopy() *PromoteWorkflowStep {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotionApproval) DeepCopyInto(out *PromotionApproval) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionApproval.
+func (in *PromotionApproval) DeepCopy() *PromotionApproval {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotionApproval)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PromotionApproval) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotionApprovalList) DeepCopyInto(out *PromotionApprovalList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PromotionApproval, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionApprovalList.
+func (in *PromotionApprovalList) DeepCopy() *PromotionApprovalList {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotionApprovalList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PromotionApprovalList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotionApprovalSpec) DeepCopyInto(out *PromotionApprovalSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionApprovalSpec.
+func (in *PromotionApprovalSpec) DeepCopy() *PromotionApprovalSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotionApprovalSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotionApprovalStatus) DeepCopyInto(out *PromotionApprovalStatus) {
+	*out = *in
+	if in.Decisions != nil {
+		in, out := &in.Decisions, &out.Decisions
+		*out = make([]ApprovalDecision, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionApprovalStatus.
+func (in *PromotionApprovalStatus) DeepCopy() *PromotionApprovalStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotionApprovalStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProtectionPolicies) DeepCopyInto(out *ProtectionPolicies) {
 	*out = *in
@@ -3941,6 +4462,11 @@ func (in *StageActivityStep) DeepCopyInto(out *StageActivityStep) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Input != nil {
+		in, out := &in.Input, &out.Input
+		*out = new(StageInputStep)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -3954,6 +4480,37 @@ func (in *StageActivityStep) DeepCopy() *StageActivityStep {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StageInputStep) DeepCopyInto(out *StageInputStep) {
+	*out = *in
+	in.CoreActivityStep.DeepCopyInto(&out.CoreActivityStep)
+	if in.Approvers != nil {
+		in, out := &in.Approvers, &out.Approvers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Deadline != nil {
+		in, out := &in.Deadline, &out.Deadline
+		*out = (*in).DeepCopy()
+	}
+	if in.Decision != nil {
+		in, out := &in.Decision, &out.Decision
+		*out = new(ApprovalDecision)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StageInputStep.
+func (in *StageInputStep) DeepCopy() *StageInputStep {
+	if in == nil {
+		return nil
+	}
+	out := new(StageInputStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Statement) DeepCopyInto(out *Statement) {
 	*out = *in
@@ -4080,6 +4637,22 @@ func (in *TeamSettings) DeepCopyInto(out *TeamSettings) {
 		copy(*out, *in)
 	}
 	out.DefaultScheduler = in.DefaultScheduler
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = make([]NotificationRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CVE != nil {
+		in, out := &in.CVE, &out.CVE
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(CVESettings)
+			**out = **in
+		}
+	}
 	return
 }
 
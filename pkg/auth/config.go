@@ -50,6 +50,8 @@ const (
 	ServiceKindBitbucketCloud ServiceKind = "bitbucketcloud"
 	// ServiceKindBitbucketServer indicates that the git server is using as service Bitbuckst Server
 	ServiceKindBitbucketServer ServiceKind = "bitbucketserver"
+	// ServiceKindAzureDevOps indicates that the git server is using as service Azure DevOps Repos
+	ServiceKindAzureDevOps ServiceKind = "azuredevops"
 )
 
 //Server stores the server configuration for a server
@@ -3,8 +3,12 @@ package chats
 const (
 	Slack = "slack"
 	Irc   = "irc"
+	// Teams the chat kind for Microsoft Teams
+	Teams = "teams"
+	// Mattermost the chat kind for Mattermost
+	Mattermost = "mattermost"
 )
 
 var (
-	ChatKinds = []string{Slack, Irc}
+	ChatKinds = []string{Slack, Irc, Teams, Mattermost}
 )
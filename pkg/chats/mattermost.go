@@ -0,0 +1,227 @@
+package chats
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+)
+
+// MattermostChatProvider posts messages to Mattermost channels using the v4 REST API and a bot or personal access token.
+//
+// The server URL is the URL of the Mattermost server, including any path it is served under, and channels are named team/channel
+type MattermostChatProvider struct {
+	Client   *http.Client
+	Server   *auth.AuthServer
+	UserAuth *auth.UserAuth
+}
+
+type mattermostChannel struct {
+	ID          string `json:"id"`
+	TeamID      string `json:"team_id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+}
+
+type mattermostChannelStats struct {
+	ChannelID   string `json:"channel_id"`
+	MemberCount int    `json:"member_count"`
+}
+
+type mattermostChannelMember struct {
+	ChannelID string `json:"channel_id"`
+	UserID    string `json:"user_id"`
+}
+
+type mattermostAttachmentField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
+
+type mattermostAttachment struct {
+	Fallback  string                      `json:"fallback,omitempty"`
+	Color     string                      `json:"color,omitempty"`
+	Title     string                      `json:"title,omitempty"`
+	TitleLink string                      `json:"title_link,omitempty"`
+	Text      string                      `json:"text,omitempty"`
+	Fields    []mattermostAttachmentField `json:"fields,omitempty"`
+}
+
+type mattermostPostProps struct {
+	Attachments []mattermostAttachment `json:"attachments,omitempty"`
+}
+
+type mattermostPost struct {
+	ID        string              `json:"id,omitempty"`
+	ChannelID string              `json:"channel_id,omitempty"`
+	Message   string              `json:"message"`
+	Props     mattermostPostProps `json:"props"`
+}
+
+// CreateMattermostChatProvider creates a new Mattermost chat provider
+func CreateMattermostChatProvider(server *auth.AuthServer, userAuth *auth.UserAuth, batchMode bool) (ChatProvider, error) {
+	u := server.URL
+	if u == "" {
+		return nil, fmt.Errorf("No base URL for server!")
+	}
+	if userAuth == nil || userAuth.IsInvalid() || userAuth.ApiToken == "" {
+		return nil, fmt.Errorf("No authentication found for Mattermost server %s", u)
+	}
+	return &MattermostChatProvider{
+		Client:   &http.Client{Timeout: 30 * time.Second},
+		Server:   server,
+		UserAuth: userAuth,
+	}, nil
+}
+
+// GetChannelMetrics returns the metrics of the given team/channel
+func (c *MattermostChatProvider) GetChannelMetrics(name string) (*ChannelMetrics, error) {
+	metrics := &ChannelMetrics{
+		Name: name,
+	}
+	channel, team, err := c.getChannel(name)
+	if err != nil {
+		return metrics, err
+	}
+	stats := &mattermostChannelStats{}
+	err = c.do(http.MethodGet, c.apiURL("channels", channel.ID, "stats"), nil, stats)
+	if err != nil {
+		return metrics, errors.Wrapf(err, "failed to get stats of Mattermost channel %s", name)
+	}
+	members := []mattermostChannelMember{}
+	err = c.do(http.MethodGet, c.apiURL("channels", channel.ID, "members"), nil, &members)
+	if err != nil {
+		return metrics, errors.Wrapf(err, "failed to get members of Mattermost channel %s", name)
+	}
+	metrics.ID = channel.ID
+	metrics.Name = channel.Name
+	metrics.MemberCount = stats.MemberCount
+	for _, m := range members {
+		metrics.Members = append(metrics.Members, m.UserID)
+	}
+	metrics.URL = util.UrlJoin(c.serverRoot(), team, "channels", channel.Name)
+	return metrics, nil
+}
+
+// PostMessage posts the message to the given team/channel
+func (c *MattermostChatProvider) PostMessage(channel string, message *Message) (*MessageReference, error) {
+	ch, _, err := c.getChannel(channel)
+	if err != nil {
+		return nil, err
+	}
+	post := toMattermostPost(message)
+	post.ChannelID = ch.ID
+	result := &mattermostPost{}
+	err = c.do(http.MethodPost, c.apiURL("posts"), post, result)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to post message to Mattermost channel %s", channel)
+	}
+	return &MessageReference{
+		Channel: result.ChannelID,
+		ID:      result.ID,
+	}, nil
+}
+
+// getChannel finds the channel for the team/channel name returning the channel and its team name
+func (c *MattermostChatProvider) getChannel(name string) (*mattermostChannel, string, error) {
+	team, channelName := c.splitChannelName(name)
+	if team == "" {
+		return nil, "", fmt.Errorf("no team specified for Mattermost channel %s; please use the form team/channel", name)
+	}
+	channel := &mattermostChannel{}
+	err := c.do(http.MethodGet, c.apiURL("teams", "name", team, "channels", "name", channelName), nil, channel)
+	if err != nil {
+		return nil, team, errors.Wrapf(err, "failed to find Mattermost channel %s in team %s", channelName, team)
+	}
+	return channel, team, nil
+}
+
+// splitChannelName splits the team/channel name
+func (c *MattermostChatProvider) splitChannelName(name string) (string, string) {
+	name = strings.TrimPrefix(name, "#")
+	idx := strings.LastIndex(name, "/")
+	if idx >= 0 {
+		return name[:idx], name[idx+1:]
+	}
+	return "", name
+}
+
+// serverRoot returns the server URL keeping the path the server is served under
+func (c *MattermostChatProvider) serverRoot() string {
+	return strings.TrimSuffix(c.Server.URL, "/")
+}
+
+func (c *MattermostChatProvider) apiURL(paths ...string) string {
+	elements := []string{c.serverRoot(), "api", "v4"}
+	for _, p := range paths {
+		elements = append(elements, url.PathEscape(p))
+	}
+	return strings.Join(elements, "/")
+}
+
+func (c *MattermostChatProvider) do(method string, u string, body interface{}, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return errors.Wrapf(err, "failed to marshal request body for %s %s", method, u)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, u, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.UserAuth.ApiToken)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s returned status %d: %s", method, u, resp.StatusCode, string(data))
+	}
+	if result != nil && len(data) > 0 {
+		return json.Unmarshal(data, result)
+	}
+	return nil
+}
+
+func toMattermostPost(message *Message) *mattermostPost {
+	attachment := mattermostAttachment{
+		Fallback:  message.Title,
+		Color:     message.Status.Color(),
+		Title:     message.Title,
+		TitleLink: message.TitleURL,
+		Text:      message.Text,
+	}
+	for _, f := range message.Fields {
+		attachment.Fields = append(attachment.Fields, mattermostAttachmentField{
+			Title: f.Title,
+			Value: f.Value,
+			Short: true,
+		})
+	}
+	return &mattermostPost{
+		Props: mattermostPostProps{
+			Attachments: []mattermostAttachment{attachment},
+		},
+	}
+}
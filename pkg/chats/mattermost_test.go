@@ -0,0 +1,105 @@
+package chats_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/chats"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/suite"
+)
+
+const mattermostChannelID = "ch1zq7ej3pgf8qxyb6tt9a5mrr"
+
+type MattermostChatProviderTestSuite struct {
+	suite.Suite
+	mux      *http.ServeMux
+	server   *httptest.Server
+	provider *chats.MattermostChatProvider
+}
+
+var mattermostRouter = util.Router{
+	"/mattermost/api/v4/teams/name/myteam/channels/name/builds": util.MethodMap{
+		"GET": "channel.json",
+	},
+	"/mattermost/api/v4/channels/" + mattermostChannelID + "/stats": util.MethodMap{
+		"GET": "stats.json",
+	},
+	"/mattermost/api/v4/channels/" + mattermostChannelID + "/members": util.MethodMap{
+		"GET": "members.json",
+	},
+	"/mattermost/api/v4/posts": util.MethodMap{
+		"POST": "post.json",
+	},
+}
+
+func TestMattermostChatProviderTestSuite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping TestMattermostChatProviderTestSuite in short mode")
+	} else {
+		suite.Run(t, new(MattermostChatProviderTestSuite))
+	}
+}
+
+func (suite *MattermostChatProviderTestSuite) SetupSuite() {
+	suite.mux = http.NewServeMux()
+
+	for path, methodMap := range mattermostRouter {
+		suite.mux.HandleFunc(path, util.GetMockAPIResponseFromFile("test_data/mattermost", methodMap))
+	}
+
+	suite.server = httptest.NewServer(suite.mux)
+	suite.Require().NotNil(suite.server)
+
+	as := auth.AuthServer{
+		URL:         suite.server.URL + "/mattermost",
+		Name:        "Test Mattermost Server",
+		Kind:        chats.Mattermost,
+		CurrentUser: "jenkins-x-bot",
+	}
+	ua := auth.UserAuth{
+		Username: "jenkins-x-bot",
+		ApiToken: "test-token",
+	}
+
+	p, err := chats.CreateMattermostChatProvider(&as, &ua, true)
+	suite.Require().NotNil(p)
+	suite.Require().Nil(err)
+
+	var ok bool
+	suite.provider, ok = p.(*chats.MattermostChatProvider)
+	suite.Require().True(ok)
+	suite.Require().NotNil(suite.provider)
+}
+
+func (suite *MattermostChatProviderTestSuite) TestGetChannelMetrics() {
+	metrics, err := suite.provider.GetChannelMetrics("myteam/builds")
+	suite.Require().Nil(err)
+	suite.Require().NotNil(metrics)
+
+	suite.Equal(mattermostChannelID, metrics.ID)
+	suite.Equal("builds", metrics.Name)
+	suite.Equal(2, metrics.MemberCount)
+	suite.Len(metrics.Members, 2)
+	suite.Equal(suite.server.URL+"/mattermost/myteam/channels/builds", metrics.URL)
+}
+
+func (suite *MattermostChatProviderTestSuite) TestGetChannelMetricsWithoutTeam() {
+	_, err := suite.provider.GetChannelMetrics("builds")
+	suite.Require().Error(err)
+}
+
+func (suite *MattermostChatProviderTestSuite) TestPostMessage() {
+	message := chats.BuildStartedMessage("jstrachan", "myapp", "master", "3", "https://dashboard/myapp/3")
+	ref, err := suite.provider.PostMessage("myteam/builds", message)
+	suite.Require().Nil(err)
+	suite.Require().NotNil(ref)
+	suite.Equal(mattermostChannelID, ref.Channel)
+	suite.Equal("po6bdkr3ajbf3ynzrf4ahmtqgr", ref.ID)
+}
+
+func (suite *MattermostChatProviderTestSuite) TearDownSuite() {
+	suite.server.Close()
+}
@@ -0,0 +1,104 @@
+package chats
+
+import (
+	"fmt"
+)
+
+// MessageStatus indicates the outcome a message is about; chat providers use it to colour the message
+type MessageStatus string
+
+const (
+	// MessageStatusInfo an informational message
+	MessageStatusInfo MessageStatus = "info"
+	// MessageStatusPending a message about something which is in progress
+	MessageStatusPending MessageStatus = "pending"
+	// MessageStatusSuccess a message about something which succeeded
+	MessageStatusSuccess MessageStatus = "success"
+	// MessageStatusFailure a message about something which failed
+	MessageStatusFailure MessageStatus = "failure"
+)
+
+// Color returns the hex colour used to render messages of this status
+func (s MessageStatus) Color() string {
+	switch s {
+	case MessageStatusPending:
+		return "#f0ad4e"
+	case MessageStatusSuccess:
+		return "#2eb886"
+	case MessageStatusFailure:
+		return "#d50200"
+	default:
+		return "#439fe0"
+	}
+}
+
+// MessageField a short name/value pair displayed with a message
+type MessageField struct {
+	Title string
+	Value string
+}
+
+// Message a chat message which chat providers render in their own format
+type Message struct {
+	Title    string
+	TitleURL string
+	Text     string
+	Status   MessageStatus
+	Fields   []MessageField
+}
+
+// AddField adds a field to the message if the value is not blank
+func (m *Message) AddField(title string, value string) *Message {
+	if value != "" {
+		m.Fields = append(m.Fields, MessageField{Title: title, Value: value})
+	}
+	return m
+}
+
+// BuildStartedMessage creates the message posted when a pipeline starts
+func BuildStartedMessage(owner string, repo string, branch string, build string, buildURL string) *Message {
+	m := &Message{
+		Title:    fmt.Sprintf("Build #%s of %s/%s/%s started", build, owner, repo, branch),
+		TitleURL: buildURL,
+		Status:   MessageStatusPending,
+	}
+	return m.AddField("Repository", owner+"/"+repo).AddField("Branch", branch).AddField("Build", build)
+}
+
+// BuildFinishedMessage creates the message posted when a pipeline completes with the given status
+func BuildFinishedMessage(owner string, repo string, branch string, build string, buildURL string, succeeded bool) *Message {
+	status := MessageStatusSuccess
+	result := "succeeded"
+	if !succeeded {
+		status = MessageStatusFailure
+		result = "failed"
+	}
+	m := &Message{
+		Title:    fmt.Sprintf("Build #%s of %s/%s/%s %s", build, owner, repo, branch, result),
+		TitleURL: buildURL,
+		Status:   status,
+	}
+	return m.AddField("Repository", owner+"/"+repo).AddField("Branch", branch).AddField("Build", build)
+}
+
+// PromotionPullRequestMessage creates the message posted when a pull request is opened to promote an application
+func PromotionPullRequestMessage(app string, version string, environment string, prURL string) *Message {
+	m := &Message{
+		Title:    fmt.Sprintf("Promoting %s version %s to %s", app, version, environment),
+		TitleURL: prURL,
+		Text:     fmt.Sprintf("Pull request %s has been created to promote the application", prURL),
+		Status:   MessageStatusPending,
+	}
+	return m.AddField("Application", app).AddField("Version", version).AddField("Environment", environment)
+}
+
+// PreviewReadyMessage creates the message posted when a preview environment is available
+func PreviewReadyMessage(app string, prURL string, previewURL string) *Message {
+	m := &Message{
+		Title:    fmt.Sprintf("Preview of %s is ready", app),
+		TitleURL: previewURL,
+		Text:     fmt.Sprintf("The preview environment for %s is available at %s", prURL, previewURL),
+		Status:   MessageStatusSuccess,
+	}
+	return m.AddField("Application", app).AddField("Pull Request", prURL).AddField("Preview", previewURL)
+}
@@ -10,6 +10,9 @@ import (
 // ChatProvider represents an integration interface to chat
 type ChatProvider interface {
 	GetChannelMetrics(name string) (*ChannelMetrics, error)
+
+	// PostMessage posts a new message to the given channel returning a reference to the posted message
+	PostMessage(channel string, message *Message) (*MessageReference, error)
 }
 
 // ChannelMetrics metrics for a channel
@@ -21,6 +24,14 @@ type ChannelMetrics struct {
 	Members     []string
 }
 
+// MessageReference refers to a message posted to a channel
+type MessageReference struct {
+	// Channel the ID of the channel the message was posted to
+	Channel string
+	// ID the ID of the message within the channel
+	ID string
+}
+
 func (m *ChannelMetrics) ToMarkdown() string {
 	return util.MarkdownLink(m.Name, m.URL)
 }
@@ -30,6 +41,10 @@ func CreateChatProvider(kind string, server *auth.AuthServer, userAuth *auth.Use
 	switch kind {
 	case Slack:
 		return CreateSlackChatProvider(server, userAuth, batchMode)
+	case Teams:
+		return CreateTeamsChatProvider(server, userAuth, batchMode)
+	case Mattermost:
+		return CreateMattermostChatProvider(server, userAuth, batchMode)
 	default:
 		return nil, fmt.Errorf("Unsupported chat provider kind: %s", kind)
 	}
@@ -39,6 +54,8 @@ func ProviderAccessTokenURL(kind string, url string) string {
 	switch kind {
 	case Slack:
 		return "https://my.slack.com/services/new/bot"
+	case Mattermost:
+		return util.UrlJoin(url, "_redirect/integrations/bots")
 	default:
 		return ""
 	}
@@ -8,6 +8,7 @@ import (
 	"github.com/jenkins-x/jx/pkg/log"
 	"github.com/jenkins-x/jx/pkg/util"
 	"github.com/nlopes/slack"
+	"github.com/pkg/errors"
 )
 
 type SlackChatProvider struct {
@@ -62,3 +63,33 @@ func (c *SlackChatProvider) GetChannelMetrics(name string) (*ChannelMetrics, err
 	metrics.URL = util.UrlJoin(c.Server.URL, "messages", info.ID)
 	return metrics, nil
 }
+
+// PostMessage posts the message to the Slack channel as an attachment
+func (c *SlackChatProvider) PostMessage(channel string, message *Message) (*MessageReference, error) {
+	channelID, timestamp, _, err := c.SlackClient.SendMessage(channel, slack.MsgOptionAttachments(toSlackAttachment(message)))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to post message to Slack channel %s", channel)
+	}
+	return &MessageReference{
+		Channel: channelID,
+		ID:      timestamp,
+	}, nil
+}
+
+func toSlackAttachment(message *Message) slack.Attachment {
+	attachment := slack.Attachment{
+		Fallback:  message.Title,
+		Color:     message.Status.Color(),
+		Title:     message.Title,
+		TitleLink: message.TitleURL,
+		Text:      message.Text,
+	}
+	for _, f := range message.Fields {
+		attachment.Fields = append(attachment.Fields, slack.AttachmentField{
+			Title: f.Title,
+			Value: f.Value,
+			Short: true,
+		})
+	}
+	return attachment
+}
@@ -0,0 +1,144 @@
+package chats
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+)
+
+// TeamsChatProvider posts messages to Microsoft Teams channels via incoming webhooks.
+//
+// Incoming webhooks are bound to a single channel so the API token of the user is the webhook URL
+// (or its path relative to the server URL) unless the channel is itself given as a webhook URL
+type TeamsChatProvider struct {
+	Client   *http.Client
+	Server   *auth.AuthServer
+	UserAuth *auth.UserAuth
+}
+
+type teamsMessageCard struct {
+	Type            string               `json:"@type"`
+	Context         string               `json:"@context"`
+	ThemeColor      string               `json:"themeColor,omitempty"`
+	Summary         string               `json:"summary"`
+	Title           string               `json:"title,omitempty"`
+	Text            string               `json:"text,omitempty"`
+	Sections        []teamsSection       `json:"sections,omitempty"`
+	PotentialAction []teamsOpenURIAction `json:"potentialAction,omitempty"`
+}
+
+type teamsSection struct {
+	Facts []teamsFact `json:"facts"`
+}
+
+type teamsFact struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type teamsOpenURIAction struct {
+	Type    string           `json:"@type"`
+	Name    string           `json:"name"`
+	Targets []teamsURITarget `json:"targets"`
+}
+
+type teamsURITarget struct {
+	OS  string `json:"os"`
+	URI string `json:"uri"`
+}
+
+// CreateTeamsChatProvider creates a new Microsoft Teams chat provider
+func CreateTeamsChatProvider(server *auth.AuthServer, userAuth *auth.UserAuth, batchMode bool) (ChatProvider, error) {
+	u := server.URL
+	if u == "" {
+		return nil, fmt.Errorf("No base URL for server!")
+	}
+	if userAuth == nil || userAuth.ApiToken == "" {
+		return nil, fmt.Errorf("No incoming webhook found for Microsoft Teams server %s", u)
+	}
+	return &TeamsChatProvider{
+		Client:   &http.Client{Timeout: 30 * time.Second},
+		Server:   server,
+		UserAuth: userAuth,
+	}, nil
+}
+
+// GetChannelMetrics returns the channel name only as incoming webhooks cannot query channels
+func (c *TeamsChatProvider) GetChannelMetrics(name string) (*ChannelMetrics, error) {
+	return &ChannelMetrics{
+		Name: name,
+		URL:  c.Server.URL,
+	}, nil
+}
+
+// PostMessage posts the message as a message card to the incoming webhook of the channel
+func (c *TeamsChatProvider) PostMessage(channel string, message *Message) (*MessageReference, error) {
+	webhookURL := c.webhookURL(channel)
+	data, err := json.Marshal(toTeamsMessageCard(message))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal Microsoft Teams message card")
+	}
+	resp, err := c.Client.Post(webhookURL, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to post message to Microsoft Teams channel %s", channel)
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("failed to post message to Microsoft Teams channel %s: status %d %s", channel, resp.StatusCode, string(body))
+	}
+	return &MessageReference{
+		Channel: channel,
+	}, nil
+}
+
+// webhookURL returns the incoming webhook URL for the channel
+func (c *TeamsChatProvider) webhookURL(channel string) string {
+	if strings.HasPrefix(channel, "http://") || strings.HasPrefix(channel, "https://") {
+		return channel
+	}
+	token := c.UserAuth.ApiToken
+	if strings.HasPrefix(token, "http://") || strings.HasPrefix(token, "https://") {
+		return token
+	}
+	return util.UrlJoin(c.Server.URL, token)
+}
+
+func toTeamsMessageCard(message *Message) *teamsMessageCard {
+	card := &teamsMessageCard{
+		Type:       "MessageCard",
+		Context:    "http://schema.org/extensions",
+		ThemeColor: strings.TrimPrefix(message.Status.Color(), "#"),
+		Summary:    message.Title,
+		Title:      message.Title,
+		Text:       message.Text,
+	}
+	if len(message.Fields) > 0 {
+		section := teamsSection{}
+		for _, f := range message.Fields {
+			section.Facts = append(section.Facts, teamsFact{Name: f.Title, Value: f.Value})
+		}
+		card.Sections = append(card.Sections, section)
+	}
+	if message.TitleURL != "" {
+		card.PotentialAction = append(card.PotentialAction, teamsOpenURIAction{
+			Type: "OpenUri",
+			Name: "View",
+			Targets: []teamsURITarget{
+				{
+					OS:  "default",
+					URI: message.TitleURL,
+				},
+			},
+		})
+	}
+	return card
+}
@@ -0,0 +1,125 @@
+package chats_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/chats"
+	"github.com/stretchr/testify/suite"
+)
+
+const teamsWebhookPath = "/webhookb2/8f0f9b2e-builds/IncomingWebhook/4d6c0a1f"
+
+type TeamsChatProviderTestSuite struct {
+	suite.Suite
+	mux      *http.ServeMux
+	server   *httptest.Server
+	provider *chats.TeamsChatProvider
+	cards    []map[string]interface{}
+}
+
+func TestTeamsChatProviderTestSuite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping TestTeamsChatProviderTestSuite in short mode")
+	} else {
+		suite.Run(t, new(TeamsChatProviderTestSuite))
+	}
+}
+
+func (suite *TeamsChatProviderTestSuite) SetupSuite() {
+	suite.mux = http.NewServeMux()
+	suite.mux.HandleFunc(teamsWebhookPath, func(w http.ResponseWriter, r *http.Request) {
+		suite.Equal(http.MethodPost, r.Method)
+		suite.Equal("application/json", r.Header.Get("Content-Type"))
+		data, err := ioutil.ReadAll(r.Body)
+		suite.Require().Nil(err)
+		card := map[string]interface{}{}
+		suite.Require().Nil(json.Unmarshal(data, &card))
+		suite.cards = append(suite.cards, card)
+		w.Write([]byte("1"))
+	})
+	suite.mux.HandleFunc("/webhookb2/unknown", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Webhook Bad Request - Null or empty event", http.StatusBadRequest)
+	})
+
+	suite.server = httptest.NewServer(suite.mux)
+	suite.Require().NotNil(suite.server)
+
+	as := auth.AuthServer{
+		URL:  suite.server.URL,
+		Name: "Test Microsoft Teams Server",
+		Kind: chats.Teams,
+	}
+	ua := auth.UserAuth{
+		Username: "jenkins-x-bot",
+		ApiToken: teamsWebhookPath,
+	}
+
+	p, err := chats.CreateTeamsChatProvider(&as, &ua, true)
+	suite.Require().NotNil(p)
+	suite.Require().Nil(err)
+
+	var ok bool
+	suite.provider, ok = p.(*chats.TeamsChatProvider)
+	suite.Require().True(ok)
+	suite.Require().NotNil(suite.provider)
+}
+
+func (suite *TeamsChatProviderTestSuite) SetupTest() {
+	suite.cards = nil
+}
+
+func (suite *TeamsChatProviderTestSuite) TestPostStartedAndFinishedMessages() {
+	message := chats.BuildStartedMessage("jstrachan", "myapp", "master", "3", "https://dashboard/myapp/3")
+	ref, err := suite.provider.PostMessage("builds", message)
+	suite.Require().Nil(err)
+	suite.Require().NotNil(ref)
+	suite.Equal("builds", ref.Channel)
+
+	message = chats.BuildFinishedMessage("jstrachan", "myapp", "master", "3", "https://dashboard/myapp/3", false)
+	ref, err = suite.provider.PostMessage("builds", message)
+	suite.Require().Nil(err)
+	suite.Require().NotNil(ref)
+
+	suite.Require().Len(suite.cards, 2)
+	card := suite.cards[0]
+	suite.Equal("MessageCard", card["@type"])
+	suite.Equal("Build #3 of jstrachan/myapp/master started", card["title"])
+	suite.Equal("f0ad4e", card["themeColor"])
+	sections := card["sections"].([]interface{})
+	suite.Require().Len(sections, 1)
+	facts := sections[0].(map[string]interface{})["facts"].([]interface{})
+	suite.Len(facts, 3)
+	actions := card["potentialAction"].([]interface{})
+	suite.Require().Len(actions, 1)
+	targets := actions[0].(map[string]interface{})["targets"].([]interface{})
+	suite.Equal("https://dashboard/myapp/3", targets[0].(map[string]interface{})["uri"])
+
+	card = suite.cards[1]
+	suite.Equal("Build #3 of jstrachan/myapp/master failed", card["title"])
+	suite.Equal("d50200", card["themeColor"])
+}
+
+func (suite *TeamsChatProviderTestSuite) TestPostMessageToWebhookChannel() {
+	message := chats.PreviewReadyMessage("myapp", "https://github.com/jstrachan/myapp/pull/1", "http://myapp.jx-pr-1.example.com")
+	ref, err := suite.provider.PostMessage(suite.server.URL+teamsWebhookPath, message)
+	suite.Require().Nil(err)
+	suite.Require().NotNil(ref)
+	suite.Require().Len(suite.cards, 1)
+	suite.Equal("Preview of myapp is ready", suite.cards[0]["title"])
+}
+
+func (suite *TeamsChatProviderTestSuite) TestPostMessageFailure() {
+	message := chats.PreviewReadyMessage("myapp", "https://github.com/jstrachan/myapp/pull/1", "http://myapp.jx-pr-1.example.com")
+	_, err := suite.provider.PostMessage(suite.server.URL+"/webhookb2/unknown", message)
+	suite.Require().Error(err)
+	suite.Empty(suite.cards)
+}
+
+func (suite *TeamsChatProviderTestSuite) TearDownSuite() {
+	suite.server.Close()
+}
@@ -56,6 +56,10 @@ func (c *FakeJenkinsV1) Plugins(namespace string) v1.PluginInterface {
 	return &FakePlugins{c, namespace}
 }
 
+func (c *FakeJenkinsV1) PromotionApprovals(namespace string) v1.PromotionApprovalInterface {
+	return &FakePromotionApprovals{c, namespace}
+}
+
 func (c *FakeJenkinsV1) Releases(namespace string) v1.ReleaseInterface {
 	return &FakeReleases{c, namespace}
 }
@@ -0,0 +1,9 @@
+package fake
+
+import "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+
+// PatchUpdate takes the representation of a promotionApproval and updates using Patch generating a JSON patch to do so.
+// Returns the server's representation of the promotionApproval, and an error, if there is any.
+func (c *FakePromotionApprovals) PatchUpdate(promotionApproval *v1.PromotionApproval) (*v1.PromotionApproval, error) {
+	return c.Update(promotionApproval)
+}
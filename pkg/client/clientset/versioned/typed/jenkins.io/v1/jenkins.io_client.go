@@ -22,6 +22,7 @@ type JenkinsV1Interface interface {
 	PipelineActivitiesGetter
 	PipelineStructuresGetter
 	PluginsGetter
+	PromotionApprovalsGetter
 	ReleasesGetter
 	SchedulersGetter
 	SourceRepositoriesGetter
@@ -80,6 +81,10 @@ func (c *JenkinsV1Client) Plugins(namespace string) PluginInterface {
 	return newPlugins(c, namespace)
 }
 
+func (c *JenkinsV1Client) PromotionApprovals(namespace string) PromotionApprovalInterface {
+	return newPromotionApprovals(c, namespace)
+}
+
 func (c *JenkinsV1Client) Releases(namespace string) ReleaseInterface {
 	return newReleases(c, namespace)
 }
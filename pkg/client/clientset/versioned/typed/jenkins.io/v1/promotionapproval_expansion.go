@@ -0,0 +1,37 @@
+package v1
+
+import (
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	util "github.com/jenkins-x/jx/pkg/util/json"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// PromotionApprovalExpansion expands the default CRUD interface for PromotionApproval.
+type PromotionApprovalExpansion interface {
+	PatchUpdate(promotionApproval *v1.PromotionApproval) (result *v1.PromotionApproval, err error)
+}
+
+// PatchUpdate takes the representation of a promotionApproval and updates using Patch generating a JSON patch to do so.
+// Returns the server's representation of the promotionApproval, and an error, if there is any.
+func (c *promotionApprovals) PatchUpdate(promotionApproval *v1.PromotionApproval) (*v1.PromotionApproval, error) {
+	resourceName := promotionApproval.ObjectMeta.Name
+
+	// force retrieval from cache
+	options := metav1.GetOptions{ResourceVersion: "0"}
+	orig, err := c.Get(resourceName, options)
+	if err != nil {
+		return nil, err
+	}
+
+	patch, err := util.CreatePatch(orig, promotionApproval)
+	if err != nil {
+		return nil, err
+	}
+	patched, err := c.Patch(resourceName, types.JSONPatchType, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	return patched, nil
+}
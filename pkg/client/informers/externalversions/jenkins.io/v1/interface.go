@@ -30,6 +30,8 @@ type Interface interface {
 	PipelineStructures() PipelineStructureInformer
 	// Plugins returns a PluginInformer.
 	Plugins() PluginInformer
+	// PromotionApprovals returns a PromotionApprovalInformer.
+	PromotionApprovals() PromotionApprovalInformer
 	// Releases returns a ReleaseInformer.
 	Releases() ReleaseInformer
 	// Schedulers returns a SchedulerInformer.
@@ -112,6 +114,11 @@ func (v *version) Plugins() PluginInformer {
 	return &pluginInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// PromotionApprovals returns a PromotionApprovalInformer.
+func (v *version) PromotionApprovals() PromotionApprovalInformer {
+	return &promotionApprovalInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // Releases returns a ReleaseInformer.
 func (v *version) Releases() ReleaseInformer {
 	return &releaseInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
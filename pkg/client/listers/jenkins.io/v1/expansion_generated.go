@@ -90,6 +90,14 @@ type PluginListerExpansion interface{}
 // PluginNamespaceLister.
 type PluginNamespaceListerExpansion interface{}
 
+// PromotionApprovalListerExpansion allows custom methods to be added to
+// PromotionApprovalLister.
+type PromotionApprovalListerExpansion interface{}
+
+// PromotionApprovalNamespaceListerExpansion allows custom methods to be added to
+// PromotionApprovalNamespaceLister.
+type PromotionApprovalNamespaceListerExpansion interface{}
+
 // ReleaseListerExpansion allows custom methods to be added to
 // ReleaseLister.
 type ReleaseListerExpansion interface{}
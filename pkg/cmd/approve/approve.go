@@ -0,0 +1,187 @@
+package approve
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ApproveOptions contains the command line options for approving or rejecting promotions
+type ApproveOptions struct {
+	*opts.CommonOptions
+
+	Username string
+	Comment  string
+
+	// Approved is true when approving and false when rejecting
+	Approved bool
+}
+
+var (
+	approveLong = templates.LongDesc(`
+		Approves the promotion of an application to an Environment which requires approval.
+
+		Promotions to an Environment which requires approval wait until the required number of approvers,
+		who are the subjects of the EnvironmentRoleBinding of the Environment, have approved the promotion.
+
+		The decision is made as the current user. Only users who can update the EnvironmentRoleBindings can decide
+		as another user with the '--username' option.
+`)
+
+	approveExample = templates.Examples(`
+		# Pick the pending promotion to approve
+		jx approve
+
+		# Approve a promotion
+		jx approve myapp-1-2-3-production -m "release notes reviewed"
+	`)
+)
+
+// NewCmdApprove creates the command to approve promotions
+func NewCmdApprove(commonOpts *opts.CommonOptions) *cobra.Command {
+	options := &ApproveOptions{
+		CommonOptions: commonOpts,
+		Approved:      true,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "approve [promotion]",
+		Short:   "Approves a promotion to an Environment which requires approval",
+		Long:    approveLong,
+		Example: approveExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			helper.CheckErr(err)
+		},
+	}
+	options.AddApproveFlags(cmd)
+	cmd.AddCommand(NewCmdApprovePipeline(commonOpts))
+	return cmd
+}
+
+// AddApproveFlags adds the flags common to approving and rejecting
+func (o *ApproveOptions) AddApproveFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&o.Username, "username", "", "", "The user name of the approver if not the current user. Requires permission to update the EnvironmentRoleBindings")
+	cmd.Flags().StringVarP(&o.Comment, "comment", "m", "", "The comment describing the decision")
+}
+
+// Run implements this command
+func (o *ApproveOptions) Run() error {
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	err = o.RegisterPromotionApprovalCRD()
+	if err != nil {
+		return err
+	}
+	userName, err := o.ApproverName(ns)
+	if err != nil {
+		return err
+	}
+
+	name := ""
+	if len(o.Args) > 0 {
+		name = o.Args[0]
+	} else {
+		pending, err := kube.GetPendingPromotionApprovals(jxClient, ns)
+		if err != nil {
+			return err
+		}
+		names := []string{}
+		for _, approval := range pending {
+			names = append(names, approval.Name)
+		}
+		if len(names) == 0 {
+			return fmt.Errorf("there are no promotions waiting for approval in namespace %s", ns)
+		}
+		if o.BatchMode {
+			return fmt.Errorf("missing argument for the promotion to decide on, pending promotions are: %s", strings.Join(names, ", "))
+		}
+		name, err = util.PickName(names, "Pick the promotion: ", "", o.In, o.Out, o.Err)
+		if err != nil {
+			return err
+		}
+	}
+
+	approvals := jxClient.JenkinsV1().PromotionApprovals(ns)
+	approval, err := approvals.Get(name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to find the PromotionApproval %s", name)
+	}
+	if !approval.IsPending() {
+		return fmt.Errorf("the promotion %s is no longer waiting for approval as it has status %s", name, string(approval.Status.Status))
+	}
+	approver, err := kube.IsPromotionApprover(jxClient, ns, approval, userName)
+	if err != nil {
+		return err
+	}
+	if !approver {
+		return fmt.Errorf("user %s is not an approver of promotions to %s as they are not a subject of the EnvironmentRoleBinding %s", userName, approval.Spec.Environment, approval.Spec.RoleBinding)
+	}
+
+	approval.AddDecision(userName, o.Approved, o.Comment)
+	_, err = approvals.PatchUpdate(approval)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update the PromotionApproval %s", name)
+	}
+
+	info := util.ColorInfo
+	spec := &approval.Spec
+	switch approval.Status.Status {
+	case v1.ActivityStatusTypeSucceeded:
+		log.Logger().Infof("Promotion of %s version %s to %s has been approved", info(spec.Application), info(spec.Version), info(spec.Environment))
+	case v1.ActivityStatusTypeAborted:
+		log.Logger().Infof("Promotion of %s version %s to %s has been rejected", info(spec.Application), info(spec.Version), info(spec.Environment))
+	default:
+		log.Logger().Infof("Promotion of %s version %s to %s has %s of %s required approvals", info(spec.Application), info(spec.Version), info(spec.Environment),
+			info(fmt.Sprintf("%d", approval.Approvals())), info(fmt.Sprintf("%d", spec.RequiredApprovals)))
+	}
+	return nil
+}
+
+// ApproverName returns the name of the user making the decision which is the current user unless the '--username'
+// option names another user. Deciding as another user requires permission to update the EnvironmentRoleBindings in
+// the namespace as that permission could make them an approver anyway
+func (o *ApproveOptions) ApproverName(ns string) (string, error) {
+	userName, err := o.GetUsername("")
+	if err != nil {
+		return "", err
+	}
+	if o.Username != "" {
+		override, err := o.GetUsername(o.Username)
+		if err != nil {
+			return "", err
+		}
+		if override != userName {
+			kubeClient, err := o.KubeClient()
+			if err != nil {
+				return "", err
+			}
+			allowed, err := kube.CanManagePromotionApprovers(kubeClient, ns)
+			if err != nil {
+				return "", err
+			}
+			if !allowed {
+				return "", fmt.Errorf("cannot decide as user %s as only users who can update the EnvironmentRoleBindings in namespace %s can use the '--username' option", override, ns)
+			}
+		}
+		userName = override
+	}
+	if userName == "" {
+		return "", util.MissingOption("username")
+	}
+	return userName, nil
+}
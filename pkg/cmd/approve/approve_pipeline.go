@@ -0,0 +1,157 @@
+package approve
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/kube/naming"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// ApprovePipelineOptions contains the command line options for approving or rejecting the input stages of pipelines
+type ApprovePipelineOptions struct {
+	ApproveOptions
+
+	Stage string
+}
+
+var (
+	approvePipelineLong = templates.LongDesc(`
+		Approves a pipeline which is waiting at an input stage so that it continues.
+
+		If the input stage specifies approvers only they can approve it.
+`)
+
+	approvePipelineExample = templates.Examples(`
+		# Pick the pipeline to approve
+		jx approve pipeline
+
+		# Approve build 3 of a pipeline
+		jx approve pipeline myorg/myrepo/master 3
+
+		# Approve the latest build of a pipeline waiting for approval
+		jx approve pipeline myorg/myrepo/master -m "looks good"
+	`)
+
+	rejectPipelineLong = templates.LongDesc(`
+		Rejects a pipeline which is waiting at an input stage which aborts the pipeline.
+
+		If the input stage specifies approvers only they can reject it.
+`)
+
+	rejectPipelineExample = templates.Examples(`
+		# Pick the pipeline to reject
+		jx reject pipeline
+
+		# Reject build 3 of a pipeline
+		jx reject pipeline myorg/myrepo/master 3 -m "not this week"
+	`)
+)
+
+// NewCmdApprovePipeline creates the command to approve the input stages of pipelines
+func NewCmdApprovePipeline(commonOpts *opts.CommonOptions) *cobra.Command {
+	return newCmdDecidePipeline(commonOpts, true, "Approves a pipeline which is waiting at an input stage", approvePipelineLong, approvePipelineExample)
+}
+
+// NewCmdRejectPipeline creates the command to reject the input stages of pipelines
+func NewCmdRejectPipeline(commonOpts *opts.CommonOptions) *cobra.Command {
+	return newCmdDecidePipeline(commonOpts, false, "Rejects a pipeline which is waiting at an input stage", rejectPipelineLong, rejectPipelineExample)
+}
+
+func newCmdDecidePipeline(commonOpts *opts.CommonOptions, approved bool, short string, long string, example string) *cobra.Command {
+	options := &ApprovePipelineOptions{
+		ApproveOptions: ApproveOptions{
+			CommonOptions: commonOpts,
+			Approved:      approved,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "pipeline [owner/repo/branch] [build]",
+		Short:   short,
+		Long:    long,
+		Example: example,
+		Aliases: []string{"pipelines", "build", "run"},
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			helper.CheckErr(err)
+		},
+	}
+	options.AddApproveFlags(cmd)
+	cmd.Flags().StringVarP(&options.Stage, "stage", "s", "", "The name of the input stage, if the pipeline has more than one waiting for approval")
+	return cmd
+}
+
+// Run implements this command
+func (o *ApprovePipelineOptions) Run() error {
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	userName, err := o.ApproverName(ns)
+	if err != nil {
+		return err
+	}
+
+	pipeline := ""
+	build := ""
+	if len(o.Args) > 0 {
+		pipeline = o.Args[0]
+		if len(strings.Split(pipeline, "/")) != 3 {
+			return util.InvalidArgf(pipeline, "the pipeline should be of the form owner/repo/branch")
+		}
+	}
+	if len(o.Args) > 1 {
+		build = strings.TrimPrefix(o.Args[1], "#")
+	}
+
+	name := ""
+	if pipeline != "" && build != "" {
+		name = naming.ToValidName(strings.Replace(pipeline, "/", "-", -1) + "-" + build)
+	} else if pipeline != "" {
+		name, err = kube.GetLatestPipelineActivityWaitingForInput(jxClient, ns, pipeline)
+		if err != nil {
+			return err
+		}
+	} else {
+		waiting, err := kube.GetPipelineActivitiesWaitingForInput(jxClient, ns)
+		if err != nil {
+			return err
+		}
+		names := []string{}
+		for _, activity := range waiting {
+			names = append(names, activity.Name)
+		}
+		if len(names) == 0 {
+			return fmt.Errorf("there are no pipelines waiting for approval in namespace %s", ns)
+		}
+		if o.BatchMode {
+			return fmt.Errorf("missing argument for the pipeline to decide on, pipelines waiting for approval are: %s", strings.Join(names, ", "))
+		}
+		name, err = util.PickName(names, "Pick the pipeline: ", "", o.In, o.Out, o.Err)
+		if err != nil {
+			return err
+		}
+	}
+
+	stage, err := kube.DecideStageInput(jxClient, ns, name, o.Stage, userName, o.Approved, o.Comment)
+	if err != nil {
+		return err
+	}
+	info := util.ColorInfo
+	if stage.Input.Status == v1.ActivityStatusTypeSucceeded {
+		log.Logger().Infof("Stage %s of pipeline %s has been approved", info(stage.Name), info(name))
+	} else {
+		log.Logger().Infof("Stage %s of pipeline %s has been rejected", info(stage.Name), info(name))
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package approve_test
+
+import (
+	"testing"
+
+	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/cmd/approve"
+	"github.com/jenkins-x/jx/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/pkg/cmd/testhelpers"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/helm/mocks"
+	"github.com/jenkins-x/jx/pkg/kube/resources/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const activityName = "myorg-myapp-master-3"
+
+func createApprovePipelineOptions(t *testing.T, approved bool, username string) *approve.ApprovePipelineOptions {
+	commonOpts := opts.NewCommonOptionsWithFactory(nil)
+	testhelpers.ConfigureTestOptionsWithResources(&commonOpts,
+		[]runtime.Object{},
+		[]runtime.Object{
+			&v1.PipelineActivity{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      activityName,
+					Namespace: "jx",
+				},
+				Spec: v1.PipelineActivitySpec{
+					Pipeline: "myorg/myapp/master",
+					Build:    "3",
+					Status:   v1.ActivityStatusTypeRunning,
+					Steps: []v1.PipelineActivityStep{
+						{
+							Kind: v1.ActivityStepKindTypeStage,
+							Stage: &v1.StageActivityStep{
+								CoreActivityStep: v1.CoreActivityStep{
+									Name:   "Build",
+									Status: v1.ActivityStatusTypeSucceeded,
+								},
+							},
+						},
+						{
+							Kind: v1.ActivityStepKindTypeStage,
+							Stage: &v1.StageActivityStep{
+								CoreActivityStep: v1.CoreActivityStep{
+									Name:   "Approve",
+									Status: v1.ActivityStatusTypeWaitingForApproval,
+								},
+								Input: &v1.StageInputStep{
+									CoreActivityStep: v1.CoreActivityStep{
+										Name:   "Approve",
+										Status: v1.ActivityStatusTypeWaitingForApproval,
+									},
+									Message:   "Deploy to production?",
+									Approvers: []string{"alice", "bob"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		gits.NewGitCLI(),
+		nil,
+		helm_test.NewMockHelmer(),
+		resources_test.NewMockInstaller(),
+	)
+	allowDecidingAsAnotherUser(t, &commonOpts, true)
+	return &approve.ApprovePipelineOptions{
+		ApproveOptions: approve.ApproveOptions{
+			CommonOptions: &commonOpts,
+			Username:      username,
+			Approved:      approved,
+		},
+	}
+}
+
+func getInput(t *testing.T, o *approve.ApprovePipelineOptions) *v1.StageInputStep {
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	require.NoError(t, err)
+	activity, err := jxClient.JenkinsV1().PipelineActivities(ns).Get(activityName, metav1.GetOptions{})
+	require.NoError(t, err)
+	return activity.Spec.Steps[1].Stage.Input
+}
+
+func TestApprovePipeline(t *testing.T) {
+	t.Parallel()
+
+	o := createApprovePipelineOptions(t, true, "alice")
+	o.Args = []string{"myorg/myapp/master", "#3"}
+	o.Comment = "ship it"
+	err := o.Run()
+	require.NoError(t, err)
+
+	input := getInput(t, o)
+	assert.Equal(t, v1.ActivityStatusTypeSucceeded, input.Status)
+	require.NotNil(t, input.Decision)
+	assert.Equal(t, "alice", input.Decision.User)
+	assert.Equal(t, "ship it", input.Decision.Comment)
+
+	// the input has been decided
+	err = o.Run()
+	assert.Error(t, err)
+}
+
+func TestRejectLatestPipeline(t *testing.T) {
+	t.Parallel()
+
+	o := createApprovePipelineOptions(t, false, "bob")
+	o.Args = []string{"myorg/myapp/master"}
+	err := o.Run()
+	require.NoError(t, err)
+
+	input := getInput(t, o)
+	assert.Equal(t, v1.ActivityStatusTypeAborted, input.Status)
+	assert.False(t, input.Decision.Approved)
+}
+
+func TestApprovePipelineByNonApproverFails(t *testing.T) {
+	t.Parallel()
+
+	o := createApprovePipelineOptions(t, true, "mallory")
+	o.Args = []string{"myorg/myapp/master", "3"}
+	err := o.Run()
+	require.Error(t, err)
+
+	input := getInput(t, o)
+	assert.Equal(t, v1.ActivityStatusTypeWaitingForApproval, input.Status)
+	assert.Nil(t, input.Decision)
+}
+
+func TestApprovePipelineIgnoresCaseOfApprovers(t *testing.T) {
+	t.Parallel()
+
+	o := createApprovePipelineOptions(t, true, "Alice")
+	o.Args = []string{"myorg/myapp/master", "3"}
+	err := o.Run()
+	require.NoError(t, err)
+
+	input := getInput(t, o)
+	assert.Equal(t, v1.ActivityStatusTypeSucceeded, input.Status)
+	assert.Equal(t, "alice", input.Decision.User)
+}
@@ -0,0 +1,158 @@
+package approve_test
+
+import (
+	"testing"
+
+	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/cmd/approve"
+	"github.com/jenkins-x/jx/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/pkg/cmd/testhelpers"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/helm/mocks"
+	"github.com/jenkins-x/jx/pkg/kube/resources/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kube_mocks "k8s.io/client-go/kubernetes/fake"
+	k8sTesting "k8s.io/client-go/testing"
+)
+
+const approvalName = "myapp-1-2-3-production"
+
+func createApproveOptions(t *testing.T, approved bool, username string) *approve.ApproveOptions {
+	commonOpts := opts.NewCommonOptionsWithFactory(nil)
+	testhelpers.ConfigureTestOptionsWithResources(&commonOpts,
+		[]runtime.Object{},
+		[]runtime.Object{
+			&v1.EnvironmentRoleBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "production-approvers",
+					Namespace: "jx",
+				},
+				Spec: v1.EnvironmentRoleBindingSpec{
+					Subjects: []rbacv1.Subject{
+						{Kind: rbacv1.UserKind, Name: "alice"},
+						{Kind: rbacv1.UserKind, Name: "bob"},
+					},
+				},
+			},
+			&v1.PromotionApproval{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      approvalName,
+					Namespace: "jx",
+				},
+				Spec: v1.PromotionApprovalSpec{
+					Environment:       "production",
+					Application:       "myapp",
+					Version:           "1.2.3",
+					RequiredApprovals: 2,
+					RoleBinding:       "production-approvers",
+				},
+				Status: v1.PromotionApprovalStatus{
+					Status: v1.ActivityStatusTypeWaitingForApproval,
+				},
+			},
+		},
+		gits.NewGitCLI(),
+		nil,
+		helm_test.NewMockHelmer(),
+		resources_test.NewMockInstaller(),
+	)
+	allowDecidingAsAnotherUser(t, &commonOpts, true)
+	return &approve.ApproveOptions{
+		CommonOptions: &commonOpts,
+		Username:      username,
+		Approved:      approved,
+	}
+}
+
+// allowDecidingAsAnotherUser answers whether the current user can update the EnvironmentRoleBindings as the tests
+// decide as the users given by the '--username' option
+func allowDecidingAsAnotherUser(t *testing.T, commonOpts *opts.CommonOptions, allowed bool) {
+	kubeClient, err := commonOpts.KubeClient()
+	require.NoError(t, err)
+	kubeClient.(*kube_mocks.Clientset).PrependReactor("create", "selfsubjectaccessreviews", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8sTesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		review.Status.Allowed = allowed && review.Spec.ResourceAttributes.Resource == "environmentrolebindings"
+		return true, review, nil
+	})
+}
+
+func getApproval(t *testing.T, o *approve.ApproveOptions) *v1.PromotionApproval {
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	require.NoError(t, err)
+	approval, err := jxClient.JenkinsV1().PromotionApprovals(ns).Get(approvalName, metav1.GetOptions{})
+	require.NoError(t, err)
+	return approval
+}
+
+func TestApproveRequiresAllApprovers(t *testing.T) {
+	t.Parallel()
+
+	o := createApproveOptions(t, true, "alice")
+	o.Args = []string{approvalName}
+	o.Comment = "looks good"
+	err := o.Run()
+	require.NoError(t, err)
+
+	approval := getApproval(t, o)
+	assert.Equal(t, v1.ActivityStatusTypeWaitingForApproval, approval.Status.Status)
+	require.Len(t, approval.Status.Decisions, 1)
+	assert.Equal(t, "alice", approval.Status.Decisions[0].User)
+	assert.Equal(t, "looks good", approval.Status.Decisions[0].Comment)
+
+	// approving twice does not count twice
+	err = o.Run()
+	require.NoError(t, err)
+	assert.Equal(t, v1.ActivityStatusTypeWaitingForApproval, getApproval(t, o).Status.Status)
+
+	o.Username = "bob"
+	err = o.Run()
+	require.NoError(t, err)
+	assert.Equal(t, v1.ActivityStatusTypeSucceeded, getApproval(t, o).Status.Status)
+
+	// the promotion has been decided
+	err = o.Run()
+	assert.Error(t, err)
+}
+
+func TestRejectPromotion(t *testing.T) {
+	t.Parallel()
+
+	o := createApproveOptions(t, false, "bob")
+	o.Args = []string{approvalName}
+	err := o.Run()
+	require.NoError(t, err)
+
+	approval := getApproval(t, o)
+	assert.Equal(t, v1.ActivityStatusTypeAborted, approval.Status.Status)
+	assert.False(t, approval.Status.Decisions[0].Approved)
+}
+
+func TestApproveByNonApproverFails(t *testing.T) {
+	t.Parallel()
+
+	o := createApproveOptions(t, true, "mallory")
+	o.Args = []string{approvalName}
+	err := o.Run()
+	require.Error(t, err)
+
+	approval := getApproval(t, o)
+	assert.Empty(t, approval.Status.Decisions)
+}
+
+func TestApproveAsAnotherUserRequiresPermission(t *testing.T) {
+	t.Parallel()
+
+	o := createApproveOptions(t, true, "alice")
+	allowDecidingAsAnotherUser(t, o.CommonOptions, false)
+	o.Args = []string{approvalName}
+	err := o.Run()
+	require.Error(t, err)
+
+	approval := getApproval(t, o)
+	assert.Empty(t, approval.Status.Decisions)
+}
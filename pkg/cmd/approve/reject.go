@@ -0,0 +1,48 @@
+package approve
+
+import (
+	"github.com/jenkins-x/jx/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/pkg/cmd/templates"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rejectLong = templates.LongDesc(`
+		Rejects the promotion of an application to an Environment which requires approval.
+
+		A single rejection from an approver fails the promotion.
+`)
+
+	rejectExample = templates.Examples(`
+		# Pick the pending promotion to reject
+		jx reject
+
+		# Reject a promotion
+		jx reject myapp-1-2-3-production -m "needs a database migration first"
+	`)
+)
+
+// NewCmdReject creates the command to reject promotions
+func NewCmdReject(commonOpts *opts.CommonOptions) *cobra.Command {
+	options := &ApproveOptions{
+		CommonOptions: commonOpts,
+		Approved:      false,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "reject [promotion]",
+		Short:   "Rejects a promotion to an Environment which requires approval",
+		Long:    rejectLong,
+		Example: rejectExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			helper.CheckErr(err)
+		},
+	}
+	options.AddApproveFlags(cmd)
+	cmd.AddCommand(NewCmdRejectPipeline(commonOpts))
+	return cmd
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"github.com/jenkins-x/jx/pkg/cmd/profile"
 
+	"github.com/jenkins-x/jx/pkg/cmd/approve"
 	"github.com/jenkins-x/jx/pkg/cmd/boot"
 	"github.com/jenkins-x/jx/pkg/cmd/cloudbees"
 	"github.com/jenkins-x/jx/pkg/cmd/compliance"
@@ -51,6 +52,7 @@ import (
 	"github.com/jenkins-x/jx/pkg/cmd/add"
 	"github.com/jenkins-x/jx/pkg/cmd/namespace"
 	"github.com/jenkins-x/jx/pkg/cmd/promote"
+	"github.com/jenkins-x/jx/pkg/cmd/rollback"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -125,6 +127,9 @@ func NewJXCommand(f clients.Factory, in terminal.FileReader, out terminal.FileWr
 	environmentsCommands := []*cobra.Command{
 		preview.NewCmdPreview(commonOpts),
 		promote.NewCmdPromote(commonOpts),
+		approve.NewCmdApprove(commonOpts),
+		approve.NewCmdReject(commonOpts),
+		rollback.NewCmdRollback(commonOpts),
 	}
 	environmentsCommands = append(environmentsCommands, findCommands("environment", createCommands, deleteCommands, editCommands, getCommands)...)
 
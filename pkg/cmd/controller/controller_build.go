@@ -23,6 +23,7 @@ import (
 
 	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx/pkg/builds"
+	"github.com/jenkins-x/jx/pkg/chats"
 	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
 	"github.com/jenkins-x/jx/pkg/log"
 	"github.com/jenkins-x/jx/pkg/util"
@@ -291,6 +292,7 @@ func (o *ControllerBuildOptions) onPipelinePod(obj interface{}, kubeClient kuber
 								log.Logger().Warnf("Failed to %s PipelineActivities for build %s: %s", operation, pri.Name, err)
 								return err
 							}
+							oldStatus := a.Spec.Status
 							if o.updatePipelineActivityForRun(kubeClient, ns, a, pri, pod) {
 								log.Logger().Debugf("updating PipelineActivity %s", a.Name)
 								_, err := activities.PatchUpdate(a)
@@ -299,6 +301,9 @@ func (o *ControllerBuildOptions) onPipelinePod(obj interface{}, kubeClient kuber
 									name = a.Name
 									return err
 								}
+								if pri.Type == tekton.BuildPipeline {
+									o.notifyBuildStatus(a, oldStatus)
+								}
 							}
 							return nil
 						})
@@ -314,6 +319,41 @@ func (o *ControllerBuildOptions) onPipelinePod(obj interface{}, kubeClient kuber
 	}
 }
 
+// notifyBuildStatus posts a message to the developer channel of the project, recorded on the PipelineActivity by
+// 'jx step create task', when the build starts or finishes linking to the logs of the build
+func (o *ControllerBuildOptions) notifyBuildStatus(activity *v1.PipelineActivity, oldStatus v1.ActivityStatusType) {
+	message := buildStatusMessage(activity, oldStatus)
+	if message == nil {
+		return
+	}
+	chatConfig := opts.ChatConfigFromAnnotations(activity)
+	if chatConfig == nil {
+		return
+	}
+	_, err := o.NotifyDeveloperChannel(chatConfig, message)
+	if err != nil {
+		log.Logger().Warnf("Failed to notify the developer channel %s of PipelineActivity %s: %s", chatConfig.DeveloperChannel, activity.Name, err)
+	}
+}
+
+// buildStatusMessage returns the message for the build starting or finishing or nil if its status did not change
+// either way
+func buildStatusMessage(activity *v1.PipelineActivity, oldStatus v1.ActivityStatusType) *chats.Message {
+	spec := &activity.Spec
+	buildURL := spec.BuildLogsURL
+	if buildURL == "" {
+		buildURL = spec.BuildURL
+	}
+	owner, repo, branch := activity.RepositoryOwner(), activity.RepositoryName(), activity.BranchName()
+	switch {
+	case spec.Status.IsTerminated() && !oldStatus.IsTerminated():
+		return chats.BuildFinishedMessage(owner, repo, branch, spec.Build, buildURL, spec.Status == v1.ActivityStatusTypeSucceeded)
+	case spec.Status == v1.ActivityStatusTypeRunning && oldStatus != v1.ActivityStatusTypeRunning:
+		return chats.BuildStartedMessage(owner, repo, branch, spec.Build, buildURL)
+	}
+	return nil
+}
+
 // createPromoteStepActivityKey deduces the pipeline metadata from the Knative build pod
 func (o *ControllerBuildOptions) createPromoteStepActivityKey(buildName string, pod *corev1.Pod) *kube.PromoteStepActivityKey {
 
@@ -899,6 +939,15 @@ func updateForStage(si *tekton.StageInfo, a *v1.PipelineActivity) {
 				stage.Status = v1.ActivityStatusTypePending
 			}
 		}
+
+		// the step of an input stage records the decision on the stage
+		if input := stage.Input; input != nil {
+			if input.Status == v1.ActivityStatusTypeWaitingForApproval && !allCompleted {
+				stage.Status = v1.ActivityStatusTypeWaitingForApproval
+			} else if input.Status == v1.ActivityStatusTypeAborted {
+				stage.Status = v1.ActivityStatusTypeAborted
+			}
+		}
 	}
 }
 
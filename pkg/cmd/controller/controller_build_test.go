@@ -131,6 +131,46 @@ func TestUpdateForStage(t *testing.T) {
 	}
 }
 
+func TestUpdateForStageWaitingForApproval(t *testing.T) {
+	si := &tekton.StageInfo{
+		Name:    "Approve",
+		Parents: []string{},
+	}
+
+	act := &v1.PipelineActivity{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "jenkins-x-jx-master-42",
+		},
+		Spec: v1.PipelineActivitySpec{
+			Steps: []v1.PipelineActivityStep{
+				{
+					Kind: v1.ActivityStepKindTypeStage,
+					Stage: &v1.StageActivityStep{
+						CoreActivityStep: v1.CoreActivityStep{
+							Name:   "Approve",
+							Status: v1.ActivityStatusTypeWaitingForApproval,
+						},
+						Input: &v1.StageInputStep{
+							CoreActivityStep: v1.CoreActivityStep{
+								Status: v1.ActivityStatusTypeWaitingForApproval,
+							},
+							Message: "Deploy to production?",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	updateForStage(si, act)
+	stage := act.Spec.Steps[0].Stage
+	assert.Equal(t, v1.ActivityStatusTypeWaitingForApproval, stage.Status)
+
+	stage.Input.AddDecision("alice", false, "not this week")
+	updateForStage(si, act)
+	assert.Equal(t, v1.ActivityStatusTypeAborted, stage.Status)
+}
+
 func TestUpdateForStagePreTekton051(t *testing.T) {
 	pod := tekton_helpers_test.AssertLoadSinglePod(t, path.Join("test_data", "controller_build", "update_stage_info_pre_tekton_0.5.1"))
 	si := &tekton.StageInfo{
@@ -239,3 +279,38 @@ func parseTime(t *testing.T, timeString string) *time.Time {
 	}
 	return nil
 }
+
+func TestBuildStatusMessage(t *testing.T) {
+	t.Parallel()
+
+	activity := &v1.PipelineActivity{
+		Spec: v1.PipelineActivitySpec{
+			Pipeline:     "jstrachan/myapp/master",
+			Build:        "3",
+			BuildURL:     "http://jenkins/job/myapp/3",
+			BuildLogsURL: "http://logs/myapp/3.log",
+			Status:       v1.ActivityStatusTypeRunning,
+		},
+	}
+	message := buildStatusMessage(activity, v1.ActivityStatusTypePending)
+	if assert.NotNil(t, message, "started message") {
+		assert.Equal(t, "Build #3 of jstrachan/myapp/master started", message.Title)
+		assert.Equal(t, "http://logs/myapp/3.log", message.TitleURL)
+	}
+	assert.Nil(t, buildStatusMessage(activity, v1.ActivityStatusTypeRunning), "still running")
+
+	activity.Spec.Status = v1.ActivityStatusTypeFailed
+	message = buildStatusMessage(activity, v1.ActivityStatusTypeRunning)
+	if assert.NotNil(t, message, "finished message") {
+		assert.Equal(t, "Build #3 of jstrachan/myapp/master failed", message.Title)
+	}
+	assert.Nil(t, buildStatusMessage(activity, v1.ActivityStatusTypeFailed), "already finished")
+
+	activity.Spec.Status = v1.ActivityStatusTypeSucceeded
+	activity.Spec.BuildLogsURL = ""
+	message = buildStatusMessage(activity, v1.ActivityStatusTypeRunning)
+	if assert.NotNil(t, message, "finished message without logs") {
+		assert.Equal(t, "Build #3 of jstrachan/myapp/master succeeded", message.Title)
+		assert.Equal(t, "http://jenkins/job/myapp/3", message.TitleURL)
+	}
+}
@@ -3,6 +3,7 @@ package controller
 import (
 	"crypto/hmac"
 	"crypto/sha1"
+	"crypto/subtle"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -543,7 +544,9 @@ func (o *ControllerEnvironmentOptions) registerWebHook(webhookURL string, secret
 
 // ValidateWebhook ensures that the provided request conforms to the
 // format of a Github webhook and the payload can be validated with
-// the provided hmac secret. It returns the event type, the event guid,
+// the provided hmac secret. Git providers which cannot sign their payloads,
+// such as Azure DevOps, instead send the secret as the basic auth password.
+// It returns the event type, the event guid,
 // the payload of the request, whether the webhook is valid or not,
 // and finally the resultant HTTP status code
 func ValidateWebhook(w http.ResponseWriter, r *http.Request, hmacSecret []byte, requireGitHubHeaders bool) (string, string, []byte, bool, int) {
@@ -576,7 +579,8 @@ func ValidateWebhook(w http.ResponseWriter, r *http.Request, hmacSecret []byte,
 		}
 	}
 	sig := r.Header.Get("X-Hub-Signature")
-	if sig == "" {
+	_, password, hasBasicAuth := r.BasicAuth()
+	if sig == "" && !hasBasicAuth {
 		responseHTTPError(w, http.StatusForbidden, "403 Forbidden: Missing X-Hub-Signature")
 		return "", "", nil, false, http.StatusForbidden
 	}
@@ -591,8 +595,13 @@ func ValidateWebhook(w http.ResponseWriter, r *http.Request, hmacSecret []byte,
 		return "", "", nil, false, http.StatusInternalServerError
 	}
 	// Validate the payload with our HMAC secret.
-	if !ValidatePayload(payload, sig, hmacSecret) {
-		responseHTTPError(w, http.StatusForbidden, "403 Forbidden: Invalid X-Hub-Signature")
+	if sig != "" {
+		if !ValidatePayload(payload, sig, hmacSecret) {
+			responseHTTPError(w, http.StatusForbidden, "403 Forbidden: Invalid X-Hub-Signature")
+			return "", "", nil, false, http.StatusForbidden
+		}
+	} else if !ValidateBasicAuthPassword(password, hmacSecret) {
+		responseHTTPError(w, http.StatusForbidden, "403 Forbidden: Invalid basic auth password")
 		return "", "", nil, false, http.StatusForbidden
 	}
 	return eventType, eventGUID, payload, true, http.StatusOK
@@ -614,6 +623,14 @@ func ValidatePayload(payload []byte, sig string, key []byte) bool {
 	return hmac.Equal(sb, expected)
 }
 
+// ValidateBasicAuthPassword ensures that the basic auth password of the request matches the key.
+func ValidateBasicAuthPassword(password string, key []byte) bool {
+	if len(key) == 0 {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(password), key) == 1
+}
+
 // PayloadSignature returns the signature that matches the payload.
 func PayloadSignature(payload []byte, key []byte) string {
 	mac := hmac.New(sha1.New, key)
@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateWebhook(t *testing.T) {
+	t.Parallel()
+
+	secret := []byte("s3cr3t")
+	payload := []byte(`{"ref": "refs/heads/master"}`)
+	newRequest := func() *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(payload))
+		r.Header.Set("content-type", "application/json")
+		return r
+	}
+
+	r := newRequest()
+	r.Header.Set("X-Hub-Signature", PayloadSignature(payload, secret))
+	_, _, data, valid, status := ValidateWebhook(httptest.NewRecorder(), r, secret, false)
+	assert.True(t, valid, "signed request")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, payload, data)
+
+	r = newRequest()
+	r.SetBasicAuth("jenkins-x", string(secret))
+	_, _, _, valid, status = ValidateWebhook(httptest.NewRecorder(), r, secret, false)
+	assert.True(t, valid, "basic auth request")
+	assert.Equal(t, http.StatusOK, status)
+
+	r = newRequest()
+	r.SetBasicAuth("jenkins-x", "wrong")
+	_, _, _, valid, status = ValidateWebhook(httptest.NewRecorder(), r, secret, false)
+	assert.False(t, valid, "basic auth request with the wrong password")
+	assert.Equal(t, http.StatusForbidden, status)
+
+	r = newRequest()
+	r.SetBasicAuth("jenkins-x", "")
+	_, _, _, valid, status = ValidateWebhook(httptest.NewRecorder(), r, nil, false)
+	assert.False(t, valid, "basic auth request without a secret")
+	assert.Equal(t, http.StatusForbidden, status)
+
+	_, _, _, valid, status = ValidateWebhook(httptest.NewRecorder(), newRequest(), secret, false)
+	assert.False(t, valid, "unauthenticated request")
+	assert.Equal(t, http.StatusForbidden, status)
+}
@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/reports"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ControllerMetricsOptions are the flags for the metrics controller
+type ControllerMetricsOptions struct {
+	ControllerOptions
+
+	Namespace   string
+	BindAddress string
+	Port        int
+	Path        string
+
+	metrics *reports.PipelineMetrics
+}
+
+var (
+	controllerMetricsLong = templates.LongDesc(`
+		Watches PipelineActivity resources and exposes Prometheus metrics of the pipelines and promotions of the team.
+
+		The metrics include histograms of the pipeline, stage, step and queue durations along with counters of the
+		completed pipelines and promotions labelled by owner, repository, branch and environment.
+`)
+
+	controllerMetricsExample = templates.Examples(`
+		# run the metrics controller exposing http://localhost:8080/metrics
+		jx controller metrics
+
+		# run the metrics controller on a different port
+		jx controller metrics --port 9090
+	`)
+)
+
+// NewCmdControllerMetrics creates a command object for the metrics controller
+func NewCmdControllerMetrics(commonOpts *opts.CommonOptions) *cobra.Command {
+	options := &ControllerMetricsOptions{
+		ControllerOptions: ControllerOptions{
+			CommonOptions: commonOpts,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "metrics",
+		Short:   "Runs the metrics controller which exposes Prometheus metrics of pipelines and promotions",
+		Long:    controllerMetricsLong,
+		Example: controllerMetricsExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			helper.CheckErr(err)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "The namespace to watch or defaults to the current namespace")
+	cmd.Flags().IntVarP(&options.Port, optionPort, "", 8080, "The TCP port to listen on")
+	cmd.Flags().StringVarP(&options.BindAddress, optionBind, "", "0.0.0.0", "The interface address to bind to")
+	cmd.Flags().StringVarP(&options.Path, "path", "p", "/metrics", "The path the metrics are exposed on")
+	return cmd
+}
+
+// Run implements this command
+func (o *ControllerMetricsOptions) Run() error {
+	// Always run in batch mode as a controller is never run interactively
+	o.BatchMode = true
+
+	err := o.RegisterPipelineActivityCRD()
+	if err != nil {
+		return err
+	}
+	jxClient, devNs, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	if o.Namespace == "" {
+		o.Namespace = devNs
+	}
+	ns := o.Namespace
+	o.metrics = reports.NewPipelineMetrics()
+
+	log.Logger().Infof("Watching for PipelineActivity resources in namespace %s", util.ColorInfo(ns))
+
+	listWatch := cache.NewListWatchFromClient(jxClient.JenkinsV1().RESTClient(), "pipelineactivities", ns, fields.Everything())
+	kube.SortListWatchByName(listWatch)
+	_, controller := cache.NewInformer(
+		listWatch,
+		&v1.PipelineActivity{},
+		time.Minute*10,
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				o.onActivityObj(obj)
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				o.onActivityObj(newObj)
+			},
+			DeleteFunc: func(obj interface{}) {
+				activity, ok := obj.(*v1.PipelineActivity)
+				if ok {
+					o.metrics.Forget(activity)
+				}
+			},
+		},
+	)
+	stop := make(chan struct{})
+	go controller.Run(stop)
+
+	mux := http.NewServeMux()
+	mux.Handle(o.Path, http.HandlerFunc(o.serveMetrics))
+	mux.Handle(healthPath, http.HandlerFunc(o.health))
+	mux.Handle(readyPath, http.HandlerFunc(o.health))
+	address := fmt.Sprintf("%s:%d", o.BindAddress, o.Port)
+	log.Logger().Infof("Exposing metrics at http://%s%s", address, o.Path)
+	return http.ListenAndServe(address, mux)
+}
+
+func (o *ControllerMetricsOptions) onActivityObj(obj interface{}) {
+	activity, ok := obj.(*v1.PipelineActivity)
+	if !ok {
+		log.Logger().Warnf("Object is not a PipelineActivity %#v", obj)
+		return
+	}
+	o.metrics.Observe(activity)
+}
+
+func (o *ControllerMetricsOptions) serveMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	err := o.metrics.Write(w)
+	if err != nil {
+		log.Logger().Warnf("Failed to write the metrics: %s", err)
+	}
+}
+
+func (o *ControllerMetricsOptions) health(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNoContent)
+}
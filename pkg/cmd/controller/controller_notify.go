@@ -0,0 +1,263 @@
+package controller
+
+import (
+	"sync"
+	"time"
+
+	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/notify"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ControllerNotifyOptions are the flags for the notify controller
+type ControllerNotifyOptions struct {
+	ControllerOptions
+
+	Namespace  string
+	ChatURL    string
+	SMTPServer string
+	From       string
+
+	detector  *notify.Detector
+	notifier  *notify.Notifier
+	startTime time.Time
+	releases  map[string]*v1.Release
+	lock      sync.Mutex
+}
+
+var (
+	controllerNotifyLong = templates.LongDesc(`
+		Watches PipelineActivity and Release resources and sends notifications when pipelines fail, recover,
+		promote to an environment or wait for approval.
+
+		Notifications are routed to chat channels, email recipients or webhooks using the notifications rules in the team settings.
+`)
+
+	controllerNotifyExample = templates.Examples(`
+		# run the notify controller sending emails via a local SMTP server
+		jx controller notify --smtp-server localhost:1025 --from jenkins-x@example.com
+
+		# run the notify controller posting chat notifications to Mattermost
+		jx controller notify --chat-url https://mattermost.example.com
+	`)
+)
+
+// NewCmdControllerNotify creates a command object for the notify controller
+func NewCmdControllerNotify(commonOpts *opts.CommonOptions) *cobra.Command {
+	options := &ControllerNotifyOptions{
+		ControllerOptions: ControllerOptions{
+			CommonOptions: commonOpts,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "notify",
+		Short:   "Runs the notify controller which sends pipeline notifications",
+		Long:    controllerNotifyLong,
+		Example: controllerNotifyExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			helper.CheckErr(err)
+		},
+		Aliases: []string{"notifications"},
+	}
+
+	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "The namespace to watch or defaults to the current namespace")
+	cmd.Flags().StringVarP(&options.ChatURL, "chat-url", "", "", "The URL of the chat server used for chat notifications")
+	cmd.Flags().StringVarP(&options.SMTPServer, "smtp-server", "", "localhost:25", "The host:port of the SMTP server used for email notifications")
+	cmd.Flags().StringVarP(&options.From, "from", "", "jenkins-x@localhost", "The sender address of email notifications")
+	return cmd
+}
+
+// Run implements this command
+func (o *ControllerNotifyOptions) Run() error {
+	// Always run in batch mode as a controller is never run interactively
+	o.BatchMode = true
+
+	err := o.RegisterPipelineActivityCRD()
+	if err != nil {
+		return err
+	}
+	err = o.RegisterReleaseCRD()
+	if err != nil {
+		return err
+	}
+
+	jxClient, devNs, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	if o.Namespace == "" {
+		o.Namespace = devNs
+	}
+	ns := o.Namespace
+
+	chatProvider, err := o.CreateChatProvider(&config.ChatConfig{URL: o.ChatURL})
+	if err != nil {
+		return errors.Wrapf(err, "failed to create the chat provider for %s", o.ChatURL)
+	}
+
+	o.detector = notify.NewDetector()
+	o.notifier = notify.NewNotifier(chatProvider, o.SMTPServer, o.From)
+	o.startTime = time.Now()
+	o.releases = map[string]*v1.Release{}
+
+	log.Logger().Infof("Watching for PipelineActivity and Release resources in namespace %s", util.ColorInfo(ns))
+
+	releaseListWatch := cache.NewListWatchFromClient(jxClient.JenkinsV1().RESTClient(), "releases", ns, fields.Everything())
+	kube.SortListWatchByName(releaseListWatch)
+	_, releaseController := cache.NewInformer(
+		releaseListWatch,
+		&v1.Release{},
+		time.Minute*10,
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				o.onReleaseObj(obj)
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				// ignore resyncs so that releases forgotten once their pipeline completed are not added back
+				oldRelease, ok := oldObj.(*v1.Release)
+				newRelease, ok2 := newObj.(*v1.Release)
+				if ok && ok2 && oldRelease.ResourceVersion == newRelease.ResourceVersion {
+					return
+				}
+				o.onReleaseObj(newObj)
+			},
+			DeleteFunc: func(obj interface{}) {
+				release, ok := obj.(*v1.Release)
+				if ok {
+					o.forgetRelease(release.Spec.GitOwner, release.Spec.GitRepository, release.Spec.Version)
+				}
+			},
+		},
+	)
+	stop := make(chan struct{})
+	go releaseController.Run(stop)
+
+	activityListWatch := cache.NewListWatchFromClient(jxClient.JenkinsV1().RESTClient(), "pipelineactivities", ns, fields.Everything())
+	kube.SortListWatchByName(activityListWatch)
+	_, activityController := cache.NewInformer(
+		activityListWatch,
+		&v1.PipelineActivity{},
+		time.Minute*10,
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				o.onActivityObj(nil, obj, jxClient, devNs)
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				o.onActivityObj(oldObj, newObj, jxClient, devNs)
+			},
+			DeleteFunc: func(obj interface{}) {
+				activity, ok := obj.(*v1.PipelineActivity)
+				if ok {
+					o.forgetActivityRelease(activity)
+				}
+			},
+		},
+	)
+	go activityController.Run(stop)
+
+	// Wait forever
+	select {}
+}
+
+func (o *ControllerNotifyOptions) onReleaseObj(obj interface{}) {
+	release, ok := obj.(*v1.Release)
+	if !ok {
+		log.Logger().Warnf("Object is not a Release %#v", obj)
+		return
+	}
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.releases[releaseKey(release.Spec.GitOwner, release.Spec.GitRepository, release.Spec.Version)] = release
+}
+
+func (o *ControllerNotifyOptions) onActivityObj(oldObj interface{}, newObj interface{}, jxClient versioned.Interface, devNs string) {
+	activity, ok := newObj.(*v1.PipelineActivity)
+	if !ok {
+		log.Logger().Warnf("Object is not a PipelineActivity %#v", newObj)
+		return
+	}
+	var oldActivity *v1.PipelineActivity
+	if oldObj != nil {
+		oldActivity, ok = oldObj.(*v1.PipelineActivity)
+		if !ok {
+			log.Logger().Warnf("Object is not a PipelineActivity %#v", oldObj)
+			return
+		}
+	} else {
+		// activities which existed before the controller started have already been notified
+		if activity.CreationTimestamp.Time.Before(o.startTime) {
+			o.detector.Baseline(activity)
+			return
+		}
+		oldActivity = &v1.PipelineActivity{}
+	}
+
+	events := o.detector.Events(oldActivity, activity)
+	if activity.Spec.Status.IsTerminated() {
+		defer o.forgetActivityRelease(activity)
+	}
+	if len(events) == 0 {
+		return
+	}
+	devEnv, err := kube.GetDevEnvironment(jxClient, devNs)
+	if err != nil {
+		log.Logger().Warnf("failed to find the dev environment in namespace %s: %s", devNs, err)
+		return
+	}
+	if devEnv == nil || len(devEnv.Spec.TeamSettings.Notifications) == 0 {
+		log.Logger().Debugf("no notification rules in the team settings so ignoring %d events for %s", len(events), activity.Name)
+		return
+	}
+	rules := devEnv.Spec.TeamSettings.Notifications
+	for _, event := range events {
+		event.Release = o.findRelease(event)
+		log.Logger().Infof("Sending %s notifications for %s", util.ColorInfo(string(event.Kind)), util.ColorInfo(activity.Name))
+		err = o.notifier.Notify(rules, event)
+		if err != nil {
+			log.Logger().Warnf("failed to send %s notifications for %s: %s", event.Kind, activity.Name, err)
+		}
+	}
+}
+
+// findRelease returns the Release of the version of the event if there is one
+func (o *ControllerNotifyOptions) findRelease(event *notify.Event) *v1.Release {
+	version := event.Activity.Spec.Version
+	if version == "" {
+		return nil
+	}
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	return o.releases[releaseKey(event.Owner(), event.Repository(), version)]
+}
+
+// forgetActivityRelease removes the Release of the version built by the activity as no more events need it
+func (o *ControllerNotifyOptions) forgetActivityRelease(activity *v1.PipelineActivity) {
+	if activity.Spec.Version != "" {
+		o.forgetRelease(activity.RepositoryOwner(), activity.RepositoryName(), activity.Spec.Version)
+	}
+}
+
+func (o *ControllerNotifyOptions) forgetRelease(owner string, repository string, version string) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	delete(o.releases, releaseKey(owner, repository, version))
+}
+
+func releaseKey(owner string, repository string, version string) string {
+	return owner + "/" + repository + "/" + version
+}
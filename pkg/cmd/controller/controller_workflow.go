@@ -324,7 +324,7 @@ func (o *ControllerWorkflowOptions) onActivity(pipeline *v1.PipelineActivity, jx
 					if status == nil || status.PullRequest == nil || status.PullRequest.PullRequestURL == "" {
 						allStepsComplete = false
 						// can we generate a PR now?
-						if canExecuteStep(flow, pipeline, &step, promoteStatusMap, envName) {
+						if canExecuteStep(flow, pipeline, &step, promoteStatusMap, envName) && !o.isFrozen(jxClient, ns, pipeline, envName) {
 							log.Logger().Infof("Creating PR for environment %s from PipelineActivity %s as current status is %#v", envName, pipeline.Name, status)
 							po := o.createPromoteOptions(repoName, envName, pipelineName, build, version)
 
@@ -709,6 +709,22 @@ func createPromoteStatus(pipeline *v1.PipelineActivity) map[string]*v1.PromoteAc
 	return answer
 }
 
+// isFrozen returns true if the environment is currently in a freeze window so that the promotion is deferred
+// until a later event after the freeze window has ended. If the freeze windows cannot be checked the environment
+// is treated as frozen so that a failure to look them up cannot promote during a freeze
+func (o *ControllerWorkflowOptions) isFrozen(jxClient versioned.Interface, ns string, activity *v1.PipelineActivity, envName string) bool {
+	window, err := workflow.GetActiveFreezeWindow(jxClient, ns, envName, time.Now())
+	if err != nil {
+		log.Logger().Warnf("Not promoting PipelineActivity %s to Environment %s as its freeze windows could not be checked: %s", activity.Name, envName, err)
+		return true
+	}
+	if window != nil {
+		log.Logger().Infof("Not promoting PipelineActivity %s to Environment %s as it is in the freeze window %s", activity.Name, envName, workflow.FreezeDescription(window))
+		return true
+	}
+	return false
+}
+
 // createPromoteStepActivityKey deduces the pipeline metadata from the Knative workflow pod
 func (o *ControllerWorkflowOptions) createPromoteStepActivityKey(buildName string, pod *corev1.Pod) *kube.PromoteStepActivityKey {
 	branch := ""
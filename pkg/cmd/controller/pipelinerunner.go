@@ -2,6 +2,9 @@ package controller
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -9,6 +12,7 @@ import (
 	"net/http/httputil"
 	"os"
 	"os/signal"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -28,6 +32,7 @@ import (
 
 	jxclient "github.com/jenkins-x/jx/pkg/client/clientset/versioned"
 	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/kube/naming"
 	"github.com/pkg/errors"
 
 	"github.com/jenkins-x/jx/pkg/cmd/opts"
@@ -43,10 +48,22 @@ const (
 	healthPath = "/health"
 	// readyPath URL path for the HTTP endpoint that returns ready status.
 	readyPath = "/ready"
+	// inputPath URL path for the HTTP endpoint that approves or rejects pipelines waiting at an input stage.
+	inputPath = "/input"
+	// wakePath URL path for the HTTP endpoint that wakes hibernated preview environments.
+	wakePath = "/wake"
 
 	// jobLabel is the label name used to identify the Prow job within PipelineRunRequest.Labels
 	jobLabel = "prowJobName"
 
+	// signatureHeader is the header of the HMAC SHA256 signature of the body of the requests which act on behalf of a
+	// user, such as approving an input, in the form sha256=<hex digest>
+	signatureHeader = "X-Hub-Signature-256"
+	// signaturePrefix is the prefix of the hex digest in the signature header
+	signaturePrefix = "sha256="
+	// hmacTokenEnvVar is the environment variable containing the HMAC token if it is not passed as an option
+	hmacTokenEnvVar = "HMAC_TOKEN"
+
 	shutdownTimeout = 5
 )
 
@@ -62,12 +79,39 @@ type PipelineRunnerOptions struct {
 	UseMetaPipeline      bool
 	MetaPipelineImage    string
 	SemanticRelease      bool
+	HMACToken            string
 }
 
 // PipelineRunRequest the request to trigger a pipeline run
 type PipelineRunRequest struct {
 	Labels      map[string]string   `json:"labels,omitempty"`
 	ProwJobSpec prowapi.ProwJobSpec `json:"prowJobSpec,omitempty"`
+	// Parameters the values of the parameters of the pipeline
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// PipelineInputRequest the request to approve or reject a pipeline waiting at an input stage, such as from a ChatOps
+// comment on the Pull Request of the pipeline
+type PipelineInputRequest struct {
+	Owner      string `json:"owner"`
+	Repository string `json:"repository"`
+	Branch     string `json:"branch"`
+	// Build defaults to the latest build of the pipeline waiting for approval
+	Build string `json:"build,omitempty"`
+	// Stage is required if the pipeline has more than one input stage waiting for approval
+	Stage    string `json:"stage,omitempty"`
+	User     string `json:"user"`
+	Approved bool   `json:"approved"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+// PreviewWakeRequest the request to wake the hibernated preview environment of a Pull Request. The body of the request
+// must be signed with the HMAC token
+type PreviewWakeRequest struct {
+	Owner       string `json:"owner"`
+	Repository  string `json:"repository"`
+	PullRequest string `json:"pullRequest"`
+	User        string `json:"user"`
 }
 
 // PipelineRunResponse the results of triggering a pipeline run
@@ -119,6 +163,7 @@ func NewCmdControllerPipelineRunner(commonOpts *opts.CommonOptions) *cobra.Comma
 	cmd.Flags().StringVarP(&options.ServiceAccount, "service-account", "", "tekton-bot", "The Kubernetes ServiceAccount to use to run the pipeline.")
 	cmd.Flags().BoolVarP(&options.NoGitCredentialsInit, "no-git-init", "", false, "Disables checking we have setup git credentials on startup.")
 	cmd.Flags().BoolVarP(&options.SemanticRelease, "semantic-release", "", false, "Enable semantic releases")
+	cmd.Flags().StringVarP(&options.HMACToken, "hmac-token", "", "", "The token used to verify the HMAC signature of the requests to approve or reject inputs and to wake preview environments. Defaults to the $"+hmacTokenEnvVar+" environment variable")
 
 	// TODO - temporary flags until meta pipeline is the default
 	cmd.Flags().BoolVarP(&options.UseMetaPipeline, "use-meta-pipeline", "", false, "Uses the meta pipeline to create the pipeline.")
@@ -128,6 +173,12 @@ func NewCmdControllerPipelineRunner(commonOpts *opts.CommonOptions) *cobra.Comma
 
 // Run will implement this command
 func (o *PipelineRunnerOptions) Run() error {
+	if o.HMACToken == "" {
+		o.HMACToken = os.Getenv(hmacTokenEnvVar)
+	}
+	if o.HMACToken == "" {
+		logger.Warnf("no HMAC token so requests to %s and %s will be rejected", inputPath, wakePath)
+	}
 	if !o.NoGitCredentialsInit {
 		err := o.InitGitConfigAndUser()
 		if err != nil {
@@ -152,6 +203,8 @@ func (o *PipelineRunnerOptions) startWorkers(ctx context.Context, wg *sync.WaitG
 		mux.Handle(o.Path, http.HandlerFunc(o.pipeline))
 		mux.Handle(healthPath, http.HandlerFunc(o.health))
 		mux.Handle(readyPath, http.HandlerFunc(o.ready))
+		mux.Handle(inputPath, http.HandlerFunc(o.input))
+		mux.Handle(wakePath, http.HandlerFunc(o.wake))
 		srv := &http.Server{
 			Addr:    fmt.Sprintf("%s:%d", o.BindAddress, o.Port),
 			Handler: mux,
@@ -214,6 +267,148 @@ func (o *PipelineRunnerOptions) pipeline(w http.ResponseWriter, r *http.Request)
 	}
 }
 
+// input handles requests to approve or reject pipelines waiting at an input stage. As the request names the user who
+// decides, its body must be signed with the HMAC token so that only trusted callers, such as a ChatOps plugin, can act
+// on behalf of users
+func (o *PipelineRunnerOptions) input(w http.ResponseWriter, r *http.Request) {
+	request := PipelineInputRequest{}
+	if !o.readSignedRequest(w, r, inputPath, &request) {
+		return
+	}
+	err := o.decideInput(request)
+	if err != nil {
+		o.returnStatusBadRequest(err, "could not decide the input: "+err.Error(), w)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// readSignedRequest reads the JSON body of the POST request into the value once its signature has been verified.
+// Returns false if the request was rejected, in which case the error response has been written
+func (o *PipelineRunnerOptions) readSignedRequest(w http.ResponseWriter, r *http.Request, path string, value interface{}) bool {
+	if r.Method != http.MethodPost {
+		logger.Errorf("unsupported method %s for %s", r.Method, path)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return false
+	}
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		o.returnStatusBadRequest(err, "could not read the JSON request body: "+err.Error(), w)
+		return false
+	}
+	err = o.verifySignature(r, data)
+	if err != nil {
+		logger.Warnf("rejecting the request to %s: %s", path, err)
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return false
+	}
+	err = json.Unmarshal(data, value)
+	if err != nil {
+		o.returnStatusBadRequest(err, "could not read the JSON request body: "+err.Error(), w)
+		return false
+	}
+	return true
+}
+
+// verifySignature returns an error unless the signature header of the request is the HMAC SHA256 of the body using
+// the HMAC token
+func (o *PipelineRunnerOptions) verifySignature(r *http.Request, body []byte) error {
+	if o.HMACToken == "" {
+		return errors.New("no HMAC token is configured to verify the request")
+	}
+	signature := r.Header.Get(signatureHeader)
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return errors.Errorf("missing the %s header", signatureHeader)
+	}
+	digest, err := hex.DecodeString(strings.TrimPrefix(signature, signaturePrefix))
+	if err != nil {
+		return errors.Wrapf(err, "invalid %s header", signatureHeader)
+	}
+	mac := hmac.New(sha256.New, []byte(o.HMACToken))
+	mac.Write(body)
+	if !hmac.Equal(digest, mac.Sum(nil)) {
+		return errors.Errorf("the %s header does not match the body", signatureHeader)
+	}
+	return nil
+}
+
+// decideInput records the decision of the user on the input stage of the pipeline
+func (o *PipelineRunnerOptions) decideInput(request PipelineInputRequest) error {
+	if request.Owner == "" || request.Repository == "" || request.Branch == "" {
+		return errors.New("the owner, repository and branch of the pipeline are required")
+	}
+	if request.User == "" {
+		return errors.New("the user is required")
+	}
+	jxClient, ns, err := o.getClientsAndNamespace()
+	if err != nil {
+		return err
+	}
+	pipeline := fmt.Sprintf("%s/%s/%s", request.Owner, request.Repository, request.Branch)
+	name := ""
+	if request.Build != "" {
+		name = naming.ToValidName(fmt.Sprintf("%s-%s-%s-%s", request.Owner, request.Repository, request.Branch, request.Build))
+	} else {
+		name, err = kube.GetLatestPipelineActivityWaitingForInput(jxClient, ns, pipeline)
+		if err != nil {
+			return err
+		}
+	}
+	stage, err := kube.DecideStageInput(jxClient, ns, name, request.Stage, request.User, request.Approved, request.Comment)
+	if err != nil {
+		return err
+	}
+	logger.Infof("user %s decided stage %s of %s is %s", request.User, stage.Name, name, string(stage.Input.Status))
+	return nil
+}
+
+// wake handles requests to wake the hibernated preview environment of a Pull Request. Its body must be signed with
+// the HMAC token like the requests to decide inputs so that only trusted callers can wake preview environments
+func (o *PipelineRunnerOptions) wake(w http.ResponseWriter, r *http.Request) {
+	request := PreviewWakeRequest{}
+	if !o.readSignedRequest(w, r, wakePath, &request) {
+		return
+	}
+	err := o.wakePreview(request)
+	if err != nil {
+		o.returnStatusBadRequest(err, "could not wake the preview environment: "+err.Error(), w)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// wakePreview wakes the preview environment of the Pull Request if it is hibernated
+func (o *PipelineRunnerOptions) wakePreview(request PreviewWakeRequest) error {
+	if request.Owner == "" || request.Repository == "" || request.PullRequest == "" {
+		return errors.New("the owner, repository and pull request of the preview environment are required")
+	}
+	jxClient, ns, err := o.getClientsAndNamespace()
+	if err != nil {
+		return err
+	}
+	kubeClient, err := o.KubeClient()
+	if err != nil {
+		return errors.Wrap(err, "unable to create Kube client")
+	}
+	env, err := kube.FindPreviewForPullRequest(jxClient, ns, request.Owner, request.Repository, request.PullRequest)
+	if err != nil {
+		return err
+	}
+	if env == nil {
+		return fmt.Errorf("no preview environment found for pull request %s of %s/%s", request.PullRequest, request.Owner, request.Repository)
+	}
+	if !kube.IsPreviewHibernated(env) {
+		logger.Infof("preview environment %s is not hibernated", env.Name)
+		return nil
+	}
+	_, err = kube.WakePreview(kubeClient, jxClient, ns, env)
+	if err != nil {
+		return err
+	}
+	logger.Infof("user %s woke preview environment %s", request.User, env.Name)
+	return nil
+}
+
 func (o *PipelineRunnerOptions) handlePostRequest(r *http.Request, w http.ResponseWriter) {
 	requestParams, err := o.parseStartPipelineRequestParameters(r)
 	if err != nil {
@@ -354,6 +549,7 @@ func (o *PipelineRunnerOptions) buildStepCreateTaskOption(prowJobSpec prowapi.Pr
 	for key, value := range envs {
 		createTaskOption.CustomEnvs = append(createTaskOption.CustomEnvs, fmt.Sprintf("%s=%s", key, value))
 	}
+	createTaskOption.Parameters = parameterValues(pipelineRun.Parameters)
 
 	return createTaskOption
 }
@@ -386,10 +582,22 @@ func (o *PipelineRunnerOptions) buildStepCreatePipelineOption(pipelineRun Pipeli
 	for key, value := range envs {
 		createPipelineOption.CustomEnvs = append(createPipelineOption.CustomEnvs, fmt.Sprintf("%s=%s", key, value))
 	}
+	createPipelineOption.Parameters = parameterValues(pipelineRun.Parameters)
 
 	return createPipelineOption, nil
 }
 
+// parameterValues turns the map of parameters into a sorted string array with = separator to match the type of the
+// parameter values which are CLI flags
+func parameterValues(parameters map[string]string) []string {
+	var answer []string
+	for key, value := range parameters {
+		answer = append(answer, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(answer)
+	return answer
+}
+
 func (o *PipelineRunnerOptions) marshalPayload(payload interface{}) ([]byte, error) {
 	data, err := json.Marshal(payload)
 	if err != nil {
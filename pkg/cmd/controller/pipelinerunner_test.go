@@ -5,16 +5,27 @@ package controller
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"github.com/jenkins-x/jx/pkg/cmd/opts"
 	"github.com/jenkins-x/jx/pkg/log"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"sync"
 	"testing"
 
+	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	v1fake "github.com/jenkins-x/jx/pkg/client/clientset/versioned/fake"
+	clientsfake "github.com/jenkins-x/jx/pkg/cmd/clients/fake"
+	"github.com/jenkins-x/jx/pkg/kube"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
 )
 
 const (
@@ -73,6 +84,13 @@ func TestPipelineRunner(t *testing.T) {
 	RunSpecs(t, "Pipeline Runner Test Suite")
 }
 
+// sign returns the signature header of the body using the HMAC token
+func sign(token string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(token))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
 var _ = Describe("Pipeline Runner", func() {
 	Describe("when running", func() {
 		var (
@@ -163,4 +181,212 @@ var _ = Describe("Pipeline Runner", func() {
 			Expect(string(htmlData)).Should(ContainSubstring("unable to find prow job name in pipeline request"))
 		})
 	})
+
+	Describe("when deciding an input", func() {
+		const token = "secret-token"
+		var (
+			pipelineRunner PipelineRunnerOptions
+			jxClient       *v1fake.Clientset
+			body           []byte
+		)
+
+		post := func(signature string) *httptest.ResponseRecorder {
+			r := httptest.NewRequest(http.MethodPost, inputPath, bytes.NewReader(body))
+			if signature != "" {
+				r.Header.Set(signatureHeader, signature)
+			}
+			w := httptest.NewRecorder()
+			pipelineRunner.input(w, r)
+			return w
+		}
+
+		BeforeEach(func() {
+			log.SetOutput(ioutil.Discard)
+			activity := &v1.PipelineActivity{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "myorg-myapp-master-1",
+					Namespace: "jx",
+				},
+				Spec: v1.PipelineActivitySpec{
+					Pipeline: "myorg/myapp/master",
+					Build:    "1",
+					Status:   v1.ActivityStatusTypeRunning,
+					Steps: []v1.PipelineActivityStep{
+						{
+							Kind: v1.ActivityStepKindTypeStage,
+							Stage: &v1.StageActivityStep{
+								CoreActivityStep: v1.CoreActivityStep{
+									Name: "Approve",
+								},
+								Input: &v1.StageInputStep{
+									CoreActivityStep: v1.CoreActivityStep{
+										Name:   "Approve",
+										Status: v1.ActivityStatusTypeWaitingForApproval,
+									},
+									Approvers: []string{"jstrachan"},
+								},
+							},
+						},
+					},
+				},
+			}
+			jxClient = v1fake.NewSimpleClientset(activity)
+			commonOpts := opts.NewCommonOptionsWithFactory(clientsfake.NewFakeFactoryFromClients(nil, jxClient, kubefake.NewSimpleClientset()))
+			commonOpts.SetDevNamespace("jx")
+			pipelineRunner = PipelineRunnerOptions{
+				CommonOptions: &commonOpts,
+				HMACToken:     token,
+			}
+			body = []byte(`{"owner": "myorg", "repository": "myapp", "branch": "master", "user": "jstrachan", "approved": true}`)
+		})
+
+		decision := func() *v1.ApprovalDecision {
+			activity, err := jxClient.JenkinsV1().PipelineActivities("jx").Get("myorg-myapp-master-1", metav1.GetOptions{})
+			Expect(err).Should(BeNil())
+			return activity.Spec.Steps[0].Stage.Input.Decision
+		}
+
+		It("records the decision of a request signed with the HMAC token", func() {
+			w := post(sign(token, body))
+			Expect(w.Code).Should(Equal(http.StatusNoContent))
+			Expect(decision()).ShouldNot(BeNil())
+			Expect(decision().User).Should(Equal("jstrachan"))
+			Expect(decision().Approved).Should(BeTrue())
+		})
+
+		It("returns HTTP 401 for an unsigned request", func() {
+			w := post("")
+			Expect(w.Code).Should(Equal(http.StatusUnauthorized))
+			Expect(decision()).Should(BeNil())
+		})
+
+		It("returns HTTP 401 for a request signed with another token", func() {
+			w := post(sign("another-token", body))
+			Expect(w.Code).Should(Equal(http.StatusUnauthorized))
+			Expect(decision()).Should(BeNil())
+		})
+
+		It("returns HTTP 401 when no HMAC token is configured", func() {
+			pipelineRunner.HMACToken = ""
+			w := post(sign("", body))
+			Expect(w.Code).Should(Equal(http.StatusUnauthorized))
+			Expect(decision()).Should(BeNil())
+		})
+
+		It("returns HTTP 400 if the user is not an approver", func() {
+			body = []byte(`{"owner": "myorg", "repository": "myapp", "branch": "master", "user": "someone-else", "approved": true}`)
+			w := post(sign(token, body))
+			Expect(w.Code).Should(Equal(http.StatusBadRequest))
+			Expect(w.Body.String()).Should(ContainSubstring("is not an approver"))
+			Expect(decision()).Should(BeNil())
+		})
+
+		It("returns HTTP 405 for a GET request", func() {
+			w := httptest.NewRecorder()
+			pipelineRunner.input(w, httptest.NewRequest(http.MethodGet, inputPath, nil))
+			Expect(w.Code).Should(Equal(http.StatusMethodNotAllowed))
+		})
+	})
+
+	Describe("when waking a preview environment", func() {
+		const token = "secret-token"
+		var (
+			pipelineRunner PipelineRunnerOptions
+			kubeClient     *kubefake.Clientset
+			jxClient       *v1fake.Clientset
+			body           []byte
+		)
+
+		post := func(signature string) *httptest.ResponseRecorder {
+			r := httptest.NewRequest(http.MethodPost, wakePath, bytes.NewReader(body))
+			if signature != "" {
+				r.Header.Set(signatureHeader, signature)
+			}
+			w := httptest.NewRecorder()
+			pipelineRunner.wake(w, r)
+			return w
+		}
+
+		BeforeEach(func() {
+			log.SetOutput(ioutil.Discard)
+			env := kube.NewPreviewEnvironment("myorg-myapp-pr-23")
+			env.Spec.Source.URL = "https://github.com/myorg/myapp.git"
+			env.Spec.PreviewGitSpec.Name = "23"
+			hibernated := metav1.Now()
+			env.Status.Hibernated = &hibernated
+			env.Status.HibernatedReplicas = map[string]int32{"myapp": 2}
+			zero := int32(0)
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "myapp", Namespace: env.Spec.Namespace},
+				Spec:       appsv1.DeploymentSpec{Replicas: &zero},
+			}
+			kubeClient = kubefake.NewSimpleClientset(deployment)
+			jxClient = v1fake.NewSimpleClientset(env)
+			commonOpts := opts.NewCommonOptionsWithFactory(clientsfake.NewFakeFactoryFromClients(nil, jxClient, kubeClient))
+			commonOpts.SetDevNamespace("jx")
+			pipelineRunner = PipelineRunnerOptions{
+				CommonOptions: &commonOpts,
+				HMACToken:     token,
+			}
+			body = []byte(`{"owner": "myorg", "repository": "myapp", "pullRequest": "23", "user": "jstrachan"}`)
+		})
+
+		replicas := func() int32 {
+			d, err := kubeClient.AppsV1().Deployments("jx-preview-myorg-myapp-pr-23").Get("myapp", metav1.GetOptions{})
+			Expect(err).Should(BeNil())
+			return *d.Spec.Replicas
+		}
+
+		It("wakes the preview environment for a request signed with the HMAC token", func() {
+			w := post(sign(token, body))
+			Expect(w.Code).Should(Equal(http.StatusNoContent))
+			Expect(replicas()).Should(Equal(int32(2)))
+			env, err := jxClient.JenkinsV1().Environments("jx").Get("myorg-myapp-pr-23", metav1.GetOptions{})
+			Expect(err).Should(BeNil())
+			Expect(kube.IsPreviewHibernated(env)).Should(BeFalse())
+		})
+
+		It("returns HTTP 401 for an unsigned request", func() {
+			w := post("")
+			Expect(w.Code).Should(Equal(http.StatusUnauthorized))
+			Expect(replicas()).Should(Equal(int32(0)))
+		})
+
+		It("returns HTTP 401 for a request signed with another token", func() {
+			w := post(sign("another-token", body))
+			Expect(w.Code).Should(Equal(http.StatusUnauthorized))
+			Expect(replicas()).Should(Equal(int32(0)))
+		})
+
+		It("returns HTTP 401 when no HMAC token is configured", func() {
+			pipelineRunner.HMACToken = ""
+			w := post(sign("", body))
+			Expect(w.Code).Should(Equal(http.StatusUnauthorized))
+			Expect(replicas()).Should(Equal(int32(0)))
+		})
+
+		It("returns HTTP 405 for a GET request", func() {
+			w := httptest.NewRecorder()
+			pipelineRunner.wake(w, httptest.NewRequest(http.MethodGet, wakePath, nil))
+			Expect(w.Code).Should(Equal(http.StatusMethodNotAllowed))
+		})
+	})
+
+	Describe("when building the options to create the pipeline", func() {
+		It("passes the parameters of the request", func() {
+			pipelineRunner := PipelineRunnerOptions{
+				CommonOptions: &opts.CommonOptions{},
+			}
+			pipelineRun := PipelineRunRequest{
+				Labels:     map[string]string{jobLabel: "cdf89f04-98ec-11e9-a846-4ad95a1bb3ab"},
+				Parameters: map[string]string{"TARGET": "production", "DRY_RUN": "false"},
+			}
+			createPipelineOption, err := pipelineRunner.buildStepCreatePipelineOption(pipelineRun, "", "https://github.com/jenkins-x-quickstarts/golang-http", "", "master", nil)
+			Expect(err).Should(BeNil())
+			Expect(createPipelineOption.Parameters).Should(Equal([]string{"DRY_RUN=false", "TARGET=production"}))
+
+			createTaskOption := pipelineRunner.buildStepCreateTaskOption(pipelineRun.ProwJobSpec, "", "https://github.com/jenkins-x-quickstarts/golang-http", "", "master", pipelineRun, nil)
+			Expect(createTaskOption.Parameters).Should(Equal([]string{"DRY_RUN=false", "TARGET=production"}))
+		})
+	})
 })
@@ -73,6 +73,7 @@ func NewCmdCreate(commonOpts *opts.CommonOptions) *cobra.Command {
 	cmd.AddCommand(NewCmdCreateDomain(commonOpts))
 	cmd.AddCommand(NewCmdCreateEnv(commonOpts))
 	cmd.AddCommand(NewCmdCreateEtcHosts(commonOpts))
+	cmd.AddCommand(NewCmdCreateFreeze(commonOpts))
 	cmd.AddCommand(NewCmdCreateGkeServiceAccount(commonOpts))
 	cmd.AddCommand(NewCmdCreateGit(commonOpts))
 	cmd.AddCommand(NewCmdCreateIssue(commonOpts))
@@ -0,0 +1,162 @@
+package create
+
+import (
+	"time"
+
+	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/jenkins-x/jx/pkg/workflow"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	createFreezeLong = templates.LongDesc(`
+		Creates a freeze window on an Environment during which promotions to the Environment are blocked.
+
+		A freeze window is either recurring, using a cron like schedule of the form 'minute hour day-of-month month day-of-week',
+		or ad hoc starting now and lasting for a duration, until a time or until it is deleted via 'jx delete freeze'.
+
+		Promotions can still be forced during a freeze via 'jx promote --override-freeze --override-reason'.
+`)
+
+	createFreezeExample = templates.Examples(`
+		# Freeze production at weekends
+		jx create freeze --env production --name weekends --schedule "* * * * 0,6" --reason "no weekend releases"
+
+		# Freeze production from 6pm every Friday for 62 hours in London
+		jx create freeze -e production -n friday --schedule "0 18 * * 5" --duration 62h --time-zone Europe/London
+
+		# Freeze production now for the next 4 hours during an incident
+		jx create freeze -e production -n incident-123 --for 4h --reason "investigating incident 123"
+
+		# Freeze staging now until it is deleted via 'jx delete freeze'
+		jx create freeze -e staging -n code-freeze
+	`)
+)
+
+// CreateFreezeOptions the options for the create freeze command
+type CreateFreezeOptions struct {
+	CreateOptions
+
+	Environment string
+	Name        string
+	Reason      string
+	Schedule    string
+	Duration    string
+	TimeZone    string
+	For         string
+	Until       string
+}
+
+// NewCmdCreateFreeze creates a command object for the "create freeze" command
+func NewCmdCreateFreeze(commonOpts *opts.CommonOptions) *cobra.Command {
+	options := &CreateFreezeOptions{
+		CreateOptions: CreateOptions{
+			CommonOptions: commonOpts,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "freeze",
+		Short:   "Creates a freeze window which blocks promotions to an Environment",
+		Aliases: []string{"freeze-window"},
+		Long:    createFreezeLong,
+		Example: createFreezeExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			helper.CheckErr(err)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.Environment, "env", "e", "", "The Environment to freeze")
+	cmd.Flags().StringVarP(&options.Name, opts.OptionName, "n", "", "The name of the freeze window")
+	cmd.Flags().StringVarP(&options.Reason, "reason", "r", "", "The reason for the freeze which is shown when a promotion is blocked")
+	cmd.Flags().StringVarP(&options.Schedule, "schedule", "s", "", "The cron schedule of a recurring freeze such as '* * * * 0,6' or '0 18 * * 5'")
+	cmd.Flags().StringVarP(&options.Duration, "duration", "d", "", "The duration of each scheduled freeze such as '62h'. If not specified every minute matching the schedule is frozen")
+	cmd.Flags().StringVarP(&options.TimeZone, "time-zone", "", "", "The time zone the schedule is evaluated in such as 'Europe/London'. Defaults to UTC")
+	cmd.Flags().StringVarP(&options.For, "for", "", "", "How long an ad hoc freeze starting now lasts such as '4h'")
+	cmd.Flags().StringVarP(&options.Until, "until", "", "", "The time an ad hoc freeze starting now ends in RFC3339 format such as '2019-12-02T09:00:00Z'")
+	return cmd
+}
+
+// Run implements the command
+func (o *CreateFreezeOptions) Run() error {
+	if o.Environment == "" {
+		return util.MissingOption("env")
+	}
+	if o.Name == "" {
+		return util.MissingOption(opts.OptionName)
+	}
+	window, err := o.createFreezeWindow(time.Now())
+	if err != nil {
+		return err
+	}
+	err = workflow.ValidateFreezeWindow(window)
+	if err != nil {
+		return err
+	}
+
+	callback := func(env *v1.Environment) error {
+		for i, w := range env.Spec.FreezeWindows {
+			if w.Name == window.Name {
+				env.Spec.FreezeWindows[i] = *window
+				log.Logger().Infof("Updated freeze window %s on Environment %s", util.ColorInfo(window.Name), util.ColorInfo(env.Name))
+				return nil
+			}
+		}
+		env.Spec.FreezeWindows = append(env.Spec.FreezeWindows, *window)
+		log.Logger().Infof("Created freeze window %s on Environment %s", util.ColorInfo(window.Name), util.ColorInfo(env.Name))
+		return nil
+	}
+	return o.ModifyEnvironment(o.Environment, callback)
+}
+
+func (o *CreateFreezeOptions) createFreezeWindow(now time.Time) (*v1.FreezeWindow, error) {
+	user, err := o.GetUsername("")
+	if err != nil {
+		return nil, err
+	}
+	window := &v1.FreezeWindow{
+		Name:      o.Name,
+		Reason:    o.Reason,
+		Schedule:  o.Schedule,
+		Duration:  o.Duration,
+		TimeZone:  o.TimeZone,
+		CreatedBy: user,
+	}
+	if o.Schedule != "" {
+		if o.For != "" || o.Until != "" {
+			return nil, errors.Errorf("cannot use --for or --until with a --schedule")
+		}
+		return window, nil
+	}
+	if o.Duration != "" {
+		return nil, errors.Errorf("--duration requires a --schedule, use --for for an ad hoc freeze")
+	}
+	window.Start = &metav1.Time{Time: now}
+	if o.For != "" {
+		if o.Until != "" {
+			return nil, errors.Errorf("cannot use both --for and --until")
+		}
+		d, err := time.ParseDuration(o.For)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid --for duration %s", o.For)
+		}
+		window.End = &metav1.Time{Time: now.Add(d)}
+	} else if o.Until != "" {
+		end, err := time.Parse(time.RFC3339, o.Until)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid --until time %s", o.Until)
+		}
+		window.End = &metav1.Time{Time: end}
+	}
+	return window, nil
+}
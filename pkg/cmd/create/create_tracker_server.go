@@ -20,6 +20,12 @@ var (
 	createTrackerServer_example = templates.Examples(`
 		# Add a new issue tracker server URL
 		jx create tracker server jira myURL
+
+		# Add a Linear workspace as the issue tracker
+		jx create tracker server linear https://linear.app/myworkspace
+
+		# Add a YouTrack server as the issue tracker
+		jx create tracker server youtrack https://mycompany.youtrack.cloud
 	`)
 
 	trackerKindToServiceName = map[string]string{
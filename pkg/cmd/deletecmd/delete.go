@@ -55,6 +55,7 @@ func NewCmdDelete(commonOpts *opts.CommonOptions) *cobra.Command {
 	cmd.AddCommand(NewCmdDeleteDevPod(commonOpts))
 	cmd.AddCommand(newCmdDeleteEks(commonOpts))
 	cmd.AddCommand(NewCmdDeleteEnv(commonOpts))
+	cmd.AddCommand(NewCmdDeleteFreeze(commonOpts))
 	cmd.AddCommand(NewCmdDeleteGit(commonOpts))
 	cmd.AddCommand(NewCmdDeleteGke(commonOpts))
 	cmd.AddCommand(NewCmdDeleteJenkins(commonOpts))
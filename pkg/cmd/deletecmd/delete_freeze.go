@@ -0,0 +1,87 @@
+package deletecmd
+
+import (
+	"fmt"
+
+	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	deleteFreezeLong = templates.LongDesc(`
+		Deletes one or more freeze windows from an Environment so that promotions to it are no longer blocked by them
+`)
+
+	deleteFreezeExample = templates.Examples(`
+		# Delete the freeze window created for an incident
+		jx delete freeze incident-123 --env production
+	`)
+)
+
+// DeleteFreezeOptions the options for the delete freeze command
+type DeleteFreezeOptions struct {
+	DeleteOptions
+
+	Environment string
+}
+
+// NewCmdDeleteFreeze creates a command object for the "delete freeze" command
+func NewCmdDeleteFreeze(commonOpts *opts.CommonOptions) *cobra.Command {
+	options := &DeleteFreezeOptions{
+		DeleteOptions: DeleteOptions{
+			CommonOptions: commonOpts,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "freeze <name>",
+		Short:   "Deletes one or more freeze windows from an Environment",
+		Aliases: []string{"freezes"},
+		Long:    deleteFreezeLong,
+		Example: deleteFreezeExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			helper.CheckErr(err)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.Environment, "env", "e", "", "The Environment to remove the freeze windows from")
+	return cmd
+}
+
+// Run implements the command
+func (o *DeleteFreezeOptions) Run() error {
+	if o.Environment == "" {
+		return util.MissingOption("env")
+	}
+	if len(o.Args) == 0 {
+		return fmt.Errorf("missing freeze window name argument")
+	}
+
+	callback := func(env *v1.Environment) error {
+		for _, name := range o.Args {
+			idx := -1
+			for i, w := range env.Spec.FreezeWindows {
+				if w.Name == name {
+					idx = i
+					break
+				}
+			}
+			if idx < 0 {
+				log.Logger().Warnf("Environment %s has no freeze window %s", env.Name, name)
+				continue
+			}
+			env.Spec.FreezeWindows = append(env.Spec.FreezeWindows[0:idx], env.Spec.FreezeWindows[idx+1:]...)
+			log.Logger().Infof("Deleted freeze window %s from Environment %s", util.ColorInfo(name), util.ColorInfo(env.Name))
+		}
+		return nil
+	}
+	return o.ModifyEnvironment(o.Environment, callback)
+}
@@ -135,5 +135,18 @@ func (o *DeletePreviewOptions) DeletePreview(name string) error {
 		DeleteNamespace: true,
 	}
 	deleteOptions.Args = []string{name}
-	return deleteOptions.Run()
+	err = deleteOptions.Run()
+	if err != nil {
+		return err
+	}
+
+	kubeClient, err := o.KubeClient()
+	if err != nil {
+		return err
+	}
+	err = kube.DeletePreviewDependencies(kubeClient, name)
+	if err != nil {
+		log.Logger().Warnf("Failed to delete the dependencies of preview environment %s: %s", name, err)
+	}
+	return nil
 }
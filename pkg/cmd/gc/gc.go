@@ -20,6 +20,7 @@ const (
 	valid_gc_resources = `Valid resource types include:
 
     * activities
+	* caches
 	* helm
 	* previews
 	* releases
@@ -36,6 +37,7 @@ var (
 
 	gc_example = templates.Examples(`
 		jx gc activities
+		jx gc caches
 		jx gc gke
 		jx gc helm
 		jx gc previews
@@ -65,6 +67,7 @@ func NewCmdGC(commonOpts *opts.CommonOptions) *cobra.Command {
 	}
 
 	cmd.AddCommand(NewCmdGCActivities(commonOpts))
+	cmd.AddCommand(NewCmdGCCaches(commonOpts))
 	cmd.AddCommand(NewCmdGCPreviews(commonOpts))
 	cmd.AddCommand(NewCmdGCGKE(commonOpts))
 	cmd.AddCommand(NewCmdGCHelm(commonOpts))
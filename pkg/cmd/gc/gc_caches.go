@@ -0,0 +1,109 @@
+package gc
+
+import (
+	"context"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/tekton/cache"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// GCCachesOptions contains the CLI options
+type GCCachesOptions struct {
+	*opts.CommonOptions
+
+	BucketURL string
+	Age       time.Duration
+	DryRun    bool
+}
+
+var (
+	GCCachesLong = templates.LongDesc(`
+		Garbage collect the dependency caches of pipelines which have not been saved or updated recently.
+
+		Caches are stored in the team's storage location for 'cache' or, for caches backed by a PersistentVolumeClaim, in the directory the volume is mounted at.
+`)
+
+	GCCachesExample = templates.Examples(`
+		# garbage collect caches older than the default age
+		jx gc caches
+
+		# show the caches older than a week which would be garbage collected
+		jx gc caches --age 168h --dry-run
+
+		# garbage collect the caches on a volume mounted at /jx-cache
+		jx gc caches --bucket-url file:///jx-cache
+`)
+)
+
+// NewCmdGCCaches creates the command object
+func NewCmdGCCaches(commonOpts *opts.CommonOptions) *cobra.Command {
+	options := &GCCachesOptions{
+		CommonOptions: commonOpts,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "caches",
+		Short:   "garbage collection for pipeline dependency caches",
+		Aliases: []string{"cache"},
+		Long:    GCCachesLong,
+		Example: GCCachesExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			helper.CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&options.BucketURL, "bucket-url", "", "", "The URL of the bucket storing the caches. Defaults to the team's storage location for '"+cache.Classifier+"'")
+	cmd.Flags().DurationVarP(&options.Age, "age", "a", 30*24*time.Hour, "The minimum age of caches to garbage collect. Any newer caches will be kept")
+	cmd.Flags().BoolVarP(&options.DryRun, "dry-run", "", false, "Only lists the caches which would be garbage collected")
+	return cmd
+}
+
+// Run implements this command
+func (o *GCCachesOptions) Run() error {
+	bucketURL := o.BucketURL
+	if bucketURL == "" {
+		settings, err := o.TeamSettings()
+		if err != nil {
+			return err
+		}
+		bucketURL = settings.StorageLocationOrDefault(cache.Classifier).BucketURL
+	}
+	if bucketURL == "" {
+		log.Logger().Infof("no bucket storage is configured for %s in the team settings so there are no caches to garbage collect", cache.Classifier)
+		return nil
+	}
+
+	ctx := context.Background()
+	bucket, err := cache.OpenBucket(ctx, bucketURL)
+	if err != nil {
+		return err
+	}
+	defer bucket.Close()
+
+	expired, err := cache.Expired(ctx, bucket, time.Now().Add(-o.Age))
+	if err != nil {
+		return err
+	}
+	for _, e := range expired {
+		if o.DryRun {
+			log.Logger().Infof("would delete cache %s last updated %s", util.ColorInfo(e.Name), e.ModTime.Format(time.RFC3339))
+			continue
+		}
+		err = bucket.Delete(ctx, e.Name)
+		if err != nil {
+			return errors.Wrapf(err, "failed to delete cache %s", e.Name)
+		}
+		log.Logger().Infof("deleted cache %s last updated %s", util.ColorInfo(e.Name), e.ModTime.Format(time.RFC3339))
+	}
+	log.Logger().Infof("found %d caches older than %s in %s", len(expired), o.Age.String(), bucketURL)
+	return nil
+}
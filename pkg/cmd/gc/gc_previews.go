@@ -2,6 +2,9 @@ package gc
 
 import (
 	"fmt"
+	"net/http"
+	"time"
+
 	"github.com/jenkins-x/jx/pkg/cmd/deletecmd"
 	"github.com/jenkins-x/jx/pkg/cmd/preview"
 
@@ -10,16 +13,26 @@ import (
 
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 
 	"strconv"
 
 	"strings"
 
 	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
 	"github.com/jenkins-x/jx/pkg/cmd/opts"
 	"github.com/jenkins-x/jx/pkg/cmd/templates"
 	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/kube"
 	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+const (
+	// defaultPreviewTrafficQuery is the Prometheus query of the requests to a preview namespace via the nginx ingress
+	// controller during the idle window of the preview
+	defaultPreviewTrafficQuery = `sum(increase(nginx_ingress_controller_requests{exported_namespace="{namespace}"}[{window}]))`
 )
 
 // GetOptions is the start of the data required to perform the operation.  As new fields are added, add them here instead of
@@ -29,6 +42,8 @@ type GCPreviewsOptions struct {
 
 	DisableImport bool
 	OutDir        string
+	PrometheusURL string
+	TrafficQuery  string
 }
 
 var (
@@ -36,11 +51,23 @@ var (
 		Garbage collect Jenkins X preview environments.  If a pull request is merged or closed the associated preview
 		environment will be deleted.
 
+		Preview environments also follow the 'ttl' and 'hibernateAfter' settings of 'previewEnvironments' in the
+		jenkins-x.yml of their project. A preview which has not been deployed for longer than its TTL is deleted even
+		though its pull request is still open. A preview which has been idle for longer than 'hibernateAfter' has its
+		Deployments scaled to zero until it is woken via 'jx preview wake' or a signed request to the /wake endpoint of
+		the pipeline runner.
+		A preview is idle if it has not been deployed or woken and, if a Prometheus URL is given, its ingress has not
+		served any requests. Without a Prometheus URL the traffic of a preview is ignored so a preview which is being
+		used but has not been deployed or woken for longer than 'hibernateAfter' is hibernated.
+
 `)
 
 	GCPreviewsExample = templates.Examples(`
 		jx garbage collect previews
 		jx gc previews
+
+		# hibernate previews only if their ingress has not served any requests
+		jx gc previews --prometheus-url http://prometheus-server.monitoring
 `)
 )
 
@@ -62,6 +89,8 @@ func NewCmdGCPreviews(commonOpts *opts.CommonOptions) *cobra.Command {
 			helper.CheckErr(err)
 		},
 	}
+	cmd.Flags().StringVarP(&options.PrometheusURL, "prometheus-url", "", "", "The URL of Prometheus used to check if idle preview environments have served any requests before hibernating them")
+	cmd.Flags().StringVarP(&options.TrafficQuery, "traffic-query", "", defaultPreviewTrafficQuery, "The Prometheus query of the requests served by a preview environment where {namespace} is its namespace and {window} its idle duration")
 	return cmd
 }
 
@@ -83,6 +112,12 @@ func (o *GCPreviewsOptions) Run() error {
 		return nil
 	}
 
+	kubeClient, err := o.KubeClient()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
 	var previewFound bool
 	for _, e := range envs.Items {
 		if e.Spec.Kind == v1.EnvironmentKindTypePreview {
@@ -112,24 +147,31 @@ func (o *GCPreviewsOptions) Run() error {
 			}
 			pullRequest, err := gitProvider.GetPullRequest(gitInfo.Organisation, gitInfo, prNum)
 			if err != nil {
-				log.Logger().Warnf("Can not get pull request %s, skipping: %s", e.Spec.PreviewGitSpec.Name, err)
-				continue
-			}
-
-			lowerState := strings.ToLower(*pullRequest.State)
+				log.Logger().Warnf("Can not get pull request %s: %s", e.Spec.PreviewGitSpec.Name, err)
+				pullRequest = nil
+			} else {
+				lowerState := strings.ToLower(*pullRequest.State)
 
-			if strings.HasPrefix(lowerState, "clos") || strings.HasPrefix(lowerState, "merged") || strings.HasPrefix(lowerState, "superseded") || strings.HasPrefix(lowerState, "declined") {
-				// lets delete the preview environment
-				deleteOpts := deletecmd.DeletePreviewOptions{
-					PreviewOptions: preview.PreviewOptions{
-						PromoteOptions: promote.PromoteOptions{
-							CommonOptions: o.CommonOptions,
-						},
-					},
+				if strings.HasPrefix(lowerState, "clos") || strings.HasPrefix(lowerState, "merged") || strings.HasPrefix(lowerState, "superseded") || strings.HasPrefix(lowerState, "declined") {
+					// lets delete the preview environment
+					err = o.deletePreview(e.Name)
+					if err != nil {
+						return err
+					}
+					continue
 				}
-				err = deleteOpts.DeletePreview(e.Name)
+			}
+
+			hibernated, err := o.applyPreviewPolicy(kubeClient, client, currentNs, &e, now)
+			if err != nil {
+				return err
+			}
+			if hibernated && pullRequest != nil {
+				comment := fmt.Sprintf(":zzz: The preview environment **%s** has been idle for %s so it has been hibernated. "+
+					"Run `jx preview wake --pr %s` to wake it", e.Name, e.Spec.PreviewPolicy.HibernateAfter, e.Spec.PreviewGitSpec.Name)
+				err = gitProvider.AddPRComment(pullRequest, comment)
 				if err != nil {
-					return fmt.Errorf("failed to delete preview environment %s: %v\n", e.Name, err)
+					log.Logger().Warnf("Failed to comment on pull request %s: %s", e.Spec.PreviewGitSpec.Name, err)
 				}
 			}
 		}
@@ -139,3 +181,81 @@ func (o *GCPreviewsOptions) Run() error {
 	}
 	return nil
 }
+
+func (o *GCPreviewsOptions) deletePreview(name string) error {
+	deleteOpts := deletecmd.DeletePreviewOptions{
+		PreviewOptions: preview.PreviewOptions{
+			PromoteOptions: promote.PromoteOptions{
+				CommonOptions: o.CommonOptions,
+			},
+		},
+	}
+	err := deleteOpts.DeletePreview(name)
+	if err != nil {
+		return fmt.Errorf("failed to delete preview environment %s: %v\n", name, err)
+	}
+	return nil
+}
+
+// applyPreviewPolicy deletes the preview environment if its TTL has passed or hibernates it if it has been idle for
+// longer than its policy allows, returning true if it was hibernated
+func (o *GCPreviewsOptions) applyPreviewPolicy(kubeClient kubernetes.Interface, jxClient versioned.Interface, ns string, env *v1.Environment, now time.Time) (bool, error) {
+	expired, err := kube.IsPreviewExpired(env, now)
+	if err != nil {
+		log.Logger().Warnf("Ignoring the policy of preview environment %s: %s", env.Name, err)
+		return false, nil
+	}
+	if expired {
+		log.Logger().Infof("Preview environment %s was last deployed at %s which is longer ago than its TTL of %s",
+			util.ColorInfo(env.Name), kube.PreviewLastDeployed(env).Format(time.RFC3339), env.Spec.PreviewPolicy.TTL)
+		return false, o.deletePreview(env.Name)
+	}
+
+	idle, err := kube.IsPreviewIdle(env, now)
+	if err != nil {
+		log.Logger().Warnf("Ignoring the policy of preview environment %s: %s", env.Name, err)
+		return false, nil
+	}
+	if !idle {
+		return false, nil
+	}
+	if o.PrometheusURL != "" {
+		requests, err := o.previewRequests(env)
+		if err != nil {
+			log.Logger().Warnf("Not hibernating preview environment %s as failed to query the requests it served: %s", env.Name, err)
+			return false, nil
+		}
+		if requests > 0 {
+			log.Logger().Debugf("preview environment %s served %f requests so is not idle", env.Name, requests)
+			active := metav1.NewTime(now)
+			env.Status.LastActive = &active
+			_, err = jxClient.JenkinsV1().Environments(ns).PatchUpdate(env)
+			if err != nil {
+				return false, fmt.Errorf("failed to update preview environment %s: %v", env.Name, err)
+			}
+			return false, nil
+		}
+	}
+
+	log.Logger().Infof("Hibernating preview environment %s as it has been idle since %s", util.ColorInfo(env.Name),
+		kube.PreviewLastActive(env).Format(time.RFC3339))
+	_, err = kube.HibernatePreview(kubeClient, jxClient, ns, env)
+	if err != nil {
+		return false, fmt.Errorf("failed to hibernate preview environment %s: %v", env.Name, err)
+	}
+	return true, nil
+}
+
+// previewRequests returns the number of requests served by the preview environment during its idle window
+func (o *GCPreviewsOptions) previewRequests(env *v1.Environment) (float64, error) {
+	window, err := time.ParseDuration(env.Spec.PreviewPolicy.HibernateAfter)
+	if err != nil {
+		return 0, err
+	}
+	query := strings.NewReplacer(
+		"{namespace}", env.Spec.Namespace,
+		"{window}", fmt.Sprintf("%ds", int64(window.Seconds())),
+	).Replace(o.TrafficQuery)
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	return promote.QueryPrometheus(httpClient, o.PrometheusURL, query)
+}
@@ -89,6 +89,7 @@ func NewCmdGet(commonOpts *opts.CommonOptions) *cobra.Command {
 	cmd.AddCommand(NewCmdGetIssues(commonOpts))
 	cmd.AddCommand(NewCmdGetLimits(commonOpts))
 	cmd.AddCommand(NewCmdGetLang(commonOpts))
+	cmd.AddCommand(NewCmdGetMetrics(commonOpts))
 	cmd.AddCommand(NewCmdGetPipeline(commonOpts))
 	cmd.AddCommand(NewCmdGetPostPreviewJob(commonOpts))
 	cmd.AddCommand(NewCmdGetPreview(commonOpts))
@@ -96,6 +97,7 @@ func NewCmdGet(commonOpts *opts.CommonOptions) *cobra.Command {
 	cmd.AddCommand(NewCmdGetQuickstarts(commonOpts))
 	cmd.AddCommand(NewCmdGetRelease(commonOpts))
 	cmd.AddCommand(NewCmdGetStorage(commonOpts))
+	cmd.AddCommand(NewCmdGetSteps(commonOpts))
 	cmd.AddCommand(NewCmdGetTeam(commonOpts))
 	cmd.AddCommand(NewCmdGetTeamRole(commonOpts))
 	cmd.AddCommand(NewCmdGetToken(commonOpts))
@@ -1,6 +1,7 @@
 package get
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
@@ -135,6 +136,13 @@ func (o *GetActivityOptions) addTableRow(table *tbl.Table, activity *v1.Pipeline
 			util.DurationString(spec.StartedTimestamp, spec.CompletedTimestamp),
 			statusText)
 		indent := indentation
+		if len(spec.Parameters) > 0 {
+			var parameters []string
+			for _, p := range spec.Parameters {
+				parameters = append(parameters, p.Name+"="+util.ColorInfo(p.Value))
+			}
+			table.AddRow(indent+"Parameters", "", "", strings.Join(parameters, " "))
+		}
 		for _, step := range spec.Steps {
 			o.addStepRow(table, &step, indent)
 		}
@@ -217,6 +225,9 @@ func addStageRow(table *tbl.Table, stage *v1.StageActivityStep, indent string) {
 	addStepRowItem(table, &stage.CoreActivityStep, indent, name, "")
 
 	indent += indentation
+	if stage.Input != nil {
+		addStepRowItem(table, &stage.Input.CoreActivityStep, indent, "Input", describeStageInput(stage.Input))
+	}
 	for _, step := range stage.Steps {
 		addStepRowItem(table, &step, indent, "", "")
 	}
@@ -237,9 +248,16 @@ func addPreviewRow(table *tbl.Table, parent *v1.PreviewActivityStep, indent stri
 }
 
 func addPromoteRow(table *tbl.Table, parent *v1.PromoteActivityStep, indent string) {
-	addStepRowItem(table, &parent.CoreActivityStep, indent, "Promote: "+parent.Environment, "")
+	if parent.Rollback != nil && parent.Canary == nil {
+		addStepRowItem(table, &parent.CoreActivityStep, indent, "Rollback: "+parent.Environment, describePromoteRollback(parent.Rollback))
+	} else {
+		addStepRowItem(table, &parent.CoreActivityStep, indent, "Promote: "+parent.Environment, "")
+	}
 	indent += indentation
 
+	for _, app := range parent.Applications {
+		addStepRowItem(table, &parent.CoreActivityStep, indent, "Application: "+app.Name, util.ColorInfo(app.Version))
+	}
 	pullRequest := parent.PullRequest
 	update := parent.Update
 	if pullRequest != nil {
@@ -248,6 +266,25 @@ func addPromoteRow(table *tbl.Table, parent *v1.PromoteActivityStep, indent stri
 	if update != nil {
 		addStepRowItem(table, &update.CoreActivityStep, indent, "Update", describePromoteUpdate(update))
 	}
+	canary := parent.Canary
+	if canary != nil {
+		addStepRowItem(table, &canary.CoreActivityStep, indent, "Canary", describePromoteCanary(canary))
+		if parent.Rollback != nil {
+			addStepRowItem(table, &canary.CoreActivityStep, indent+indentation, "Rollback", describePromoteRollback(parent.Rollback))
+		}
+	}
+	verify := parent.Verify
+	if verify != nil {
+		addStepRowItem(table, &verify.CoreActivityStep, indent, "Verify", "")
+		for _, check := range verify.Checks {
+			step := v1.CoreActivityStep{
+				Status:             check.Status,
+				StartedTimestamp:   verify.StartedTimestamp,
+				CompletedTimestamp: verify.CompletedTimestamp,
+			}
+			addStepRowItem(table, &step, indent+indentation, string(check.Kind)+": "+check.Name, check.Message)
+		}
+	}
 	appURL := parent.ApplicationURL
 	if appURL != "" {
 		addStepRowItem(table, &update.CoreActivityStep, indent, "Promoted", " Application is at: "+util.ColorInfo(appURL))
@@ -286,10 +323,29 @@ func statusString(statusType v1.ActivityStatusType) string {
 		return util.ColorInfo(text)
 	case v1.ActivityStatusTypeRunning:
 		return util.ColorStatus(text)
+	case v1.ActivityStatusTypeWaitingForApproval:
+		return util.ColorWarning(text)
 	}
 	return text
 }
 
+func describeStageInput(input *v1.StageInputStep) string {
+	description := input.Message
+	if input.Decision != nil {
+		if input.Decision.Approved {
+			description += " approved by " + util.ColorInfo(input.Decision.User)
+		} else {
+			description += " rejected by " + util.ColorInfo(input.Decision.User)
+		}
+		if input.Decision.Comment != "" {
+			description += ": " + input.Decision.Comment
+		}
+	} else if input.IsPending() && len(input.Approvers) > 0 {
+		description += " approvers: " + util.ColorInfo(strings.Join(input.Approvers, ", "))
+	}
+	return description
+}
+
 func describePromotePullRequest(promote *v1.PromotePullRequestStep) string {
 	description := ""
 	if promote.PullRequestURL != "" {
@@ -301,6 +357,17 @@ func describePromotePullRequest(promote *v1.PromotePullRequestStep) string {
 	return description
 }
 
+func describePromoteRollback(rollback *v1.PromoteRollback) string {
+	description := "from " + util.ColorInfo(rollback.FromVersion) + " to " + util.ColorInfo(rollback.ToVersion)
+	if rollback.User != "" {
+		description += " by " + rollback.User
+	}
+	if rollback.Reason != "" {
+		description += ": " + rollback.Reason
+	}
+	return description
+}
+
 func describePromoteUpdate(promote *v1.PromoteUpdateStep) string {
 	description := ""
 	for _, status := range promote.Statuses {
@@ -314,6 +381,29 @@ func describePromoteUpdate(promote *v1.PromoteUpdateStep) string {
 	return description
 }
 
+func describePromoteCanary(canary *v1.PromoteCanaryStep) string {
+	description := ""
+	if canary.Phase != "" {
+		description += " Phase: " + util.ColorInfo(canary.Phase)
+	}
+	if canary.MaxWeight > 0 {
+		description += fmt.Sprintf(" Weight: %s", util.ColorInfo(fmt.Sprintf("%d%%/%d%%", canary.CanaryWeight, canary.MaxWeight)))
+	}
+	if len(canary.Metrics) > 0 {
+		checks := fmt.Sprintf("%d/%d", canary.FailedChecks, canary.Threshold)
+		if canary.FailedChecks > 0 {
+			checks = util.ColorWarning(checks)
+		} else {
+			checks = util.ColorInfo(checks)
+		}
+		description += " Failed checks: " + checks + " of " + strings.Join(canary.Metrics, ", ")
+	}
+	if canary.Message != "" {
+		description += " " + canary.Message
+	}
+	return description
+}
+
 func pullRequestStatusString(text string) string {
 	title := strings.Title(text)
 	switch text {
@@ -135,7 +135,7 @@ func (o *GetEnvOptions) Run() error {
 		}
 		table := o.CreateTable()
 		if o.PreviewOnly {
-			table.AddRow("PULL REQUEST", "NAMESPACE", "APPLICATION")
+			table.AddRow("PULL REQUEST", "NAMESPACE", "APPLICATION", "STATUS")
 		} else {
 			table.AddRow("NAME", "LABEL", "KIND", "PROMOTE", "NAMESPACE", "ORDER", "CLUSTER", "SOURCE", "REF", "PR")
 		}
@@ -143,7 +143,7 @@ func (o *GetEnvOptions) Run() error {
 		for _, env := range environments {
 			spec := &env.Spec
 			if o.PreviewOnly {
-				table.AddRow(spec.PullRequestURL, spec.Namespace, util.ColorInfo(spec.PreviewGitSpec.ApplicationURL))
+				table.AddRow(spec.PullRequestURL, spec.Namespace, util.ColorInfo(spec.PreviewGitSpec.ApplicationURL), previewStatus(&env))
 			} else {
 				table.AddRow(env.Name, spec.Label, kindString(spec), string(spec.PromotionStrategy), spec.Namespace, util.Int32ToA(spec.Order), spec.Cluster, spec.Source.URL, spec.Source.Ref, spec.PullRequestURL)
 			}
@@ -153,6 +153,13 @@ func (o *GetEnvOptions) Run() error {
 	return nil
 }
 
+func previewStatus(env *v1.Environment) string {
+	if kube.IsPreviewHibernated(env) {
+		return "Hibernated"
+	}
+	return "Running"
+}
+
 func kindString(spec *v1.EnvironmentSpec) string {
 	answer := string(spec.Kind)
 	if answer == "" {
@@ -160,8 +160,11 @@ func (o *GetIssueOptions) findRelease(tracker issues.IssueProvider, issue *gits.
 
 func (o *GetIssueOptions) parseIssueIDs(issue v1.IssueSummary, issueKind string) []string {
 	regex := regexp.MustCompile(`(\#\d+)`)
-	if issueKind == issues.Jira {
+	switch {
+	case issueKind == issues.Jira:
 		regex = regexp.MustCompile(`[A-Z][A-Z]+-(\d+)`)
+	case issues.UsesIssueKeys(issueKind):
+		regex = regexp.MustCompile(`\b[A-Z][A-Z0-9_]*-(\d+)\b`)
 	}
 	issues := []string{}
 	foundIssues := map[string]bool{}
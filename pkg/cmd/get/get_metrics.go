@@ -0,0 +1,53 @@
+package get
+
+import (
+	"github.com/jenkins-x/jx/pkg/cmd/helper"
+	"github.com/spf13/cobra"
+
+	"github.com/jenkins-x/jx/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/pkg/cmd/templates"
+)
+
+// GetMetricsOptions the command line options
+type GetMetricsOptions struct {
+	*opts.CommonOptions
+}
+
+var (
+	getMetricsLong = templates.LongDesc(`
+		Display metrics aggregated from the pipelines and promotions of the team
+`)
+
+	getMetricsExample = templates.Examples(`
+		# Display the DORA metrics of all applications and environments
+		jx get metrics dora
+	`)
+)
+
+// NewCmdGetMetrics creates the command object
+func NewCmdGetMetrics(commonOpts *opts.CommonOptions) *cobra.Command {
+	options := &GetMetricsOptions{
+		CommonOptions: commonOpts,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "metrics",
+		Short:   "Display metrics aggregated from the pipelines and promotions of the team",
+		Long:    getMetricsLong,
+		Example: getMetricsExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			helper.CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(NewCmdGetMetricsDORA(commonOpts))
+	return cmd
+}
+
+// Run implements this command
+func (o *GetMetricsOptions) Run() error {
+	return o.Cmd.Help()
+}
@@ -0,0 +1,156 @@
+package get
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/reports"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetMetricsDORAOptions the command line options
+type GetMetricsDORAOptions struct {
+	GetOptions
+
+	Application string
+	Environment string
+	Duration    time.Duration
+	To          string
+}
+
+var (
+	getMetricsDORALong = templates.LongDesc(`
+		Display the DORA metrics of each application and environment calculated from the PipelineActivity and Release resources:
+
+		* deployment frequency: the number of successful deployments per day
+		* lead time for changes: the mean time from the start of the release pipeline to the deployment completing
+		* change failure rate: the ratio of deployments which failed, whose Release failed or which were rolled back
+		* mean time to restore: the mean time from a failed deployment to the next successful deployment
+`)
+
+	getMetricsDORAExample = templates.Examples(`
+		# Display the DORA metrics for the last 30 days
+		jx get metrics dora
+
+		# Display the DORA metrics of production for the last week as JSON
+		jx get metrics dora --env production --duration 168h -o json
+
+		# Display the DORA metrics of an application in the Prometheus exposition format
+		jx get metrics dora --app myapp -o prometheus
+	`)
+)
+
+// NewCmdGetMetricsDORA creates the command object
+func NewCmdGetMetricsDORA(commonOpts *opts.CommonOptions) *cobra.Command {
+	options := &GetMetricsDORAOptions{
+		GetOptions: GetOptions{
+			CommonOptions: commonOpts,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "dora",
+		Short:   "Display the DORA metrics of deployment frequency, lead time, change failure rate and mean time to restore",
+		Long:    getMetricsDORALong,
+		Example: getMetricsDORAExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			helper.CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&options.Application, opts.OptionApplication, "a", "", "Only display the metrics of the application")
+	cmd.Flags().StringVarP(&options.Environment, opts.OptionEnvironment, "e", "", "Only display the metrics of the environment")
+	cmd.Flags().DurationVarP(&options.Duration, "duration", "d", 30*24*time.Hour, "The length of the time range to calculate the metrics over")
+	cmd.Flags().StringVarP(&options.To, "to", "", "", "The end of the time range in RFC3339 format. Defaults to now")
+	cmd.Flags().StringVarP(&options.Output, "output", "o", "", "The output format: 'json' or 'prometheus'. Defaults to a table")
+	return cmd
+}
+
+// Run implements this command
+func (o *GetMetricsDORAOptions) Run() error {
+	to := time.Now()
+	if o.To != "" {
+		var err error
+		to, err = time.Parse(time.RFC3339, o.To)
+		if err != nil {
+			return util.InvalidOptionError("to", o.To, err)
+		}
+	}
+	from := to.Add(-o.Duration)
+
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	activities, err := jxClient.JenkinsV1().PipelineActivities(ns).List(metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to list the PipelineActivities in namespace %s", ns)
+	}
+	deployments := []reports.Deployment{}
+	for _, d := range reports.DeploymentsFromActivities(activities.Items) {
+		if (o.Application == "" || d.Application == o.Application) && (o.Environment == "" || d.Environment == o.Environment) {
+			deployments = append(deployments, d)
+		}
+	}
+
+	envs, err := jxClient.JenkinsV1().Environments(ns).List(metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to list the Environments in namespace %s", ns)
+	}
+	envNamespaces := map[string]string{}
+	for _, env := range envs.Items {
+		if !env.Spec.Kind.IsPermanent() || env.Spec.Namespace == "" {
+			continue
+		}
+		if o.Environment != "" && env.Name != o.Environment {
+			continue
+		}
+		envNamespaces[env.Name] = env.Spec.Namespace
+		releases, err := jxClient.JenkinsV1().Releases(env.Spec.Namespace).List(metav1.ListOptions{})
+		if err != nil {
+			log.Logger().Warnf("Failed to list the Releases in namespace %s: %s", env.Spec.Namespace, err)
+			continue
+		}
+		reports.MarkFailedReleases(deployments, releases.Items, envNamespaces)
+	}
+
+	metrics := reports.CalculateDORAMetrics(deployments, from, to)
+	switch o.Output {
+	case "":
+		if len(metrics) == 0 {
+			return outputEmptyListWarning(o.Out)
+		}
+		for _, m := range metrics {
+			report := reports.NewTableBarReport(o.CreateTable(), m.Application, m.Environment)
+			reports.AddDORAMetrics(report, m)
+			err = report.Render()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	case "json":
+		return o.renderResult(metrics, o.Output)
+	case "prometheus":
+		for _, t := range reports.DORAMetricTypes {
+			report := reports.NewPrometheusBarReport(o.Out, t.Name, t.Help, "application", "environment")
+			reports.AddDORAMetric(report, t, metrics)
+			err = report.Render()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format: %s", o.Output)
+	}
+}
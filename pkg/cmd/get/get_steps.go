@@ -0,0 +1,111 @@
+package get
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/tekton/syntax"
+	"github.com/spf13/cobra"
+)
+
+// GetStepsOptions the command line options
+type GetStepsOptions struct {
+	GetOptions
+
+	VersionsRepository string
+	VersionsGitRef     string
+	Dir                string
+}
+
+var (
+	getStepsLong = templates.LongDesc(`
+		Display the step templates which pipelines can use via 'step: <name>' with options.
+
+		Step templates are loaded from the 'steps' directory of the team's build pack repository and the version stream. The team's templates take precedence.
+`)
+
+	getStepsExample = templates.Examples(`
+		# List the step templates
+		jx get steps
+
+		# List the step templates of a version stream branch
+		jx get steps --versions-ref my-branch
+	`)
+)
+
+// NewCmdGetSteps creates the command
+func NewCmdGetSteps(commonOpts *opts.CommonOptions) *cobra.Command {
+	options := &GetStepsOptions{
+		GetOptions: GetOptions{
+			CommonOptions: commonOpts,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "steps",
+		Short:   "Display the step templates which pipelines can use",
+		Long:    getStepsLong,
+		Example: getStepsExample,
+		Aliases: []string{"step"},
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			helper.CheckErr(err)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.VersionsRepository, "versions-repo", "", "", "The version stream git repository. Defaults to the team's version stream")
+	cmd.Flags().StringVarP(&options.VersionsGitRef, "versions-ref", "", "", "The git ref of the version stream")
+	cmd.Flags().StringVarP(&options.Dir, "dir", "d", "", "The directory of the team's step templates. Defaults to the 'steps' directory of the team's build pack repository")
+	return cmd
+}
+
+// Run implements this command
+func (o *GetStepsOptions) Run() error {
+	resolver, err := o.CreateVersionResolver(o.VersionsRepository, o.VersionsGitRef)
+	if err != nil {
+		return err
+	}
+	teamDir := o.Dir
+	if teamDir == "" {
+		packsDir, _, err := o.InitBuildPacks(nil)
+		if err != nil {
+			log.Logger().Warnf("failed to load the team's build pack so not loading its step templates: %s", err.Error())
+		} else {
+			teamDir = filepath.Join(filepath.Dir(packsDir), syntax.StepTemplatesDirName)
+		}
+	}
+	stepTemplates, err := syntax.LoadStepTemplates(syntax.StepTemplateDirs(resolver.VersionsDir, teamDir))
+	if err != nil {
+		return err
+	}
+	if len(stepTemplates) == 0 {
+		log.Logger().Info("no step templates found")
+		return nil
+	}
+
+	table := o.CreateTable()
+	table.AddRow("NAME", "VERSION", "OPTIONS", "SOURCE", "DESCRIPTION")
+	for _, t := range stepTemplates {
+		var options []string
+		for _, option := range t.Options {
+			name := option.Name
+			if option.Required {
+				name += "*"
+			}
+			options = append(options, name)
+		}
+		source := "version stream"
+		if t.Dir == teamDir {
+			source = "team"
+		}
+		table.AddRow(t.Name, t.Version, strings.Join(options, ", "), source, t.Description)
+	}
+	table.Render()
+	return nil
+}
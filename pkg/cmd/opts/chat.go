@@ -1,8 +1,19 @@
 package opts
 
 import (
+	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx/pkg/chats"
 	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/pkg/errors"
+)
+
+const (
+	// AnnotationChatKind the annotation of the kind of the chat service of the project of a PipelineActivity
+	AnnotationChatKind = "jenkins.io/chat-kind"
+	// AnnotationChatURL the annotation of the URL of the chat service of the project of a PipelineActivity
+	AnnotationChatURL = "jenkins.io/chat-url"
+	// AnnotationChatDeveloperChannel the annotation of the developer channel of the project of a PipelineActivity
+	AnnotationChatDeveloperChannel = "jenkins.io/chat-developer-channel"
 )
 
 // CreateChatProvider creates a new chart provider from the given configuration
@@ -24,3 +35,57 @@ func (o *CommonOptions) CreateChatProvider(chatConfig *config.ChatConfig) (chats
 	}
 	return chats.CreateChatProvider(server.Kind, server, userAuth, o.BatchMode)
 }
+
+// NotifyDeveloperChannel posts the message to the developer channel of the chat configuration.
+// Nothing is posted if there is no chat configuration or developer channel
+func (o *CommonOptions) NotifyDeveloperChannel(chatConfig *config.ChatConfig, message *chats.Message) (*chats.MessageReference, error) {
+	if chatConfig == nil || chatConfig.DeveloperChannel == "" {
+		return nil, nil
+	}
+	provider, err := o.CreateChatProvider(chatConfig)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create the %s chat provider for %s", chatConfig.Kind, chatConfig.URL)
+	}
+	if provider == nil {
+		return nil, nil
+	}
+	return provider.PostMessage(chatConfig.DeveloperChannel, message)
+}
+
+// AnnotateChatConfig records the developer channel of the chat configuration of a project on the PipelineActivity of
+// its build so that 'jx controller build' can notify the channel when the build starts and finishes. Returns true if
+// the annotations changed
+func AnnotateChatConfig(activity *v1.PipelineActivity, chatConfig *config.ChatConfig) bool {
+	if chatConfig == nil || chatConfig.URL == "" || chatConfig.DeveloperChannel == "" {
+		return false
+	}
+	values := map[string]string{
+		AnnotationChatKind:             chatConfig.Kind,
+		AnnotationChatURL:              chatConfig.URL,
+		AnnotationChatDeveloperChannel: chatConfig.DeveloperChannel,
+	}
+	if activity.Annotations == nil {
+		activity.Annotations = map[string]string{}
+	}
+	changed := false
+	for k, v := range values {
+		if activity.Annotations[k] != v {
+			activity.Annotations[k] = v
+			changed = true
+		}
+	}
+	return changed
+}
+
+// ChatConfigFromAnnotations returns the chat configuration recorded on the PipelineActivity or nil if there is none
+func ChatConfigFromAnnotations(activity *v1.PipelineActivity) *config.ChatConfig {
+	annotations := activity.Annotations
+	if annotations[AnnotationChatURL] == "" || annotations[AnnotationChatDeveloperChannel] == "" {
+		return nil
+	}
+	return &config.ChatConfig{
+		Kind:             annotations[AnnotationChatKind],
+		URL:              annotations[AnnotationChatURL],
+		DeveloperChannel: annotations[AnnotationChatDeveloperChannel],
+	}
+}
@@ -0,0 +1,27 @@
+package opts_test
+
+import (
+	"testing"
+
+	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChatConfigAnnotations(t *testing.T) {
+	t.Parallel()
+
+	activity := &v1.PipelineActivity{}
+	assert.Nil(t, opts.ChatConfigFromAnnotations(activity), "no annotations")
+	assert.False(t, opts.AnnotateChatConfig(activity, &config.ChatConfig{URL: "https://slack.com"}), "no developer channel")
+
+	chatConfig := &config.ChatConfig{
+		Kind:             "slack",
+		URL:              "https://slack.com",
+		DeveloperChannel: "#jx-dev",
+	}
+	assert.True(t, opts.AnnotateChatConfig(activity, chatConfig), "annotated")
+	assert.False(t, opts.AnnotateChatConfig(activity, chatConfig), "already annotated")
+	assert.Equal(t, chatConfig, opts.ChatConfigFromAnnotations(activity))
+}
@@ -0,0 +1,58 @@
+package opts
+
+import (
+	"fmt"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/cve"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// CreateCVEProvider creates the CVE provider configured in the team settings defaulting to the Anchore addon
+func (o *CommonOptions) CreateCVEProvider() (cve.CVEProvider, error) {
+	teamSettings, err := o.TeamSettings()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load the team settings")
+	}
+	settings := teamSettings.CVE
+	if settings == nil || settings.Kind == "" || settings.Kind == cve.Anchore {
+		return o.createAnchoreProvider()
+	}
+	server := &auth.AuthServer{
+		URL:  settings.URL,
+		Kind: settings.Kind,
+	}
+	var userAuth *auth.UserAuth
+	if settings.Kind == cve.Clair && settings.URL != "" {
+		server, userAuth, err = o.GetAddonAuthByKind(kube.ValueKindCVE, settings.URL)
+		if err != nil {
+			log.Logger().Debugf("no credentials found for the clair server %s so using anonymous access: %s", settings.URL, err)
+			server = &auth.AuthServer{URL: settings.URL, Kind: settings.Kind}
+			userAuth = nil
+		}
+	}
+	p, err := cve.CreateCVEProvider(settings.Kind, server, userAuth)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error creating %s provider", settings.Kind)
+	}
+	return p, nil
+}
+
+func (o *CommonOptions) createAnchoreProvider() (cve.CVEProvider, error) {
+	externalURL, err := o.EnsureAddonServiceAvailable(kube.AddonServices[cve.Anchore])
+	if err != nil {
+		log.Logger().Warnf("no CVE provider service found, are you in your teams dev environment?  Type `jx env` to switch.")
+		return nil, fmt.Errorf("if no CVE provider running, try running `jx create addon anchore` in your teams dev environment: %v", err)
+	}
+	server, auth, err := o.GetAddonAuthByKind(kube.ValueKindCVE, externalURL)
+	if err != nil {
+		return nil, fmt.Errorf("error getting anchore engine auth details, %v", err)
+	}
+	p, err := cve.NewAnchoreProvider(server, auth)
+	if err != nil {
+		return nil, fmt.Errorf("error creating anchore provider, %v", err)
+	}
+	return p, nil
+}
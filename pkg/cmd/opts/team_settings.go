@@ -244,6 +244,19 @@ func (o *CommonOptions) RegisterPipelineActivityCRD() error {
 	return nil
 }
 
+// RegisterPromotionApprovalCRD registers the PromotionApproval CRD
+func (o *CommonOptions) RegisterPromotionApprovalCRD() error {
+	apisClient, err := o.ApiExtensionsClient()
+	if err != nil {
+		return err
+	}
+	err = kube.RegisterPromotionApprovalCRD(apisClient)
+	if err != nil {
+		return errors.Wrap(err, "failed to register the PromotionApproval CRD")
+	}
+	return nil
+}
+
 // RegisterWorkflowCRD registers Workflow CRD
 func (o *CommonOptions) RegisterWorkflowCRD() error {
 	apisClient, err := o.ApiExtensionsClient()
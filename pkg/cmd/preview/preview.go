@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
@@ -26,6 +27,7 @@ import (
 	"github.com/jenkins-x/jx/pkg/kube/services"
 
 	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/chats"
 	"github.com/jenkins-x/jx/pkg/cmd/opts"
 	"github.com/jenkins-x/jx/pkg/cmd/templates"
 	"github.com/jenkins-x/jx/pkg/config"
@@ -45,6 +47,15 @@ var (
 	previewLong = templates.LongDesc(`
 		Creates or updates a Preview Environment for the given Pull Request or Branch.
 
+		The 'ttl' and 'hibernateAfter' settings of 'previewEnvironments' in the jenkins-x.yml of the project are
+		recorded on the Preview Environment so that 'jx gc previews' deletes it once the TTL has passed since it was
+		last deployed or scales its Deployments to zero when it is idle. Deploying a hibernated Preview Environment
+		wakes it.
+
+		The 'dependencies' of 'previewEnvironments' are PersistentVolumeClaims which are seeded from either a
+		VolumeSnapshot or a SQL dump in bucket storage before the application is first deployed so that the
+		Preview Environment starts with realistic data.
+
 		For more documentation on Preview Environments see: [https://jenkins-x.io/about/features/#preview-environments](https://jenkins-x.io/about/features/#preview-environments)
 
 `)
@@ -134,6 +145,7 @@ func NewCmdPreview(commonOpts *opts.CommonOptions) *cobra.Command {
 	options.HelmValuesConfig.AddExposeControllerValues(cmd, false)
 	options.PromoteOptions.AddPromoteOptions(cmd)
 
+	cmd.AddCommand(NewCmdPreviewWake(commonOpts))
 	return cmd
 }
 
@@ -223,6 +235,19 @@ func (o *PreviewOptions) Run() error {
 	if err != nil {
 		return err
 	}
+	previewPolicy := kube.PreviewPolicyFromConfig(projectConfig.PreviewEnvironments)
+	err = kube.ValidatePreviewPolicy(previewPolicy)
+	if err != nil {
+		return err
+	}
+	var dependencies []config.PreviewDependency
+	if projectConfig.PreviewEnvironments != nil {
+		dependencies = projectConfig.PreviewEnvironments.Dependencies
+	}
+	err = kube.ValidatePreviewDependencies(dependencies)
+	if err != nil {
+		return err
+	}
 
 	if o.GitInfo == nil {
 		log.Logger().Warnf("No GitInfo found")
@@ -363,6 +388,10 @@ func (o *PreviewOptions) Run() error {
 			source.Ref = o.SourceRef
 			update = true
 		}
+		if !reflect.DeepEqual(spec.PreviewPolicy, previewPolicy) {
+			spec.PreviewPolicy = previewPolicy
+			update = true
+		}
 
 		gitSpec := spec.PreviewGitSpec
 		if gitSpec.BuildStatus != buildStatus {
@@ -443,6 +472,7 @@ func (o *PreviewOptions) Run() error {
 					Ref:  o.SourceRef,
 				},
 				PreviewGitSpec: previewGitSpec,
+				PreviewPolicy:  previewPolicy,
 			},
 		}
 		_, err = environmentsResource.Create(env)
@@ -457,6 +487,17 @@ func (o *PreviewOptions) Run() error {
 		return err
 	}
 
+	if len(dependencies) > 0 {
+		jobs, err := kube.SeedPreviewDependencies(kubeClient, ns, o.Name, o.Namespace, dependencies)
+		if err != nil {
+			return errors.Wrap(err, "failed to seed the preview dependencies")
+		}
+		err = o.waitForJobsToComplete(kubeClient, jobs)
+		if err != nil {
+			return errors.Wrap(err, "failed to seed the preview dependencies")
+		}
+	}
+
 	domain, err := kube.GetCurrentDomain(kubeClient, ns)
 	if err != nil {
 		return err
@@ -499,11 +540,29 @@ func (o *PreviewOptions) Run() error {
 		helmOptions.ValueFiles = append(helmOptions.ValueFiles, defaultValuesFileName)
 	}
 
+	if kube.IsPreviewHibernated(env) {
+		log.Logger().Infof("Waking hibernated preview environment %s", util.ColorInfo(env.Name))
+		env, err = kube.WakePreview(kubeClient, jxClient, ns, env)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = o.InstallChartWithOptions(helmOptions)
 	if err != nil {
 		return err
 	}
 
+	env, err = environmentsResource.Get(o.Name, metav1.GetOptions{})
+	if err == nil {
+		deployed := metav1.Now()
+		env.Status.LastDeployed = &deployed
+		_, err = environmentsResource.PatchUpdate(env)
+	}
+	if err != nil {
+		log.Logger().Warnf("Failed to record when preview environment %s was deployed: %s", o.Name, err)
+	}
+
 	url, appNames, err := o.findPreviewURL(kubeClient, kserveClient)
 
 	if url == "" {
@@ -596,6 +655,11 @@ func (o *PreviewOptions) Run() error {
 			}
 		}
 		log.Logger().Infof("Preview application is now available at: %s\n", util.ColorInfo(url))
+
+		_, err = o.NotifyDeveloperChannel(projectConfig.Chat, chats.PreviewReadyMessage(o.Application, o.PullRequestURL, url))
+		if err != nil {
+			log.Logger().Warnf("Failed to notify the developer chat channel that the preview is ready: %s", err)
+		}
 	}
 
 	stepPRCommentOptions := pr.StepPRCommentOptions{
@@ -751,7 +815,7 @@ func (o *PreviewOptions) waitForJob(kubeClient kubernetes.Interface, job *batchv
 	}
 	err := o.RetryUntilTrueOrTimeout(o.PostPreviewJobTimeoutDuration, o.PostPreviewJobPollDuration, fn)
 	if err != nil {
-		log.Logger().Warnf("\nFailed to complete post Preview Job %s in namespace %s: %s", name, ns, err)
+		log.Logger().Warnf("\nFailed to complete Job %s in namespace %s: %s", name, ns, err)
 	}
 	return err
 }
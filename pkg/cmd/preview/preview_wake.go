@@ -0,0 +1,161 @@
+package preview
+
+import (
+	"fmt"
+
+	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PreviewWakeOptions the options for waking hibernated Preview Environments
+type PreviewWakeOptions struct {
+	*opts.CommonOptions
+
+	PullRequest string
+	Owner       string
+	Repository  string
+	Dir         string
+}
+
+var (
+	previewWakeLong = templates.LongDesc(`
+		Wakes a Preview Environment which was hibernated by 'jx gc previews' as it was idle.
+
+		The Deployments of the Preview Environment are scaled back to their replicas before it was hibernated and it
+		will not be hibernated again until it has been idle for the 'hibernateAfter' duration of the 'previewEnvironments'
+		configuration of its project.
+`)
+
+	previewWakeExample = templates.Examples(`
+		# Pick the hibernated Preview Environment to wake
+		jx preview wake
+
+		# Wake a Preview Environment by name
+		jx preview wake myorg-myapp-pr-23
+
+		# Wake the Preview Environment of a Pull Request of the repository in the current directory
+		jx preview wake --pr 23
+	`)
+)
+
+// NewCmdPreviewWake creates the command to wake hibernated Preview Environments
+func NewCmdPreviewWake(commonOpts *opts.CommonOptions) *cobra.Command {
+	options := &PreviewWakeOptions{
+		CommonOptions: commonOpts,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "wake [name]",
+		Short:   "Wakes a hibernated Preview Environment",
+		Long:    previewWakeLong,
+		Example: previewWakeExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			helper.CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&options.PullRequest, "pr", "", "", "The Pull Request of the Preview Environment (e.g. 'PR-23' or just '23')")
+	cmd.Flags().StringVarP(&options.Owner, "owner", "o", "", "The git owner of the Pull Request. Defaults to the repository in the current directory")
+	cmd.Flags().StringVarP(&options.Repository, "repo", "r", "", "The git repository of the Pull Request. Defaults to the repository in the current directory")
+	cmd.Flags().StringVarP(&options.Dir, "dir", "", "", "The source directory used to detect the git repository of the Pull Request")
+	return cmd
+}
+
+// Run implements this command
+func (o *PreviewWakeOptions) Run() error {
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	kubeClient, err := o.KubeClient()
+	if err != nil {
+		return err
+	}
+
+	var env *v1.Environment
+	if len(o.Args) > 0 {
+		env, err = kube.GetEnvironment(jxClient, ns, o.Args[0])
+		if err != nil {
+			return err
+		}
+	} else if o.PullRequest != "" {
+		if o.Owner == "" || o.Repository == "" {
+			gitInfo, err := o.FindGitInfo(o.Dir)
+			if err != nil {
+				return err
+			}
+			if o.Owner == "" {
+				o.Owner = gitInfo.Organisation
+			}
+			if o.Repository == "" {
+				o.Repository = gitInfo.Name
+			}
+		}
+		env, err = kube.FindPreviewForPullRequest(jxClient, ns, o.Owner, o.Repository, o.PullRequest)
+		if err != nil {
+			return err
+		}
+		if env == nil {
+			return fmt.Errorf("no Preview Environment found for Pull Request %s of %s/%s", o.PullRequest, o.Owner, o.Repository)
+		}
+	} else {
+		env, err = o.pickHibernatedPreview(ns)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !kube.IsPreviewEnvironment(env) {
+		return fmt.Errorf("environment %s is not a Preview Environment", env.Name)
+	}
+	if !kube.IsPreviewHibernated(env) {
+		log.Logger().Infof("Preview Environment %s is not hibernated", util.ColorInfo(env.Name))
+		return nil
+	}
+	_, err = kube.WakePreview(kubeClient, jxClient, ns, env)
+	if err != nil {
+		return err
+	}
+	log.Logger().Infof("Woke Preview Environment %s", util.ColorInfo(env.Name))
+	return nil
+}
+
+func (o *PreviewWakeOptions) pickHibernatedPreview(ns string) (*v1.Environment, error) {
+	jxClient, _, err := o.JXClient()
+	if err != nil {
+		return nil, err
+	}
+	envs, err := jxClient.JenkinsV1().Environments(ns).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	hibernated := map[string]*v1.Environment{}
+	names := []string{}
+	for i := range envs.Items {
+		env := &envs.Items[i]
+		if kube.IsPreviewEnvironment(env) && kube.IsPreviewHibernated(env) {
+			hibernated[env.Name] = env
+			names = append(names, env.Name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no hibernated Preview Environments found in namespace %s", ns)
+	}
+	if o.BatchMode {
+		return nil, util.MissingArgument("name")
+	}
+	name, err := util.PickName(names, "Pick the Preview Environment to wake:", "", o.In, o.Out, o.Err)
+	if err != nil {
+		return nil, err
+	}
+	return hibernated[name], nil
+}
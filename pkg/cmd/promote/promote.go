@@ -22,20 +22,27 @@ import (
 	"github.com/jenkins-x/jx/pkg/kube/services"
 
 	"github.com/blang/semver"
+	"github.com/jenkins-x/jx/pkg/chats"
 	typev1 "github.com/jenkins-x/jx/pkg/client/clientset/versioned/typed/jenkins.io/v1"
 	"github.com/jenkins-x/jx/pkg/cmd/opts"
 	"github.com/jenkins-x/jx/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/cve"
 	"github.com/jenkins-x/jx/pkg/gits"
 	"github.com/jenkins-x/jx/pkg/helm"
 	"github.com/jenkins-x/jx/pkg/kube"
 	"github.com/jenkins-x/jx/pkg/log"
 	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/jenkins-x/jx/pkg/workflow"
 	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
 const (
 	optionPullRequestPollTime = "pull-request-poll-time"
+	optionCanary              = "canary"
 
 	GitStatusSuccess = "success"
 )
@@ -68,6 +75,33 @@ type PromoteOptions struct {
 	PullRequestPollTime     string
 	Filter                  string
 	Alias                   string
+	Image                   string
+	CVESeverityThreshold    string
+	OverrideFreeze          bool
+	OverrideReason          string
+	OverrideUser            string
+
+	// Apps and BundleFile specify the versions of several applications which are promoted together in a single
+	// Pull Request
+	Apps       string
+	BundleFile string
+
+	// Rollback if specified marks the promotion as a rollback to an earlier version
+	Rollback *v1.PromoteRollback
+
+	// Canary if enabled waits for the Flagger Canary of the application to complete its analysis and rolls back
+	// the promotion if the canary is aborted
+	Canary           bool
+	CanaryName       string
+	NoCanaryRollback bool
+
+	// Verify if enabled checks the application is healthy after it has been promoted, failing the promotion otherwise
+	Verify         bool
+	VerifyTimeout  string
+	VerifyPaths    []string
+	PrometheusURL  string
+	ErrorRateQuery string
+	MaxErrorRate   float64
 
 	// allow git to be configured externally before a PR is created
 	ConfigureGitCallback gits.ConfigureGitFn
@@ -81,6 +115,10 @@ type PromoteOptions struct {
 	releaseResource         *v1.Release
 	ReleaseInfo             *ReleaseInfo
 	prow                    bool
+	canaryStart             time.Time
+	canaryPreviousVersion   string
+	verifyTimeoutDuration   *time.Duration
+	releaseBundle           *ReleaseBundle
 }
 
 type ReleaseInfo struct {
@@ -113,6 +151,29 @@ var (
 		# To promote a postgres chart using an alias
 		jx promote -f postgres --alias mydb
 
+		# Promote to production failing if the image has any critical vulnerabilities
+		jx promote myapp --version 1.2.3 --env production --cve-severity-threshold Critical
+
+		# Promote several applications to staging together in a single Pull Request
+		jx promote --apps frontend:1.2.0,backend:3.4.1 --env staging
+
+		# Promote the applications of a release bundle file to production together
+		jx promote --bundle release.yaml --env production
+
+		# Promote to production with progressive delivery, waiting for the Flagger canary analysis to succeed
+		# and rolling back the promotion if the canary is aborted
+		jx promote myapp --version 1.2.3 --env production --canary
+
+		# Promote to production then verify the rollout completes, the application responds on /health and
+		# its error rate reported by Prometheus is below 1%
+		jx promote myapp --version 1.2.3 --env production --verify --verify-path /health \
+			--prometheus-url http://prometheus-server.monitoring \
+			--error-rate-query 'sum(rate(http_requests_total{app="myapp",code=~"5.."}[1m])) / sum(rate(http_requests_total{app="myapp"}[1m]))' \
+			--max-error-rate 0.01
+
+		# Promote to production even though it is in a freeze window
+		jx promote myapp --version 1.2.3 --env production --override-freeze --override-reason "hotfix for incident 123"
+
 		# To create or update a Preview Environment please see the 'jx preview' command
 		jx preview
 	`)
@@ -139,6 +200,19 @@ func NewCmdPromote(commonOpts *opts.CommonOptions) *cobra.Command {
 	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "The Namespace to promote to")
 	cmd.Flags().StringVarP(&options.Environment, opts.OptionEnvironment, "e", "", "The Environment to promote to")
 	cmd.Flags().BoolVarP(&options.AllAutomatic, "all-auto", "", false, "Promote to all automatic environments in order")
+	cmd.Flags().StringVarP(&options.Image, "image", "", "", "The image being promoted which is checked for vulnerabilities. Defaults to the image of the version in the docker registry of the team")
+	cmd.Flags().StringVarP(&options.CVESeverityThreshold, "cve-severity-threshold", "", "", "Fails the promotion if the image has vulnerabilities of this severity or above such as High or Critical. Defaults to the team settings; use 'none' to disable")
+	cmd.Flags().StringVarP(&options.Apps, optionApps, "", "", "A comma separated list of applications and versions of the form 'app:version' which are promoted together in a single Pull Request")
+	cmd.Flags().StringVarP(&options.BundleFile, optionBundle, "", "", "A YAML release bundle file listing the 'apps' with their 'name' and 'version' which are promoted together in a single Pull Request")
+	cmd.Flags().BoolVarP(&options.Canary, optionCanary, "", false, "Waits for the Flagger Canary of the application to complete its analysis, failing and rolling back the promotion if the canary is aborted")
+	cmd.Flags().StringVarP(&options.CanaryName, "canary-name", "", "", "The name of the Flagger Canary of the application. Defaults to the Canary which targets the deployment of the application")
+	cmd.Flags().BoolVarP(&options.NoCanaryRollback, "no-canary-rollback", "", false, "Disables rolling back the Environment to the previous version if the canary is aborted")
+	cmd.Flags().BoolVarP(&options.Verify, optionVerify, "", false, "Verifies the application is healthy after it has been promoted by waiting for the rollout of its Deployment and running any smoke and error rate checks, failing the promotion otherwise")
+	cmd.Flags().StringVarP(&options.VerifyTimeout, "verify-timeout", "", "5m", "The timeout for all the checks of the application to pass when using --verify")
+	cmd.Flags().StringArrayVarP(&options.VerifyPaths, "verify-path", "", nil, "A path of the application URL which must respond successfully when using --verify. Can be specified more than once")
+	cmd.Flags().StringVarP(&options.PrometheusURL, optionPrometheusURL, "", "", "The URL of Prometheus to check the error rate of the application when using --verify")
+	cmd.Flags().StringVarP(&options.ErrorRateQuery, optionErrorRateQuery, "", "", "The Prometheus query which returns the error rate of the application. Required with --"+optionPrometheusURL)
+	cmd.Flags().Float64VarP(&options.MaxErrorRate, "max-error-rate", "", 0.05, "The maximum error rate returned by the --"+optionErrorRateQuery+" query")
 
 	options.AddPromoteOptions(cmd)
 	return cmd
@@ -161,10 +235,19 @@ func (options *PromoteOptions) AddPromoteOptions(cmd *cobra.Command) {
 	cmd.Flags().BoolVarP(&options.NoPoll, "no-poll", "", false, "Disables polling for Pull Request or Pipeline status")
 	cmd.Flags().BoolVarP(&options.NoWaitAfterMerge, "no-wait", "", false, "Disables waiting for completing promotion after the Pull request is merged")
 	cmd.Flags().BoolVarP(&options.IgnoreLocalFiles, "ignore-local-file", "", false, "Ignores the local file system when deducing the Git repository")
+	cmd.Flags().BoolVarP(&options.OverrideFreeze, "override-freeze", "", false, "Promotes even if the Environment is in a freeze window. Requires --override-reason")
+	cmd.Flags().StringVarP(&options.OverrideReason, "override-reason", "", "", "The reason for overriding a freeze window which is recorded on the PipelineActivity")
+	cmd.Flags().StringVarP(&options.OverrideUser, "override-user", "", "", "The user on whose behalf a freeze window is overridden which is recorded as an annotation on the PipelineActivity as the override is recorded as the current user")
 }
 
 // Run implements this command
 func (o *PromoteOptions) Run() error {
+	if o.Apps != "" || o.BundleFile != "" {
+		err := o.loadReleaseBundle()
+		if err != nil {
+			return err
+		}
+	}
 	app := o.Application
 	if app == "" {
 		args := o.Args
@@ -236,6 +319,27 @@ func (o *PromoteOptions) Run() error {
 		}
 		o.TimeoutDuration = &duration
 	}
+	if o.Canary && o.NoPoll {
+		return fmt.Errorf("the --%s option cannot be used with --no-poll as the promotion waits for the canary analysis", optionCanary)
+	}
+	if o.Verify {
+		if o.NoPoll {
+			return fmt.Errorf("the --%s option cannot be used with --no-poll as the promotion waits for the checks to pass", optionVerify)
+		}
+		if o.NoWaitAfterMerge {
+			return fmt.Errorf("the --%s option cannot be used with --no-wait as the promotion waits for the checks to pass", optionVerify)
+		}
+		if o.PrometheusURL != "" && o.ErrorRateQuery == "" {
+			return util.MissingOption(optionErrorRateQuery)
+		}
+		if o.VerifyTimeout != "" {
+			duration, err := time.ParseDuration(o.VerifyTimeout)
+			if err != nil {
+				return fmt.Errorf("Invalid duration format %s for option --verify-timeout: %s", o.VerifyTimeout, err)
+			}
+			o.verifyTimeoutDuration = &duration
+		}
+	}
 
 	targetNS, env, err := o.GetTargetNamespace(o.Namespace, o.Environment)
 	if err != nil {
@@ -265,7 +369,12 @@ func (o *PromoteOptions) Run() error {
 			return fmt.Errorf("Could not find an Environment called %s", o.Environment)
 		}
 	}
-	releaseInfo, err := o.Promote(targetNS, env, true)
+	var releaseInfo *ReleaseInfo
+	if o.releaseBundle != nil {
+		releaseInfo, err = o.PromoteApps(targetNS, env)
+	} else {
+		releaseInfo, err = o.Promote(targetNS, env, true)
+	}
 	if err != nil {
 		return err
 	}
@@ -374,11 +483,44 @@ func (o *PromoteOptions) Promote(targetNS string, env *v1.Environment, warnIfAut
 		}
 	}
 
+	if env != nil && env.Spec.Kind.IsPermanent() {
+		err := o.checkImageVulnerabilities(env, version)
+		if err != nil {
+			return releaseInfo, err
+		}
+	}
+
 	jxClient, _, err := o.JXClient()
 	if err != nil {
 		return releaseInfo, err
 	}
 	promoteKey := o.CreatePromoteKey(env)
+	if env != nil && env.Spec.Kind.IsPermanent() {
+		err = o.checkFreezeWindows(env, promoteKey)
+		if err != nil {
+			return releaseInfo, err
+		}
+	}
+	if o.Rollback != nil {
+		rollback := o.Rollback
+		markRollback := func(a *v1.PipelineActivity, s *v1.PipelineActivityStep, ps *v1.PromoteActivityStep) error {
+			ps.Rollback = rollback
+			return nil
+		}
+		err = promoteKey.OnPromote(jxClient, o.Namespace, markRollback)
+		if err != nil {
+			log.Logger().Warnf("Failed to mark the PipelineActivity as a rollback: %s", err)
+		}
+	}
+	if env != nil && env.Spec.RequiresApproval() {
+		err = o.waitForApproval(env, version, promoteKey)
+		if err != nil {
+			return releaseInfo, err
+		}
+	}
+	if o.Canary {
+		o.prepareCanary(targetNS, version)
+	}
 	if env != nil {
 		source := &env.Spec.Source
 		if source.URL != "" && env.Spec.Kind.IsPermanent() {
@@ -399,6 +541,7 @@ func (o *PromoteOptions) Promote(targetNS string, env *v1.Environment, warnIfAut
 				if err != nil {
 					log.Logger().Warnf("Failed to update PipelineActivity: %s", err)
 				}
+				o.notifyPromotionPullRequest(env, releaseInfo)
 				// lets sleep a little before we try poll for the PR status
 				time.Sleep(waitAfterPullRequestCreated)
 			}
@@ -450,6 +593,276 @@ func (o *PromoteOptions) Promote(targetNS string, env *v1.Environment, warnIfAut
 	return releaseInfo, err
 }
 
+// checkImageVulnerabilities fails the promotion if the image of the version has vulnerabilities at or above
+// the severity threshold of the command line or the team settings
+func (o *PromoteOptions) checkImageVulnerabilities(env *v1.Environment, version string) error {
+	threshold := o.CVESeverityThreshold
+	if threshold == "" {
+		teamSettings, err := o.TeamSettings()
+		if err != nil {
+			return errors.Wrap(err, "failed to load the team settings")
+		}
+		if teamSettings.CVE != nil {
+			threshold = teamSettings.CVE.PromotionSeverityThreshold
+		}
+	}
+	if threshold == "" || strings.EqualFold(threshold, "none") {
+		return nil
+	}
+	severity, err := cve.ParseSeverity(threshold)
+	if err != nil {
+		return err
+	}
+	image := o.Image
+	if image == "" {
+		if version == "" {
+			log.Logger().Warnf("Cannot check the image of the latest version of %s for vulnerabilities; please specify a --version", o.Application)
+			return nil
+		}
+		image = o.promotedImage(version)
+	}
+	log.Logger().Infof("Checking image %s for vulnerabilities of severity %s or above", util.ColorInfo(image), util.ColorInfo(severity.String()))
+
+	provider, err := o.CreateCVEProvider()
+	if err != nil {
+		return err
+	}
+	vulnerabilities, err := provider.GetImageVulnerabilities(image)
+	if err != nil {
+		return errors.Wrapf(err, "failed to check image %s for vulnerabilities", image)
+	}
+	blocking := cve.FilterBySeverity(vulnerabilities, severity)
+	if len(blocking) == 0 {
+		return nil
+	}
+	table := o.CreateTable()
+	table.AddRow("Image", util.ColorInfo("Severity"), "Vulnerability", "URL", "Package", "Fix")
+	cve.AddVulnerabilityRows(&table, blocking)
+	table.Render()
+	return fmt.Errorf("cannot promote %s to %s as image %s has %d vulnerabilities of severity %s or above", o.Application, env.Name, image, len(blocking), severity.String())
+}
+
+// checkFreezeWindows fails the promotion if the environment is in a freeze window unless the freeze is being
+// overridden in which case the override is recorded on the PipelineActivity
+func (o *PromoteOptions) checkFreezeWindows(env *v1.Environment, promoteKey *kube.PromoteStepActivityKey) error {
+	window, err := workflow.ActiveFreezeWindow(env, time.Now())
+	if err != nil {
+		return err
+	}
+	if window == nil {
+		return nil
+	}
+	description := workflow.FreezeDescription(window)
+	if !o.OverrideFreeze {
+		return fmt.Errorf("cannot promote to Environment %s as it is in the freeze window %s. Use --override-freeze with --override-reason to promote anyway", env.Name, description)
+	}
+	if o.OverrideReason == "" {
+		return util.MissingOption("override-reason")
+	}
+	user, err := o.GetUsername("")
+	if err != nil {
+		return err
+	}
+	if user == "" {
+		return fmt.Errorf("cannot override the freeze window %s of Environment %s as there is no current user. Run jx promote outside of the cluster to override it", description, env.Name)
+	}
+	log.Logger().Warnf("User %s is overriding the freeze window %s of Environment %s: %s", user, description, env.Name, o.OverrideReason)
+
+	jxClient, _, err := o.JXClient()
+	if err != nil {
+		return err
+	}
+	recordOverride := func(a *v1.PipelineActivity, s *v1.PipelineActivityStep, ps *v1.PromoteActivityStep) error {
+		ps.FreezeOverride = &v1.FreezeOverride{
+			FreezeWindow: window.Name,
+			User:         user,
+			Reason:       o.OverrideReason,
+			Timestamp: metav1.Time{
+				Time: time.Now(),
+			},
+		}
+		if o.OverrideUser != "" {
+			if a.Annotations == nil {
+				a.Annotations = map[string]string{}
+			}
+			a.Annotations[kube.AnnotationFreezeOverrideUser] = o.OverrideUser
+		}
+		return nil
+	}
+	err = promoteKey.OnPromote(jxClient, o.Namespace, recordOverride)
+	if err != nil {
+		return errors.Wrap(err, "failed to record the freeze override on the PipelineActivity")
+	}
+	return nil
+}
+
+// waitForApproval creates a PromotionApproval for promoting the version to the environment then waits until
+// enough approvers have approved it, failing the promotion if it is rejected or the approval times out
+func (o *PromoteOptions) waitForApproval(env *v1.Environment, version string, promoteKey *kube.PromoteStepActivityKey) error {
+	jxClient, _, err := o.JXClient()
+	if err != nil {
+		return err
+	}
+	err = o.RegisterPromotionApprovalCRD()
+	if err != nil {
+		return err
+	}
+	settings := env.Spec.Approval
+	timeout := time.Hour
+	if o.TimeoutDuration != nil {
+		timeout = *o.TimeoutDuration
+	}
+	if settings.Timeout != "" {
+		timeout, err = time.ParseDuration(settings.Timeout)
+		if err != nil {
+			return errors.Wrapf(err, "invalid approval timeout %s on Environment %s", settings.Timeout, env.Name)
+		}
+	}
+	pollDuration := 20 * time.Second
+	if o.PullRequestPollDuration != nil {
+		pollDuration = *o.PullRequestPollDuration
+	}
+
+	name := kube.PromotionApprovalName(o.Application, version, env.Name)
+	approvals := jxClient.JenkinsV1().PromotionApprovals(o.Namespace)
+	approval, err := approvals.Get(name, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get the PromotionApproval %s", name)
+		}
+		approval = nil
+	} else if !approval.IsPending() {
+		// lets start again if a previous promotion of this version was approved or rejected
+		err = approvals.Delete(name, &metav1.DeleteOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to delete the previous PromotionApproval %s", name)
+		}
+		approval = nil
+	}
+	if approval == nil {
+		approval, err = approvals.Create(&v1.PromotionApproval{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Spec: v1.PromotionApprovalSpec{
+				Environment:       env.Name,
+				Application:       o.Application,
+				Version:           version,
+				PipelineActivity:  promoteKey.Name,
+				Pipeline:          promoteKey.Pipeline,
+				Build:             promoteKey.Build,
+				RequiredApprovals: settings.RequiredApprovalCount(),
+				RoleBinding:       settings.RoleBinding,
+			},
+			Status: v1.PromotionApprovalStatus{
+				Status: v1.ActivityStatusTypeWaitingForApproval,
+			},
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to create the PromotionApproval %s", name)
+		}
+	}
+
+	startApproval := func(a *v1.PipelineActivity, s *v1.PipelineActivityStep, ps *v1.PromoteActivityStep, p *v1.PromoteApprovalStep) error {
+		kube.StartPromotionApproval(a, s, ps, p)
+		p.PromotionApproval = name
+		p.RequiredApprovals = settings.RequiredApprovalCount()
+		if version != "" && a.Spec.Version == "" {
+			a.Spec.Version = version
+		}
+		return nil
+	}
+	err = promoteKey.OnPromoteApproval(jxClient, o.Namespace, startApproval)
+	if err != nil {
+		log.Logger().Warnf("Failed to update PipelineActivity: %s", err)
+	}
+
+	info := util.ColorInfo
+	log.Logger().Infof("Promotion to %s requires %s approvals. Waiting up to %s for them via: %s", info(env.Name), info(strconv.Itoa(int(settings.RequiredApprovalCount()))), timeout.String(), info("jx approve "+name))
+	end := time.Now().Add(timeout)
+	for approval.IsPending() {
+		if time.Now().After(end) {
+			approval.Status.Status = v1.ActivityStatusTypeAborted
+			_, err = approvals.PatchUpdate(approval)
+			if err != nil {
+				log.Logger().Warnf("Failed to update PromotionApproval %s: %s", name, err)
+			}
+			break
+		}
+		time.Sleep(pollDuration)
+		approval, err = approvals.Get(name, metav1.GetOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to get the PromotionApproval %s", name)
+		}
+	}
+
+	decisions := approval.Status.Decisions
+	approved := approval.Status.Status == v1.ActivityStatusTypeSucceeded
+	updateApproval := func(a *v1.PipelineActivity, s *v1.PipelineActivityStep, ps *v1.PromoteActivityStep, p *v1.PromoteApprovalStep) error {
+		p.Decisions = decisions
+		if approved {
+			return kube.CompletePromotionApproval(a, s, ps, p)
+		}
+		return kube.RejectedPromotionApproval(a, s, ps, p)
+	}
+	err = promoteKey.OnPromoteApproval(jxClient, o.Namespace, updateApproval)
+	if err != nil {
+		log.Logger().Warnf("Failed to update PipelineActivity: %s", err)
+	}
+	if !approved {
+		rejectors := []string{}
+		for _, d := range decisions {
+			if !d.Approved {
+				rejectors = append(rejectors, d.User)
+			}
+		}
+		if len(rejectors) == 0 {
+			return fmt.Errorf("the promotion of %s version %s to %s was not approved within %s", o.Application, version, env.Name, timeout.String())
+		}
+		return fmt.Errorf("the promotion of %s version %s to %s was rejected by %s", o.Application, version, env.Name, strings.Join(rejectors, ", "))
+	}
+	log.Logger().Infof("Promotion to %s has been approved", info(env.Name))
+	return nil
+}
+
+// promotedImage returns the image of the version of the application in the docker registry of the team
+func (o *PromoteOptions) promotedImage(version string) string {
+	projectConfig, _, err := config.LoadProjectConfig("")
+	if err != nil {
+		log.Logger().Debugf("failed to load the project configuration: %s", err)
+		projectConfig = nil
+	}
+	paths := []string{}
+	for _, p := range []string{o.GetDockerRegistry(projectConfig), o.GetDockerRegistryOrg(projectConfig, o.GitInfo), o.Application} {
+		if p != "" {
+			paths = append(paths, p)
+		}
+	}
+	return strings.Join(paths, "/") + ":" + version
+}
+
+// notifyPromotionPullRequest posts a message to the developer chat channel of the project that the promotion pull request has been created
+func (o *PromoteOptions) notifyPromotionPullRequest(env *v1.Environment, releaseInfo *ReleaseInfo) {
+	pr := releaseInfo.PullRequestInfo
+	if pr == nil || pr.PullRequest == nil {
+		return
+	}
+	projectConfig, _, err := config.LoadProjectConfig("")
+	if err != nil {
+		log.Logger().Warnf("Failed to load the project configuration: %s", err)
+		return
+	}
+	version := o.Version
+	if version == "" {
+		version = "latest"
+	}
+	message := chats.PromotionPullRequestMessage(o.Application, version, env.Spec.Label, pr.PullRequest.URL)
+	_, err = o.NotifyDeveloperChannel(projectConfig.Chat, message)
+	if err != nil {
+		log.Logger().Warnf("Failed to notify the developer chat channel of the promotion pull request: %s", err)
+	}
+}
+
 func (o *PromoteOptions) PromoteViaPullRequest(env *v1.Environment, releaseInfo *ReleaseInfo) error {
 	version := o.Version
 	versionName := version
@@ -463,6 +876,16 @@ func (o *PromoteOptions) PromoteViaPullRequest(env *v1.Environment, releaseInfo
 		Title:      app + " to " + versionName,
 		Message:    fmt.Sprintf("Promote %s to version %s", app, versionName),
 	}
+	if o.Rollback != nil {
+		details = gits.PullRequestDetails{
+			BranchName: "rollback-" + app + "-" + versionName,
+			Title:      "rollback " + app + " to " + versionName,
+			Message:    fmt.Sprintf("Rollback %s from version %s to version %s", app, o.Rollback.FromVersion, versionName),
+		}
+		if o.Rollback.Reason != "" {
+			details.Message += "\n\n" + o.Rollback.Reason
+		}
+	}
 
 	modifyChartFn := func(requirements *helm.Requirements, metadata *chart.Metadata, values map[string]interface{},
 		templates map[string]string, dir string, details *gits.PullRequestDetails) error {
@@ -563,10 +986,9 @@ func (o *PromoteOptions) WaitForPromotion(ns string, env *v1.Environment, releas
 	if err != nil {
 		return errors.Wrap(err, "Getting jx client")
 	}
+	promoteKey := o.CreatePromoteKey(env)
 	pullRequestInfo := releaseInfo.PullRequestInfo
 	if pullRequestInfo != nil {
-		promoteKey := o.CreatePromoteKey(env)
-
 		err := o.waitForGitOpsPullRequest(ns, env, releaseInfo, end, duration, promoteKey)
 		if err != nil {
 			// TODO based on if the PR completed or not fail the PR or the Promote?
@@ -574,6 +996,18 @@ func (o *PromoteOptions) WaitForPromotion(ns string, env *v1.Environment, releas
 			return err
 		}
 	}
+	if o.NoWaitAfterMerge {
+		return nil
+	}
+	if o.Canary {
+		err = o.waitForCanary(ns, env, releaseInfo, end, promoteKey)
+		if err != nil {
+			return err
+		}
+	}
+	if o.Verify {
+		return o.verifyPromotion(ns, releaseInfo, promoteKey)
+	}
 	return nil
 }
 
@@ -630,7 +1064,7 @@ func (o *PromoteOptions) waitForGitOpsPullRequest(ns string, env *v1.Environment
 
 						if o.NoWaitForUpdatePipeline {
 							log.Logger().Info("Pull Request merged but we are not waiting for the update pipeline to complete!")
-							err = o.CommentOnIssues(ns, env, promoteKey)
+							err = o.commentOnPromotedIssues(ns, env, promoteKey)
 							if err == nil {
 								err = promoteKey.OnPromoteUpdate(jxClient, o.Namespace, kube.CompletePromotionUpdate)
 							}
@@ -695,7 +1129,7 @@ func (o *PromoteOptions) waitForGitOpsPullRequest(ns string, env *v1.Environment
 								}
 								if succeeded {
 									log.Logger().Info("Merge status checks all passed so the promotion worked!")
-									err = o.CommentOnIssues(ns, env, promoteKey)
+									err = o.commentOnPromotedIssues(ns, env, promoteKey)
 									if err == nil {
 										err = promoteKey.OnPromoteUpdate(jxClient, o.Namespace, kube.CompletePromotionUpdate)
 									}
@@ -736,7 +1170,11 @@ func (o *PromoteOptions) waitForGitOpsPullRequest(ns string, env *v1.Environment
 				if pr.Mergeable != nil && !*pr.Mergeable {
 					log.Logger().Info("Rebasing PullRequest due to conflict")
 
-					err = o.PromoteViaPullRequest(env, releaseInfo)
+					if o.releaseBundle != nil {
+						err = o.promoteAppsViaPullRequest(env, releaseInfo)
+					} else {
+						err = o.PromoteViaPullRequest(env, releaseInfo)
+					}
 					if releaseInfo.PullRequestInfo != nil {
 						pullRequestInfo = releaseInfo.PullRequestInfo
 					}
@@ -949,16 +1387,9 @@ func (o *PromoteOptions) CommentOnIssues(targetNS string, environment *v1.Enviro
 	if err != nil {
 		return err
 	}
-	appNames := []string{app, o.ReleaseName, ens + "-" + app}
-	url := ""
-	for _, n := range appNames {
-		url, err = services.FindServiceURL(kubeClient, ens, n)
-		if url != "" {
-			break
-		}
-	}
+	url := o.findServiceURL(kubeClient, ens, app)
 	if url == "" {
-		log.Logger().Warnf("Could not find the service URL in namespace %s for names %s", ens, strings.Join(appNames, ", "))
+		log.Logger().Warnf("Could not find the service URL of %s in namespace %s", app, ens)
 	}
 	available := ""
 	if url != "" {
@@ -1068,3 +1499,15 @@ func (o *PromoteOptions) SearchForChart(filter string) (string, error) {
 	o.HelmRepositoryURL = repoUrl
 	return appName, nil
 }
+
+// findServiceURL returns the URL of the service of the app in the namespace or blank if it cannot be found
+func (o *PromoteOptions) findServiceURL(kubeClient kubernetes.Interface, ns string, app string) string {
+	appNames := []string{app, o.ReleaseName, ns + "-" + app}
+	for _, n := range appNames {
+		url, _ := services.FindServiceURL(kubeClient, ns, n)
+		if url != "" {
+			return url
+		}
+	}
+	return ""
+}
@@ -0,0 +1,352 @@
+package promote
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/pkg/environments"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+const (
+	optionApps   = "apps"
+	optionBundle = "bundle"
+
+	defaultBundleName = "bundle"
+)
+
+// AppVersion is a version of an application which is promoted as part of a release bundle
+type AppVersion struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Alias   string `json:"alias,omitempty"`
+}
+
+// ReleaseBundle is a set of versions of applications which are promoted to an environment together
+type ReleaseBundle struct {
+	Name    string       `json:"name,omitempty"`
+	Version string       `json:"version,omitempty"`
+	Apps    []AppVersion `json:"apps"`
+}
+
+// ParseAppVersions parses a comma separated list of applications and their versions of the form 'app:version'
+func ParseAppVersions(text string) ([]AppVersion, error) {
+	answer := []AppVersion{}
+	for _, entry := range strings.Split(text, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("the application %s is not of the form name:version", entry)
+		}
+		answer = append(answer, AppVersion{
+			Name:    strings.TrimSpace(parts[0]),
+			Version: strings.TrimSpace(parts[1]),
+		})
+	}
+	return answer, ValidateAppVersions(answer)
+}
+
+// LoadReleaseBundle loads a release bundle from a YAML file
+func LoadReleaseBundle(fileName string) (*ReleaseBundle, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read release bundle %s", fileName)
+	}
+	bundle := &ReleaseBundle{}
+	err = yaml.Unmarshal(data, bundle)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse release bundle %s", fileName)
+	}
+	err = ValidateAppVersions(bundle.Apps)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid release bundle %s", fileName)
+	}
+	return bundle, nil
+}
+
+// ValidateAppVersions returns an error unless there is at least one application, each with a name and a version
+// and no application appears more than once
+func ValidateAppVersions(apps []AppVersion) error {
+	if len(apps) == 0 {
+		return fmt.Errorf("no applications specified")
+	}
+	names := map[string]bool{}
+	for _, app := range apps {
+		if app.Name == "" {
+			return fmt.Errorf("an application has no name")
+		}
+		if app.Version == "" {
+			return fmt.Errorf("the application %s has no version", app.Name)
+		}
+		if names[app.Name] {
+			return fmt.Errorf("the application %s is specified more than once", app.Name)
+		}
+		names[app.Name] = true
+	}
+	return nil
+}
+
+// BundleVersion returns the version of the bundle or, if it has none, a short digest of the versions of its
+// applications so that promoting the same versions again results in the same version
+func BundleVersion(bundle *ReleaseBundle) string {
+	if bundle.Version != "" {
+		return bundle.Version
+	}
+	entries := []string{}
+	for _, app := range bundle.Apps {
+		entries = append(entries, app.Name+":"+app.Version)
+	}
+	sort.Strings(entries)
+	digest := sha256.Sum256([]byte(strings.Join(entries, ",")))
+	return fmt.Sprintf("%x", digest[:4])
+}
+
+// describeAppVersions returns a human readable list of the versions of the applications
+func describeAppVersions(apps []AppVersion) string {
+	entries := []string{}
+	for _, app := range apps {
+		entries = append(entries, app.Name+" "+app.Version)
+	}
+	return strings.Join(entries, ", ")
+}
+
+// loadReleaseBundle loads the applications to promote from the --apps or --bundle options
+func (o *PromoteOptions) loadReleaseBundle() error {
+	if o.Apps != "" && o.BundleFile != "" {
+		return fmt.Errorf("the --%s and --%s options cannot be used together", optionApps, optionBundle)
+	}
+	conflicts := []struct {
+		option string
+		set    bool
+	}{
+		{opts.OptionApplication, o.Application != "" || len(o.Args) > 0},
+		{"version", o.Version != ""},
+		{"filter", o.Filter != ""},
+		{"alias", o.Alias != ""},
+		{"image", o.Image != ""},
+		{"all-auto", o.AllAutomatic},
+		{optionCanary, o.Canary},
+		{optionVerify, o.Verify},
+	}
+	for _, c := range conflicts {
+		if c.set {
+			return fmt.Errorf("the --%s option cannot be used when promoting several applications with --%s or --%s", c.option, optionApps, optionBundle)
+		}
+	}
+	if o.BundleFile != "" {
+		bundle, err := LoadReleaseBundle(o.BundleFile)
+		if err != nil {
+			return err
+		}
+		o.releaseBundle = bundle
+	} else {
+		apps, err := ParseAppVersions(o.Apps)
+		if err != nil {
+			return util.InvalidOptionError(optionApps, o.Apps, err)
+		}
+		o.releaseBundle = &ReleaseBundle{Apps: apps}
+	}
+	if o.releaseBundle.Name == "" {
+		o.releaseBundle.Name = defaultBundleName
+	}
+	// the bundle is promoted as a single application so that it is tracked by a single PipelineActivity
+	o.Application = o.releaseBundle.Name
+	o.Version = BundleVersion(o.releaseBundle)
+	return nil
+}
+
+// PromoteApps promotes all the applications of the release bundle to the environment via a single Pull Request.
+// All the checks of every application are performed before the Pull Request is created so that either all or none
+// of the applications are promoted
+func (o *PromoteOptions) PromoteApps(targetNS string, env *v1.Environment) (*ReleaseInfo, error) {
+	bundle := o.releaseBundle
+	if env == nil || env.Spec.Source.URL == "" || !env.Spec.Kind.IsPermanent() {
+		return nil, fmt.Errorf("cannot promote several applications together to namespace %s as it is not a permanent Environment with a git repository", targetNS)
+	}
+	info := util.ColorInfo
+	log.Logger().Infof("Promoting %s to namespace %s", info(describeAppVersions(bundle.Apps)), info(targetNS))
+	releaseInfo := &ReleaseInfo{
+		ReleaseName: o.ReleaseName,
+		FullAppName: bundle.Name,
+		Version:     o.Version,
+	}
+
+	err := o.checkAppVersionsExist(bundle.Apps)
+	if err != nil {
+		return releaseInfo, err
+	}
+	for _, app := range bundle.Apps {
+		err = o.appOptions(app).checkImageVulnerabilities(env, app.Version)
+		if err != nil {
+			return releaseInfo, err
+		}
+	}
+
+	jxClient, _, err := o.JXClient()
+	if err != nil {
+		return releaseInfo, err
+	}
+	promoteKey := o.CreatePromoteKey(env)
+	err = o.checkFreezeWindows(env, promoteKey)
+	if err != nil {
+		return releaseInfo, err
+	}
+	applications := []v1.PromoteApplication{}
+	for _, app := range bundle.Apps {
+		applications = append(applications, v1.PromoteApplication{Name: app.Name, Version: app.Version})
+	}
+	recordApplications := func(a *v1.PipelineActivity, s *v1.PipelineActivityStep, ps *v1.PromoteActivityStep) error {
+		ps.Applications = applications
+		return nil
+	}
+	err = promoteKey.OnPromote(jxClient, o.Namespace, recordApplications)
+	if err != nil {
+		log.Logger().Warnf("Failed to record the applications on the PipelineActivity: %s", err)
+	}
+	if env.Spec.RequiresApproval() {
+		err = o.waitForApproval(env, o.Version, promoteKey)
+		if err != nil {
+			return releaseInfo, err
+		}
+	}
+
+	err = o.promoteAppsViaPullRequest(env, releaseInfo)
+	if err != nil {
+		return releaseInfo, err
+	}
+	startPromotePR := func(a *v1.PipelineActivity, s *v1.PipelineActivityStep, ps *v1.PromoteActivityStep, p *v1.PromotePullRequestStep) error {
+		kube.StartPromotionPullRequest(a, s, ps, p)
+		pr := releaseInfo.PullRequestInfo
+		if pr != nil && pr.PullRequest != nil && p.PullRequestURL == "" {
+			p.PullRequestURL = pr.PullRequest.URL
+		}
+		if a.Spec.Version == "" {
+			a.Spec.Version = o.Version
+		}
+		return nil
+	}
+	err = promoteKey.OnPromotePullRequest(jxClient, o.Namespace, startPromotePR)
+	if err != nil {
+		log.Logger().Warnf("Failed to update PipelineActivity: %s", err)
+	}
+	o.notifyPromotionPullRequest(env, releaseInfo)
+	return releaseInfo, nil
+}
+
+// checkAppVersionsExist returns an error if the chart version of any application cannot be found
+func (o *PromoteOptions) checkAppVersionsExist(apps []AppVersion) error {
+	err := o.verifyHelmConfigured()
+	if err != nil {
+		return err
+	}
+	if !o.NoHelmUpdate {
+		log.Logger().Info("Updating the helm repositories to ensure we can find the versions to promote...")
+		err = o.Helm().UpdateRepo()
+		if err != nil {
+			return err
+		}
+	}
+	missing := []string{}
+	for _, app := range apps {
+		chartName := app.Name
+		if o.LocalHelmRepoName != "" {
+			chartName = o.LocalHelmRepoName + "/" + app.Name
+		}
+		versions, err := o.Helm().SearchChartVersions(chartName)
+		if err != nil {
+			return errors.Wrapf(err, "failed to find the versions of chart %s", chartName)
+		}
+		if util.StringArrayIndex(versions, app.Version) < 0 {
+			missing = append(missing, app.Name+" "+app.Version)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("cannot promote any of the applications as these versions could not be found: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// promoteAppsViaPullRequest creates a single Pull Request on the environment which updates the versions of all
+// the applications of the release bundle
+func (o *PromoteOptions) promoteAppsViaPullRequest(env *v1.Environment, releaseInfo *ReleaseInfo) error {
+	bundle := o.releaseBundle
+	versionName := o.Version
+	details := gits.PullRequestDetails{
+		BranchName: "promote-" + bundle.Name + "-" + versionName,
+		Title:      bundle.Name + " to " + versionName,
+		Message:    fmt.Sprintf("Promote %s:\n", bundle.Name),
+	}
+	for _, app := range bundle.Apps {
+		details.Message += fmt.Sprintf("\n* %s to version %s", app.Name, app.Version)
+	}
+
+	modifyChartFn := func(requirements *helm.Requirements, metadata *chart.Metadata, values map[string]interface{},
+		templates map[string]string, dir string, details *gits.PullRequestDetails) error {
+		for _, app := range bundle.Apps {
+			requirements.SetAppVersion(app.Name, app.Version, o.HelmRepositoryURL, app.Alias)
+		}
+		return nil
+	}
+	gitProvider, _, err := o.CreateGitProviderForURLWithoutKind(env.Spec.Source.URL)
+	if err != nil {
+		return errors.Wrapf(err, "creating git provider for %s", env.Spec.Source.URL)
+	}
+	environmentsDir, err := o.EnvironmentsDir()
+	if err != nil {
+		return errors.Wrapf(err, "getting environments dir")
+	}
+
+	options := environments.EnvironmentPullRequestOptions{
+		ConfigGitFn:   o.ConfigureGitCallback,
+		Gitter:        o.Git(),
+		ModifyChartFn: modifyChartFn,
+		GitProvider:   gitProvider,
+	}
+	filter := &gits.PullRequestFilter{}
+	if releaseInfo.PullRequestInfo != nil && releaseInfo.PullRequestInfo.PullRequest != nil {
+		filter.Number = releaseInfo.PullRequestInfo.PullRequest.Number
+	}
+	info, err := options.Create(env, environmentsDir, &details, filter, "", false)
+	releaseInfo.PullRequestInfo = info
+	return err
+}
+
+// appOptions returns a copy of the options for a single application of the release bundle
+func (o *PromoteOptions) appOptions(app AppVersion) *PromoteOptions {
+	answer := *o
+	answer.Application = app.Name
+	answer.Version = app.Version
+	answer.Alias = app.Alias
+	answer.ReleaseName = ""
+	answer.releaseBundle = nil
+	return &answer
+}
+
+// commentOnPromotedIssues comments on the issues of each promoted application
+func (o *PromoteOptions) commentOnPromotedIssues(targetNS string, env *v1.Environment, promoteKey *kube.PromoteStepActivityKey) error {
+	if o.releaseBundle == nil {
+		return o.CommentOnIssues(targetNS, env, promoteKey)
+	}
+	for _, app := range o.releaseBundle.Apps {
+		err := o.appOptions(app).CommentOnIssues(targetNS, env, promoteKey)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
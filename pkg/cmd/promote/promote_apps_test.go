@@ -0,0 +1,90 @@
+package promote_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/cmd/promote"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAppVersions(t *testing.T) {
+	t.Parallel()
+
+	apps, err := promote.ParseAppVersions("frontend:1.2.0, backend:3.4.1")
+	require.NoError(t, err)
+	assert.Equal(t, []promote.AppVersion{
+		{Name: "frontend", Version: "1.2.0"},
+		{Name: "backend", Version: "3.4.1"},
+	}, apps)
+
+	for _, text := range []string{"", "frontend", "frontend:", "frontend:1.2.0,frontend:1.3.0"} {
+		_, err = promote.ParseAppVersions(text)
+		assert.Error(t, err, "parsing %s", text)
+	}
+}
+
+func TestLoadReleaseBundle(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "test-release-bundle-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	fileName := filepath.Join(dir, "release.yaml")
+	err = ioutil.WriteFile(fileName, []byte(`name: shop
+apps:
+- name: frontend
+  version: 1.2.0
+- name: backend
+  version: 3.4.1
+  alias: api
+`), 0600)
+	require.NoError(t, err)
+
+	bundle, err := promote.LoadReleaseBundle(fileName)
+	require.NoError(t, err)
+	assert.Equal(t, "shop", bundle.Name)
+	assert.Equal(t, []promote.AppVersion{
+		{Name: "frontend", Version: "1.2.0"},
+		{Name: "backend", Version: "3.4.1", Alias: "api"},
+	}, bundle.Apps)
+
+	err = ioutil.WriteFile(fileName, []byte("apps:\n- name: frontend\n"), 0600)
+	require.NoError(t, err)
+	_, err = promote.LoadReleaseBundle(fileName)
+	assert.Error(t, err)
+}
+
+func TestBundleVersion(t *testing.T) {
+	t.Parallel()
+
+	bundle := &promote.ReleaseBundle{
+		Apps: []promote.AppVersion{
+			{Name: "frontend", Version: "1.2.0"},
+			{Name: "backend", Version: "3.4.1"},
+		},
+	}
+	reordered := &promote.ReleaseBundle{
+		Apps: []promote.AppVersion{
+			{Name: "backend", Version: "3.4.1"},
+			{Name: "frontend", Version: "1.2.0"},
+		},
+	}
+	changed := &promote.ReleaseBundle{
+		Apps: []promote.AppVersion{
+			{Name: "frontend", Version: "1.2.1"},
+			{Name: "backend", Version: "3.4.1"},
+		},
+	}
+	version := promote.BundleVersion(bundle)
+	assert.Len(t, version, 8)
+	assert.Equal(t, version, promote.BundleVersion(reordered))
+	assert.NotEqual(t, version, promote.BundleVersion(changed))
+
+	bundle.Version = "2019.8.1"
+	assert.Equal(t, "2019.8.1", promote.BundleVersion(bundle))
+}
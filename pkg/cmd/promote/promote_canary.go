@@ -0,0 +1,238 @@
+package promote
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/flagger"
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// prepareCanary records the time the promotion started, so that the result of the canary analysis of an earlier
+// revision is ignored, and the version of the application to roll back to if the canary is aborted
+func (o *PromoteOptions) prepareCanary(targetNS string, version string) {
+	o.canaryStart = time.Now()
+	o.canaryPreviousVersion = ""
+	if o.NoCanaryRollback {
+		return
+	}
+	jxClient, _, err := o.JXClient()
+	if err != nil {
+		log.Logger().Warnf("Failed to create the jx client so cannot find the version to roll back to: %s", err)
+		return
+	}
+	releases, err := jxClient.JenkinsV1().Releases(targetNS).List(metav1.ListOptions{})
+	if err != nil {
+		log.Logger().Warnf("Failed to list the Releases in namespace %s so cannot find the version to roll back to: %s", targetNS, err)
+		return
+	}
+	o.canaryPreviousVersion = PreviousReleaseVersion(releases.Items, o.Application, version)
+}
+
+// PreviousReleaseVersion returns the version of the newest Release of the app which did not fail and is not the
+// given version or blank if there is none
+func PreviousReleaseVersion(releases []v1.Release, app string, version string) string {
+	sorted := []v1.Release{}
+	for _, r := range releases {
+		if r.Spec.Name == app && r.Spec.Version != "" && r.Spec.Version != version && r.Status.Status != v1.ReleaseStatusTypeFailed {
+			sorted = append(sorted, r)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[j].CreationTimestamp.Before(&sorted[i].CreationTimestamp)
+	})
+	if len(sorted) == 0 {
+		return ""
+	}
+	return sorted[0].Spec.Version
+}
+
+// waitForCanary waits for the Flagger Canary of the application to complete the analysis of the promoted version,
+// recording its progress on the PipelineActivity. If the canary is aborted the promotion fails and is rolled back
+func (o *PromoteOptions) waitForCanary(ns string, env *v1.Environment, releaseInfo *ReleaseInfo, end time.Time, promoteKey *kube.PromoteStepActivityKey) error {
+	kubeClient, err := o.KubeClient()
+	if err != nil {
+		return errors.Wrap(err, "Getting kube client")
+	}
+	jxClient, _, err := o.JXClient()
+	if err != nil {
+		return errors.Wrap(err, "Getting jx client")
+	}
+	app := o.Application
+	log.Logger().Infof("Waiting for the canary analysis of %s in namespace %s", util.ColorInfo(app), util.ColorInfo(ns))
+	err = promoteKey.OnPromoteCanary(jxClient, o.Namespace, kube.StartPromotionCanary)
+	if err != nil {
+		log.Logger().Warnf("Failed to update PipelineActivity: %s", err)
+	}
+
+	logNoCanary := false
+	lastStatus := ""
+	for {
+		canary, err := o.findCanary(kubeClient, ns, app)
+		if err != nil {
+			if !logNoCanary {
+				logNoCanary = true
+				log.Logger().Warnf("Failed to find the Flagger Canary of %s, is Flagger installed via 'jx create addon flagger'? %s", app, err)
+			}
+		} else if canary == nil {
+			if !logNoCanary {
+				logNoCanary = true
+				log.Logger().Infof("No Flagger Canary found for %s in namespace %s yet", util.ColorInfo(app), util.ColorInfo(ns))
+			}
+		} else {
+			status := fmt.Sprintf("%s weight %d%% failed checks %d/%d", canary.Status.Phase, canary.Status.CanaryWeight,
+				canary.Status.FailedChecks, canary.Spec.CanaryAnalysis.Threshold)
+			if status != lastStatus {
+				lastStatus = status
+				log.Logger().Infof("Canary %s is %s", util.ColorInfo(canary.Name), util.ColorInfo(status))
+			}
+			updateCanary := func(a *v1.PipelineActivity, s *v1.PipelineActivityStep, ps *v1.PromoteActivityStep, p *v1.PromoteCanaryStep) error {
+				flagger.UpdatePromoteCanaryStep(canary, p)
+				return nil
+			}
+			err = promoteKey.OnPromoteCanary(jxClient, o.Namespace, updateCanary)
+			if err != nil {
+				log.Logger().Warnf("Failed to update PipelineActivity: %s", err)
+			}
+
+			if canary.HasCompletedSince(o.canaryStart) {
+				if canary.IsFailed() {
+					return o.failCanary(ns, env, releaseInfo, canary, promoteKey)
+				}
+				log.Logger().Infof("Canary analysis of %s succeeded so the promotion worked!", util.ColorInfo(app))
+				return promoteKey.OnPromoteCanary(jxClient, o.Namespace, kube.CompletePromotionCanary)
+			}
+		}
+		if time.Now().After(end) {
+			err = promoteKey.OnPromoteCanary(jxClient, o.Namespace, kube.FailedPromotionCanary)
+			if err != nil {
+				log.Logger().Warnf("Failed to update PipelineActivity: %s", err)
+			}
+			return fmt.Errorf("Timed out waiting for the canary analysis of %s in namespace %s", app, ns)
+		}
+		time.Sleep(*o.PullRequestPollDuration)
+	}
+}
+
+// findCanary returns the Flagger Canary of the application or nil if it has not been created yet
+func (o *PromoteOptions) findCanary(kubeClient kubernetes.Interface, ns string, app string) (*flagger.Canary, error) {
+	if o.CanaryName != "" {
+		return flagger.GetCanary(kubeClient, ns, o.CanaryName)
+	}
+	canaries, err := flagger.ListCanaries(kubeClient, ns)
+	if err != nil {
+		return nil, err
+	}
+	return flagger.FindCanaryForApp(canaries, app), nil
+}
+
+// failCanary fails the promotion as the canary was aborted. Flagger has already routed all traffic back to the
+// previous version so the Environment is rolled back to it too, unless disabled
+func (o *PromoteOptions) failCanary(ns string, env *v1.Environment, releaseInfo *ReleaseInfo, canary *flagger.Canary, promoteKey *kube.PromoteStepActivityKey) error {
+	jxClient, _, err := o.JXClient()
+	if err != nil {
+		return errors.Wrap(err, "Getting jx client")
+	}
+	version := releaseInfo.Version
+	reason := fmt.Sprintf("Canary analysis of %s version %s failed after %d failed checks", o.Application, version, canary.Status.FailedChecks)
+	if message := canary.Message(); message != "" {
+		reason += ": " + message
+	}
+	log.Logger().Warn(reason)
+
+	previousVersion := o.canaryPreviousVersion
+	var rollback *v1.PromoteRollback
+	if !o.NoCanaryRollback && previousVersion != "" {
+		user, err := o.GetUsername("")
+		if err != nil {
+			log.Logger().Warnf("Failed to find the current user rolling back %s: %s", o.Application, err)
+		}
+		rollback = &v1.PromoteRollback{
+			FromVersion: version,
+			ToVersion:   previousVersion,
+			User:        user,
+			Reason:      reason,
+		}
+	}
+	failed := func(a *v1.PipelineActivity, s *v1.PipelineActivityStep, ps *v1.PromoteActivityStep, p *v1.PromoteCanaryStep) error {
+		flagger.UpdatePromoteCanaryStep(canary, p)
+		if rollback != nil {
+			ps.Rollback = rollback
+		}
+		return kube.FailedPromotionCanary(a, s, ps, p)
+	}
+	err = promoteKey.OnPromoteCanary(jxClient, o.Namespace, failed)
+	if err != nil {
+		log.Logger().Warnf("Failed to update PipelineActivity: %s", err)
+	}
+
+	if rollback == nil {
+		if !o.NoCanaryRollback {
+			log.Logger().Warnf("No previous version of %s found so not rolling back the Environment", o.Application)
+		}
+		return errors.New(reason)
+	}
+	err = o.rollbackCanary(ns, env, releaseInfo, rollback, promoteKey)
+	if err != nil {
+		return errors.Wrapf(err, "%s and failed to roll back to version %s", reason, previousVersion)
+	}
+	return errors.New(reason)
+}
+
+// rollbackCanary promotes the previous version of the application so that the Environment matches the version
+// Flagger routes traffic to, waiting for the Pull Request of the rollback like any other promotion
+func (o *PromoteOptions) rollbackCanary(ns string, env *v1.Environment, releaseInfo *ReleaseInfo, rollback *v1.PromoteRollback, promoteKey *kube.PromoteStepActivityKey) error {
+	log.Logger().Infof("Rolling back %s in namespace %s to version %s", util.ColorInfo(o.Application), util.ColorInfo(ns), util.ColorInfo(rollback.ToVersion))
+	rollbackOptions := *o
+	rollbackOptions.Version = rollback.ToVersion
+	rollbackOptions.Rollback = rollback
+	rollbackOptions.Canary = false
+	rollbackInfo := &ReleaseInfo{
+		ReleaseName: releaseInfo.ReleaseName,
+		FullAppName: releaseInfo.FullAppName,
+		Version:     rollback.ToVersion,
+	}
+	if env != nil && env.Spec.Source.URL != "" && env.Spec.Kind.IsPermanent() {
+		err := rollbackOptions.PromoteViaPullRequest(env, rollbackInfo)
+		if err != nil {
+			return err
+		}
+		if rollbackInfo.PullRequestInfo != nil && rollbackInfo.PullRequestInfo.PullRequest != nil {
+			log.Logger().Infof("Created Pull Request %s to roll back the Environment", util.ColorInfo(rollbackInfo.PullRequestInfo.PullRequest.URL))
+		}
+		err = rollbackOptions.WaitForPromotion(ns, env, rollbackInfo)
+
+		// waiting for the Pull Request completes the promotion so mark it as failed again as the canary was aborted
+		jxClient, _, jxErr := o.JXClient()
+		if jxErr != nil {
+			return errors.Wrap(jxErr, "Getting jx client")
+		}
+		failed := func(a *v1.PipelineActivity, s *v1.PipelineActivityStep, ps *v1.PromoteActivityStep) error {
+			return kube.FailedPromote(ps)
+		}
+		if activityErr := promoteKey.OnPromote(jxClient, o.Namespace, failed); activityErr != nil {
+			log.Logger().Warnf("Failed to update PipelineActivity: %s", activityErr)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "waiting for the rollback of the Environment %s", env.Name)
+		}
+		log.Logger().Infof("Rolled back %s in namespace %s to version %s", util.ColorInfo(o.Application), util.ColorInfo(ns), util.ColorInfo(rollback.ToVersion))
+		return nil
+	}
+	return o.InstallChartWithOptions(helm.InstallChartOptions{
+		Chart:       rollbackInfo.FullAppName,
+		ReleaseName: rollbackInfo.ReleaseName,
+		Ns:          ns,
+		Version:     rollbackInfo.Version,
+		NoForce:     true,
+		Wait:        true,
+	})
+}
@@ -0,0 +1,42 @@
+package promote_test
+
+import (
+	"testing"
+	"time"
+
+	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/cmd/promote"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPreviousReleaseVersion(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	release := func(app string, version string, age time.Duration, status v1.ReleaseStatusType) v1.Release {
+		return v1.Release{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              app + "-" + version,
+				CreationTimestamp: metav1.NewTime(now.Add(-age)),
+			},
+			Spec: v1.ReleaseSpec{
+				Name:    app,
+				Version: version,
+			},
+			Status: v1.ReleaseStatus{
+				Status: status,
+			},
+		}
+	}
+	releases := []v1.Release{
+		release("myapp", "1.0.0", 3*time.Hour, v1.ReleaseStatusTypeDeployed),
+		release("myapp", "1.1.0", 2*time.Hour, v1.ReleaseStatusTypeDeployed),
+		release("myapp", "1.2.0", time.Hour, v1.ReleaseStatusTypeFailed),
+		release("other", "2.0.0", time.Minute, v1.ReleaseStatusTypeDeployed),
+	}
+
+	assert.Equal(t, "1.1.0", promote.PreviousReleaseVersion(releases, "myapp", "1.3.0"))
+	assert.Equal(t, "1.0.0", promote.PreviousReleaseVersion(releases, "myapp", "1.1.0"))
+	assert.Equal(t, "", promote.PreviousReleaseVersion(releases, "missing", "1.0.0"))
+}
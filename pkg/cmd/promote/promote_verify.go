@@ -0,0 +1,324 @@
+package promote
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	optionVerify         = "verify"
+	optionPrometheusURL  = "prometheus-url"
+	optionErrorRateQuery = "error-rate-query"
+
+	verifyHTTPTimeout = time.Second * 10
+)
+
+// verifyPromotion waits for the rollout of the Deployment of the application, the HTTP smoke checks and the error
+// rate check to pass, recording the outcome of each check on the PipelineActivity. The promotion fails if the checks
+// do not all pass within the verify timeout
+func (o *PromoteOptions) verifyPromotion(ns string, releaseInfo *ReleaseInfo, promoteKey *kube.PromoteStepActivityKey) error {
+	kubeClient, err := o.KubeClient()
+	if err != nil {
+		return errors.Wrap(err, "Getting kube client")
+	}
+	jxClient, _, err := o.JXClient()
+	if err != nil {
+		return errors.Wrap(err, "Getting jx client")
+	}
+	app := o.Application
+	log.Logger().Infof("Verifying %s is healthy in namespace %s", util.ColorInfo(app), util.ColorInfo(ns))
+	err = promoteKey.OnPromoteVerify(jxClient, o.Namespace, kube.StartPromotionVerify)
+	if err != nil {
+		log.Logger().Warnf("Failed to update PipelineActivity: %s", err)
+	}
+
+	timeout := time.Minute * 5
+	if o.verifyTimeoutDuration != nil {
+		timeout = *o.verifyTimeoutDuration
+	}
+	end := time.Now().Add(timeout)
+	httpClient := util.GetClientWithTimeout(verifyHTTPTimeout)
+	lastMessages := map[string]string{}
+	for {
+		checks, err := o.runVerifyChecks(kubeClient, httpClient, ns, releaseInfo.Version, promoteKey)
+		passed := err == nil
+		for _, check := range checks {
+			if lastMessages[check.Name] != check.Message {
+				lastMessages[check.Name] = check.Message
+				log.Logger().Infof("%s check %s: %s", check.Kind, util.ColorInfo(check.Name), check.Message)
+			}
+			if check.Status != v1.ActivityStatusTypeSucceeded {
+				passed = false
+			}
+		}
+		updateChecks := func(a *v1.PipelineActivity, s *v1.PipelineActivityStep, ps *v1.PromoteActivityStep, p *v1.PromoteVerifyStep) error {
+			p.Checks = checks
+			return nil
+		}
+		err2 := promoteKey.OnPromoteVerify(jxClient, o.Namespace, updateChecks)
+		if err2 != nil {
+			log.Logger().Warnf("Failed to update PipelineActivity: %s", err2)
+		}
+
+		if err != nil {
+			err2 = promoteKey.OnPromoteVerify(jxClient, o.Namespace, kube.FailedPromotionVerify)
+			if err2 != nil {
+				log.Logger().Warnf("Failed to update PipelineActivity: %s", err2)
+			}
+			return errors.Wrapf(err, "failed to verify %s in namespace %s", app, ns)
+		}
+		if passed {
+			log.Logger().Infof("All checks of %s passed so the promotion worked!", util.ColorInfo(app))
+			return promoteKey.OnPromoteVerify(jxClient, o.Namespace, kube.CompletePromotionVerify)
+		}
+		if time.Now().After(end) {
+			failed := func(a *v1.PipelineActivity, s *v1.PipelineActivityStep, ps *v1.PromoteActivityStep, p *v1.PromoteVerifyStep) error {
+				for i := range p.Checks {
+					if p.Checks[i].Status != v1.ActivityStatusTypeSucceeded {
+						p.Checks[i].Status = v1.ActivityStatusTypeFailed
+					}
+				}
+				return kube.FailedPromotionVerify(a, s, ps, p)
+			}
+			err2 = promoteKey.OnPromoteVerify(jxClient, o.Namespace, failed)
+			if err2 != nil {
+				log.Logger().Warnf("Failed to update PipelineActivity: %s", err2)
+			}
+			failures := []string{}
+			for _, check := range checks {
+				if check.Status != v1.ActivityStatusTypeSucceeded {
+					failures = append(failures, fmt.Sprintf("%s: %s", check.Name, check.Message))
+				}
+			}
+			return fmt.Errorf("Timed out verifying %s in namespace %s after %s: %s", app, ns, timeout.String(), strings.Join(failures, ", "))
+		}
+		time.Sleep(*o.PullRequestPollDuration)
+	}
+}
+
+// runVerifyChecks runs each of the checks once returning their outcome. An error is returned if a check failed in a
+// way which retrying cannot fix
+func (o *PromoteOptions) runVerifyChecks(kubeClient kubernetes.Interface, httpClient *http.Client, ns string, version string, promoteKey *kube.PromoteStepActivityKey) ([]v1.PromoteVerifyCheck, error) {
+	app := o.Application
+	checks := []v1.PromoteVerifyCheck{}
+
+	rollout := v1.PromoteVerifyCheck{
+		Name:   "rollout",
+		Kind:   v1.PromoteVerifyCheckKindRollout,
+		Status: v1.ActivityStatusTypeRunning,
+	}
+	deployments, err := kubeClient.AppsV1().Deployments(ns).List(metav1.ListOptions{})
+	if err != nil {
+		rollout.Message = fmt.Sprintf("failed to list the Deployments: %s", err)
+	} else {
+		deployment := FindAppDeployment(deployments.Items, app)
+		if deployment == nil {
+			rollout.Message = fmt.Sprintf("no Deployment found for %s", app)
+		} else if !deploymentHasVersion(deployment, version) {
+			rollout.Message = fmt.Sprintf("waiting for deployment %s to be updated to version %s", deployment.Name, version)
+		} else {
+			done, message, err := kube.DeploymentRolloutStatus(deployment)
+			if err != nil {
+				rollout.Status = v1.ActivityStatusTypeFailed
+				rollout.Message = err.Error()
+				return append(checks, rollout), err
+			}
+			rollout.Message = message
+			if done {
+				rollout.Status = v1.ActivityStatusTypeSucceeded
+			}
+		}
+	}
+	checks = append(checks, rollout)
+
+	if len(o.VerifyPaths) > 0 {
+		appURL := promoteKey.ApplicationURL
+		if appURL == "" {
+			appURL = o.findServiceURL(kubeClient, ns, app)
+			promoteKey.ApplicationURL = appURL
+		}
+		for _, path := range o.VerifyPaths {
+			check := v1.PromoteVerifyCheck{
+				Name:   path,
+				Kind:   v1.PromoteVerifyCheckKindHTTP,
+				Status: v1.ActivityStatusTypeRunning,
+			}
+			if appURL == "" {
+				check.Message = fmt.Sprintf("no URL found for %s", app)
+			} else if rollout.Status != v1.ActivityStatusTypeSucceeded {
+				check.Message = "waiting for the rollout to complete"
+			} else {
+				checkURL := util.UrlJoin(appURL, path)
+				err := SmokeCheck(httpClient, checkURL)
+				if err != nil {
+					check.Message = err.Error()
+				} else {
+					check.Status = v1.ActivityStatusTypeSucceeded
+					check.Message = fmt.Sprintf("%s responded successfully", checkURL)
+				}
+			}
+			checks = append(checks, check)
+		}
+	}
+
+	if o.PrometheusURL != "" {
+		check := v1.PromoteVerifyCheck{
+			Name:   "error-rate",
+			Kind:   v1.PromoteVerifyCheckKindPrometheus,
+			Status: v1.ActivityStatusTypeRunning,
+		}
+		if rollout.Status != v1.ActivityStatusTypeSucceeded {
+			check.Message = "waiting for the rollout to complete"
+		} else {
+			rate, err := QueryPrometheus(httpClient, o.PrometheusURL, o.ErrorRateQuery)
+			if err != nil {
+				check.Message = err.Error()
+			} else if rate > o.MaxErrorRate {
+				check.Message = fmt.Sprintf("error rate %g is above the threshold %g", rate, o.MaxErrorRate)
+			} else {
+				check.Status = v1.ActivityStatusTypeSucceeded
+				check.Message = fmt.Sprintf("error rate %g is within the threshold %g", rate, o.MaxErrorRate)
+			}
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
+
+// FindAppDeployment returns the Deployment of the given app or nil if there is none. A deployment matches if it is
+// named after the app with or without the prefix of the helm release. If Flagger manages the deployment then the
+// primary deployment, which receives the traffic, is returned
+func FindAppDeployment(deployments []appsv1.Deployment, app string) *appsv1.Deployment {
+	var answer *appsv1.Deployment
+	for i := range deployments {
+		d := &deployments[i]
+		if d.Name == app {
+			answer = d
+			break
+		}
+		if answer == nil && strings.HasSuffix(d.Name, "-"+app) {
+			answer = d
+		}
+	}
+	if answer == nil {
+		return nil
+	}
+	for i := range deployments {
+		if deployments[i].Name == answer.Name+"-primary" {
+			return &deployments[i]
+		}
+	}
+	return answer
+}
+
+// deploymentHasVersion returns false if the chart label of the deployment shows it is not yet at the given version
+func deploymentHasVersion(d *appsv1.Deployment, version string) bool {
+	chart := d.Labels["chart"]
+	if version == "" || chart == "" {
+		return true
+	}
+	return strings.HasSuffix(chart, "-"+strings.Replace(version, "+", "_", -1))
+}
+
+// SmokeCheck returns an error if a GET request of the URL does not respond successfully
+func SmokeCheck(httpClient *http.Client, checkURL string) error {
+	resp, err := httpClient.Get(checkURL)
+	if err != nil {
+		return errors.Wrapf(err, "failed to GET %s", checkURL)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return fmt.Errorf("GET %s returned status %d", checkURL, resp.StatusCode)
+	}
+	return nil
+}
+
+type prometheusQueryResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	Data   struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+type prometheusSample struct {
+	Value []interface{} `json:"value"`
+}
+
+// QueryPrometheus returns the value of an instant query which results in a scalar or a vector of a single sample. A
+// query without any samples, such as an error rate of an application without requests, returns zero
+func QueryPrometheus(httpClient *http.Client, prometheusURL string, query string) (float64, error) {
+	queryURL := util.UrlJoin(prometheusURL, "/api/v1/query") + "?query=" + url.QueryEscape(query)
+	resp, err := httpClient.Get(queryURL)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to query Prometheus at %s", prometheusURL)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to read the response of Prometheus at %s", prometheusURL)
+	}
+	response := prometheusQueryResponse{}
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to parse the response of Prometheus at %s with status %d", prometheusURL, resp.StatusCode)
+	}
+	if response.Status != "success" {
+		return 0, fmt.Errorf("query %s failed: %s", query, response.Error)
+	}
+
+	var value []interface{}
+	switch response.Data.ResultType {
+	case "scalar":
+		err = json.Unmarshal(response.Data.Result, &value)
+	case "vector":
+		samples := []prometheusSample{}
+		err = json.Unmarshal(response.Data.Result, &samples)
+		if err == nil {
+			if len(samples) == 0 {
+				return 0, nil
+			}
+			if len(samples) > 1 {
+				return 0, fmt.Errorf("query %s returned %d samples rather than a single value", query, len(samples))
+			}
+			value = samples[0].Value
+		}
+	default:
+		return 0, fmt.Errorf("query %s returned a %s rather than a scalar or vector", query, response.Data.ResultType)
+	}
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to parse the result of query %s", query)
+	}
+	if len(value) != 2 {
+		return 0, fmt.Errorf("query %s returned an invalid sample %v", query, value)
+	}
+	text, ok := value[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("query %s returned an invalid sample value %v", query, value[1])
+	}
+	answer, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to parse the result %s of query %s", text, query)
+	}
+	if math.IsNaN(answer) {
+		return 0, nil
+	}
+	return answer, nil
+}
@@ -0,0 +1,95 @@
+package promote_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/cmd/promote"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFindAppDeployment(t *testing.T) {
+	t.Parallel()
+
+	deployment := func(name string) appsv1.Deployment {
+		return appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+
+	d := promote.FindAppDeployment([]appsv1.Deployment{deployment("jx-other"), deployment("jx-myapp")}, "myapp")
+	require.NotNil(t, d)
+	assert.Equal(t, "jx-myapp", d.Name)
+
+	d = promote.FindAppDeployment([]appsv1.Deployment{deployment("jx-myapp"), deployment("myapp")}, "myapp")
+	require.NotNil(t, d)
+	assert.Equal(t, "myapp", d.Name, "an exact match should win over a release prefix")
+
+	d = promote.FindAppDeployment([]appsv1.Deployment{deployment("jx-myapp"), deployment("jx-myapp-primary")}, "myapp")
+	require.NotNil(t, d)
+	assert.Equal(t, "jx-myapp-primary", d.Name, "the Flagger primary deployment should be used")
+
+	assert.Nil(t, promote.FindAppDeployment([]appsv1.Deployment{deployment("jx-other")}, "myapp"))
+}
+
+func TestSmokeCheck(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	assert.NoError(t, promote.SmokeCheck(server.Client(), server.URL+"/health"))
+	err := promote.SmokeCheck(server.Client(), server.URL+"/ready")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "returned status 503")
+}
+
+func TestQueryPrometheus(t *testing.T) {
+	t.Parallel()
+
+	responses := map[string]string{
+		"vector": `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1565000000.1,"0.025"]}]}}`,
+		"empty":  `{"status":"success","data":{"resultType":"vector","result":[]}}`,
+		"scalar": `{"status":"success","data":{"resultType":"scalar","result":[1565000000.1,"0.5"]}}`,
+		"nan":    `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1565000000.1,"NaN"]}]}}`,
+		"error":  `{"status":"error","errorType":"bad_data","error":"parse error"}`,
+		"many":   `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"pod":"a"},"value":[1,"1"]},{"metric":{"pod":"b"},"value":[1,"2"]}]}}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/query", r.URL.Path)
+		fmt.Fprint(w, responses[r.URL.Query().Get("query")])
+	}))
+	defer server.Close()
+
+	value, err := promote.QueryPrometheus(server.Client(), server.URL, "vector")
+	require.NoError(t, err)
+	assert.Equal(t, 0.025, value)
+
+	value, err = promote.QueryPrometheus(server.Client(), server.URL, "empty")
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, value)
+
+	value, err = promote.QueryPrometheus(server.Client(), server.URL, "scalar")
+	require.NoError(t, err)
+	assert.Equal(t, 0.5, value)
+
+	value, err = promote.QueryPrometheus(server.Client(), server.URL, "nan")
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, value)
+
+	_, err = promote.QueryPrometheus(server.Client(), server.URL, "error")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "parse error")
+
+	_, err = promote.QueryPrometheus(server.Client(), server.URL, "many")
+	assert.Error(t, err)
+}
@@ -0,0 +1,259 @@
+package rollback
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/pkg/cmd/promote"
+	"github.com/jenkins-x/jx/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/environments"
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// StrategyPullRequest opens a Pull Request on the environment git repository which is left for review
+	StrategyPullRequest = "pr"
+	// StrategyMerge opens a Pull Request on the environment git repository and merges it once it is green
+	StrategyMerge = "merge"
+)
+
+var (
+	rollbackLong = templates.LongDesc(`
+		Rolls back an application in an Environment to a previous version.
+
+		The versions of the application are found from the history of the Environment git repository and the Release resources
+		of the Environment. Unless a version is specified the application is rolled back to the last version before the current one
+		which did not fail.
+
+		The rollback is either a Pull Request on the Environment git repository which is left for review or is merged once it is green.
+		The default depends on the promotion strategy of the Environment: automatic Environments merge and manual Environments only
+		open the Pull Request.
+`)
+
+	rollbackExample = templates.Examples(`
+		# Roll back myapp in production to the previous version
+		jx rollback myapp --env production
+
+		# Roll back myapp in production to a specific version merging the Pull Request straight away
+		jx rollback myapp --env production --to-version 1.2.3 --strategy merge --reason "memory leak in 1.2.4"
+	`)
+)
+
+// RollbackOptions the options for the rollback command
+type RollbackOptions struct {
+	promote.PromoteOptions
+
+	ToVersion string
+	Strategy  string
+	Reason    string
+}
+
+// NewCmdRollback creates the command for: jx rollback
+func NewCmdRollback(commonOpts *opts.CommonOptions) *cobra.Command {
+	options := &RollbackOptions{
+		PromoteOptions: promote.PromoteOptions{
+			CommonOptions: commonOpts,
+		},
+	}
+	cmd := &cobra.Command{
+		Use:     "rollback <application>",
+		Short:   "Rolls back an application in an Environment to a previous version",
+		Long:    rollbackLong,
+		Example: rollbackExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			helper.CheckErr(err)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.Environment, opts.OptionEnvironment, "e", "", "The Environment to roll back the application in")
+	cmd.Flags().StringVarP(&options.ToVersion, "to-version", "", "", "The version to roll back to. Defaults to the last successful version before the current one")
+	cmd.Flags().StringVarP(&options.Strategy, "strategy", "", "", fmt.Sprintf("Either '%s' to only open a Pull Request or '%s' to merge it once it is green. Defaults to '%s' for automatic Environments and '%s' otherwise", StrategyPullRequest, StrategyMerge, StrategyMerge, StrategyPullRequest))
+	cmd.Flags().StringVarP(&options.Reason, "reason", "", "", "The reason for the rollback which is added to the Pull Request and recorded on the PipelineActivity")
+	cmd.Flags().StringVarP(&options.LocalHelmRepoName, "helm-repo-name", "r", kube.LocalHelmRepoName, "The name of the helm repository that contains the app")
+	cmd.Flags().StringVarP(&options.HelmRepositoryURL, "helm-repo-url", "u", helm.InClusterHelmRepositoryURL, "The Helm Repository URL to use for the App")
+	cmd.Flags().StringVarP(&options.Alias, "alias", "", "", "The optional alias used in the 'requirements.yaml' file")
+	cmd.Flags().StringVarP(&options.Timeout, opts.OptionTimeout, "t", "1h", "The timeout to wait for the rollback to succeed in the Environment")
+	cmd.Flags().BoolVarP(&options.OverrideFreeze, "override-freeze", "", false, "Rolls back even if the Environment is in a freeze window. Requires --override-reason")
+	cmd.Flags().StringVarP(&options.OverrideReason, "override-reason", "", "", "The reason for overriding a freeze window which is recorded on the PipelineActivity")
+	return cmd
+}
+
+// Run implements this command
+func (o *RollbackOptions) Run() error {
+	app := o.Application
+	if app == "" {
+		if len(o.Args) == 0 {
+			return fmt.Errorf("missing application argument")
+		}
+		app = o.Args[0]
+	}
+	if o.Environment == "" {
+		return util.MissingOption(opts.OptionEnvironment)
+	}
+	switch o.Strategy {
+	case "", StrategyPullRequest, StrategyMerge:
+	default:
+		return util.InvalidOption("strategy", o.Strategy, []string{StrategyPullRequest, StrategyMerge})
+	}
+
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	env, err := jxClient.JenkinsV1().Environments(ns).Get(o.Environment, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to find Environment %s", o.Environment)
+	}
+	if !env.Spec.Kind.IsPermanent() {
+		return fmt.Errorf("cannot roll back in Environment %s as it is not a permanent Environment", env.Name)
+	}
+
+	history, err := o.appVersionHistory(env, app)
+	if err != nil {
+		return err
+	}
+	releases, err := jxClient.JenkinsV1().Releases(env.Spec.Namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to list the Releases in namespace %s", env.Spec.Namespace)
+	}
+	appReleases := []v1.Release{}
+	for _, r := range releases.Items {
+		if r.Spec.Name == app {
+			appReleases = append(appReleases, r)
+		}
+	}
+
+	fromVersion, toVersion, err := FindRollbackVersions(history, appReleases, o.ToVersion)
+	if err != nil {
+		return errors.Wrapf(err, "failed to find the version to roll back %s to in Environment %s", app, env.Name)
+	}
+
+	user, err := o.GetUsername("")
+	if err != nil {
+		return err
+	}
+	strategy := o.Strategy
+	if strategy == "" {
+		strategy = StrategyPullRequest
+		if env.Spec.PromotionStrategy == v1.PromotionStrategyTypeAutomatic {
+			strategy = StrategyMerge
+		}
+	}
+	log.Logger().Infof("Rolling back %s in Environment %s from version %s to %s using strategy %s", util.ColorInfo(app), util.ColorInfo(env.Name),
+		util.ColorInfo(fromVersion), util.ColorInfo(toVersion), util.ColorInfo(strategy))
+
+	o.Application = app
+	o.Version = toVersion
+	o.IgnoreLocalFiles = true
+	o.BatchMode = true
+	if strategy == StrategyPullRequest {
+		o.NoMergePullRequest = true
+		o.NoPoll = true
+	}
+	o.Rollback = &v1.PromoteRollback{
+		FromVersion: fromVersion,
+		ToVersion:   toVersion,
+		User:        user,
+		Reason:      o.Reason,
+	}
+	return o.PromoteOptions.Run()
+}
+
+// appVersionHistory returns the versions of the app in the history of the environment git repository, newest first
+func (o *RollbackOptions) appVersionHistory(env *v1.Environment, app string) ([]environments.AppVersionRevision, error) {
+	gitURL := env.Spec.Source.URL
+	if gitURL == "" {
+		log.Logger().Warnf("Environment %s has no git repository so only using its Releases", env.Name)
+		return nil, nil
+	}
+	gitProvider, _, err := o.CreateGitProviderForURLWithoutKind(gitURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "creating git provider for %s", gitURL)
+	}
+	userAuth := gitProvider.UserAuth()
+	cloneURL, err := o.Git().CreatePushURL(gitURL, &userAuth)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := ioutil.TempDir("", "jx-rollback-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	err = o.Git().Clone(cloneURL, dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to clone %s", gitURL)
+	}
+	return environments.GetAppVersionHistory(dir, app)
+}
+
+// FindRollbackVersions returns the current version of an application and the version to roll back to given the
+// versions of the application in the environment git history, newest first, and the Releases of the application.
+// If toVersion is blank the newest earlier version whose Release did not fail is used
+func FindRollbackVersions(history []environments.AppVersionRevision, releases []v1.Release, toVersion string) (string, string, error) {
+	failed := map[string]bool{}
+	released := map[string]bool{}
+	for _, r := range releases {
+		released[r.Spec.Version] = true
+		if r.Status.Status == v1.ReleaseStatusTypeFailed {
+			failed[r.Spec.Version] = true
+		}
+	}
+
+	versions := []string{}
+	for _, h := range history {
+		versions = append(versions, h.Version)
+	}
+	if len(versions) == 0 {
+		// without git history lets use the newest releases
+		sorted := append([]v1.Release{}, releases...)
+		sort.Slice(sorted, func(i, j int) bool {
+			return sorted[j].CreationTimestamp.Before(&sorted[i].CreationTimestamp)
+		})
+		for _, r := range sorted {
+			versions = append(versions, r.Spec.Version)
+		}
+	}
+	if len(versions) == 0 {
+		return "", "", fmt.Errorf("no previous versions found")
+	}
+
+	current := versions[0]
+	previous := []string{}
+	for _, v := range versions[1:] {
+		if v != current && util.StringArrayIndex(previous, v) < 0 {
+			previous = append(previous, v)
+		}
+	}
+
+	if toVersion != "" {
+		if toVersion == current {
+			return "", "", fmt.Errorf("version %s is the current version", toVersion)
+		}
+		if util.StringArrayIndex(previous, toVersion) < 0 && !released[toVersion] {
+			return "", "", fmt.Errorf("version %s has never been released. Previous versions are: %s", toVersion, previous)
+		}
+		return current, toVersion, nil
+	}
+	for _, v := range previous {
+		if !failed[v] {
+			return current, v, nil
+		}
+	}
+	return "", "", fmt.Errorf("no successful version before the current version %s", current)
+}
@@ -0,0 +1,105 @@
+package rollback_test
+
+import (
+	"testing"
+	"time"
+
+	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/cmd/rollback"
+	"github.com/jenkins-x/jx/pkg/environments"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func createHistory(versions ...string) []environments.AppVersionRevision {
+	answer := []environments.AppVersionRevision{}
+	for _, v := range versions {
+		answer = append(answer, environments.AppVersionRevision{
+			Version: v,
+			Sha:     "sha-" + v,
+		})
+	}
+	return answer
+}
+
+func createRelease(version string, status v1.ReleaseStatusType, created time.Time) v1.Release {
+	return v1.Release{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "myapp-" + version,
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec: v1.ReleaseSpec{
+			Name:    "myapp",
+			Version: version,
+		},
+		Status: v1.ReleaseStatus{
+			Status: status,
+		},
+	}
+}
+
+func TestFindRollbackVersionsUsesPreviousVersion(t *testing.T) {
+	t.Parallel()
+
+	from, to, err := rollback.FindRollbackVersions(createHistory("1.0.3", "1.0.2", "1.0.1"), nil, "")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.3", from)
+	assert.Equal(t, "1.0.2", to)
+}
+
+func TestFindRollbackVersionsSkipsFailedReleases(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	releases := []v1.Release{
+		createRelease("1.0.1", v1.ReleaseStatusTypeDeployed, now.Add(-3*time.Hour)),
+		createRelease("1.0.2", v1.ReleaseStatusTypeFailed, now.Add(-2*time.Hour)),
+		createRelease("1.0.3", v1.ReleaseStatusTypeDeployed, now.Add(-time.Hour)),
+	}
+	from, to, err := rollback.FindRollbackVersions(createHistory("1.0.3", "1.0.2", "1.0.1"), releases, "")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.3", from)
+	assert.Equal(t, "1.0.1", to)
+}
+
+func TestFindRollbackVersionsWithoutHistoryUsesReleases(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	releases := []v1.Release{
+		createRelease("1.0.2", v1.ReleaseStatusTypeDeployed, now.Add(-2*time.Hour)),
+		createRelease("1.0.3", v1.ReleaseStatusTypeDeployed, now.Add(-time.Hour)),
+		createRelease("1.0.1", v1.ReleaseStatusTypeDeployed, now.Add(-3*time.Hour)),
+	}
+	from, to, err := rollback.FindRollbackVersions(nil, releases, "")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.3", from)
+	assert.Equal(t, "1.0.2", to)
+}
+
+func TestFindRollbackVersionsToVersion(t *testing.T) {
+	t.Parallel()
+
+	history := createHistory("1.0.3", "1.0.2", "1.0.1")
+	from, to, err := rollback.FindRollbackVersions(history, nil, "1.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.3", from)
+	assert.Equal(t, "1.0.1", to)
+
+	_, _, err = rollback.FindRollbackVersions(history, nil, "1.0.3")
+	assert.Error(t, err, "should not roll back to the current version")
+
+	_, _, err = rollback.FindRollbackVersions(history, nil, "0.9.0")
+	assert.Error(t, err, "should not roll back to a version which was never released")
+}
+
+func TestFindRollbackVersionsNoPreviousVersion(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := rollback.FindRollbackVersions(createHistory("1.0.1"), nil, "")
+	assert.Error(t, err)
+
+	_, _, err = rollback.FindRollbackVersions(nil, nil, "")
+	assert.Error(t, err)
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/jenkins-x/jx/pkg/cmd/opts"
 	"github.com/jenkins-x/jx/pkg/cmd/templates"
 	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/tekton/syntax"
 	"github.com/jenkins-x/jx/pkg/util"
 	build "github.com/knative/build/pkg/apis/build/v1alpha1"
 	v1 "k8s.io/api/core/v1"
@@ -54,6 +55,9 @@ type StartPipelineOptions struct {
 	Context      string
 	CustomLabels []string
 	CustomEnvs   []string
+	Parameters   []string
+
+	parameterValues map[string]string
 }
 
 var (
@@ -71,6 +75,9 @@ var (
 
 		# Select the pipeline to start and tail the log
 		jx start pipeline -t
+
+		# Start a pipeline with values for its parameters
+		jx start pipeline myorg/myrepo/master --param TARGET=production --param DRY_RUN=false
 	`)
 )
 
@@ -99,6 +106,7 @@ func NewCmdStartPipeline(commonOpts *opts.CommonOptions) *cobra.Command {
 	cmd.Flags().StringVar(&options.ServiceAccount, "service-account", "tekton-bot", "The Kubernetes ServiceAccount to use to run the meta pipeline")
 	cmd.Flags().StringArrayVarP(&options.CustomLabels, "label", "l", nil, "List of custom labels to be applied to the generated PipelineRun (can be use multiple times)")
 	cmd.Flags().StringArrayVarP(&options.CustomEnvs, "env", "e", nil, "List of custom environment variables to be applied to the generated PipelineRun that are created (can be use multiple times)")
+	cmd.Flags().StringArrayVarP(&options.Parameters, "param", "p", nil, "List of values of the parameters of the pipeline such as 'name=value' (can be use multiple times)")
 
 	options.JenkinsSelector.AddFlags(cmd)
 
@@ -107,6 +115,11 @@ func NewCmdStartPipeline(commonOpts *opts.CommonOptions) *cobra.Command {
 
 // Run implements this command
 func (o *StartPipelineOptions) Run() error {
+	var err error
+	o.parameterValues, err = syntax.ParseParameterValues(o.Parameters)
+	if err != nil {
+		return util.InvalidOptionError("param", o.Parameters, err)
+	}
 	kubeClient, currentNamespace, err := o.KubeClientAndNamespace()
 	if err != nil {
 		return err
@@ -225,6 +238,7 @@ func (o *StartPipelineOptions) createMetaPipeline(jobname string) error {
 		Context:      o.Context,
 		CustomLabels: o.CustomLabels,
 		CustomEnvs:   o.CustomEnvs,
+		Parameters:   o.Parameters,
 	}
 	po.CommonOptions = o.CommonOptions
 	po.ServiceAccount = o.ServiceAccount
@@ -282,6 +296,11 @@ func (o *StartPipelineOptions) createProwJob(jobname string) error {
 		env[repoOwnerEnv] = org
 		env[repoNameEnv] = repo
 
+		// the parameters of the pipeline are exposed as environment variables
+		for k, v := range o.parameterValues {
+			env[k] = v
+		}
+
 		for i, step := range jobSpec.BuildSpec.Steps {
 			if len(step.Env) == 0 {
 
@@ -373,6 +392,9 @@ func (o *StartPipelineOptions) startJenkinsJob(name string) error {
 	previous, _ := jenkinsClient.GetLastBuild(job)
 
 	params := url.Values{}
+	for k, v := range o.parameterValues {
+		params.Set(k, v)
+	}
 	err = jenkinsClient.Build(job, params)
 	if err != nil {
 		return err
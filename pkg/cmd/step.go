@@ -7,6 +7,7 @@ import (
 	"github.com/jenkins-x/jx/pkg/cmd/step"
 	"github.com/jenkins-x/jx/pkg/cmd/step/boot"
 	"github.com/jenkins-x/jx/pkg/cmd/step/buildpack"
+	"github.com/jenkins-x/jx/pkg/cmd/step/cache"
 	"github.com/jenkins-x/jx/pkg/cmd/step/create"
 	"github.com/jenkins-x/jx/pkg/cmd/step/e2e"
 	"github.com/jenkins-x/jx/pkg/cmd/step/env"
@@ -18,6 +19,7 @@ import (
 	"github.com/jenkins-x/jx/pkg/cmd/step/pr"
 	"github.com/jenkins-x/jx/pkg/cmd/step/pre"
 	"github.com/jenkins-x/jx/pkg/cmd/step/scheduler"
+	"github.com/jenkins-x/jx/pkg/cmd/step/services"
 	"github.com/jenkins-x/jx/pkg/cmd/step/syntax"
 	"github.com/jenkins-x/jx/pkg/cmd/step/update"
 	"github.com/jenkins-x/jx/pkg/cmd/step/verify"
@@ -45,6 +47,7 @@ func NewCmdStep(commonOpts *opts.CommonOptions) *cobra.Command {
 	cmd.AddCommand(boot.NewCmdStepBoot(commonOpts))
 	cmd.AddCommand(buildpack.NewCmdStepBuildPack(commonOpts))
 	cmd.AddCommand(NewCmdStepBDD(commonOpts))
+	cmd.AddCommand(cache.NewCmdStepCache(commonOpts))
 	cmd.AddCommand(e2e.NewCmdStepE2E(commonOpts))
 	cmd.AddCommand(step.NewCmdStepBlog(commonOpts))
 	cmd.AddCommand(step.NewCmdStepChangelog(commonOpts))
@@ -56,6 +59,7 @@ func NewCmdStep(commonOpts *opts.CommonOptions) *cobra.Command {
 	cmd.AddCommand(git.NewCmdStepGit(commonOpts))
 	cmd.AddCommand(step.NewCmdStepGpgCredentials(commonOpts))
 	cmd.AddCommand(helm.NewCmdStepHelm(commonOpts))
+	cmd.AddCommand(step.NewCmdStepInput(commonOpts))
 	cmd.AddCommand(step.NewCmdStepLinkServices(commonOpts))
 	cmd.AddCommand(nexus.NewCmdStepNexus(commonOpts))
 	cmd.AddCommand(step.NewCmdStepNextVersion(commonOpts))
@@ -64,6 +68,7 @@ func NewCmdStep(commonOpts *opts.CommonOptions) *cobra.Command {
 	cmd.AddCommand(pr.NewCmdStepPR(commonOpts))
 	cmd.AddCommand(post.NewCmdStepPost(commonOpts))
 	cmd.AddCommand(step.NewCmdStepRelease(commonOpts))
+	cmd.AddCommand(services.NewCmdStepServices(commonOpts))
 	cmd.AddCommand(step.NewCmdStepSplitMonorepo(commonOpts))
 	cmd.AddCommand(syntax.NewCmdStepSyntax(commonOpts))
 	cmd.AddCommand(step.NewCmdStepTag(commonOpts))
@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/tekton/cache"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gocloud.dev/blob"
+)
+
+// StepCacheOptions contains the command line flags
+type StepCacheOptions struct {
+	*opts.CommonOptions
+}
+
+// NewCmdStepCache creates the command object for the "step cache" command
+func NewCmdStepCache(commonOpts *opts.CommonOptions) *cobra.Command {
+	options := &StepCacheOptions{
+		CommonOptions: commonOpts,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "restores and saves the dependency caches of pipelines",
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			helper.CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(NewCmdStepCacheRestore(commonOpts))
+	cmd.AddCommand(NewCmdStepCacheSave(commonOpts))
+
+	return cmd
+}
+
+// Run implements this command
+func (o *StepCacheOptions) Run() error {
+	return o.Cmd.Help()
+}
+
+// cacheOptions are the flags common to restoring and saving caches
+type cacheOptions struct {
+	opts.StepOptions
+
+	Key       string
+	Files     []string
+	BucketURL string
+	Dir       string
+	Timeout   time.Duration
+}
+
+func (o *cacheOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&o.Key, "key", "k", "", "The prefix of the cache key, such as 'maven' or 'go-mod'")
+	cmd.Flags().StringArrayVarP(&o.Files, "file", "f", nil, "The lock files whose contents are hashed into the cache key, such as 'pom.xml' or 'go.sum'")
+	cmd.Flags().StringVarP(&o.BucketURL, "bucket-url", "", "", "The URL of the bucket storing the caches such as 'gs://mybucket' or 'file:///jx-cache' for a mounted volume. Defaults to the team's storage location for '"+cache.Classifier+"'")
+	cmd.Flags().StringVarP(&o.Dir, "dir", "d", "", "The directory the lock files and relative cache paths are in. Defaults to the current directory")
+	cmd.Flags().DurationVarP(&o.Timeout, "timeout", "t", 5*time.Minute, "The timeout for restoring or saving the cache")
+}
+
+// openBucket returns the bucket storing the caches, or nil if there is no bucket storage configured for caches
+func (o *cacheOptions) openBucket(ctx context.Context) (*blob.Bucket, error) {
+	bucketURL := o.BucketURL
+	if bucketURL == "" {
+		settings, err := o.TeamSettings()
+		if err != nil {
+			return nil, err
+		}
+		bucketURL = settings.StorageLocationOrDefault(cache.Classifier).BucketURL
+	}
+	if bucketURL == "" {
+		log.Logger().Warnf("no bucket storage is configured for %s in the team settings so there is no cache", cache.Classifier)
+		return nil, nil
+	}
+	return cache.OpenBucket(ctx, bucketURL)
+}
+
+// validate checks the flags and returns the owner and name of the repository the cache belongs to
+func (o *cacheOptions) validate() (string, string, error) {
+	if o.Key == "" {
+		return "", "", util.MissingOption("key")
+	}
+	var err error
+	if o.Dir == "" {
+		o.Dir, err = os.Getwd()
+		if err != nil {
+			return "", "", err
+		}
+	}
+	owner := os.Getenv("REPO_OWNER")
+	repo := os.Getenv("REPO_NAME")
+	if owner == "" || repo == "" {
+		gitInfo, err := o.FindGitInfo(o.Dir)
+		if err != nil {
+			return "", "", errors.Wrapf(err, "failed to find the git repository in %s", o.Dir)
+		}
+		owner = gitInfo.Organisation
+		repo = gitInfo.Name
+	}
+	return owner, repo, nil
+}
@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/jenkins-x/jx/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/tekton/cache"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// StepCacheRestoreOptions contains the command line flags
+type StepCacheRestoreOptions struct {
+	cacheOptions
+}
+
+var (
+	stepCacheRestoreLong = templates.LongDesc(`
+		Restores the cache for the key computed from the key prefix and the contents of the lock files.
+
+		If there is no cache for the key the most recent cache of the repository with the same key prefix is restored instead. The files of the cache are restored to the paths they were saved from.
+
+		This step is generated for the cache option of the jenkins-x.yml pipelines and stages.
+`)
+
+	stepCacheRestoreExample = templates.Examples(`
+		# restore the maven repository for the current pom.xml
+		jx step cache restore --key maven --file pom.xml
+
+		# restore the go modules from a mounted volume
+		jx step cache restore --key go-mod --file go.sum --bucket-url file:///jx-cache
+`)
+)
+
+// NewCmdStepCacheRestore creates the command object
+func NewCmdStepCacheRestore(commonOpts *opts.CommonOptions) *cobra.Command {
+	options := &StepCacheRestoreOptions{
+		cacheOptions: cacheOptions{
+			StepOptions: opts.StepOptions{
+				CommonOptions: commonOpts,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "restore",
+		Short:   "Restores a dependency cache of a pipeline",
+		Long:    stepCacheRestoreLong,
+		Example: stepCacheRestoreExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			helper.CheckErr(err)
+		},
+	}
+	options.addFlags(cmd)
+	return cmd
+}
+
+// Run implements this command
+func (o *StepCacheRestoreOptions) Run() error {
+	owner, repo, err := o.validate()
+	if err != nil {
+		return err
+	}
+	key, err := cache.Key(o.Key, o.Files, o.Dir)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), o.Timeout)
+	defer cancel()
+	bucket, err := o.openBucket(ctx)
+	if err != nil || bucket == nil {
+		return err
+	}
+	defer bucket.Close()
+
+	restored, err := cache.Restore(ctx, bucket, cache.ObjectName(owner, repo, key), cache.ObjectPrefix(owner, repo, o.Key), "/")
+	if err != nil {
+		return err
+	}
+	if restored == "" {
+		log.Logger().Infof("no cache found for %s", util.ColorInfo(key))
+		return nil
+	}
+	log.Logger().Infof("restored cache %s", util.ColorInfo(restored))
+	return nil
+}
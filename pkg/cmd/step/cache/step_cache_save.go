@@ -0,0 +1,118 @@
+package cache
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/jenkins-x/jx/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/tekton/cache"
+	"github.com/jenkins-x/jx/pkg/tekton/syntax"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// StepCacheSaveOptions contains the command line flags
+type StepCacheSaveOptions struct {
+	cacheOptions
+
+	Paths      []string
+	MarkerFile string
+}
+
+var (
+	stepCacheSaveLong = templates.LongDesc(`
+		Saves the files in the given paths as the cache for the key computed from the key prefix and the contents of the lock files.
+
+		If the cache already exists or a previous step of the pipeline failed the cache is not saved.
+
+		This step is generated for the cache option of the jenkins-x.yml pipelines and stages.
+`)
+
+	stepCacheSaveExample = templates.Examples(`
+		# save the maven repository for the current pom.xml
+		jx step cache save --key maven --file pom.xml --path /root/.m2/repository
+
+		# save the node modules to a mounted volume
+		jx step cache save --key npm --file package-lock.json --path node_modules --bucket-url file:///jx-cache
+`)
+)
+
+// NewCmdStepCacheSave creates the command object
+func NewCmdStepCacheSave(commonOpts *opts.CommonOptions) *cobra.Command {
+	options := &StepCacheSaveOptions{
+		cacheOptions: cacheOptions{
+			StepOptions: opts.StepOptions{
+				CommonOptions: commonOpts,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "save",
+		Short:   "Saves a dependency cache of a pipeline",
+		Long:    stepCacheSaveLong,
+		Example: stepCacheSaveExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			helper.CheckErr(err)
+		},
+	}
+	options.addFlags(cmd)
+	cmd.Flags().StringArrayVarP(&options.Paths, "path", "p", nil, "The directories or files to save in the cache")
+	cmd.Flags().StringVarP(&options.MarkerFile, "marker-file", "", syntax.PostFailedMarkerFile, "The file which exists if a step has failed")
+	return cmd
+}
+
+// Run implements this command
+func (o *StepCacheSaveOptions) Run() error {
+	owner, repo, err := o.validate()
+	if err != nil {
+		return err
+	}
+	if len(o.Paths) == 0 {
+		return util.MissingOption("path")
+	}
+	failed, err := util.FileExists(o.MarkerFile)
+	if err != nil {
+		return errors.Wrapf(err, "failed to check if file exists %s", o.MarkerFile)
+	}
+	if failed {
+		log.Logger().Infof("not saving the cache %s as a step failed", util.ColorInfo(o.Key))
+		return nil
+	}
+	key, err := cache.Key(o.Key, o.Files, o.Dir)
+	if err != nil {
+		return err
+	}
+	var paths []string
+	for _, p := range o.Paths {
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(o.Dir, p)
+		}
+		paths = append(paths, p)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), o.Timeout)
+	defer cancel()
+	bucket, err := o.openBucket(ctx)
+	if err != nil || bucket == nil {
+		return err
+	}
+	defer bucket.Close()
+
+	name := cache.ObjectName(owner, repo, key)
+	saved, err := cache.Save(ctx, bucket, name, paths, "/")
+	if err != nil {
+		return err
+	}
+	if saved {
+		log.Logger().Infof("saved cache %s", util.ColorInfo(name))
+	}
+	return nil
+}
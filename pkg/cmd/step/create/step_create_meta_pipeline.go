@@ -36,6 +36,7 @@ const (
 	labelOptionName          = "label"
 	noApplyOptionName        = "no-apply"
 	outputOptionName         = "output"
+	paramOptionName          = "param"
 	pullRefOptionName        = "pull-refs"
 	serviceAccountOptionName = "service-account"
 	sourceURLOptionName      = "source-url"
@@ -69,6 +70,7 @@ type StepCreatePipelineOptions struct {
 
 	CustomLabels []string
 	CustomEnvs   []string
+	Parameters   []string
 	DefaultImage string
 
 	Results tekton.CRDWrapper
@@ -108,6 +110,7 @@ func NewCmdCreateMetaPipeline(commonOpts *opts.CommonOptions) *cobra.Command {
 	cmd.Flags().StringVar(&options.DefaultImage, defaultImageOptionName, syntax.DefaultContainerImage, "Specify the docker image to use if there is no image specified for a step. Default "+syntax.DefaultContainerImage)
 	cmd.Flags().StringArrayVarP(&options.CustomLabels, labelOptionName, "l", nil, "List of custom labels to be applied to the generated PipelineRun (can be use multiple times)")
 	cmd.Flags().StringArrayVarP(&options.CustomEnvs, envOptionName, "e", nil, "List of custom environment variables to be applied to resources that are created (can be use multiple times)")
+	cmd.Flags().StringArrayVar(&options.Parameters, paramOptionName, nil, "List of values of the parameters of the pipeline such as 'name=value' (can be use multiple times)")
 
 	cmd.Flags().StringVar(&options.ServiceAccount, serviceAccountOptionName, "tekton-bot", "The Kubernetes ServiceAccount to use to run the pipeline")
 
@@ -206,6 +209,7 @@ func (o *StepCreatePipelineOptions) Run() error {
 		ServiceAccount:   o.ServiceAccount,
 		Labels:           o.CustomLabels,
 		EnvVars:          o.CustomEnvs,
+		Parameters:       o.Parameters,
 		DefaultImage:     o.DefaultImage,
 		Apps:             extendingApps,
 		VersionsDir:      o.VersionResolver.VersionsDir,
@@ -244,6 +248,11 @@ func (o *StepCreatePipelineOptions) validateCommandLineFlags() error {
 		return errors.Wrap(err, "unable to parse custom environment variables")
 	}
 
+	_, err = syntax.ParseParameterValues(o.Parameters)
+	if err != nil {
+		return util.InvalidOptionError(paramOptionName, o.Parameters, err)
+	}
+
 	if o.PullRefs == "" {
 		return util.MissingOption(pullRefOptionName)
 	}
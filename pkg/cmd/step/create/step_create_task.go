@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/jenkins-x/jx/pkg/prow"
 
 	"github.com/ghodss/yaml"
+	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
 	jxclient "github.com/jenkins-x/jx/pkg/client/clientset/versioned"
 	"github.com/jenkins-x/jx/pkg/cmd/opts"
 	syntaxstep "github.com/jenkins-x/jx/pkg/cmd/step/syntax"
@@ -75,6 +77,7 @@ type StepCreateTaskOptions struct {
 	Context           string
 	CustomLabels      []string
 	CustomEnvs        []string
+	Parameters        []string
 	NoApply           *bool
 	DryRun            bool
 	InterpretMode     bool
@@ -116,6 +119,8 @@ type StepCreateTaskOptions struct {
 	previewVersionPrefix string
 	VersionResolver      *opts.VersionResolver
 	CloneDir             string
+	teamStepsDir         string
+	parameters           []v1.PipelineParameter
 }
 
 // NewCmdStepCreateTask Creates a new Command object
@@ -152,6 +157,7 @@ func NewCmdStepCreateTaskAndOption(commonOpts *opts.CommonOptions) (*cobra.Comma
 	cmd.Flags().StringVarP(&options.PipelineKind, "kind", "k", "release", "The kind of pipeline to create such as: "+strings.Join(jenkinsfile.PipelineKinds, ", "))
 	cmd.Flags().StringArrayVarP(&options.CustomLabels, "label", "l", nil, "List of custom labels to be applied to resources that are created")
 	cmd.Flags().StringArrayVarP(&options.CustomEnvs, "env", "e", nil, "List of custom environment variables to be applied to resources that are created")
+	cmd.Flags().StringArrayVar(&options.Parameters, paramOptionName, nil, "List of values of the parameters of the pipeline such as 'name=value' (can be use multiple times)")
 	cmd.Flags().StringVarP(&options.CloneGitURL, "clone-git-url", "", "", "Specify the git URL to clone to a temporary directory to get the source code")
 	cmd.Flags().StringVarP(&options.CloneDir, "clone-dir", "", "", "Specify the directory of the directory containing the git clone")
 	cmd.Flags().StringVarP(&options.PullRequestNumber, "pr-number", "", "", "If a Pull Request this is it's number")
@@ -343,6 +349,7 @@ func (o *StepCreateTaskOptions) Run() error {
 		}
 	} else {
 		activityKey := tekton.GeneratePipelineActivity(o.BuildNumber, o.Branch, o.GitInfo, pr, tekton.BuildPipeline)
+		activityKey.Parameters = o.parameters
 
 		log.Logger().Debugf(" PipelineActivity for %s created successfully", tektonCRDs.Name())
 
@@ -354,10 +361,27 @@ func (o *StepCreateTaskOptions) Run() error {
 		tektonCRDs.AddLabels(o.labels)
 
 		log.Logger().Debugf(" for %s", tektonCRDs.PipelineRun().Name)
+
+		o.recordChatConfig(jxClient, ns, activityKey, effectiveProjectConfig)
 	}
 	return nil
 }
 
+// recordChatConfig records the developer channel of the project, if it has one, on the PipelineActivity so that
+// 'jx controller build' notifies it when the build starts and finishes
+func (o *StepCreateTaskOptions) recordChatConfig(jxClient jxclient.Interface, ns string, activityKey *kube.PromoteStepActivityKey, projectConfig *config.ProjectConfig) {
+	if projectConfig.Chat == nil || projectConfig.Chat.DeveloperChannel == "" {
+		return
+	}
+	activity, _, err := activityKey.GetOrCreate(jxClient, ns)
+	if err == nil && opts.AnnotateChatConfig(activity, projectConfig.Chat) {
+		_, err = jxClient.JenkinsV1().PipelineActivities(ns).PatchUpdate(activity)
+	}
+	if err != nil {
+		log.Logger().Warnf("failed to record the chat configuration on the PipelineActivity so build %s will not be notified: %s", o.BuildNumber, err.Error())
+	}
+}
+
 func (o *StepCreateTaskOptions) createEffectiveProjectConfigFromOptions(tektonClient tektonclient.Interface, jxClient jxclient.Interface, kubeClient kubeclient.Interface, ns string, pipelineName string) (*config.ProjectConfig, error) {
 	if o.InterpretMode {
 		// lets allow this command to run in an empty cluster
@@ -464,6 +488,7 @@ func (o *StepCreateTaskOptions) createEffectiveProjectConfigFromOptions(tektonCl
 	if err != nil {
 		return nil, err
 	}
+	o.teamStepsDir = filepath.Join(filepath.Dir(packsDir), syntax.StepTemplatesDirName)
 
 	resolver, err := gitresolver.CreateResolver(packsDir, o.Git())
 	if err != nil {
@@ -536,7 +561,17 @@ func (o *StepCreateTaskOptions) generateTektonCRDs(effectiveProjectConfig *confi
 		return nil, errors.Wrapf(err, "unable to extract the requested pipeline")
 	}
 
-	pipeline, tasks, structure, err := effectivePipeline.GenerateCRDs(pipelineName, o.BuildNumber, ns, o.PodTemplates, o.VersionResolver.VersionsDir, o.getDefaultTaskInputs().Params, o.SourceName, o.labels, "")
+	effectivePipeline = effectivePipeline.DeepCopy()
+	err = o.applyParameters(effectivePipeline)
+	if err != nil {
+		return nil, err
+	}
+	skipped := effectivePipeline.ApplyWhen(o.createWhenContext(effectivePipeline))
+	if len(skipped) > 0 {
+		log.Logger().Infof("skipping stages %s as their when conditions are not met", strings.Join(skipped, ", "))
+	}
+
+	pipeline, tasks, structure, err := effectivePipeline.GenerateCRDs(pipelineName, o.BuildNumber, ns, o.PodTemplates, o.VersionResolver.VersionsDir, o.getDefaultTaskInputs().Params, o.SourceName, o.labels, "", o.teamStepsDir)
 	if err != nil {
 		return nil, errors.Wrapf(err, "generation failed for Pipeline")
 	}
@@ -561,6 +596,140 @@ func (o *StepCreateTaskOptions) generateTektonCRDs(effectiveProjectConfig *confi
 	return tektonCRDs, nil
 }
 
+// applyParameters resolves the parameters of the pipeline from the --param values, exposing them to the steps as
+// environment variables and recording them for the PipelineActivity. Parameters without a --param value are taken
+// from the environment variables of the same name, such as those of the ProwJobs created by 'jx start pipeline'
+func (o *StepCreateTaskOptions) applyParameters(pipeline *syntax.ParsedPipeline) error {
+	values, err := syntax.ParseParameterValues(o.Parameters)
+	if err != nil {
+		return util.InvalidOptionError(paramOptionName, o.Parameters, err)
+	}
+	for _, p := range pipeline.Parameters {
+		if _, ok := values[p.Name]; ok {
+			continue
+		}
+		if value, ok := o.lookupParameterEnv(p.Name); ok {
+			values[p.Name] = value
+		}
+	}
+	resolved, err := pipeline.ApplyParameters(values)
+	if err != nil {
+		return err
+	}
+	o.parameters = nil
+	for _, e := range resolved {
+		o.parameters = append(o.parameters, v1.PipelineParameter{Name: e.Name, Value: e.Value})
+	}
+	if len(resolved) > 0 {
+		var values []string
+		for _, p := range o.parameters {
+			values = append(values, p.Name+"="+p.Value)
+		}
+		log.Logger().Infof("pipeline parameters: %s", util.ColorInfo(strings.Join(values, ", ")))
+	}
+	return nil
+}
+
+// lookupParameterEnv returns the value of the environment variable passed via --env, which includes the environment
+// of the ProwJob, ignoring the environment of this process so unrelated variables cannot override parameters
+func (o *StepCreateTaskOptions) lookupParameterEnv(name string) (string, bool) {
+	for _, envVar := range o.CustomEnvs {
+		parts := strings.SplitN(envVar, "=", 2)
+		if len(parts) == 2 && parts[0] == name {
+			return parts[1], true
+		}
+	}
+	return "", false
+}
+
+// createWhenContext creates the context the when conditions of the stages and steps are evaluated against, only
+// querying the Pull Request labels and changed files if the pipeline uses them
+func (o *StepCreateTaskOptions) createWhenContext(pipeline *syntax.ParsedPipeline) *syntax.WhenContext {
+	ctx := &syntax.WhenContext{
+		Branch:      o.Branch,
+		Kind:        o.PipelineKind,
+		Environment: map[string]string{},
+	}
+	for k, v := range o.AdditionalEnvVars {
+		ctx.Environment[k] = v
+	}
+	for _, envVar := range o.CustomEnvs {
+		parts := strings.SplitN(envVar, "=", 2)
+		if len(parts) == 2 {
+			ctx.Environment[parts[0]] = parts[1]
+		}
+	}
+	for _, p := range o.parameters {
+		ctx.Environment[p.Name] = p.Value
+	}
+
+	if pipeline.UsesLabels() && o.PullRequestNumber != "" && o.GitInfo != nil {
+		labels, err := o.pullRequestLabels()
+		if err != nil {
+			log.Logger().Warnf("failed to find the labels of Pull Request %s: %s", o.PullRequestNumber, err.Error())
+		}
+		ctx.Labels = labels
+	}
+
+	if pipeline.UsesChangeSet() {
+		files, err := o.changedFiles()
+		if err != nil {
+			// leaving the changes unknown means all the changeset conditions match
+			log.Logger().Warnf("failed to find the changed files so running all stages: %s", err.Error())
+		} else {
+			ctx.ChangedFiles = files
+		}
+	}
+	return ctx
+}
+
+func (o *StepCreateTaskOptions) pullRequestLabels() ([]string, error) {
+	prNumber, err := strconv.Atoi(o.PullRequestNumber)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid Pull Request number %s", o.PullRequestNumber)
+	}
+	provider, err := o.GitProviderForURL(o.GitInfo.URL, "user name to query the Pull Request labels")
+	if err != nil {
+		return nil, err
+	}
+	pr, err := provider.GetPullRequest(o.GitInfo.Organisation, o.GitInfo, prNumber)
+	if err != nil {
+		return nil, err
+	}
+	var answer []string
+	for _, label := range pr.Labels {
+		if label != nil && label.Name != nil {
+			answer = append(answer, *label.Name)
+		}
+	}
+	return answer, nil
+}
+
+// changedFiles returns the files changed compared to the base of the Pull Request or the previous commit
+func (o *StepCreateTaskOptions) changedFiles() ([]string, error) {
+	base := "HEAD~1"
+	pr, err := o.parsePullRefs()
+	if err != nil {
+		return nil, err
+	}
+	if pr != nil && pr.BaseSha != "" {
+		base = pr.BaseSha
+	}
+	text, err := o.Git().ListChangedFilesFromBranch(o.CloneDir, base)
+	if err != nil {
+		return nil, err
+	}
+	answer := []string{}
+	for _, line := range strings.Split(text, "\n") {
+		// each line is the status followed by one path or two for renames and copies
+		fields := strings.Split(strings.TrimSpace(line), "\t")
+		if len(fields) > 1 {
+			answer = append(answer, fields[1:]...)
+		}
+	}
+	return answer, nil
+}
+
 func (o *StepCreateTaskOptions) loadProjectConfig() (*config.ProjectConfig, string, error) {
 	if o.Context != "" {
 		fileName := filepath.Join(o.CloneDir, fmt.Sprintf("jenkins-x-%s.yml", o.Context))
@@ -1262,8 +1431,7 @@ func (o *StepCreateTaskOptions) invokeSteps(steps []*syntax.Step) error {
 				return err
 			}
 		}
-		when := strings.TrimSpace(s.When)
-		if when == "!prow" || s.GetCommand() == "" {
+		if (s.When != nil && strings.TrimSpace(s.When.Legacy) == "!prow") || s.GetCommand() == "" {
 			continue
 		}
 		err := o.runStepCommand(s)
@@ -24,10 +24,12 @@ import (
 
 	"github.com/ghodss/yaml"
 	"github.com/google/go-cmp/cmp"
+	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx/pkg/cmd/opts"
 	"github.com/jenkins-x/jx/pkg/config"
 	"github.com/jenkins-x/jx/pkg/gits"
 	"github.com/jenkins-x/jx/pkg/jenkinsfile"
+	"github.com/jenkins-x/jx/pkg/tekton/syntax"
 	"github.com/jenkins-x/jx/pkg/tekton/tekton_helpers_test"
 	"github.com/jenkins-x/jx/pkg/tests"
 	"github.com/stretchr/testify/assert"
@@ -454,6 +456,37 @@ func assertTektonCRDs(t *testing.T, testCase testCase, crds *tekton.CRDWrapper,
 	}
 }
 
+func TestApplyParametersFromEnvironmentVariables(t *testing.T) {
+	// only the environment variables passed via --env are used
+	err := os.Setenv("DRY_RUN", "true")
+	assert.NoError(t, err)
+	defer os.Unsetenv("DRY_RUN")
+
+	pipeline := &syntax.ParsedPipeline{
+		Parameters: []syntax.Parameter{
+			{Name: "TARGET", Default: "staging"},
+			{Name: "DRY_RUN", Type: syntax.ParameterTypeBool},
+			{Name: "MESSAGE", Default: "none"},
+		},
+		Env: []corev1.EnvVar{{Name: "TARGET", Value: "staging"}},
+	}
+	createTask := &StepCreateTaskOptions{
+		Parameters: []string{"MESSAGE=hotfix"},
+		CustomEnvs: []string{"TARGET=production", "MESSAGE=ignored"},
+	}
+
+	err = createTask.applyParameters(pipeline)
+	assert.NoError(t, err)
+
+	expected := []v1.PipelineParameter{
+		{Name: "TARGET", Value: "production"},
+		{Name: "DRY_RUN", Value: "false"},
+		{Name: "MESSAGE", Value: "hotfix"},
+	}
+	assert.Equal(t, expected, createTask.parameters)
+	assert.Contains(t, pipeline.GetEnv(), corev1.EnvVar{Name: "TARGET", Value: "production"})
+}
+
 func assertLoadPodTemplates(t *testing.T) map[string]*corev1.Pod {
 	fileName := filepath.Join("test_data", "step_create_task", "PodTemplates.yml")
 	if tests.AssertFileExists(t, fileName) {
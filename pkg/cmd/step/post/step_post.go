@@ -32,7 +32,9 @@ func NewCmdStepPost(commonOpts *opts.CommonOptions) *cobra.Command {
 		},
 	}
 
+	cmd.AddCommand(NewCmdStepPostAction(commonOpts))
 	cmd.AddCommand(NewCmdStepPostBuild(commonOpts))
+	cmd.AddCommand(NewCmdStepPostExec(commonOpts))
 	cmd.AddCommand(NewCmdStepPostInstall(commonOpts))
 	cmd.AddCommand(NewCmdStepPostRun(commonOpts))
 
@@ -0,0 +1,285 @@
+package post
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	jenkinsv1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/chats"
+	"github.com/jenkins-x/jx/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/collector"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/kube/naming"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/tekton/syntax"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// StepPostActionOptions contains the command line flags
+type StepPostActionOptions struct {
+	opts.StepOptions
+
+	Name       string
+	Condition  string
+	Stage      string
+	Options    []string
+	MarkerFile string
+	Dir        string
+}
+
+var (
+	stepPostActionLong = templates.LongDesc(`
+		Runs one of the built-in post actions of a pipeline or stage if its condition is met by the outcome of the steps.
+
+		This step is generated for the post section of the jenkins-x.yml pipelines. The supported actions are:
+
+		* notify: posts a message to the developer channel of the chat configuration or the 'channel' option. The build controller already notifies the developer channel when a build starts and finishes so this is for stages or other channels
+		* collect-test-reports: stores the files matching the comma separated 'pattern' option in the team's storage for the 'classifier' option which defaults to 'tests'
+		* mark-activity: adds the options as labels on the PipelineActivity so the keys and values must be valid Kubernetes labels
+`)
+
+	stepPostActionExample = templates.Examples(`
+		# notify the developer channel if the steps failed
+		jx step post action --name notify --condition failure
+
+		# collect the test reports whatever the outcome
+		jx step post action --name collect-test-reports --condition always --option pattern=target/surefire-reports/*.xml
+`)
+)
+
+// NewCmdStepPostAction creates the command object
+func NewCmdStepPostAction(commonOpts *opts.CommonOptions) *cobra.Command {
+	options := &StepPostActionOptions{
+		StepOptions: opts.StepOptions{
+			CommonOptions: commonOpts,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "action",
+		Short:   "Runs a built-in post action if its condition is met",
+		Long:    stepPostActionLong,
+		Example: stepPostActionExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			helper.CheckErr(err)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.Name, "name", "n", "", "The name of the post action: "+strings.Join(syntax.PostActions, ", "))
+	cmd.Flags().StringVarP(&options.Condition, "condition", "c", string(syntax.PostConditionAlways), "The condition for running the action: success, failure or always")
+	cmd.Flags().StringVarP(&options.Stage, "stage", "s", "", "The name of the stage the post action belongs to if it is not for the whole pipeline")
+	cmd.Flags().StringArrayVarP(&options.Options, "option", "o", nil, "The options of the action as key=value pairs")
+	cmd.Flags().StringVarP(&options.MarkerFile, "marker-file", "", syntax.PostFailedMarkerFile, "The file which exists if a step has failed")
+	cmd.Flags().StringVarP(&options.Dir, "dir", "d", "", "The source directory. Defaults to the current directory")
+	return cmd
+}
+
+// Run implements this command
+func (o *StepPostActionOptions) Run() error {
+	if o.Name == "" {
+		return util.MissingOption("name")
+	}
+	if util.StringArrayIndex(syntax.PostActions, o.Name) < 0 {
+		return util.InvalidOption("name", o.Name, syntax.PostActions)
+	}
+	failed, err := util.FileExists(o.MarkerFile)
+	if err != nil {
+		return errors.Wrapf(err, "failed to check if file exists %s", o.MarkerFile)
+	}
+	if !syntax.PostCondition(o.Condition).IsMet(failed) {
+		log.Logger().Infof("skipping post action %s as the condition %s is not met", util.ColorInfo(o.Name), util.ColorInfo(o.Condition))
+		return nil
+	}
+	if o.Dir == "" {
+		o.Dir, err = os.Getwd()
+		if err != nil {
+			return err
+		}
+	}
+	options := map[string]string{}
+	for _, option := range o.Options {
+		parts := strings.SplitN(option, "=", 2)
+		if len(parts) != 2 {
+			return util.InvalidOptionf("option", option, "options must be of the form key=value")
+		}
+		options[parts[0]] = parts[1]
+	}
+
+	switch o.Name {
+	case syntax.PostActionNotify:
+		return o.notify(options, failed)
+	case syntax.PostActionCollectTestReports:
+		return o.collectTestReports(options)
+	default:
+		return o.markActivity(options)
+	}
+}
+
+func (o *StepPostActionOptions) notify(options map[string]string, failed bool) error {
+	projectConfig, _, err := config.LoadProjectConfig(o.Dir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load the project configuration in %s", o.Dir)
+	}
+	chatConfig := projectConfig.Chat
+	if chatConfig == nil {
+		log.Logger().Warnf("no chat configuration in the project so cannot notify")
+		return nil
+	}
+	chatConfig = &config.ChatConfig{
+		Kind:             chatConfig.Kind,
+		URL:              chatConfig.URL,
+		DeveloperChannel: chatConfig.DeveloperChannel,
+		UserChannel:      chatConfig.UserChannel,
+	}
+	if options["channel"] != "" {
+		chatConfig.DeveloperChannel = options["channel"]
+	}
+
+	owner, repo, branch, build := o.pipelineCoordinates()
+	message := chats.BuildFinishedMessage(owner, repo, branch, build, "", !failed)
+	if o.Stage != "" {
+		outcome := "succeeded"
+		if failed {
+			outcome = "failed"
+		}
+		message.Title = fmt.Sprintf("Stage %s of build #%s of %s/%s/%s %s", o.Stage, build, owner, repo, branch, outcome)
+		message.AddField("Stage", o.Stage)
+	}
+	if options["message"] != "" {
+		message.Title = options["message"]
+	}
+	_, err = o.NotifyDeveloperChannel(chatConfig, message)
+	if err != nil {
+		return errors.Wrapf(err, "failed to notify channel %s", chatConfig.DeveloperChannel)
+	}
+	return nil
+}
+
+func (o *StepPostActionOptions) collectTestReports(options map[string]string) error {
+	if options["pattern"] == "" {
+		return util.MissingOption("pattern")
+	}
+	patterns := strings.Split(options["pattern"], ",")
+	classifier := options["classifier"]
+	if classifier == "" {
+		classifier = "tests"
+	}
+	settings, err := o.TeamSettings()
+	if err != nil {
+		return err
+	}
+	location := settings.StorageLocationOrDefault(classifier)
+	if location.IsEmpty() {
+		gitInfo, err := o.FindGitInfo(o.Dir)
+		if err != nil {
+			return errors.Wrapf(err, "no storage location configured for %s and failed to find the git repository in %s", classifier, o.Dir)
+		}
+		location.GitURL = gitInfo.URL
+		location.GitBranch = "gh-pages"
+	}
+	coll, err := collector.NewCollector(location, settings, o.Git())
+	if err != nil {
+		return errors.Wrapf(err, "failed to create the collector for storage settings %s", location.Description())
+	}
+	owner, repo, branch, build := o.pipelineCoordinates()
+	storagePath := filepath.Join("jenkins-x", classifier, owner, repo, branch, build)
+	urls, err := coll.CollectFiles(patterns, storagePath, options["basedir"])
+	if err != nil {
+		return errors.Wrapf(err, "failed to collect patterns %s to path %s", strings.Join(patterns, ", "), storagePath)
+	}
+	for _, u := range urls {
+		log.Logger().Infof("collected: %s", util.ColorInfo(u))
+	}
+	if len(urls) == 0 {
+		return nil
+	}
+	return o.modifyActivity(func(activity *jenkinsv1.PipelineActivity) {
+		activity.Spec.Attachments = append(activity.Spec.Attachments, jenkinsv1.Attachment{
+			Name: classifier,
+			URLs: urls,
+		})
+	})
+}
+
+func (o *StepPostActionOptions) markActivity(options map[string]string) error {
+	if len(options) == 0 {
+		return util.MissingOption("option")
+	}
+	for k, v := range options {
+		if msgs := syntax.ValidateActivityLabel(k, v); len(msgs) > 0 {
+			return util.InvalidOptionf("option", k+"="+v, strings.Join(msgs, ", "))
+		}
+	}
+	return o.modifyActivity(func(activity *jenkinsv1.PipelineActivity) {
+		if activity.Labels == nil {
+			activity.Labels = map[string]string{}
+		}
+		for k, v := range options {
+			activity.Labels[k] = v
+		}
+	})
+}
+
+func (o *StepPostActionOptions) modifyActivity(fn func(activity *jenkinsv1.PipelineActivity)) error {
+	owner, repo, branch, build := o.pipelineCoordinates()
+	if owner == "" || repo == "" || build == "" {
+		log.Logger().Warnf("could not find the pipeline of the build so cannot update the PipelineActivity")
+		return nil
+	}
+	client, ns, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return errors.Wrap(err, "cannot create the JX client")
+	}
+	pipeline := util.UrlJoin(owner, repo, branch)
+	key := &kube.PromoteStepActivityKey{
+		PipelineActivityKey: kube.PipelineActivityKey{
+			Name:     naming.ToValidName(fmt.Sprintf("%s-%s-%s-%s", owner, repo, branch, build)),
+			Pipeline: pipeline,
+			Build:    build,
+			GitInfo: &gits.GitRepository{
+				Organisation: owner,
+				Name:         repo,
+			},
+		},
+	}
+	activity, _, err := key.GetOrCreate(client, ns)
+	if err != nil {
+		return err
+	}
+	fn(activity)
+	_, err = client.JenkinsV1().PipelineActivities(ns).PatchUpdate(activity)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update PipelineActivity %s", activity.Name)
+	}
+	return nil
+}
+
+// pipelineCoordinates returns the owner, repository, branch and build number of the pipeline from the environment
+// variables of the step, falling back to the local git repository
+func (o *StepPostActionOptions) pipelineCoordinates() (string, string, string, string) {
+	owner := os.Getenv("REPO_OWNER")
+	repo := os.Getenv("REPO_NAME")
+	branch := os.Getenv("BRANCH_NAME")
+	if owner == "" || repo == "" {
+		gitInfo, err := o.FindGitInfo(o.Dir)
+		if err == nil && gitInfo != nil {
+			owner = gitInfo.Organisation
+			repo = gitInfo.Name
+		}
+	}
+	if branch == "" {
+		branch, _ = o.Git().Branch(o.Dir)
+	}
+	return owner, repo, branch, o.GetBuildNumber()
+}
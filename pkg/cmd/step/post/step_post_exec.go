@@ -0,0 +1,93 @@
+package post
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/jenkins-x/jx/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/tekton/syntax"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// StepPostExecOptions contains the command line flags
+type StepPostExecOptions struct {
+	opts.StepOptions
+
+	MarkerFile string
+}
+
+var (
+	stepPostExecLong = templates.LongDesc(`
+		Runs a command of a pipeline step which has post actions, recording a failure of the command in the marker file rather than failing the step.
+
+		The command is skipped if an earlier step has already failed. This step is generated for the steps of pipelines with a post section which are not run by a shell, such as kaniko, so that the post actions still run if they fail.
+`)
+
+	stepPostExecExample = templates.Examples(`
+		# run kaniko recording any failure for the post actions
+		jx step post exec -- /kaniko/executor --dockerfile=Dockerfile --destination=myorg/myapp:1.0.1
+`)
+)
+
+// NewCmdStepPostExec creates the command object
+func NewCmdStepPostExec(commonOpts *opts.CommonOptions) *cobra.Command {
+	options := &StepPostExecOptions{
+		StepOptions: opts.StepOptions{
+			CommonOptions: commonOpts,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "exec -- command [args...]",
+		Short:   "Runs a command recording its failure for the post actions",
+		Long:    stepPostExecLong,
+		Example: stepPostExecExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			helper.CheckErr(err)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.MarkerFile, "marker-file", "", syntax.PostFailedMarkerFile, "The file which is created if the command fails")
+	return cmd
+}
+
+// Run implements this command
+func (o *StepPostExecOptions) Run() error {
+	if len(o.Args) == 0 {
+		return errors.Errorf("missing the command to run")
+	}
+	failed, err := util.FileExists(o.MarkerFile)
+	if err != nil {
+		return errors.Wrapf(err, "failed to check if file exists %s", o.MarkerFile)
+	}
+	if failed {
+		log.Logger().Infof("skipping as an earlier step failed")
+		return nil
+	}
+	cmd := util.Command{
+		Name: o.Args[0],
+		Args: o.Args[1:],
+		Out:  o.Out,
+		Err:  o.Err,
+		In:   os.Stdin,
+	}
+	_, err = cmd.RunWithoutRetry()
+	if err == nil {
+		return nil
+	}
+	log.Logger().Warnf("%s failed: %s", o.Args[0], err)
+	err = os.MkdirAll(filepath.Dir(o.MarkerFile), util.DefaultWritePermissions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create the directory of %s", o.MarkerFile)
+	}
+	return ioutil.WriteFile(o.MarkerFile, []byte{}, util.DefaultWritePermissions)
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"github.com/jenkins-x/jx/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/pkg/cmd/opts"
+	"github.com/spf13/cobra"
+)
+
+// StepServicesOptions contains the command line flags
+type StepServicesOptions struct {
+	*opts.CommonOptions
+}
+
+// NewCmdStepServices creates the command object for the "step services" command
+func NewCmdStepServices(commonOpts *opts.CommonOptions) *cobra.Command {
+	options := &StepServicesOptions{
+		CommonOptions: commonOpts,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "services",
+		Short: "starts and stops the services of pipeline stages",
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			helper.CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(NewCmdStepServicesStart(commonOpts))
+	cmd.AddCommand(NewCmdStepServicesStop(commonOpts))
+
+	return cmd
+}
+
+// Run implements this command
+func (o *StepServicesOptions) Run() error {
+	return o.Cmd.Help()
+}
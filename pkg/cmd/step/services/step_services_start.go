@@ -0,0 +1,120 @@
+package services
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/tekton/services"
+	"github.com/jenkins-x/jx/pkg/tekton/syntax"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StepServicesStartOptions contains the command line flags
+type StepServicesStartOptions struct {
+	opts.StepOptions
+
+	Group    string
+	Services []string
+	OwnerPod string
+	Timeout  time.Duration
+}
+
+var (
+	stepServicesStartLong = templates.LongDesc(`
+		Starts the services of a pipeline stage, such as databases, and waits for them to be ready.
+
+		Each service runs in its own Pod with a Service named after it so that the steps of the stage can reach it. The Pods are owned by the Pod running this step so that they are removed with it.
+
+		This step is generated for the services of the jenkins-x.yml pipelines and stages.
+`)
+
+	stepServicesStartExample = templates.Examples(`
+		# start a postgres service
+		jx step services start --group jenkins-x-myapp-master-build-1 --service '{"name": "postgres", "image": "postgres:11", "ports": [5432]}'
+`)
+)
+
+// NewCmdStepServicesStart creates the command object
+func NewCmdStepServicesStart(commonOpts *opts.CommonOptions) *cobra.Command {
+	options := &StepServicesStartOptions{
+		StepOptions: opts.StepOptions{
+			CommonOptions: commonOpts,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "start",
+		Short:   "Starts the services of a pipeline stage",
+		Long:    stepServicesStartLong,
+		Example: stepServicesStartExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			helper.CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&options.Group, "group", "g", "", "The name of the group of services, which is the name of the Task of the stage")
+	cmd.Flags().StringArrayVarP(&options.Services, "service", "s", nil, "The JSON definition of a service")
+	cmd.Flags().StringVarP(&options.OwnerPod, "owner-pod", "", os.Getenv("HOSTNAME"), "The name of the Pod which owns the services. Defaults to the current Pod")
+	cmd.Flags().DurationVarP(&options.Timeout, "timeout", "t", 5*time.Minute, "The timeout for each service to be ready")
+	return cmd
+}
+
+// Run implements this command
+func (o *StepServicesStartOptions) Run() error {
+	if o.Group == "" {
+		return util.MissingOption("group")
+	}
+	if len(o.Services) == 0 {
+		return util.MissingOption("service")
+	}
+	var svcs []syntax.Service
+	for _, text := range o.Services {
+		s := syntax.Service{}
+		err := json.Unmarshal([]byte(text), &s)
+		if err != nil {
+			return util.InvalidOptionError("service", text, err)
+		}
+		svcs = append(svcs, s)
+	}
+
+	kubeClient, ns, err := o.KubeClientAndNamespace()
+	if err != nil {
+		return err
+	}
+
+	var owner *metav1.OwnerReference
+	if o.OwnerPod != "" {
+		pod, err := kubeClient.CoreV1().Pods(ns).Get(o.OwnerPod, metav1.GetOptions{})
+		if err != nil {
+			log.Logger().Warnf("failed to find the owner Pod %s so the services will not be removed with it: %s", o.OwnerPod, err.Error())
+		} else {
+			owner = &metav1.OwnerReference{
+				APIVersion: "v1",
+				Kind:       "Pod",
+				Name:       pod.Name,
+				UID:        pod.UID,
+			}
+		}
+	}
+
+	names, err := services.Start(kubeClient, ns, o.Group, owner, svcs)
+	if err != nil {
+		return err
+	}
+	err = services.WaitForReady(kubeClient, ns, names, o.Timeout)
+	if err != nil {
+		return errors.Wrapf(err, "failed waiting for the services of %s", o.Group)
+	}
+	log.Logger().Infof("the services of %s are ready", util.ColorInfo(o.Group))
+	return nil
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"github.com/jenkins-x/jx/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/tekton/services"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// StepServicesStopOptions contains the command line flags
+type StepServicesStopOptions struct {
+	opts.StepOptions
+
+	Group string
+}
+
+var (
+	stepServicesStopLong = templates.LongDesc(`
+		Stops the services of a pipeline stage by deleting their Pods and Services.
+
+		This step is generated for the services of the jenkins-x.yml pipelines and stages.
+`)
+
+	stepServicesStopExample = templates.Examples(`
+		# stop the services of a stage
+		jx step services stop --group jenkins-x-myapp-master-build-1
+`)
+)
+
+// NewCmdStepServicesStop creates the command object
+func NewCmdStepServicesStop(commonOpts *opts.CommonOptions) *cobra.Command {
+	options := &StepServicesStopOptions{
+		StepOptions: opts.StepOptions{
+			CommonOptions: commonOpts,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "stop",
+		Short:   "Stops the services of a pipeline stage",
+		Long:    stepServicesStopLong,
+		Example: stepServicesStopExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			helper.CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&options.Group, "group", "g", "", "The name of the group of services, which is the name of the Task of the stage")
+	return cmd
+}
+
+// Run implements this command
+func (o *StepServicesStopOptions) Run() error {
+	if o.Group == "" {
+		return util.MissingOption("group")
+	}
+	kubeClient, ns, err := o.KubeClientAndNamespace()
+	if err != nil {
+		return err
+	}
+	return services.Stop(kubeClient, ns, o.Group)
+}
@@ -146,6 +146,9 @@ e.g. define environment variables GIT_USERNAME and GIT_API_TOKEN
 
 	GitHubIssueRegex = regexp.MustCompile(`(\#\d+)`)
 	JIRAIssueRegex   = regexp.MustCompile(`[A-Z][A-Z]+-(\d+)`)
+	// IssueKeyRegex matches issue keys such as ENG-123 or ENG2-7 used by Linear and YouTrack whose project keys may
+	// contain digits and underscores
+	IssueKeyRegex = regexp.MustCompile(`\b[A-Z][A-Z0-9_]*-(\d+)\b`)
 )
 
 func NewCmdStepChangelog(commonOpts *opts.CommonOptions) *cobra.Command {
@@ -687,17 +690,23 @@ func (o *StepChangelogOptions) addIssuesAndPullRequests(spec *v1.ReleaseSpec, co
 	tracker := o.State.Tracker
 
 	gitProvider := o.State.GitProvider
-	if gitProvider == nil || !gitProvider.HasIssues() {
+	if gitProvider == nil {
 		return nil
 	}
 	regex := GitHubIssueRegex
 	issueKind := issues.GetIssueProvider(tracker)
+	if issueKind == issues.Git && !gitProvider.HasIssues() {
+		return nil
+	}
 	if !o.State.LoggedIssueKind {
 		o.State.LoggedIssueKind = true
 		log.Logger().Infof("Finding issues in commit messages using %s format", issueKind)
 	}
-	if issueKind == issues.Jira {
+	switch {
+	case issueKind == issues.Jira:
 		regex = JIRAIssueRegex
+	case issues.UsesIssueKeys(issueKind):
+		regex = IssueKeyRegex
 	}
 	message := fullCommitMessageText(rawCommit)
 
@@ -712,7 +721,12 @@ func (o *StepChangelogOptions) addIssuesAndPullRequests(spec *v1.ReleaseSpec, co
 		GitProvider: gitProvider,
 	}
 	for _, match := range matches {
-		for _, result := range match {
+		results := match
+		if regex == IssueKeyRegex {
+			// only use the whole match as the submatch of an issue key is just its number
+			results = match[:1]
+		}
+		for _, result := range results {
 			result = strings.TrimPrefix(result, "#")
 			if _, ok := o.State.FoundIssueNames[result]; !ok {
 				o.State.FoundIssueNames[result] = true
@@ -44,3 +44,15 @@ func TestCollapseDependencyUpdates(t *testing.T) {
 		})
 	}
 }
+
+func TestJIRAIssueRegex(t *testing.T) {
+	message := "fix: ENG-123 handle empty values\n\nalso closes JX-42 but not ENG2-7, lowercase eng-1 or 5-6"
+	matches := step.JIRAIssueRegex.FindAllStringSubmatch(message, -1)
+	assert.Equal(t, [][]string{{"ENG-123", "123"}, {"JX-42", "42"}}, matches)
+}
+
+func TestIssueKeyRegex(t *testing.T) {
+	message := "fix: ENG-123 handle empty values\n\nalso closes ENG2-7 and JX_CORE-42 but not lowercase eng-1 or 5-6"
+	matches := step.IssueKeyRegex.FindAllString(message, -1)
+	assert.Equal(t, []string{"ENG-123", "ENG2-7", "JX_CORE-42"}, matches)
+}
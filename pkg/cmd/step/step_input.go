@@ -0,0 +1,222 @@
+package step
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/kube/naming"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StepInputOptions contains the command line flags
+type StepInputOptions struct {
+	opts.StepOptions
+
+	Stage     string
+	Message   string
+	Approvers []string
+	Timeout   time.Duration
+	PollTime  time.Duration
+}
+
+var (
+	stepInputLong = templates.LongDesc(`
+		Pauses the current pipeline until a user approves or rejects it continuing.
+
+		The stage of the PipelineActivity of the pipeline is marked as WaitingForApproval until a decision is made via 'jx approve pipeline', 'jx reject pipeline' or a request, such as from a ChatOps plugin, posted to the /input endpoint of the pipeline runner and signed with its HMAC token. Rejecting the input or it timing out fails this step, which aborts the pipeline.
+
+		This step is generated for the input stages of the jenkins-x.yml pipelines.
+`)
+
+	stepInputExample = templates.Examples(`
+		# wait for alice or bob to approve the deployment
+		jx step input --stage Approve --message "Deploy to production?" --approver alice --approver bob --timeout 2h
+`)
+)
+
+// NewCmdStepInput creates the command object
+func NewCmdStepInput(commonOpts *opts.CommonOptions) *cobra.Command {
+	options := &StepInputOptions{
+		StepOptions: opts.StepOptions{
+			CommonOptions: commonOpts,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "input",
+		Short:   "Pauses the current pipeline until a user approves or rejects it continuing",
+		Long:    stepInputLong,
+		Example: stepInputExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			helper.CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&options.Stage, "stage", "s", "", "The name of the input stage in the PipelineActivity")
+	cmd.Flags().StringVarP(&options.Message, "message", "m", "Continue?", "The message displayed to the approvers")
+	cmd.Flags().StringArrayVarP(&options.Approvers, "approver", "a", nil, "The users who can approve or reject the input. Defaults to any user")
+	cmd.Flags().DurationVarP(&options.Timeout, "timeout", "t", time.Hour, "The time to wait for a decision before aborting the pipeline")
+	cmd.Flags().DurationVarP(&options.PollTime, "poll-time", "", 10*time.Second, "The time between checks for a decision")
+	return cmd
+}
+
+// Run implements this command
+func (o *StepInputOptions) Run() error {
+	if o.Stage == "" {
+		return util.MissingOption("stage")
+	}
+	owner, repo, branch, build, err := o.pipelineCoordinates()
+	if err != nil {
+		return err
+	}
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return errors.Wrap(err, "cannot create the JX client")
+	}
+	name := naming.ToValidName(fmt.Sprintf("%s-%s-%s-%s", owner, repo, branch, build))
+
+	input, err := o.startInput(jxClient, ns, name)
+	if err != nil {
+		return err
+	}
+	if input.IsPending() {
+		log.Logger().Infof("%s waiting for approval until %s", input.Message, input.Deadline.Format(time.RFC822))
+		log.Logger().Infof("to approve run: %s", util.ColorInfo(fmt.Sprintf("jx approve pipeline %s/%s/%s %s", owner, repo, branch, build)))
+		log.Logger().Infof("to reject run:  %s", util.ColorInfo(fmt.Sprintf("jx reject pipeline %s/%s/%s %s", owner, repo, branch, build)))
+	}
+	for input.IsPending() {
+		if time.Now().After(input.Deadline.Time) {
+			return o.timeoutInput(jxClient, ns, name)
+		}
+		time.Sleep(o.PollTime)
+		activity, err := jxClient.JenkinsV1().PipelineActivities(ns).Get(name, metav1.GetOptions{})
+		if err != nil {
+			log.Logger().Warnf("failed to get the PipelineActivity %s: %s", name, err.Error())
+			continue
+		}
+		_, stage, _ := kube.GetOrCreateStage(activity, o.Stage)
+		if stage.Input != nil {
+			input = stage.Input
+		}
+	}
+	return inputDecisionError(input)
+}
+
+// startInput marks the stage of the PipelineActivity as waiting for approval, returning the existing input if the
+// stage has already been decided such as when the pipeline is retried
+func (o *StepInputOptions) startInput(jxClient versioned.Interface, ns string, name string) (*v1.StageInputStep, error) {
+	activities := jxClient.JenkinsV1().PipelineActivities(ns)
+	activity, err := activities.Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find the PipelineActivity %s", name)
+	}
+	_, stage, _ := kube.GetOrCreateStage(activity, o.Stage)
+	if stage.Input != nil && !stage.Input.IsPending() {
+		return stage.Input, nil
+	}
+	now := metav1.Now()
+	deadline := metav1.NewTime(now.Add(o.Timeout))
+	stage.Input = &v1.StageInputStep{
+		CoreActivityStep: v1.CoreActivityStep{
+			Name:             o.Stage,
+			Status:           v1.ActivityStatusTypeWaitingForApproval,
+			StartedTimestamp: &now,
+		},
+		Message:   o.Message,
+		Approvers: o.Approvers,
+		Deadline:  &deadline,
+	}
+	stage.Status = v1.ActivityStatusTypeWaitingForApproval
+	if stage.StartedTimestamp == nil {
+		stage.StartedTimestamp = &now
+	}
+	_, err = activities.PatchUpdate(activity)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to update the PipelineActivity %s", name)
+	}
+	return stage.Input, nil
+}
+
+// timeoutInput aborts the input as no decision was made in time
+func (o *StepInputOptions) timeoutInput(jxClient versioned.Interface, ns string, name string) error {
+	activities := jxClient.JenkinsV1().PipelineActivities(ns)
+	activity, err := activities.Get(name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to find the PipelineActivity %s", name)
+	}
+	_, stage, _ := kube.GetOrCreateStage(activity, o.Stage)
+	if stage.Input != nil && !stage.Input.IsPending() {
+		// a decision was made at the last moment
+		return inputDecisionError(stage.Input)
+	}
+	if stage.Input == nil {
+		stage.Input = &v1.StageInputStep{}
+	}
+	now := metav1.Now()
+	stage.Input.Status = v1.ActivityStatusTypeAborted
+	stage.Input.CompletedTimestamp = &now
+	stage.Status = v1.ActivityStatusTypeAborted
+	_, err = activities.PatchUpdate(activity)
+	if err != nil {
+		log.Logger().Warnf("failed to update the PipelineActivity %s: %s", name, err.Error())
+	}
+	return fmt.Errorf("timed out after %s waiting for approval of stage %s", o.Timeout.String(), o.Stage)
+}
+
+// inputDecisionError returns an error if the input was not approved
+func inputDecisionError(input *v1.StageInputStep) error {
+	if input.Status == v1.ActivityStatusTypeSucceeded {
+		if input.Decision != nil {
+			log.Logger().Infof("approved by %s", util.ColorInfo(input.Decision.User))
+		}
+		return nil
+	}
+	if input.Decision != nil {
+		message := fmt.Sprintf("rejected by %s", input.Decision.User)
+		if input.Decision.Comment != "" {
+			message += ": " + input.Decision.Comment
+		}
+		return errors.New(message)
+	}
+	return fmt.Errorf("the input was %s", string(input.Status))
+}
+
+// pipelineCoordinates returns the owner, repository, branch and build number of the current pipeline
+func (o *StepInputOptions) pipelineCoordinates() (string, string, string, string, error) {
+	owner := os.Getenv("REPO_OWNER")
+	repo := os.Getenv("REPO_NAME")
+	branch := os.Getenv(envVarBranchName)
+	if owner == "" || repo == "" {
+		gitInfo, err := o.FindGitInfo("")
+		if err != nil {
+			return "", "", "", "", errors.Wrap(err, "failed to find the git information of the current directory")
+		}
+		owner = gitInfo.Organisation
+		repo = gitInfo.Name
+	}
+	if branch == "" {
+		var err error
+		branch, err = o.Git().Branch("")
+		if err != nil {
+			return "", "", "", "", err
+		}
+	}
+	build := o.GetBuildNumber()
+	if branch == "" || build == "" {
+		return "", "", "", "", fmt.Errorf("could not find the branch and build number of the pipeline of stage %s", o.Stage)
+	}
+	return owner, repo, branch, build, nil
+}
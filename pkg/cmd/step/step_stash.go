@@ -1,6 +1,7 @@
 package step
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -27,6 +28,7 @@ import (
 // StepStashOptions contains the command line flags
 type StepStashOptions struct {
 	opts.StepOptions
+	Name            string
 	Pattern         []string
 	Dir             string
 	ToPath          string
@@ -40,6 +42,11 @@ const (
 	envVarBranchName = "BRANCH_NAME"
 	envVarSourceUrl  = "SOURCE_URL"
 
+	// namedStashClassifier the default classifier of named stashes
+	namedStashClassifier = "stash"
+	// namedStashAttachmentPrefix the prefix of the names of the PipelineActivity attachments for named stashes
+	namedStashAttachmentPrefix = "stash:"
+
 	// storageSupportDescription common text for long command descriptions around storage
 	StorageSupportDescription = `
 Currently Jenkins X supports storing files into a branch of a git repository or in cloud blob storage like S3, GCS, Azure blobs etc.
@@ -51,6 +58,8 @@ When using Cloud Storage we use URLs like 's3://nameOfBucket' on AWS, 'gs://anot
 var (
 	stepStashLong = templates.LongDesc(`
 		This pipeline step stashes the specified files from the build into some stable storage location.
+
+		If a name is specified the files are stored as a single archive which later stages of the same pipeline can fetch via 'jx step unstash --name'. This is used for the stash option of stages in jenkins-x.yml.
 ` + StorageSupportDescription + opts.SeeAlsoText("jx step unstash", "jx edit storage"))
 
 	stepStashExample = templates.Examples(`
@@ -72,6 +81,9 @@ var (
 		# lets collect some files to a specific cloud storage bucket and specify the path to store them inside
 		jx step stash -c tests -p "target/test-reports/*" ---bucket-url gs://my-gcp-bucket --to-path tests/mystuff
 
+		# lets stash the build output as a named stash for later stages of the pipeline
+		jx step stash --name binaries -p "bin/*"
+
 `)
 )
 
@@ -98,6 +110,7 @@ func NewCmdStepStash(commonOpts *opts.CommonOptions) *cobra.Command {
 
 	addStorageLocationFlags(cmd, &options.StorageLocation)
 
+	cmd.Flags().StringVarP(&options.Name, "name", "n", "", "The name of the stash for later stages of the pipeline to unstash. If specified the files are stored as a single archive and the classifier defaults to '"+namedStashClassifier+"'")
 	cmd.Flags().StringArrayVarP(&options.Pattern, "pattern", "p", nil, "Specify the pattern to use to look for files")
 	cmd.Flags().StringVarP(&options.Dir, "dir", "", "", "The source directory to try detect the current git repository or branch. Defaults to using the current directory")
 	cmd.Flags().StringVarP(&options.ToPath, "to-path", "t", "", "The path within the storage to store the files. If not specified it defaults to 'jenkins-x/$category/$owner/$repoName/$branch/$buildNumber'")
@@ -120,6 +133,10 @@ func (o *StepStashOptions) Run() error {
 		return util.MissingOption("pattern")
 	}
 	classifier := o.StorageLocation.Classifier
+	if classifier == "" && o.Name != "" {
+		classifier = namedStashClassifier
+		o.StorageLocation.Classifier = classifier
+	}
 	if classifier == "" {
 		return util.MissingOption("classifier")
 	}
@@ -206,9 +223,20 @@ func (o *StepStashOptions) Run() error {
 		storagePath = filepath.Join("jenkins-x", classifier, projectOrg, projectRepoName, projectBranchName, buildNo)
 	}
 
-	urls, err := coll.CollectFiles(o.Pattern, storagePath, o.Basedir)
-	if err != nil {
-		return errors.Wrapf(err, "failed to collect patterns %s to path %s", strings.Join(o.Pattern, ", "), storagePath)
+	var urls []string
+	attachmentName := classifier
+	if o.Name != "" {
+		attachmentName = namedStashAttachmentPrefix + o.Name
+		u, err := o.collectArchive(coll, filepath.Join(storagePath, naming.ToValidName(o.Name)+".tar.gz"))
+		if err != nil {
+			return errors.Wrapf(err, "failed to stash %s", o.Name)
+		}
+		urls = append(urls, u)
+	} else {
+		urls, err = coll.CollectFiles(o.Pattern, storagePath, o.Basedir)
+		if err != nil {
+			return errors.Wrapf(err, "failed to collect patterns %s to path %s", strings.Join(o.Pattern, ", "), storagePath)
+		}
 	}
 
 	for _, u := range urls {
@@ -236,7 +264,7 @@ func (o *StepStashOptions) Run() error {
 			return err
 		}
 		a.Spec.Attachments = append(a.Spec.Attachments, jenkinsv1.Attachment{
-			Name: classifier,
+			Name: attachmentName,
 			URLs: urls,
 		})
 		_, err = client.JenkinsV1().PipelineActivities(ns).PatchUpdate(a)
@@ -246,3 +274,26 @@ func (o *StepStashOptions) Run() error {
 	}
 	return nil
 }
+
+// collectArchive stores the files matching the patterns as a single gzipped tarball and returns its URL
+func (o *StepStashOptions) collectArchive(coll collector.Collector, outputName string) (string, error) {
+	var files []string
+	for _, p := range o.Pattern {
+		err := util.GlobAllFiles("", p, func(name string) error {
+			files = append(files, name)
+			return nil
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	if len(files) == 0 {
+		log.Logger().Warnf("no files match the patterns %s", strings.Join(o.Pattern, ", "))
+	}
+	buffer := &bytes.Buffer{}
+	err := util.TarGzFiles(buffer, files, o.Basedir)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create the archive of %d files", len(files))
+	}
+	return coll.CollectData(buffer.Bytes(), outputName)
+}
@@ -1,8 +1,10 @@
 package step
 
 import (
+	"fmt"
 	"io/ioutil"
 	"net/url"
+	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -14,10 +16,12 @@ import (
 	"github.com/jenkins-x/jx/pkg/cmd/opts"
 	"github.com/jenkins-x/jx/pkg/cmd/templates"
 	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/kube/naming"
 	"github.com/jenkins-x/jx/pkg/log"
 	"github.com/jenkins-x/jx/pkg/util"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // StepUnstashOptions contains the command line flags
@@ -25,6 +29,7 @@ type StepUnstashOptions struct {
 	opts.StepOptions
 
 	URL     string
+	Name    string
 	OutDir  string
 	Timeout time.Duration
 }
@@ -32,6 +37,8 @@ type StepUnstashOptions struct {
 var (
 	stepUnstashLong = templates.LongDesc(`
 		This pipeline step unstashes the files in storage to a local file or the console
+
+		If a name is specified the files of the named stash created by an earlier stage of the current pipeline via 'jx step stash --name' are extracted into the output directory. This is used for the unstash option of stages in jenkins-x.yml.
 ` + StorageSupportDescription + opts.SeeAlsoText("jx step stash", "jx edit storage"))
 
 	stepUnstashExample = templates.Examples(`
@@ -40,6 +47,9 @@ var (
 
 		# unstash the file to the from GCS to the console
 		jx step unstash -u gs://mybucket/foo/bar/output.log
+
+		# unstash the files of the 'binaries' stash of an earlier stage into the current directory
+		jx step unstash --name binaries
 `)
 )
 
@@ -64,6 +74,7 @@ func NewCmdStepUnstash(commonOpts *opts.CommonOptions) *cobra.Command {
 		},
 	}
 	cmd.Flags().StringVarP(&options.URL, "url", "u", "", "The fully qualified URL to the file to unstash including the storage host, path and file name")
+	cmd.Flags().StringVarP(&options.Name, "name", "n", "", "The name of a stash created by an earlier stage of the current pipeline")
 	cmd.Flags().StringVarP(&options.OutDir, "output", "o", "", "The output file or directory")
 	cmd.Flags().DurationVarP(&options.Timeout, "timeout", "t", time.Second*30, "The timeout period before we should fail unstashing the entry")
 	return cmd
@@ -71,6 +82,9 @@ func NewCmdStepUnstash(commonOpts *opts.CommonOptions) *cobra.Command {
 
 // Run runs the command
 func (o *StepUnstashOptions) Run() error {
+	if o.Name != "" {
+		return o.unstashNamed()
+	}
 	u := o.URL
 	if u == "" {
 		// TODO lets guess from the project etc...
@@ -116,6 +130,74 @@ func (o *StepUnstashOptions) Run() error {
 	return nil
 }
 
+// unstashNamed extracts the archive of the named stash of the current pipeline into the output directory
+func (o *StepUnstashOptions) unstashNamed() error {
+	dir := o.OutDir
+	if dir == "" {
+		dir = "."
+	}
+	gitInfo, err := o.FindGitInfo("")
+	if err != nil {
+		return errors.Wrap(err, "failed to find the git information of the current directory")
+	}
+	branch := os.Getenv(envVarBranchName)
+	if branch == "" {
+		branch, err = o.Git().Branch("")
+		if err != nil {
+			return err
+		}
+	}
+	buildNo := o.GetBuildNumber()
+	if branch == "" || buildNo == "" {
+		return fmt.Errorf("could not find the branch and build number of the pipeline to unstash %s from", o.Name)
+	}
+
+	client, ns, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return errors.Wrap(err, "cannot create the JX client")
+	}
+	// TODO this pipeline name construction needs moving to a shared lib, and other things refactoring to use it
+	name := naming.ToValidName(fmt.Sprintf("%s-%s-%s-%s", gitInfo.Organisation, gitInfo.Name, branch, buildNo))
+	activity, err := client.JenkinsV1().PipelineActivities(ns).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to find the PipelineActivity %s", name)
+	}
+	u := ""
+	for _, a := range activity.Spec.Attachments {
+		if a.Name == namedStashAttachmentPrefix+o.Name && len(a.URLs) > 0 {
+			u = a.URLs[0]
+		}
+	}
+	if u == "" {
+		return fmt.Errorf("no stash named %s has been created by PipelineActivity %s", o.Name, name)
+	}
+
+	authSvc, err := o.CreateGitAuthConfigService()
+	if err != nil {
+		return err
+	}
+	data, err := buckets.ReadURL(u, o.Timeout, CreateBucketHTTPFn(authSvc))
+	if err != nil {
+		return errors.Wrapf(err, "failed to read stash %s from %s", o.Name, u)
+	}
+	tmpFile, err := ioutil.TempFile("", "jx-unstash-")
+	if err != nil {
+		return errors.Wrap(err, "failed to create a temporary file")
+	}
+	defer os.Remove(tmpFile.Name())
+	_, err = tmpFile.Write(data)
+	tmpFile.Close()
+	if err != nil {
+		return errors.Wrapf(err, "failed to write file %s", tmpFile.Name())
+	}
+	err = util.UnTargzAll(tmpFile.Name(), dir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to extract stash %s into %s", o.Name, dir)
+	}
+	log.Logger().Infof("unstashed %s into %s", util.ColorInfo(o.Name), util.ColorInfo(dir))
+	return nil
+}
+
 // CreateBucketHTTPFn creates a function to transform a git URL to add the token for accessing a git based bucket
 func CreateBucketHTTPFn(authSvc auth.ConfigService) func(string) (string, error) {
 	return func(urlText string) (string, error) {
@@ -54,6 +54,8 @@ type StepSyntaxEffectiveOptions struct {
 var (
 	stepSyntaxEffectiveLong = templates.LongDesc(`
 		Reads the appropriate jenkins-x.yml, depending on context, from the current directory, if one exists, and outputs an effective representation of the pipelines
+
+		Any when conditions on the stages and steps are included so you can see which parts of the pipeline only run for certain branches, changed files, environment variables, Pull Request labels or pipeline kinds.
 `)
 
 	stepSyntaxEffectiveExample = templates.Examples(`
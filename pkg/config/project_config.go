@@ -43,6 +43,51 @@ type ProjectConfig struct {
 type PreviewEnvironmentConfig struct {
 	Disabled         bool `json:"disabled,omitempty"`
 	MaximumInstances int  `json:"maximumInstances,omitempty"`
+	// TTL is how long a preview environment lives after it was last deployed before 'jx gc previews' deletes it
+	// even though its Pull Request is still open such as '72h'
+	TTL string `json:"ttl,omitempty"`
+	// HibernateAfter is how long a preview environment can be idle, without being deployed, woken or serving traffic,
+	// before 'jx gc previews' scales its Deployments to zero such as '8h'. Traffic is only checked if 'jx gc previews'
+	// is given a Prometheus URL, otherwise previews which are in use but not deployed or woken are hibernated
+	HibernateAfter string `json:"hibernateAfter,omitempty"`
+	// Dependencies are the data stores of a preview environment which are seeded from snapshots before the application
+	// is deployed
+	Dependencies []PreviewDependency `json:"dependencies,omitempty"`
+}
+
+// PreviewDependency is a data store of a preview environment. It is a PersistentVolumeClaim in the preview namespace
+// which is seeded from either a VolumeSnapshot or a SQL dump in bucket storage and mounted by the chart of the application
+type PreviewDependency struct {
+	// Name is the name of the PersistentVolumeClaim
+	Name string `json:"name"`
+	// StorageClass is the storage class of the PersistentVolumeClaim. Defaults to the default storage class of the cluster
+	StorageClass string `json:"storageClass,omitempty"`
+	// Size is the size of the PersistentVolumeClaim such as '10Gi'. Defaults to the restore size of the VolumeSnapshot
+	// or 1Gi for a SQL dump
+	Size string `json:"size,omitempty"`
+	// VolumeSnapshot clones the PersistentVolumeClaim from a VolumeSnapshot
+	VolumeSnapshot *PreviewVolumeSnapshot `json:"volumeSnapshot,omitempty"`
+	// SQLDump copies a SQL dump from bucket storage into the PersistentVolumeClaim such as for the
+	// /docker-entrypoint-initdb.d directory of a database image
+	SQLDump *PreviewSQLDump `json:"sqlDump,omitempty"`
+}
+
+// PreviewVolumeSnapshot is a CSI VolumeSnapshot a preview dependency is cloned from
+type PreviewVolumeSnapshot struct {
+	// Name is the name of the VolumeSnapshot
+	Name string `json:"name"`
+	// Namespace is the namespace of the VolumeSnapshot. Defaults to the development namespace
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// PreviewSQLDump is a SQL dump in bucket storage a preview dependency is seeded from
+type PreviewSQLDump struct {
+	// URL is the URL of the dump such as 'gs://my-bucket/dumps/orders.sql.gz'
+	URL string `json:"url"`
+	// Image is the image of the Job which copies the dump. It must contain the jx binary
+	Image string `json:"image,omitempty"`
+	// ServiceAccount is the service account of the Job which copies the dump which can read the bucket
+	ServiceAccount string `json:"serviceAccount,omitempty"`
 }
 
 type IssueTrackerConfig struct {
@@ -684,9 +684,50 @@ func (in *Preview) DeepCopy() *Preview {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreviewDependency) DeepCopyInto(out *PreviewDependency) {
+	*out = *in
+	if in.VolumeSnapshot != nil {
+		in, out := &in.VolumeSnapshot, &out.VolumeSnapshot
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(PreviewVolumeSnapshot)
+			**out = **in
+		}
+	}
+	if in.SQLDump != nil {
+		in, out := &in.SQLDump, &out.SQLDump
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(PreviewSQLDump)
+			**out = **in
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreviewDependency.
+func (in *PreviewDependency) DeepCopy() *PreviewDependency {
+	if in == nil {
+		return nil
+	}
+	out := new(PreviewDependency)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PreviewEnvironmentConfig) DeepCopyInto(out *PreviewEnvironmentConfig) {
 	*out = *in
+	if in.Dependencies != nil {
+		in, out := &in.Dependencies, &out.Dependencies
+		*out = make([]PreviewDependency, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -700,6 +741,22 @@ func (in *PreviewEnvironmentConfig) DeepCopy() *PreviewEnvironmentConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreviewSQLDump) DeepCopyInto(out *PreviewSQLDump) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreviewSQLDump.
+func (in *PreviewSQLDump) DeepCopy() *PreviewSQLDump {
+	if in == nil {
+		return nil
+	}
+	out := new(PreviewSQLDump)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PreviewValuesConfig) DeepCopyInto(out *PreviewValuesConfig) {
 	*out = *in
@@ -734,6 +791,22 @@ func (in *PreviewValuesConfig) DeepCopy() *PreviewValuesConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreviewVolumeSnapshot) DeepCopyInto(out *PreviewVolumeSnapshot) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreviewVolumeSnapshot.
+func (in *PreviewVolumeSnapshot) DeepCopy() *PreviewVolumeSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(PreviewVolumeSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProjectConfig) DeepCopyInto(out *ProjectConfig) {
 	*out = *in
@@ -750,7 +823,7 @@ func (in *ProjectConfig) DeepCopyInto(out *ProjectConfig) {
 			*out = nil
 		} else {
 			*out = new(PreviewEnvironmentConfig)
-			**out = **in
+			(*in).DeepCopyInto(*out)
 		}
 	}
 	if in.IssueTracker != nil {
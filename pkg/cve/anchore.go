@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"strings"
 
 	"fmt"
 
@@ -217,3 +218,47 @@ func (a AnchoreProvider) getCVEsFromImageList(table *table.Table, vList *Vulnera
 	}
 	return nil
 }
+
+// GetImageVulnerabilities returns the vulnerabilities of the analysed images whose full tag matches the image
+func (a AnchoreProvider) GetImageVulnerabilities(image string) ([]ImageVulnerability, error) {
+	var images []Image
+	err := a.AnchoreGet(GetImages, &images)
+	if err != nil {
+		return nil, fmt.Errorf("error getting images %v", err)
+	}
+	var imageIDs []string
+	for _, i := range images {
+		for _, d := range i.ImageDetails {
+			if d.Fulltag == image || strings.HasSuffix(d.Fulltag, "/"+image) {
+				imageIDs = append(imageIDs, d.ImageId)
+			}
+		}
+	}
+	if len(imageIDs) == 0 {
+		return nil, fmt.Errorf("no analysed image found in anchore engine for %s", image)
+	}
+	answer := []ImageVulnerability{}
+	for _, imageID := range imageIDs {
+		var vList VulnerabilityList
+		subPath := fmt.Sprintf(getVulnerabilitiesByImageID, imageID, vulnerabilityType)
+		err := a.AnchoreGet(subPath, &vList)
+		if err != nil {
+			return nil, fmt.Errorf("error getting vulnerabilities for image %s: %v", imageID, err)
+		}
+		for _, v := range vList.Vulnerabilities {
+			fix := v.Fix
+			if fix == "None" {
+				fix = ""
+			}
+			answer = append(answer, ImageVulnerability{
+				Image:        image,
+				ID:           v.Vuln,
+				Severity:     ToSeverity(v.Severity),
+				Package:      v.Package,
+				FixedVersion: fix,
+				URL:          v.URL,
+			})
+		}
+	}
+	return answer, nil
+}
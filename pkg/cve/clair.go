@@ -0,0 +1,263 @@
+package cve
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/pkg/table"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	clairVulnerabilityReportPath = "/matcher/api/v1/vulnerability_report/%s"
+
+	dockerHubRegistry = "registry-1.docker.io"
+
+	manifestMediaTypes = "application/vnd.docker.distribution.manifest.v2+json, " +
+		"application/vnd.docker.distribution.manifest.list.v2+json, " +
+		"application/vnd.oci.image.manifest.v1+json, " +
+		"application/vnd.oci.image.index.v1+json"
+)
+
+// ClairPackage is a package in a Clair vulnerability report
+type ClairPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// ClairVulnerability is a vulnerability in a Clair vulnerability report
+type ClairVulnerability struct {
+	ID                 string        `json:"id"`
+	Name               string        `json:"name"`
+	Description        string        `json:"description"`
+	Links              string        `json:"links"`
+	Severity           string        `json:"severity"`
+	NormalizedSeverity string        `json:"normalized_severity"`
+	FixedInVersion     string        `json:"fixed_in_version"`
+	Package            *ClairPackage `json:"package"`
+}
+
+// ClairVulnerabilityReport is the vulnerability report of a manifest returned by the Clair v4 matcher
+type ClairVulnerabilityReport struct {
+	ManifestHash           string                        `json:"manifest_hash"`
+	Packages               map[string]ClairPackage       `json:"packages"`
+	Vulnerabilities        map[string]ClairVulnerability `json:"vulnerabilities"`
+	PackageVulnerabilities map[string][]string           `json:"package_vulnerabilities"`
+}
+
+// ClairProvider implements CVEProvider using the Clair v4 matcher API.
+//
+// Images must already have been indexed by Clair, for example by the registry or clairctl
+type ClairProvider struct {
+	Client  *http.Client
+	BaseURL string
+	Token   string
+	// ResolveDigest returns the manifest digest of an image
+	ResolveDigest func(image string) (string, error)
+}
+
+// NewClairProvider creates a new Clair v4 provider; the API token of the user is used as a bearer token if present
+func NewClairProvider(server *auth.AuthServer, user *auth.UserAuth) (CVEProvider, error) {
+	if server == nil || server.URL == "" {
+		return nil, fmt.Errorf("no URL for the Clair server")
+	}
+	provider := &ClairProvider{
+		Client:  http.DefaultClient,
+		BaseURL: strings.TrimSuffix(server.URL, "/"),
+	}
+	if user != nil {
+		provider.Token = user.ApiToken
+	}
+	provider.ResolveDigest = provider.resolveRegistryDigest
+	return provider, nil
+}
+
+// GetImageVulnerabilityTable adds the vulnerabilities of the images of the query to the table
+func (c *ClairProvider) GetImageVulnerabilityTable(jxClient versioned.Interface, client kubernetes.Interface, table *table.Table, query CVEQuery) error {
+	return getImageVulnerabilityTable(c, client, table, query)
+}
+
+// GetImageVulnerabilities returns the vulnerabilities Clair has matched for the manifest of the image
+func (c *ClairProvider) GetImageVulnerabilities(image string) ([]ImageVulnerability, error) {
+	digest := ""
+	if idx := strings.Index(image, "@"); idx >= 0 {
+		digest = image[idx+1:]
+	} else {
+		var err error
+		digest, err = c.ResolveDigest(image)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to resolve the digest of image %s", image)
+		}
+	}
+
+	u := c.BaseURL + fmt.Sprintf(clairVulnerabilityReportPath, digest)
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error getting vulnerability report from clair %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("image %s with digest %s has not been indexed by clair", image, digest)
+	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("error response getting vulnerability report from clair: %s", resp.Status)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	report := &ClairVulnerabilityReport{}
+	err = json.Unmarshal(data, report)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling %v", err)
+	}
+
+	answer := []ImageVulnerability{}
+	for _, v := range report.Vulnerabilities {
+		iv := ImageVulnerability{
+			Image:        image,
+			ID:           v.Name,
+			Severity:     ToSeverity(v.NormalizedSeverity),
+			FixedVersion: v.FixedInVersion,
+		}
+		if v.Package != nil {
+			iv.Package = v.Package.Name
+			iv.Version = v.Package.Version
+		}
+		links := strings.Fields(v.Links)
+		if len(links) > 0 {
+			iv.URL = links[0]
+		}
+		answer = append(answer, iv)
+	}
+	return answer, nil
+}
+
+// resolveRegistryDigest looks up the manifest digest of the image tag in its registry using anonymous access
+func (c *ClairProvider) resolveRegistryDigest(image string) (string, error) {
+	registry, repository, tag := parseImageReference(image)
+	u := fmt.Sprintf("https://%s/v2/%s/manifests/%s", registry, repository, tag)
+
+	resp, err := c.headManifest(u, "")
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		token, err := c.registryToken(resp.Header.Get("WWW-Authenticate"))
+		if err != nil {
+			return "", err
+		}
+		resp, err = c.headManifest(u, token)
+		if err != nil {
+			return "", err
+		}
+	}
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("error response getting manifest %s: %s", u, resp.Status)
+	}
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		return "", fmt.Errorf("no digest returned for manifest %s", u)
+	}
+	return digest, nil
+}
+
+func (c *ClairProvider) headManifest(u string, token string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodHead, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", manifestMediaTypes)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+// registryToken requests an anonymous token for the Bearer challenge of a registry
+func (c *ClairProvider) registryToken(challenge string) (string, error) {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return "", fmt.Errorf("unsupported registry authentication challenge %s", challenge)
+	}
+	params := map[string]string{}
+	for _, part := range strings.Split(strings.TrimPrefix(challenge, "Bearer "), ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) == 2 {
+			params[kv[0]] = strings.Trim(kv[1], `"`)
+		}
+	}
+	realm := params["realm"]
+	if realm == "" {
+		return "", fmt.Errorf("no realm in registry authentication challenge %s", challenge)
+	}
+	values := url.Values{}
+	if params["service"] != "" {
+		values.Set("service", params["service"])
+	}
+	if params["scope"] != "" {
+		values.Set("scope", params["scope"])
+	}
+	resp, err := c.Client.Get(realm + "?" + values.Encode())
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("error response getting registry token from %s: %s", realm, resp.Status)
+	}
+	token := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	err = json.NewDecoder(resp.Body).Decode(&token)
+	if err != nil {
+		return "", err
+	}
+	if token.Token != "" {
+		return token.Token, nil
+	}
+	return token.AccessToken, nil
+}
+
+// parseImageReference splits an image into its registry, repository and tag applying the Docker Hub defaults
+func parseImageReference(image string) (string, string, string) {
+	registry := dockerHubRegistry
+	repository := image
+	paths := strings.SplitN(image, "/", 2)
+	if len(paths) == 2 && (strings.ContainsAny(paths[0], ".:") || paths[0] == "localhost") {
+		registry = paths[0]
+		repository = paths[1]
+	}
+	if registry == "docker.io" || registry == "index.docker.io" {
+		registry = dockerHubRegistry
+	}
+	tag := "latest"
+	if idx := strings.LastIndex(repository, ":"); idx >= 0 {
+		tag = repository[idx+1:]
+		repository = repository[:idx]
+	}
+	if registry == dockerHubRegistry && !strings.Contains(repository, "/") {
+		repository = util.UrlJoin("library", repository)
+	}
+	return registry, repository, tag
+}
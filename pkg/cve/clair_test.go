@@ -0,0 +1,59 @@
+package cve_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/cve"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const clairDigest = "sha256:350efbb4aa3f6a8e5bb3ad3ae9d1b4c1b5e7d5bd1f6c2b28b8e1b7d5b1c0f8a2"
+
+func TestClairProviderVulnerabilityReport(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/matcher/api/v1/vulnerability_report/"+clairDigest, util.GetMockAPIResponseFromFile("test_data/clair", util.MethodMap{
+		"GET": "vulnerability_report.json",
+	}))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p, err := cve.CreateCVEProvider(cve.Clair, &auth.AuthServer{URL: server.URL}, nil)
+	require.NoError(t, err)
+	clair, ok := p.(*cve.ClairProvider)
+	require.True(t, ok)
+	clair.ResolveDigest = func(image string) (string, error) {
+		assert.Equal(t, "gcr.io/myorg/myapp:1.2.3", image)
+		return clairDigest, nil
+	}
+
+	vulnerabilities, err := p.GetImageVulnerabilities("gcr.io/myorg/myapp:1.2.3")
+	require.NoError(t, err)
+	require.Len(t, vulnerabilities, 2)
+	sort.Slice(vulnerabilities, func(i, j int) bool {
+		return vulnerabilities[i].ID < vulnerabilities[j].ID
+	})
+
+	assert.Equal(t, "CVE-2019-14697", vulnerabilities[0].ID)
+	assert.Equal(t, cve.SeverityCritical, vulnerabilities[0].Severity)
+	assert.Equal(t, "musl", vulnerabilities[0].Package)
+	assert.Equal(t, "CVE-2019-1549", vulnerabilities[1].ID)
+	assert.Equal(t, cve.SeverityMedium, vulnerabilities[1].Severity)
+	assert.Equal(t, "1.1.1d-r0", vulnerabilities[1].FixedVersion)
+	assert.Equal(t, "https://nvd.nist.gov/vuln/detail/CVE-2019-1549", vulnerabilities[1].URL)
+
+	// images referenced by digest do not need resolving
+	vulnerabilities, err = p.GetImageVulnerabilities("gcr.io/myorg/myapp@" + clairDigest)
+	require.NoError(t, err)
+	assert.Len(t, vulnerabilities, 2)
+
+	_, err = p.GetImageVulnerabilities("gcr.io/myorg/myapp@sha256:0000")
+	assert.Error(t, err)
+}
@@ -1,13 +1,32 @@
 package cve
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/auth"
 	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
 	"github.com/jenkins-x/jx/pkg/table"
+	"github.com/jenkins-x/jx/pkg/util"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
 const (
 	AnnotationCVEImageId = "jenkins-x.io/cve-image-id"
+
+	// Anchore the kind of the Anchore Engine CVE provider
+	Anchore = "anchore"
+	// Trivy the kind of the Trivy CVE provider
+	Trivy = "trivy"
+	// Clair the kind of the Clair v4 CVE provider
+	Clair = "clair"
+)
+
+var (
+	// CVEProviderKinds the kinds of CVE providers
+	CVEProviderKinds = []string{Anchore, Clair, Trivy}
 )
 
 type CVEQuery struct {
@@ -19,4 +38,159 @@ type CVEQuery struct {
 }
 type CVEProvider interface {
 	GetImageVulnerabilityTable(jxClient versioned.Interface, client kubernetes.Interface, table *table.Table, query CVEQuery) error
+
+	// GetImageVulnerabilities returns the vulnerabilities of the given image such as myregistry/myorg/myapp:1.2.3
+	GetImageVulnerabilities(image string) ([]ImageVulnerability, error)
+}
+
+// Severity is the severity of a vulnerability
+type Severity int
+
+const (
+	// SeverityUnknown the severity has not been assessed
+	SeverityUnknown Severity = iota
+	// SeverityNegligible a negligible vulnerability
+	SeverityNegligible
+	// SeverityLow a low severity vulnerability
+	SeverityLow
+	// SeverityMedium a medium severity vulnerability
+	SeverityMedium
+	// SeverityHigh a high severity vulnerability
+	SeverityHigh
+	// SeverityCritical a critical vulnerability
+	SeverityCritical
+)
+
+var severityNames = []string{"Unknown", "Negligible", "Low", "Medium", "High", "Critical"}
+
+func (s Severity) String() string {
+	if s < SeverityUnknown || s > SeverityCritical {
+		return severityNames[SeverityUnknown]
+	}
+	return severityNames[s]
+}
+
+// ParseSeverity parses the severity names used by the CVE providers ignoring case
+func ParseSeverity(name string) (Severity, error) {
+	for i, n := range severityNames {
+		if strings.EqualFold(n, name) {
+			return Severity(i), nil
+		}
+	}
+	if strings.EqualFold(name, "Defcon1") {
+		return SeverityCritical, nil
+	}
+	return SeverityUnknown, fmt.Errorf("unknown vulnerability severity %s; should be one of %s", name, strings.Join(severityNames, ", "))
+}
+
+// ToSeverity converts the severity name of a provider to a Severity defaulting to unknown
+func ToSeverity(name string) Severity {
+	s, _ := ParseSeverity(name)
+	return s
+}
+
+// ImageVulnerability is a vulnerability found in an image
+type ImageVulnerability struct {
+	Image        string
+	ID           string
+	Severity     Severity
+	Package      string
+	Version      string
+	FixedVersion string
+	URL          string
+}
+
+// CreateCVEProvider creates a CVE provider of the given kind
+func CreateCVEProvider(kind string, server *auth.AuthServer, user *auth.UserAuth) (CVEProvider, error) {
+	switch kind {
+	case Anchore, "":
+		return NewAnchoreProvider(server, user)
+	case Trivy:
+		return NewTrivyProvider(server)
+	case Clair:
+		return NewClairProvider(server, user)
+	default:
+		return nil, fmt.Errorf("unsupported CVE provider kind %s; should be one of %s", kind, strings.Join(CVEProviderKinds, ", "))
+	}
+}
+
+// FilterBySeverity returns the vulnerabilities whose severity is at least the given threshold
+func FilterBySeverity(vulnerabilities []ImageVulnerability, threshold Severity) []ImageVulnerability {
+	answer := []ImageVulnerability{}
+	for _, v := range vulnerabilities {
+		if v.Severity >= threshold {
+			answer = append(answer, v)
+		}
+	}
+	return answer
+}
+
+// AddVulnerabilityRows adds the vulnerabilities to the table with the most severe first
+func AddVulnerabilityRows(table *table.Table, vulnerabilities []ImageVulnerability) {
+	sort.SliceStable(vulnerabilities, func(i, j int) bool {
+		return vulnerabilities[i].Severity > vulnerabilities[j].Severity
+	})
+	for _, v := range vulnerabilities {
+		table.AddRow(v.Image, colorSeverity(v.Severity), v.ID, v.URL, v.Package, v.FixedVersion)
+	}
+}
+
+func colorSeverity(s Severity) string {
+	switch {
+	case s >= SeverityHigh:
+		return util.ColorError(s.String())
+	case s == SeverityMedium:
+		return util.ColorWarning(s.String())
+	default:
+		return util.ColorStatus(s.String())
+	}
+}
+
+// queryImages returns the images to check for a query using the image id, the image name and version or the
+// images of the pods running in the target namespace of the environment
+func queryImages(client kubernetes.Interface, query CVEQuery) ([]string, error) {
+	if query.ImageID != "" {
+		return []string{query.ImageID}, nil
+	}
+	if query.ImageName != "" {
+		image := query.ImageName
+		if query.Vesion != "" {
+			image += ":" + query.Vesion
+		}
+		return []string{image}, nil
+	}
+	if query.Environment != "" {
+		podList, err := client.CoreV1().Pods(query.TargetNamespace).List(meta_v1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		found := map[string]bool{}
+		images := []string{}
+		for _, p := range podList.Items {
+			for _, c := range p.Spec.Containers {
+				if !found[c.Image] {
+					found[c.Image] = true
+					images = append(images, c.Image)
+				}
+			}
+		}
+		return images, nil
+	}
+	return nil, fmt.Errorf("choose an image name, an optional version or an image to find vulnerabilities")
+}
+
+// getImageVulnerabilityTable adds the vulnerabilities of the images of the query to the table
+func getImageVulnerabilityTable(provider CVEProvider, client kubernetes.Interface, table *table.Table, query CVEQuery) error {
+	images, err := queryImages(client, query)
+	if err != nil {
+		return err
+	}
+	for _, image := range images {
+		vulnerabilities, err := provider.GetImageVulnerabilities(image)
+		if err != nil {
+			return fmt.Errorf("error getting vulnerabilities for image %s: %v", image, err)
+		}
+		AddVulnerabilityRows(table, vulnerabilities)
+	}
+	return nil
 }
@@ -0,0 +1,165 @@
+package cve
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/pkg/table"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+)
+
+// TrivyVulnerability is a vulnerability in a Trivy JSON report
+type TrivyVulnerability struct {
+	VulnerabilityID  string   `json:"VulnerabilityID"`
+	PkgName          string   `json:"PkgName"`
+	InstalledVersion string   `json:"InstalledVersion"`
+	FixedVersion     string   `json:"FixedVersion"`
+	Title            string   `json:"Title"`
+	Severity         string   `json:"Severity"`
+	PrimaryURL       string   `json:"PrimaryURL"`
+	References       []string `json:"References"`
+}
+
+// TrivyResult are the vulnerabilities of one target in a Trivy JSON report
+type TrivyResult struct {
+	Target          string               `json:"Target"`
+	Vulnerabilities []TrivyVulnerability `json:"Vulnerabilities"`
+}
+
+// TrivyReport is a Trivy JSON report; older versions of Trivy only output the results
+type TrivyReport struct {
+	ArtifactName string        `json:"ArtifactName"`
+	Results      []TrivyResult `json:"Results"`
+}
+
+// TrivyProvider implements CVEProvider using Trivy JSON reports.
+//
+// If a reports URL is configured the report of an image is loaded from the file or HTTP URL named after the image
+// otherwise the trivy binary is invoked to scan the image
+type TrivyProvider struct {
+	Client     *http.Client
+	ReportsURL string
+	// ScanImage runs trivy on an image returning its JSON report
+	ScanImage func(image string) ([]byte, error)
+}
+
+// NewTrivyProvider creates a new Trivy provider using the server URL as the location of the reports
+func NewTrivyProvider(server *auth.AuthServer) (CVEProvider, error) {
+	provider := &TrivyProvider{
+		Client:    http.DefaultClient,
+		ScanImage: runTrivy,
+	}
+	if server != nil {
+		provider.ReportsURL = server.URL
+	}
+	return provider, nil
+}
+
+// GetImageVulnerabilityTable adds the vulnerabilities of the images of the query to the table
+func (t *TrivyProvider) GetImageVulnerabilityTable(jxClient versioned.Interface, client kubernetes.Interface, table *table.Table, query CVEQuery) error {
+	return getImageVulnerabilityTable(t, client, table, query)
+}
+
+// GetImageVulnerabilities returns the vulnerabilities in the Trivy report of the image
+func (t *TrivyProvider) GetImageVulnerabilities(image string) ([]ImageVulnerability, error) {
+	data, err := t.loadReport(image)
+	if err != nil {
+		return nil, err
+	}
+	results, err := ParseTrivyReport(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the Trivy report of image %s", image)
+	}
+	answer := []ImageVulnerability{}
+	for _, r := range results {
+		for _, v := range r.Vulnerabilities {
+			u := v.PrimaryURL
+			if u == "" && len(v.References) > 0 {
+				u = v.References[0]
+			}
+			answer = append(answer, ImageVulnerability{
+				Image:        image,
+				ID:           v.VulnerabilityID,
+				Severity:     ToSeverity(v.Severity),
+				Package:      v.PkgName,
+				Version:      v.InstalledVersion,
+				FixedVersion: v.FixedVersion,
+				URL:          u,
+			})
+		}
+	}
+	return answer, nil
+}
+
+// ParseTrivyReport parses either format of Trivy JSON report returning the results
+func ParseTrivyReport(data []byte) ([]TrivyResult, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "[") {
+		results := []TrivyResult{}
+		err := json.Unmarshal(data, &results)
+		return results, err
+	}
+	report := &TrivyReport{}
+	err := json.Unmarshal(data, report)
+	return report.Results, err
+}
+
+// TrivyReportName returns the file name of the report of an image
+func TrivyReportName(image string) string {
+	replacer := strings.NewReplacer("/", "_", ":", "_", "@", "_")
+	return replacer.Replace(image) + ".json"
+}
+
+func (t *TrivyProvider) loadReport(image string) ([]byte, error) {
+	if t.ReportsURL == "" {
+		return t.ScanImage(image)
+	}
+	name := TrivyReportName(image)
+	if !strings.HasPrefix(t.ReportsURL, "http://") && !strings.HasPrefix(t.ReportsURL, "https://") {
+		path := filepath.Join(strings.TrimPrefix(t.ReportsURL, "file://"), name)
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load the Trivy report of image %s", image)
+		}
+		return data, nil
+	}
+	u := util.UrlJoin(t.ReportsURL, name)
+	resp, err := t.Client.Get(u)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load the Trivy report of image %s from %s", image, u)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("error response loading the Trivy report of image %s from %s: %s", image, u, resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// runTrivy scans the image with the trivy binary
+func runTrivy(image string) ([]byte, error) {
+	file, err := ioutil.TempFile("", "trivy-report-")
+	if err != nil {
+		return nil, err
+	}
+	file.Close()
+	defer os.Remove(file.Name())
+
+	cmd := util.Command{
+		Name: "trivy",
+		Args: []string{"--quiet", "image", "--format", "json", "--output", file.Name(), image},
+	}
+	_, err = cmd.RunWithoutRetry()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to scan image %s with trivy", image)
+	}
+	return ioutil.ReadFile(file.Name())
+}
@@ -0,0 +1,73 @@
+package cve_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/cve"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrivyProviderReportFromDirectory(t *testing.T) {
+	t.Parallel()
+
+	p, err := cve.CreateCVEProvider(cve.Trivy, &auth.AuthServer{URL: "test_data/trivy"}, nil)
+	require.NoError(t, err)
+
+	vulnerabilities, err := p.GetImageVulnerabilities("gcr.io/myorg/myapp:1.2.3")
+	require.NoError(t, err)
+	require.Len(t, vulnerabilities, 2)
+
+	assert.Equal(t, "CVE-2019-14697", vulnerabilities[0].ID)
+	assert.Equal(t, cve.SeverityCritical, vulnerabilities[0].Severity)
+	assert.Equal(t, "musl", vulnerabilities[0].Package)
+	assert.Equal(t, "1.1.22-r3", vulnerabilities[0].FixedVersion)
+	assert.Equal(t, "https://avd.aquasec.com/nvd/cve-2019-14697", vulnerabilities[0].URL)
+	assert.Equal(t, cve.SeverityMedium, vulnerabilities[1].Severity)
+	assert.Equal(t, "https://nvd.nist.gov/vuln/detail/CVE-2019-1549", vulnerabilities[1].URL)
+
+	blocking := cve.FilterBySeverity(vulnerabilities, cve.SeverityHigh)
+	require.Len(t, blocking, 1)
+	assert.Equal(t, "CVE-2019-14697", blocking[0].ID)
+}
+
+func TestTrivyProviderScansImage(t *testing.T) {
+	t.Parallel()
+
+	p, err := cve.NewTrivyProvider(nil)
+	require.NoError(t, err)
+	trivy, ok := p.(*cve.TrivyProvider)
+	require.True(t, ok)
+
+	scanned := ""
+	trivy.ScanImage = func(image string) ([]byte, error) {
+		scanned = image
+		return []byte(`[{"Target": "myorg/legacy:0.0.1", "Vulnerabilities": [{"VulnerabilityID": "CVE-2019-5094", "PkgName": "e2fsprogs", "Severity": "HIGH"}]}]`), nil
+	}
+	vulnerabilities, err := p.GetImageVulnerabilities("myorg/legacy:0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, "myorg/legacy:0.0.1", scanned)
+	require.Len(t, vulnerabilities, 1)
+	assert.Equal(t, cve.SeverityHigh, vulnerabilities[0].Severity)
+}
+
+func TestParseSeverity(t *testing.T) {
+	t.Parallel()
+
+	for name, expected := range map[string]cve.Severity{
+		"critical":   cve.SeverityCritical,
+		"HIGH":       cve.SeverityHigh,
+		"Medium":     cve.SeverityMedium,
+		"low":        cve.SeverityLow,
+		"Negligible": cve.SeverityNegligible,
+		"Unknown":    cve.SeverityUnknown,
+		"Defcon1":    cve.SeverityCritical,
+	} {
+		actual, err := cve.ParseSeverity(name)
+		assert.NoError(t, err, "severity %s", name)
+		assert.Equal(t, expected, actual, "severity %s", name)
+	}
+	_, err := cve.ParseSeverity("severe")
+	assert.Error(t, err)
+}
@@ -0,0 +1,95 @@
+package environments
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+)
+
+// AppVersionRevision is a version of an application in the history of an environment git repository
+type AppVersionRevision struct {
+	Version string
+	Sha     string
+	Date    time.Time
+}
+
+// GetAppVersionHistory returns the versions of the application in the requirements.yaml of the environment
+// git repository cloned into the given directory, newest first. Consecutive commits which did not change the
+// version of the application are collapsed into the earliest commit with that version
+func GetAppVersionHistory(dir string, app string) ([]AppVersionRevision, error) {
+	requirementsFile, err := helm.FindRequirementsFileName(dir)
+	if err != nil {
+		return nil, err
+	}
+	path, err := filepath.Rel(dir, requirementsFile)
+	if err != nil {
+		return nil, err
+	}
+	path = filepath.ToSlash(path)
+
+	cmd := util.Command{
+		Dir:  dir,
+		Name: "git",
+		Args: []string{"log", "--format=%H %cI", "--", path},
+	}
+	output, err := cmd.RunWithoutRetry()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the git history of %s", path)
+	}
+
+	answer := []AppVersionRevision{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		sha := fields[0]
+		cmd := util.Command{
+			Dir:  dir,
+			Name: "git",
+			Args: []string{"show", sha + ":" + path},
+		}
+		data, err := cmd.RunWithoutRetry()
+		if err != nil {
+			// the file was removed in this commit
+			log.Logger().Debugf("Could not load %s at commit %s: %s", path, sha, err)
+			continue
+		}
+		requirements, err := helm.LoadRequirements([]byte(data))
+		if err != nil {
+			log.Logger().Warnf("Failed to parse %s at commit %s: %s", path, sha, err)
+			continue
+		}
+		version := ""
+		for _, dep := range requirements.Dependencies {
+			if dep != nil && dep.Name == app {
+				version = dep.Version
+				break
+			}
+		}
+		if version == "" {
+			continue
+		}
+		date, err := time.Parse(time.RFC3339, fields[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse the date of commit %s", sha)
+		}
+		revision := AppVersionRevision{
+			Version: version,
+			Sha:     sha,
+			Date:    date,
+		}
+		last := len(answer) - 1
+		if last >= 0 && answer[last].Version == version {
+			answer[last] = revision
+		} else {
+			answer = append(answer, revision)
+		}
+	}
+	return answer, nil
+}
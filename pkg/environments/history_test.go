@@ -0,0 +1,60 @@
+package environments_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/environments"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const requirementsTemplate = `dependencies:
+- name: exposecontroller
+  repository: https://chartmuseum.jenkins-x.io
+  version: 2.3.89
+- name: myapp
+  repository: http://jenkins-x-chartmuseum:8080
+  version: %s
+`
+
+func TestGetAppVersionHistory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-env-history-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	fail := func(message string, _ ...int) {
+		t.Fatal(message)
+	}
+	gits.GitCmd(fail, dir, "init")
+	gits.GitCmd(fail, dir, "config", "user.name", "test")
+	gits.GitCmd(fail, dir, "config", "user.email", "test@example.com")
+
+	for _, version := range []string{"1.0.1", "1.0.2", "1.0.2", "1.0.3"} {
+		gits.WriteFile(fail, dir, "env/requirements.yaml", fmt.Sprintf(requirementsTemplate, version))
+		gits.GitCmd(fail, dir, "add", ".")
+		gits.GitCmd(fail, dir, "commit", "--allow-empty", "-m", "promote myapp to "+version)
+	}
+	// an unrelated change should not affect the history
+	gits.WriteFile(fail, dir, "README.md", "my environment")
+	gits.Add(fail, dir)
+	gits.Commit(fail, dir, "add readme")
+
+	history, err := environments.GetAppVersionHistory(dir, "myapp")
+	require.NoError(t, err)
+
+	versions := []string{}
+	for _, h := range history {
+		versions = append(versions, h.Version)
+		assert.NotEmpty(t, h.Sha)
+		assert.False(t, h.Date.IsZero())
+	}
+	assert.Equal(t, []string{"1.0.3", "1.0.2", "1.0.1"}, versions)
+
+	history, err = environments.GetAppVersionHistory(dir, "doesnotexist")
+	require.NoError(t, err)
+	assert.Empty(t, history)
+}
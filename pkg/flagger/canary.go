@@ -0,0 +1,210 @@
+package flagger
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// CanaryAPIPath is the path of the Flagger Canary API
+const CanaryAPIPath = "/apis/flagger.app/v1alpha3"
+
+// CanaryPhase is the phase of the analysis of a Flagger Canary
+type CanaryPhase string
+
+const (
+	// CanaryPhaseInitializing the canary is being initialized
+	CanaryPhaseInitializing CanaryPhase = "Initializing"
+	// CanaryPhaseInitialized the primary deployment has been created and the canary is waiting for a new revision
+	CanaryPhaseInitialized CanaryPhase = "Initialized"
+	// CanaryPhaseWaiting the canary is waiting for confirmation before the analysis starts
+	CanaryPhaseWaiting CanaryPhase = "Waiting"
+	// CanaryPhaseProgressing traffic is being shifted to the new revision while its metrics are checked
+	CanaryPhaseProgressing CanaryPhase = "Progressing"
+	// CanaryPhasePromoting the new revision is being copied to the primary deployment
+	CanaryPhasePromoting CanaryPhase = "Promoting"
+	// CanaryPhaseFinalising traffic is being routed back to the primary deployment
+	CanaryPhaseFinalising CanaryPhase = "Finalising"
+	// CanaryPhaseSucceeded the new revision has been promoted
+	CanaryPhaseSucceeded CanaryPhase = "Succeeded"
+	// CanaryPhaseFailed the analysis was aborted and traffic routed back to the previous revision
+	CanaryPhaseFailed CanaryPhase = "Failed"
+)
+
+// Canary is the subset of the Flagger Canary resource used to track the progressive delivery of a promotion
+type Canary struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CanarySpec   `json:"spec"`
+	Status CanaryStatus `json:"status"`
+}
+
+// CanaryList is a list of Flagger Canary resources
+type CanaryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []Canary `json:"items"`
+}
+
+// CanarySpec is the specification of a Flagger Canary
+type CanarySpec struct {
+	TargetRef      CanaryTargetReference `json:"targetRef"`
+	CanaryAnalysis CanaryAnalysis        `json:"canaryAnalysis"`
+}
+
+// CanaryTargetReference refers to the deployment the canary progressively delivers
+type CanaryTargetReference struct {
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind,omitempty"`
+	Name       string `json:"name"`
+}
+
+// CanaryAnalysis is the configuration of the analysis of a new revision
+type CanaryAnalysis struct {
+	Threshold  int            `json:"threshold"`
+	MaxWeight  int            `json:"maxWeight"`
+	StepWeight int            `json:"stepWeight"`
+	Iterations int            `json:"iterations,omitempty"`
+	Metrics    []CanaryMetric `json:"metrics,omitempty"`
+}
+
+// CanaryMetric is a metric which is checked on each iteration of the analysis
+type CanaryMetric struct {
+	Name      string  `json:"name"`
+	Interval  string  `json:"interval,omitempty"`
+	Threshold float64 `json:"threshold"`
+}
+
+// CanaryStatus is the status of the analysis of the current revision
+type CanaryStatus struct {
+	Phase              CanaryPhase       `json:"phase"`
+	FailedChecks       int               `json:"failedChecks"`
+	CanaryWeight       int               `json:"canaryWeight"`
+	Iterations         int               `json:"iterations"`
+	LastAppliedSpec    string            `json:"lastAppliedSpec,omitempty"`
+	LastTransitionTime metav1.Time       `json:"lastTransitionTime,omitempty"`
+	Conditions         []CanaryCondition `json:"conditions,omitempty"`
+}
+
+// CanaryCondition is a condition of a Flagger Canary
+type CanaryCondition struct {
+	Type               string      `json:"type"`
+	Status             string      `json:"status"`
+	LastUpdateTime     metav1.Time `json:"lastUpdateTime,omitempty"`
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	Reason             string      `json:"reason,omitempty"`
+	Message            string      `json:"message,omitempty"`
+}
+
+// IsCompleted returns true if the analysis of the canary has finished, successfully or not
+func (c *Canary) IsCompleted() bool {
+	return c.Status.Phase == CanaryPhaseSucceeded || c.Status.Phase == CanaryPhaseFailed
+}
+
+// IsFailed returns true if the analysis of the canary was aborted and the new revision rolled back
+func (c *Canary) IsFailed() bool {
+	return c.Status.Phase == CanaryPhaseFailed
+}
+
+// HasCompletedSince returns true if the analysis of the canary finished after the given time. This ignores the
+// result of the analysis of an earlier revision
+func (c *Canary) HasCompletedSince(t time.Time) bool {
+	return c.IsCompleted() && !c.Status.LastTransitionTime.Time.Before(t)
+}
+
+// Message returns the message of the latest condition of the canary
+func (c *Canary) Message() string {
+	answer := ""
+	var latest metav1.Time
+	for _, condition := range c.Status.Conditions {
+		if answer == "" || latest.Before(&condition.LastUpdateTime) {
+			answer = condition.Message
+			latest = condition.LastUpdateTime
+		}
+	}
+	return answer
+}
+
+// MetricNames returns the names of the metrics checked by the analysis of the canary
+func (c *Canary) MetricNames() []string {
+	var answer []string
+	for _, m := range c.Spec.CanaryAnalysis.Metrics {
+		answer = append(answer, m.Name)
+	}
+	return answer
+}
+
+// UpdatePromoteCanaryStep copies the status of the canary analysis to the step of the promotion
+func UpdatePromoteCanaryStep(c *Canary, step *v1.PromoteCanaryStep) {
+	step.Canary = c.Name
+	step.Phase = string(c.Status.Phase)
+	step.CanaryWeight = int32(c.Status.CanaryWeight)
+	step.MaxWeight = int32(c.Spec.CanaryAnalysis.MaxWeight)
+	step.FailedChecks = int32(c.Status.FailedChecks)
+	step.Threshold = int32(c.Spec.CanaryAnalysis.Threshold)
+	step.Iterations = int32(c.Status.Iterations)
+	step.Metrics = c.MetricNames()
+	step.Message = c.Message()
+}
+
+// FindCanaryForApp returns the canary which progressively delivers the given app or nil if there is none.
+// A canary matches if either it or the deployment it targets is named after the app, with or without
+// the prefix of the helm release
+func FindCanaryForApp(canaries []Canary, app string) *Canary {
+	matches := func(name string) bool {
+		return name == app || strings.HasSuffix(name, "-"+app)
+	}
+	for i := range canaries {
+		c := &canaries[i]
+		if c.Name == app || c.Spec.TargetRef.Name == app {
+			return c
+		}
+	}
+	for i := range canaries {
+		c := &canaries[i]
+		if matches(c.Name) || matches(c.Spec.TargetRef.Name) {
+			return c
+		}
+	}
+	return nil
+}
+
+// GetCanary returns the Flagger Canary of the given name in the namespace
+func GetCanary(kubeClient kubernetes.Interface, ns string, name string) (*Canary, error) {
+	canary := &Canary{}
+	err := getCanaryResource(kubeClient, fmt.Sprintf("%s/namespaces/%s/canaries/%s", CanaryAPIPath, ns, name), canary)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get Canary %s in namespace %s", name, ns)
+	}
+	return canary, nil
+}
+
+// ListCanaries returns the Flagger Canaries in the namespace
+func ListCanaries(kubeClient kubernetes.Interface, ns string) ([]Canary, error) {
+	list := &CanaryList{}
+	err := getCanaryResource(kubeClient, fmt.Sprintf("%s/namespaces/%s/canaries", CanaryAPIPath, ns), list)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list Canaries in namespace %s", ns)
+	}
+	return list.Items, nil
+}
+
+func getCanaryResource(kubeClient kubernetes.Interface, path string, result interface{}) error {
+	restClient := kubeClient.Discovery().RESTClient()
+	if restClient == nil {
+		return fmt.Errorf("no REST client available to query %s", path)
+	}
+	data, err := restClient.Get().AbsPath(path).DoRaw()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, result)
+}
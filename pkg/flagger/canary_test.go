@@ -0,0 +1,107 @@
+package flagger_test
+
+import (
+	"testing"
+	"time"
+
+	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/flagger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFindCanaryForApp(t *testing.T) {
+	t.Parallel()
+
+	canaries := []flagger.Canary{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "jx-other"},
+			Spec:       flagger.CanarySpec{TargetRef: flagger.CanaryTargetReference{Name: "jx-other"}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "jx-myapp"},
+			Spec:       flagger.CanarySpec{TargetRef: flagger.CanaryTargetReference{Name: "jx-myapp"}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "canary"},
+			Spec:       flagger.CanarySpec{TargetRef: flagger.CanaryTargetReference{Name: "myapp"}},
+		},
+	}
+
+	canary := flagger.FindCanaryForApp(canaries, "myapp")
+	require.NotNil(t, canary)
+	assert.Equal(t, "canary", canary.Name, "an exact match of the target should win over a release prefix")
+
+	canary = flagger.FindCanaryForApp(canaries, "other")
+	require.NotNil(t, canary)
+	assert.Equal(t, "jx-other", canary.Name)
+
+	assert.Nil(t, flagger.FindCanaryForApp(canaries, "missing"))
+}
+
+func TestCanaryHasCompletedSince(t *testing.T) {
+	t.Parallel()
+
+	start := time.Now()
+	canary := &flagger.Canary{
+		Status: flagger.CanaryStatus{
+			Phase:              flagger.CanaryPhaseSucceeded,
+			LastTransitionTime: metav1.NewTime(start.Add(-time.Minute)),
+		},
+	}
+	assert.False(t, canary.HasCompletedSince(start), "the analysis of an earlier revision should be ignored")
+
+	canary.Status.Phase = flagger.CanaryPhaseProgressing
+	canary.Status.LastTransitionTime = metav1.NewTime(start.Add(time.Minute))
+	assert.False(t, canary.HasCompletedSince(start))
+
+	canary.Status.Phase = flagger.CanaryPhaseFailed
+	assert.True(t, canary.HasCompletedSince(start))
+	assert.True(t, canary.IsFailed())
+}
+
+func TestUpdatePromoteCanaryStep(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	canary := &flagger.Canary{
+		ObjectMeta: metav1.ObjectMeta{Name: "jx-myapp"},
+		Spec: flagger.CanarySpec{
+			CanaryAnalysis: flagger.CanaryAnalysis{
+				Threshold:  5,
+				MaxWeight:  50,
+				StepWeight: 10,
+				Metrics: []flagger.CanaryMetric{
+					{Name: "request-success-rate", Threshold: 99},
+					{Name: "request-duration", Threshold: 500},
+				},
+			},
+		},
+		Status: flagger.CanaryStatus{
+			Phase:        flagger.CanaryPhaseProgressing,
+			CanaryWeight: 20,
+			FailedChecks: 2,
+			Iterations:   3,
+			Conditions: []flagger.CanaryCondition{
+				{Type: "Promoted", Message: "Canary analysis started", LastUpdateTime: metav1.NewTime(now.Add(-time.Minute))},
+				{Type: "Promoted", Message: "Advance jx-myapp.jx-staging canary weight 20", LastUpdateTime: metav1.NewTime(now)},
+			},
+		},
+	}
+
+	step := &v1.PromoteCanaryStep{}
+	flagger.UpdatePromoteCanaryStep(canary, step)
+
+	assert.Equal(t, &v1.PromoteCanaryStep{
+		Canary:       "jx-myapp",
+		Phase:        "Progressing",
+		CanaryWeight: 20,
+		MaxWeight:    50,
+		FailedChecks: 2,
+		Threshold:    5,
+		Iterations:   3,
+		Metrics:      []string{"request-success-rate", "request-duration"},
+		Message:      "Advance jx-myapp.jx-staging canary weight 20",
+	}, step)
+}
@@ -0,0 +1,1379 @@
+package gits
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blang/semver"
+	"github.com/google/go-github/github"
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	// azureDevOpsAPIVersion the REST API version used for all Azure DevOps requests
+	azureDevOpsAPIVersion = "5.1"
+
+	azureDevOpsBranchPrefix = "refs/heads/"
+	azureDevOpsTagPrefix    = "refs/tags/"
+
+	// AzureDevOpsWebHookUsername the basic auth username of the webhooks whose password is the webhook secret, as
+	// Azure DevOps service hooks cannot sign their payloads with an HMAC signature
+	AzureDevOpsWebHookUsername = "jenkins-x"
+)
+
+// azureDevOpsWebHookEvents the service hook events we subscribe to for a repository webhook
+var azureDevOpsWebHookEvents = []string{"git.push", "git.pullrequest.created", "git.pullrequest.updated", "git.pullrequest.merged"}
+
+// AzureDevOpsProvider implements GitProvider interface for Azure DevOps Repos.
+//
+// The server URL is expected to include the Azure DevOps organisation (e.g. https://dev.azure.com/myorg)
+// and the org argument of the GitProvider methods maps to an Azure DevOps project
+type AzureDevOpsProvider struct {
+	Client   *http.Client
+	Username string
+
+	Server auth.AuthServer
+	User   auth.UserAuth
+	Git    Gitter
+}
+
+type azureDevOpsProject struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url,omitempty"`
+	Visibility  string `json:"visibility,omitempty"`
+}
+
+type azureDevOpsRepository struct {
+	ID               string                 `json:"id,omitempty"`
+	Name             string                 `json:"name"`
+	URL              string                 `json:"url,omitempty"`
+	Project          *azureDevOpsProject    `json:"project,omitempty"`
+	DefaultBranch    string                 `json:"defaultBranch,omitempty"`
+	RemoteURL        string                 `json:"remoteUrl,omitempty"`
+	SSHURL           string                 `json:"sshUrl,omitempty"`
+	WebURL           string                 `json:"webUrl,omitempty"`
+	IsFork           bool                   `json:"isFork,omitempty"`
+	ParentRepository *azureDevOpsRepository `json:"parentRepository,omitempty"`
+}
+
+type azureDevOpsIdentity struct {
+	ID          string `json:"id,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+	UniqueName  string `json:"uniqueName,omitempty"`
+	URL         string `json:"url,omitempty"`
+	ImageURL    string `json:"imageUrl,omitempty"`
+}
+
+type azureDevOpsLabel struct {
+	ID     string `json:"id,omitempty"`
+	Name   string `json:"name"`
+	Active bool   `json:"active,omitempty"`
+}
+
+type azureDevOpsCommitRef struct {
+	CommitID string `json:"commitId"`
+	URL      string `json:"url,omitempty"`
+}
+
+type azureDevOpsCompletionOptions struct {
+	MergeCommitMessage string `json:"mergeCommitMessage,omitempty"`
+	DeleteSourceBranch bool   `json:"deleteSourceBranch,omitempty"`
+}
+
+type azureDevOpsPullRequest struct {
+	PullRequestID         int                           `json:"pullRequestId,omitempty"`
+	Status                string                        `json:"status,omitempty"`
+	MergeStatus           string                        `json:"mergeStatus,omitempty"`
+	Title                 string                        `json:"title,omitempty"`
+	Description           string                        `json:"description,omitempty"`
+	SourceRefName         string                        `json:"sourceRefName,omitempty"`
+	TargetRefName         string                        `json:"targetRefName,omitempty"`
+	CreatedBy             *azureDevOpsIdentity          `json:"createdBy,omitempty"`
+	CreationDate          *time.Time                    `json:"creationDate,omitempty"`
+	ClosedDate            *time.Time                    `json:"closedDate,omitempty"`
+	Reviewers             []azureDevOpsIdentity         `json:"reviewers,omitempty"`
+	Labels                []azureDevOpsLabel            `json:"labels,omitempty"`
+	Repository            *azureDevOpsRepository        `json:"repository,omitempty"`
+	LastMergeSourceCommit *azureDevOpsCommitRef         `json:"lastMergeSourceCommit,omitempty"`
+	LastMergeCommit       *azureDevOpsCommitRef         `json:"lastMergeCommit,omitempty"`
+	CompletionOptions     *azureDevOpsCompletionOptions `json:"completionOptions,omitempty"`
+	URL                   string                        `json:"url,omitempty"`
+}
+
+type azureDevOpsGitUserDate struct {
+	Name  string     `json:"name,omitempty"`
+	Email string     `json:"email,omitempty"`
+	Date  *time.Time `json:"date,omitempty"`
+}
+
+type azureDevOpsCommit struct {
+	CommitID  string                  `json:"commitId"`
+	Comment   string                  `json:"comment,omitempty"`
+	Author    *azureDevOpsGitUserDate `json:"author,omitempty"`
+	Committer *azureDevOpsGitUserDate `json:"committer,omitempty"`
+	URL       string                  `json:"url,omitempty"`
+	RemoteURL string                  `json:"remoteUrl,omitempty"`
+}
+
+type azureDevOpsStatusContext struct {
+	Name  string `json:"name"`
+	Genre string `json:"genre,omitempty"`
+}
+
+type azureDevOpsCommitStatus struct {
+	ID          int                      `json:"id,omitempty"`
+	State       string                   `json:"state"`
+	Description string                   `json:"description,omitempty"`
+	Context     azureDevOpsStatusContext `json:"context"`
+	TargetURL   string                   `json:"targetUrl,omitempty"`
+	URL         string                   `json:"url,omitempty"`
+}
+
+type azureDevOpsRef struct {
+	Name     string `json:"name"`
+	ObjectID string `json:"objectId"`
+	URL      string `json:"url,omitempty"`
+	IsLocked bool   `json:"isLocked,omitempty"`
+}
+
+type azureDevOpsItem struct {
+	ObjectID      string `json:"objectId"`
+	GitObjectType string `json:"gitObjectType,omitempty"`
+	CommitID      string `json:"commitId,omitempty"`
+	Path          string `json:"path"`
+	Content       string `json:"content,omitempty"`
+	URL           string `json:"url,omitempty"`
+}
+
+type azureDevOpsComment struct {
+	Content     string `json:"content"`
+	CommentType int    `json:"commentType"`
+}
+
+type azureDevOpsThread struct {
+	Comments []azureDevOpsComment `json:"comments"`
+	Status   int                  `json:"status"`
+}
+
+type azureDevOpsSubscription struct {
+	ID               string            `json:"id,omitempty"`
+	PublisherID      string            `json:"publisherId"`
+	EventType        string            `json:"eventType"`
+	ResourceVersion  string            `json:"resourceVersion"`
+	ConsumerID       string            `json:"consumerId"`
+	ConsumerActionID string            `json:"consumerActionId"`
+	PublisherInputs  map[string]string `json:"publisherInputs"`
+	ConsumerInputs   map[string]string `json:"consumerInputs"`
+}
+
+type azureDevOpsConnectionData struct {
+	AuthenticatedUser azureDevOpsIdentity `json:"authenticatedUser"`
+}
+
+type azureDevOpsProjectsPage struct {
+	Count int                  `json:"count"`
+	Value []azureDevOpsProject `json:"value"`
+}
+
+type azureDevOpsRepositoriesPage struct {
+	Count int                     `json:"count"`
+	Value []azureDevOpsRepository `json:"value"`
+}
+
+type azureDevOpsPullRequestsPage struct {
+	Count int                      `json:"count"`
+	Value []azureDevOpsPullRequest `json:"value"`
+}
+
+type azureDevOpsCommitsPage struct {
+	Count int                 `json:"count"`
+	Value []azureDevOpsCommit `json:"value"`
+}
+
+type azureDevOpsCommitStatusesPage struct {
+	Count int                       `json:"count"`
+	Value []azureDevOpsCommitStatus `json:"value"`
+}
+
+type azureDevOpsRefsPage struct {
+	Count int              `json:"count"`
+	Value []azureDevOpsRef `json:"value"`
+}
+
+type azureDevOpsSubscriptionsPage struct {
+	Count int                       `json:"count"`
+	Value []azureDevOpsSubscription `json:"value"`
+}
+
+// azureDevOpsError the error body returned by the Azure DevOps REST API
+type azureDevOpsError struct {
+	StatusCode int
+	Message    string `json:"message"`
+	TypeKey    string `json:"typeKey"`
+}
+
+func (e *azureDevOpsError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("status %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("status %d", e.StatusCode)
+}
+
+// IsAzureDevOpsNotFound returns true if the error is a 404 from the Azure DevOps REST API
+func IsAzureDevOpsNotFound(err error) bool {
+	if e, ok := errors.Cause(err).(*azureDevOpsError); ok {
+		return e.StatusCode == http.StatusNotFound
+	}
+	return false
+}
+
+// NewAzureDevOpsProvider creates a new Azure DevOps Repos git provider
+func NewAzureDevOpsProvider(server *auth.AuthServer, user *auth.UserAuth, git Gitter) (GitProvider, error) {
+	if server.URL == "" {
+		return nil, fmt.Errorf("no server URL for the Azure DevOps git provider")
+	}
+	provider := AzureDevOpsProvider{
+		Client:   &http.Client{Timeout: 30 * time.Second},
+		Server:   *server,
+		User:     *user,
+		Username: user.Username,
+		Git:      git,
+	}
+	return &provider, nil
+}
+
+// apiURL returns the REST API URL for the given path segments and query parameters
+func (a *AzureDevOpsProvider) apiURL(query url.Values, paths ...string) string {
+	elements := []string{strings.TrimSuffix(a.Server.URL, "/")}
+	for _, p := range paths {
+		elements = append(elements, url.PathEscape(p))
+	}
+	u := strings.Join(elements, "/")
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("api-version", azureDevOpsAPIVersion)
+	return u + "?" + query.Encode()
+}
+
+// projectURL returns the REST API URL for the given path within an Azure DevOps project
+func (a *AzureDevOpsProvider) projectURL(project string, query url.Values, paths ...string) string {
+	return a.apiURL(query, append([]string{project, "_apis"}, paths...)...)
+}
+
+// repoURL returns the REST API URL for the given path within an Azure DevOps git repository
+func (a *AzureDevOpsProvider) repoURL(project string, repo string, query url.Values, paths ...string) string {
+	return a.projectURL(project, query, append([]string{"git", "repositories", repo}, paths...)...)
+}
+
+// do invokes the REST API marshalling the optional body as JSON and unmarshalling the response into result
+func (a *AzureDevOpsProvider) do(method string, u string, body interface{}, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return errors.Wrapf(err, "failed to marshal request body for %s %s", method, u)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, u, reader)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create request %s %s", method, u)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.SetBasicAuth(a.Username, a.User.ApiToken)
+
+	resp, err := a.Client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to invoke %s %s", method, u)
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read response of %s %s", method, u)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := &azureDevOpsError{}
+		_ = json.Unmarshal(data, apiErr)
+		apiErr.StatusCode = resp.StatusCode
+		return errors.Wrapf(apiErr, "%s %s", method, u)
+	}
+	if result != nil && len(data) > 0 {
+		err = json.Unmarshal(data, result)
+		if err != nil {
+			return errors.Wrapf(err, "failed to unmarshal response of %s %s", method, u)
+		}
+	}
+	return nil
+}
+
+func (a *AzureDevOpsProvider) toGitRepository(project string, repo *azureDevOpsRepository) *GitRepository {
+	if repo.Project != nil && repo.Project.Name != "" {
+		project = repo.Project.Name
+	}
+	answer := &GitRepository{
+		Name:         repo.Name,
+		HTMLURL:      repo.WebURL,
+		CloneURL:     repo.RemoteURL,
+		SSHURL:       repo.SSHURL,
+		URL:          repo.WebURL,
+		Fork:         repo.IsFork,
+		Organisation: project,
+		Project:      project,
+		Private:      repo.Project == nil || repo.Project.Visibility != "public",
+	}
+	if answer.URL == "" {
+		answer.URL = repo.RemoteURL
+	}
+	u, err := url.Parse(a.Server.URL)
+	if err == nil {
+		answer.Scheme = u.Scheme
+		answer.Host = u.Host
+	}
+	return answer
+}
+
+func (a *AzureDevOpsProvider) toGitPullRequest(project string, repo string, pr *azureDevOpsPullRequest) *GitPullRequest {
+	number := pr.PullRequestID
+	state := azureDevOpsPullRequestState(pr.Status)
+	headRef := strings.TrimPrefix(pr.SourceRefName, azureDevOpsBranchPrefix)
+	merged := pr.Status == "completed"
+	mergeable := pr.MergeStatus == "succeeded"
+	answer := &GitPullRequest{
+		URL:       util.UrlJoin(a.repositoryWebURL(project, repo), "pullrequest", strconv.Itoa(number)),
+		Owner:     project,
+		Repo:      repo,
+		Number:    &number,
+		State:     &state,
+		HeadRef:   &headRef,
+		Merged:    &merged,
+		Mergeable: &mergeable,
+		Title:     pr.Title,
+		Body:      pr.Description,
+		UpdatedAt: pr.CreationDate,
+	}
+	if pr.CreatedBy != nil {
+		answer.Author = azureDevOpsIdentityToGitUser(pr.CreatedBy)
+	}
+	if pr.ClosedDate != nil && !pr.ClosedDate.IsZero() {
+		answer.ClosedAt = pr.ClosedDate
+		if merged {
+			answer.MergedAt = pr.ClosedDate
+		}
+	}
+	if pr.LastMergeSourceCommit != nil {
+		answer.LastCommitSha = pr.LastMergeSourceCommit.CommitID
+	}
+	if merged && pr.LastMergeCommit != nil {
+		sha := pr.LastMergeCommit.CommitID
+		answer.MergeCommitSHA = &sha
+	}
+	for i := range pr.Reviewers {
+		answer.RequestedReviewers = append(answer.RequestedReviewers, azureDevOpsIdentityToGitUser(&pr.Reviewers[i]))
+	}
+	for _, l := range pr.Labels {
+		name := l.Name
+		answer.Labels = append(answer.Labels, &Label{Name: &name})
+	}
+	return answer
+}
+
+// azureDevOpsPullRequestState maps the Azure DevOps pull request status to the GitHub style state
+func azureDevOpsPullRequestState(status string) string {
+	switch status {
+	case "active":
+		return "open"
+	case "completed", "abandoned":
+		return "closed"
+	default:
+		return status
+	}
+}
+
+func azureDevOpsIdentityToGitUser(identity *azureDevOpsIdentity) *GitUser {
+	return &GitUser{
+		Login:     identity.UniqueName,
+		Name:      identity.DisplayName,
+		URL:       identity.URL,
+		AvatarURL: identity.ImageURL,
+	}
+}
+
+func azureDevOpsCommitToGitCommit(commit *azureDevOpsCommit) *GitCommit {
+	answer := &GitCommit{
+		SHA:     commit.CommitID,
+		Message: commit.Comment,
+		URL:     commit.RemoteURL,
+	}
+	if commit.Author != nil {
+		answer.Author = &GitUser{
+			Name:  commit.Author.Name,
+			Email: commit.Author.Email,
+		}
+	}
+	if commit.Committer != nil {
+		answer.Committer = &GitUser{
+			Name:  commit.Committer.Name,
+			Email: commit.Committer.Email,
+		}
+	}
+	return answer
+}
+
+// azureDevOpsStatusToGitState maps the Azure DevOps commit status state to the GitHub style state
+func azureDevOpsStatusToGitState(state string) string {
+	switch state {
+	case "succeeded":
+		return "success"
+	case "failed":
+		return "failure"
+	case "error":
+		return "error"
+	default:
+		return "pending"
+	}
+}
+
+// gitStateToAzureDevOpsStatus maps the GitHub style commit status state to the Azure DevOps state
+func gitStateToAzureDevOpsStatus(state string) string {
+	switch state {
+	case "success":
+		return "succeeded"
+	case "failure":
+		return "failed"
+	case "error":
+		return "error"
+	default:
+		return "pending"
+	}
+}
+
+func azureDevOpsCommitStatusToGitStatus(status *azureDevOpsCommitStatus) *GitRepoStatus {
+	context := status.Context.Name
+	if status.Context.Genre != "" {
+		context = status.Context.Genre + "/" + context
+	}
+	return &GitRepoStatus{
+		ID:          strconv.Itoa(status.ID),
+		Context:     context,
+		URL:         status.URL,
+		State:       azureDevOpsStatusToGitState(status.State),
+		TargetURL:   status.TargetURL,
+		Description: status.Description,
+	}
+}
+
+// repositoryWebURL returns the URL to browse the given repository
+func (a *AzureDevOpsProvider) repositoryWebURL(project string, repo string) string {
+	return util.UrlJoin(a.Server.URL, project, "_git", repo)
+}
+
+func (a *AzureDevOpsProvider) getProject(project string) (*azureDevOpsProject, error) {
+	answer := &azureDevOpsProject{}
+	err := a.do(http.MethodGet, a.apiURL(nil, "_apis", "projects", project), nil, answer)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find Azure DevOps project %s", project)
+	}
+	return answer, nil
+}
+
+func (a *AzureDevOpsProvider) getRepository(project string, name string) (*azureDevOpsRepository, error) {
+	answer := &azureDevOpsRepository{}
+	err := a.do(http.MethodGet, a.repoURL(project, name, nil), nil, answer)
+	if err != nil {
+		return nil, err
+	}
+	return answer, nil
+}
+
+// ListOrganisations lists the Azure DevOps projects of the organisation
+func (a *AzureDevOpsProvider) ListOrganisations() ([]GitOrganisation, error) {
+	page := azureDevOpsProjectsPage{}
+	err := a.do(http.MethodGet, a.apiURL(nil, "_apis", "projects"), nil, &page)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list Azure DevOps projects")
+	}
+	answer := []GitOrganisation{}
+	for _, p := range page.Value {
+		answer = append(answer, GitOrganisation{Login: p.Name})
+	}
+	return answer, nil
+}
+
+// ListRepositories lists the repositories of the given Azure DevOps project
+func (a *AzureDevOpsProvider) ListRepositories(org string) ([]*GitRepository, error) {
+	page := azureDevOpsRepositoriesPage{}
+	err := a.do(http.MethodGet, a.projectURL(org, nil, "git", "repositories"), nil, &page)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list repositories of project %s", org)
+	}
+	answer := []*GitRepository{}
+	for i := range page.Value {
+		answer = append(answer, a.toGitRepository(org, &page.Value[i]))
+	}
+	return answer, nil
+}
+
+// CreateRepository creates a new repository in the given Azure DevOps project.
+// The visibility of Azure DevOps repositories is defined by their project so private is ignored
+func (a *AzureDevOpsProvider) CreateRepository(org string, name string, private bool) (*GitRepository, error) {
+	project, err := a.getProject(org)
+	if err != nil {
+		return nil, err
+	}
+	body := &azureDevOpsRepository{
+		Name:    name,
+		Project: &azureDevOpsProject{ID: project.ID},
+	}
+	repo := &azureDevOpsRepository{}
+	err = a.do(http.MethodPost, a.projectURL(org, nil, "git", "repositories"), body, repo)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create repository %s/%s", org, name)
+	}
+	return a.toGitRepository(org, repo), nil
+}
+
+// GetRepository returns the repository with the given name in the Azure DevOps project
+func (a *AzureDevOpsProvider) GetRepository(org string, name string) (*GitRepository, error) {
+	repo, err := a.getRepository(org, name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get repository %s/%s", org, name)
+	}
+	return a.toGitRepository(org, repo), nil
+}
+
+// DeleteRepository deletes the repository with the given name in the Azure DevOps project
+func (a *AzureDevOpsProvider) DeleteRepository(org string, name string) error {
+	repo, err := a.getRepository(org, name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get repository %s/%s", org, name)
+	}
+	err = a.do(http.MethodDelete, a.repoURL(org, repo.ID, nil), nil, nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete repository %s/%s", org, name)
+	}
+	return nil
+}
+
+// ForkRepository forks the repository into the destination Azure DevOps project
+func (a *AzureDevOpsProvider) ForkRepository(originalOrg string, name string, destinationOrg string) (*GitRepository, error) {
+	if destinationOrg == "" {
+		destinationOrg = originalOrg
+	}
+	original, err := a.getRepository(originalOrg, name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get repository %s/%s", originalOrg, name)
+	}
+	project, err := a.getProject(destinationOrg)
+	if err != nil {
+		return nil, err
+	}
+	forkName := name
+	if destinationOrg == originalOrg {
+		forkName = name + "-fork"
+	}
+	body := &azureDevOpsRepository{
+		Name:    forkName,
+		Project: &azureDevOpsProject{ID: project.ID},
+		ParentRepository: &azureDevOpsRepository{
+			ID:      original.ID,
+			Project: original.Project,
+		},
+	}
+	repo := &azureDevOpsRepository{}
+	err = a.do(http.MethodPost, a.projectURL(destinationOrg, nil, "git", "repositories"), body, repo)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fork repository %s/%s to %s", originalOrg, name, destinationOrg)
+	}
+	return a.toGitRepository(destinationOrg, repo), nil
+}
+
+// RenameRepository renames the repository in the Azure DevOps project
+func (a *AzureDevOpsProvider) RenameRepository(org string, name string, newName string) (*GitRepository, error) {
+	repo, err := a.getRepository(org, name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get repository %s/%s", org, name)
+	}
+	body := map[string]string{
+		"name": newName,
+	}
+	renamed := &azureDevOpsRepository{}
+	err = a.do(http.MethodPatch, a.repoURL(org, repo.ID, nil), body, renamed)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to rename repository %s/%s to %s", org, name, newName)
+	}
+	return a.toGitRepository(org, renamed), nil
+}
+
+// ValidateRepositoryName returns an error if the repository already exists in the Azure DevOps project
+func (a *AzureDevOpsProvider) ValidateRepositoryName(org string, name string) error {
+	_, err := a.getRepository(org, name)
+	if err == nil {
+		return fmt.Errorf("repository %s/%s already exists", org, name)
+	}
+	if IsAzureDevOpsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// CreatePullRequest creates a pull request
+func (a *AzureDevOpsProvider) CreatePullRequest(data *GitPullRequestArguments) (*GitPullRequest, error) {
+	gitRepo := data.GitRepository
+	if gitRepo == nil {
+		return nil, fmt.Errorf("missing GitRepository for the pull request %s", data.Title)
+	}
+	project := gitRepo.Organisation
+	repo := gitRepo.Name
+	body := &azureDevOpsPullRequest{
+		Title:         data.Title,
+		Description:   data.Body,
+		SourceRefName: azureDevOpsBranchPrefix + strings.TrimPrefix(data.Head, azureDevOpsBranchPrefix),
+		TargetRefName: azureDevOpsBranchPrefix + strings.TrimPrefix(data.Base, azureDevOpsBranchPrefix),
+	}
+	for _, l := range data.Labels {
+		body.Labels = append(body.Labels, azureDevOpsLabel{Name: l})
+	}
+	pr := &azureDevOpsPullRequest{}
+	err := a.do(http.MethodPost, a.repoURL(project, repo, nil, "pullrequests"), body, pr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create pull request on %s/%s", project, repo)
+	}
+	return a.toGitPullRequest(project, repo, pr), nil
+}
+
+// UpdatePullRequest updates the title and description of the pull request
+func (a *AzureDevOpsProvider) UpdatePullRequest(data *GitPullRequestArguments, number int) (*GitPullRequest, error) {
+	gitRepo := data.GitRepository
+	if gitRepo == nil {
+		return nil, fmt.Errorf("missing GitRepository for the pull request %d", number)
+	}
+	project := gitRepo.Organisation
+	repo := gitRepo.Name
+	body := &azureDevOpsPullRequest{
+		Title:       data.Title,
+		Description: data.Body,
+	}
+	pr := &azureDevOpsPullRequest{}
+	err := a.do(http.MethodPatch, a.repoURL(project, repo, nil, "pullrequests", strconv.Itoa(number)), body, pr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to update pull request %d on %s/%s", number, project, repo)
+	}
+	return a.toGitPullRequest(project, repo, pr), nil
+}
+
+func (a *AzureDevOpsProvider) getPullRequest(project string, repo string, number int) (*azureDevOpsPullRequest, error) {
+	pr := &azureDevOpsPullRequest{}
+	err := a.do(http.MethodGet, a.repoURL(project, repo, nil, "pullrequests", strconv.Itoa(number)), nil, pr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get pull request %d on %s/%s", number, project, repo)
+	}
+	return pr, nil
+}
+
+// UpdatePullRequestStatus refreshes the state of the given pull request
+func (a *AzureDevOpsProvider) UpdatePullRequestStatus(pr *GitPullRequest) error {
+	if pr.Number == nil {
+		return fmt.Errorf("missing Number for GitPullRequest %#v", pr)
+	}
+	latest, err := a.getPullRequest(pr.Owner, pr.Repo, *pr.Number)
+	if err != nil {
+		return err
+	}
+	updated := a.toGitPullRequest(pr.Owner, pr.Repo, latest)
+	pr.URL = updated.URL
+	pr.Author = updated.Author
+	pr.State = updated.State
+	pr.HeadRef = updated.HeadRef
+	pr.Merged = updated.Merged
+	pr.Mergeable = updated.Mergeable
+	pr.MergeCommitSHA = updated.MergeCommitSHA
+	pr.MergedAt = updated.MergedAt
+	pr.ClosedAt = updated.ClosedAt
+	pr.LastCommitSha = updated.LastCommitSha
+	pr.Title = updated.Title
+	pr.Body = updated.Body
+	pr.Labels = updated.Labels
+	pr.RequestedReviewers = updated.RequestedReviewers
+	return nil
+}
+
+// AddLabelsToIssue adds labels to the given pull request
+func (a *AzureDevOpsProvider) AddLabelsToIssue(owner, repo string, number int, labels []string) error {
+	for _, l := range labels {
+		err := a.do(http.MethodPost, a.repoURL(owner, repo, nil, "pullrequests", strconv.Itoa(number), "labels"), &azureDevOpsLabel{Name: l}, nil)
+		if err != nil {
+			return errors.Wrapf(err, "failed to add label %s to pull request %d on %s/%s", l, number, owner, repo)
+		}
+	}
+	return nil
+}
+
+// GetPullRequest returns the pull request with the given number
+func (a *AzureDevOpsProvider) GetPullRequest(owner string, repo *GitRepository, number int) (*GitPullRequest, error) {
+	pr, err := a.getPullRequest(owner, repo.Name, number)
+	if err != nil {
+		return nil, err
+	}
+	return a.toGitPullRequest(owner, repo.Name, pr), nil
+}
+
+// ListOpenPullRequests lists the active pull requests of the repository
+func (a *AzureDevOpsProvider) ListOpenPullRequests(owner string, repo string) ([]*GitPullRequest, error) {
+	query := url.Values{}
+	query.Set("searchCriteria.status", "active")
+	page := azureDevOpsPullRequestsPage{}
+	err := a.do(http.MethodGet, a.repoURL(owner, repo, query, "pullrequests"), nil, &page)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list open pull requests on %s/%s", owner, repo)
+	}
+	answer := []*GitPullRequest{}
+	for i := range page.Value {
+		answer = append(answer, a.toGitPullRequest(owner, repo, &page.Value[i]))
+	}
+	return answer, nil
+}
+
+// GetPullRequestCommits returns the commits of the pull request
+func (a *AzureDevOpsProvider) GetPullRequestCommits(owner string, repo *GitRepository, number int) ([]*GitCommit, error) {
+	page := azureDevOpsCommitsPage{}
+	err := a.do(http.MethodGet, a.repoURL(owner, repo.Name, nil, "pullrequests", strconv.Itoa(number), "commits"), nil, &page)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list commits of pull request %d on %s/%s", number, owner, repo.Name)
+	}
+	answer := []*GitCommit{}
+	for i := range page.Value {
+		answer = append(answer, azureDevOpsCommitToGitCommit(&page.Value[i]))
+	}
+	return answer, nil
+}
+
+// PullRequestLastCommitStatus returns the state of the latest status of the last commit of the pull request
+func (a *AzureDevOpsProvider) PullRequestLastCommitStatus(pr *GitPullRequest) (string, error) {
+	if pr.LastCommitSha == "" {
+		return "", fmt.Errorf("missing last commit SHA for pull request %s on %s/%s", pr.NumberString(), pr.Owner, pr.Repo)
+	}
+	statuses, err := a.ListCommitStatus(pr.Owner, pr.Repo, pr.LastCommitSha)
+	if err != nil {
+		return "", err
+	}
+	if len(statuses) == 0 {
+		return "", fmt.Errorf("no commit statuses found for %s on %s/%s", pr.LastCommitSha, pr.Owner, pr.Repo)
+	}
+	return statuses[0].State, nil
+}
+
+// ListCommitStatus lists the statuses of the given commit, most recent first
+func (a *AzureDevOpsProvider) ListCommitStatus(org string, repo string, sha string) ([]*GitRepoStatus, error) {
+	page := azureDevOpsCommitStatusesPage{}
+	err := a.do(http.MethodGet, a.repoURL(org, repo, nil, "commits", sha, "statuses"), nil, &page)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list statuses of commit %s on %s/%s", sha, org, repo)
+	}
+	answer := []*GitRepoStatus{}
+	for i := range page.Value {
+		answer = append(answer, azureDevOpsCommitStatusToGitStatus(&page.Value[i]))
+	}
+	return answer, nil
+}
+
+// ListCommits lists the commits of the repository
+func (a *AzureDevOpsProvider) ListCommits(owner string, repo string, opt *ListCommitsArguments) ([]*GitCommit, error) {
+	query := url.Values{}
+	if opt != nil {
+		if opt.SHA != "" {
+			query.Set("searchCriteria.itemVersion.version", opt.SHA)
+			query.Set("searchCriteria.itemVersion.versionType", "commit")
+		}
+		if opt.Path != "" {
+			query.Set("searchCriteria.itemPath", opt.Path)
+		}
+		if opt.Author != "" {
+			query.Set("searchCriteria.author", opt.Author)
+		}
+		if !opt.Since.IsZero() {
+			query.Set("searchCriteria.fromDate", opt.Since.Format(time.RFC3339))
+		}
+		if !opt.Until.IsZero() {
+			query.Set("searchCriteria.toDate", opt.Until.Format(time.RFC3339))
+		}
+		if opt.PerPage > 0 {
+			query.Set("searchCriteria.$top", strconv.Itoa(opt.PerPage))
+			if opt.Page > 1 {
+				query.Set("searchCriteria.$skip", strconv.Itoa((opt.Page-1)*opt.PerPage))
+			}
+		}
+	}
+	page := azureDevOpsCommitsPage{}
+	err := a.do(http.MethodGet, a.repoURL(owner, repo, query, "commits"), nil, &page)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list commits on %s/%s", owner, repo)
+	}
+	answer := []*GitCommit{}
+	for i := range page.Value {
+		answer = append(answer, azureDevOpsCommitToGitCommit(&page.Value[i]))
+	}
+	return answer, nil
+}
+
+// UpdateCommitStatus adds a new status to the given commit
+func (a *AzureDevOpsProvider) UpdateCommitStatus(org string, repo string, sha string, status *GitRepoStatus) (*GitRepoStatus, error) {
+	context := status.Context
+	genre := ""
+	idx := strings.LastIndex(context, "/")
+	if idx > 0 {
+		genre = context[:idx]
+		context = context[idx+1:]
+	}
+	targetURL := status.TargetURL
+	if targetURL == "" {
+		targetURL = status.URL
+	}
+	body := &azureDevOpsCommitStatus{
+		State:       gitStateToAzureDevOpsStatus(status.State),
+		Description: status.Description,
+		Context: azureDevOpsStatusContext{
+			Name:  context,
+			Genre: genre,
+		},
+		TargetURL: targetURL,
+	}
+	result := &azureDevOpsCommitStatus{}
+	err := a.do(http.MethodPost, a.repoURL(org, repo, nil, "commits", sha, "statuses"), body, result)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to update status of commit %s on %s/%s", sha, org, repo)
+	}
+	return azureDevOpsCommitStatusToGitStatus(result), nil
+}
+
+// MergePullRequest completes the pull request
+func (a *AzureDevOpsProvider) MergePullRequest(pr *GitPullRequest, message string) error {
+	if pr.Number == nil {
+		return fmt.Errorf("missing Number for GitPullRequest %#v", pr)
+	}
+	n := *pr.Number
+	sha := pr.LastCommitSha
+	if sha == "" {
+		latest, err := a.getPullRequest(pr.Owner, pr.Repo, n)
+		if err != nil {
+			return err
+		}
+		if latest.LastMergeSourceCommit != nil {
+			sha = latest.LastMergeSourceCommit.CommitID
+		}
+	}
+	body := &azureDevOpsPullRequest{
+		Status:                "completed",
+		LastMergeSourceCommit: &azureDevOpsCommitRef{CommitID: sha},
+		CompletionOptions: &azureDevOpsCompletionOptions{
+			MergeCommitMessage: message,
+		},
+	}
+	err := a.do(http.MethodPatch, a.repoURL(pr.Owner, pr.Repo, nil, "pullrequests", strconv.Itoa(n)), body, nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to merge pull request %d on %s/%s", n, pr.Owner, pr.Repo)
+	}
+	return nil
+}
+
+// listSubscriptions lists the service hook subscriptions of the given repository
+func (a *AzureDevOpsProvider) listSubscriptions(repo *azureDevOpsRepository) ([]azureDevOpsSubscription, error) {
+	page := azureDevOpsSubscriptionsPage{}
+	err := a.do(http.MethodGet, a.apiURL(nil, "_apis", "hooks", "subscriptions"), nil, &page)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list service hook subscriptions")
+	}
+	answer := []azureDevOpsSubscription{}
+	for _, s := range page.Value {
+		if s.ConsumerID == "webHooks" && s.PublisherInputs["repository"] == repo.ID {
+			answer = append(answer, s)
+		}
+	}
+	return answer, nil
+}
+
+// CreateWebHook creates service hook subscriptions posting the git push and pull request events of the repository to the webhook URL
+func (a *AzureDevOpsProvider) CreateWebHook(data *GitWebHookArguments) error {
+	project := data.Owner
+	if project == "" && data.Repo != nil {
+		project = data.Repo.Organisation
+	}
+	if data.Repo == nil {
+		return fmt.Errorf("missing repository for webhook %s", data.URL)
+	}
+	repo, err := a.getRepository(project, data.Repo.Name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get repository %s/%s", project, data.Repo.Name)
+	}
+	existing, err := a.listSubscriptions(repo)
+	if err != nil {
+		return err
+	}
+	for _, event := range azureDevOpsWebHookEvents {
+		found := false
+		for _, s := range existing {
+			if s.EventType == event && s.ConsumerInputs["url"] == data.URL {
+				found = true
+				break
+			}
+		}
+		if found {
+			log.Logger().Warnf("Already has a webhook registered for %s on %s/%s", data.URL, project, data.Repo.Name)
+			continue
+		}
+		subscription := &azureDevOpsSubscription{
+			PublisherID:      "tfs",
+			EventType:        event,
+			ResourceVersion:  "1.0",
+			ConsumerID:       "webHooks",
+			ConsumerActionID: "httpRequest",
+			PublisherInputs: map[string]string{
+				"projectId":  repo.Project.ID,
+				"repository": repo.ID,
+			},
+			ConsumerInputs: map[string]string{
+				"url": data.URL,
+			},
+		}
+		setAzureDevOpsWebHookSecret(subscription.ConsumerInputs, data.Secret)
+		log.Logger().Infof("Creating Azure DevOps %s webhook for %s/%s for url %s", event, util.ColorInfo(project), util.ColorInfo(data.Repo.Name), util.ColorInfo(data.URL))
+		err = a.do(http.MethodPost, a.apiURL(nil, "_apis", "hooks", "subscriptions"), subscription, nil)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create %s webhook on %s/%s", event, project, data.Repo.Name)
+		}
+	}
+	return nil
+}
+
+// setAzureDevOpsWebHookSecret sets the secret of the webhook as the basic auth password of the service hook which
+// Azure DevOps stores encrypted, rather than as a plain text header sent on every delivery
+func setAzureDevOpsWebHookSecret(inputs map[string]string, secret string) {
+	if secret == "" {
+		return
+	}
+	delete(inputs, "httpHeaders")
+	inputs["basicAuthUsername"] = AzureDevOpsWebHookUsername
+	inputs["basicAuthPassword"] = secret
+}
+
+// ListWebHooks lists the webhook URLs of the repository
+func (a *AzureDevOpsProvider) ListWebHooks(org string, repoName string) ([]*GitWebHookArguments, error) {
+	repo, err := a.getRepository(org, repoName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get repository %s/%s", org, repoName)
+	}
+	subscriptions, err := a.listSubscriptions(repo)
+	if err != nil {
+		return nil, err
+	}
+	gitRepo := a.toGitRepository(org, repo)
+	answer := []*GitWebHookArguments{}
+	urls := map[string]bool{}
+	for _, s := range subscriptions {
+		u := s.ConsumerInputs["url"]
+		if u == "" || urls[u] {
+			continue
+		}
+		urls[u] = true
+		answer = append(answer, &GitWebHookArguments{
+			Owner: org,
+			Repo:  gitRepo,
+			URL:   u,
+		})
+	}
+	return answer, nil
+}
+
+// UpdateWebHook updates the service hook subscriptions of the repository which post to the existing URL
+func (a *AzureDevOpsProvider) UpdateWebHook(data *GitWebHookArguments) error {
+	project := data.Owner
+	if project == "" && data.Repo != nil {
+		project = data.Repo.Organisation
+	}
+	if data.Repo == nil {
+		return fmt.Errorf("missing repository for webhook %s", data.URL)
+	}
+	repo, err := a.getRepository(project, data.Repo.Name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get repository %s/%s", project, data.Repo.Name)
+	}
+	subscriptions, err := a.listSubscriptions(repo)
+	if err != nil {
+		return err
+	}
+	existingURL := data.ExistingURL
+	if existingURL == "" {
+		existingURL = data.URL
+	}
+	for _, s := range subscriptions {
+		if s.ConsumerInputs["url"] != existingURL {
+			continue
+		}
+		s.ConsumerInputs["url"] = data.URL
+		setAzureDevOpsWebHookSecret(s.ConsumerInputs, data.Secret)
+		log.Logger().Infof("Updating Azure DevOps %s webhook for %s/%s for url %s", s.EventType, util.ColorInfo(project), util.ColorInfo(data.Repo.Name), util.ColorInfo(data.URL))
+		err = a.do(http.MethodPut, a.apiURL(nil, "_apis", "hooks", "subscriptions", s.ID), &s, nil)
+		if err != nil {
+			return errors.Wrapf(err, "failed to update webhook %s on %s/%s", s.ID, project, data.Repo.Name)
+		}
+	}
+	return nil
+}
+
+// IsGitHub returns false
+func (a *AzureDevOpsProvider) IsGitHub() bool {
+	return false
+}
+
+// IsGitea returns false
+func (a *AzureDevOpsProvider) IsGitea() bool {
+	return false
+}
+
+// IsBitbucketCloud returns false
+func (a *AzureDevOpsProvider) IsBitbucketCloud() bool {
+	return false
+}
+
+// IsBitbucketServer returns false
+func (a *AzureDevOpsProvider) IsBitbucketServer() bool {
+	return false
+}
+
+// IsGerrit returns false
+func (a *AzureDevOpsProvider) IsGerrit() bool {
+	return false
+}
+
+// Kind returns the git provider kind
+func (a *AzureDevOpsProvider) Kind() string {
+	return KindAzureDevOps
+}
+
+// GetIssue is not supported as Azure Boards work items are not git issues
+func (a *AzureDevOpsProvider) GetIssue(org string, name string, number int) (*GitIssue, error) {
+	log.Logger().Warn("Finding an issue on Azure DevOps is not supported at this moment")
+	return &GitIssue{}, nil
+}
+
+// IssueURL returns the URL of the pull request; work items are not supported
+func (a *AzureDevOpsProvider) IssueURL(org string, name string, number int, isPull bool) string {
+	if isPull {
+		return util.UrlJoin(a.repositoryWebURL(org, name), "pullrequest", strconv.Itoa(number))
+	}
+	return util.UrlJoin(a.Server.URL, org, "_workitems", "edit", strconv.Itoa(number))
+}
+
+// SearchIssues is not supported as Azure Boards work items are not git issues
+func (a *AzureDevOpsProvider) SearchIssues(org string, name string, query string) ([]*GitIssue, error) {
+	log.Logger().Warn("Searching issues on Azure DevOps is not supported at this moment")
+	return []*GitIssue{}, nil
+}
+
+// SearchIssuesClosedSince is not supported as Azure Boards work items are not git issues
+func (a *AzureDevOpsProvider) SearchIssuesClosedSince(org string, name string, t time.Time) ([]*GitIssue, error) {
+	issues, err := a.SearchIssues(org, name, "")
+	if err != nil {
+		return issues, err
+	}
+	return FilterIssuesClosedSince(issues, t), nil
+}
+
+// CreateIssue is not supported as Azure Boards work items are not git issues
+func (a *AzureDevOpsProvider) CreateIssue(owner string, repo string, issue *GitIssue) (*GitIssue, error) {
+	log.Logger().Warn("Creating an issue on Azure DevOps is not supported at this moment")
+	return &GitIssue{}, nil
+}
+
+// HasIssues returns false as Azure Boards work items are not git issues
+func (a *AzureDevOpsProvider) HasIssues() bool {
+	return false
+}
+
+// AddPRComment adds a comment thread to the pull request
+func (a *AzureDevOpsProvider) AddPRComment(pr *GitPullRequest, comment string) error {
+	if pr.Number == nil {
+		return fmt.Errorf("missing Number for GitPullRequest %#v", pr)
+	}
+	return a.CreateIssueComment(pr.Owner, pr.Repo, *pr.Number, comment)
+}
+
+// CreateIssueComment adds a comment thread to the pull request with the given number
+func (a *AzureDevOpsProvider) CreateIssueComment(owner string, repo string, number int, comment string) error {
+	thread := &azureDevOpsThread{
+		Comments: []azureDevOpsComment{
+			{
+				Content:     comment,
+				CommentType: 1,
+			},
+		},
+		Status: 1,
+	}
+	err := a.do(http.MethodPost, a.repoURL(owner, repo, nil, "pullrequests", strconv.Itoa(number), "threads"), thread, nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to comment on pull request %d on %s/%s", number, owner, repo)
+	}
+	return nil
+}
+
+// listTags returns the tags of the repository
+func (a *AzureDevOpsProvider) listTags(org string, name string) ([]azureDevOpsRef, error) {
+	query := url.Values{}
+	query.Set("filter", "tags/")
+	page := azureDevOpsRefsPage{}
+	err := a.do(http.MethodGet, a.repoURL(org, name, query, "refs"), nil, &page)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list tags on %s/%s", org, name)
+	}
+	return page.Value, nil
+}
+
+func (a *AzureDevOpsProvider) tagToGitRelease(org string, name string, ref *azureDevOpsRef) *GitRelease {
+	tag := strings.TrimPrefix(ref.Name, azureDevOpsTagPrefix)
+	u := a.repositoryWebURL(org, name) + "?version=GT" + url.QueryEscape(tag)
+	return &GitRelease{
+		Name:    tag,
+		TagName: tag,
+		URL:     u,
+		HTMLURL: u,
+	}
+}
+
+// UpdateRelease verifies the release tag exists; Azure DevOps has no releases so tags are used instead
+func (a *AzureDevOpsProvider) UpdateRelease(owner string, repo string, tag string, releaseInfo *GitRelease) error {
+	release, err := a.GetRelease(owner, repo, tag)
+	if err != nil {
+		return err
+	}
+	if release == nil {
+		return fmt.Errorf("no tag %s found on %s/%s; Azure DevOps releases are backed by git tags so the tag must be pushed first", tag, owner, repo)
+	}
+	return nil
+}
+
+// UpdateReleaseStatus is not supported for this git provider
+func (a *AzureDevOpsProvider) UpdateReleaseStatus(owner string, repo string, tag string, releaseInfo *GitRelease) error {
+	log.Logger().Warn("Azure DevOps doesn't support release statuses")
+	return nil
+}
+
+// ListReleases lists the tags of the repository as releases
+func (a *AzureDevOpsProvider) ListReleases(org string, name string) ([]*GitRelease, error) {
+	tags, err := a.listTags(org, name)
+	if err != nil {
+		return nil, err
+	}
+	answer := []*GitRelease{}
+	for i := range tags {
+		answer = append(answer, a.tagToGitRelease(org, name, &tags[i]))
+	}
+	return answer, nil
+}
+
+// GetRelease returns the release for the given tag or nil if the tag does not exist
+func (a *AzureDevOpsProvider) GetRelease(org string, name string, tag string) (*GitRelease, error) {
+	tags, err := a.listTags(org, name)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tags {
+		if strings.TrimPrefix(tags[i].Name, azureDevOpsTagPrefix) == tag {
+			return a.tagToGitRelease(org, name, &tags[i]), nil
+		}
+	}
+	return nil, nil
+}
+
+// UploadReleaseAsset is not supported as Azure DevOps releases are backed by git tags
+func (a *AzureDevOpsProvider) UploadReleaseAsset(org string, repo string, id int64, name string, asset *os.File) (*GitReleaseAsset, error) {
+	return nil, fmt.Errorf("uploading release assets is not supported on Azure DevOps")
+}
+
+// GetLatestRelease returns the release for the tag with the highest semantic version
+func (a *AzureDevOpsProvider) GetLatestRelease(org string, name string) (*GitRelease, error) {
+	releases, err := a.ListReleases(org, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(releases) == 0 {
+		return nil, nil
+	}
+	var latest *GitRelease
+	var latestVersion semver.Version
+	for _, release := range releases {
+		v, err := semver.ParseTolerant(release.TagName)
+		if err != nil {
+			continue
+		}
+		if latest == nil || v.GT(latestVersion) {
+			latest = release
+			latestVersion = v
+		}
+	}
+	return latest, nil
+}
+
+// GetContent returns the base64 encoded content of the file at the given path and ref
+func (a *AzureDevOpsProvider) GetContent(org string, name string, path string, ref string) (*GitFileContent, error) {
+	query := url.Values{}
+	query.Set("path", path)
+	query.Set("includeContent", "true")
+	if ref != "" {
+		query.Set("versionDescriptor.version", ref)
+	}
+	item := &azureDevOpsItem{}
+	err := a.do(http.MethodGet, a.repoURL(org, name, query, "items"), nil, item)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get content of %s on %s/%s", path, org, name)
+	}
+	if item.GitObjectType == "tree" {
+		return nil, fmt.Errorf("Directory Content not yet supported")
+	}
+	htmlURL := a.repositoryWebURL(org, name) + "?path=" + url.QueryEscape(item.Path)
+	return &GitFileContent{
+		Type:        "file",
+		Encoding:    "base64",
+		Size:        len(item.Content),
+		Name:        pathBase(item.Path),
+		Path:        strings.TrimPrefix(item.Path, "/"),
+		Content:     base64.StdEncoding.EncodeToString([]byte(item.Content)),
+		Sha:         item.ObjectID,
+		Url:         item.URL,
+		HtmlUrl:     htmlURL,
+		DownloadUrl: item.URL,
+	}, nil
+}
+
+func pathBase(path string) string {
+	idx := strings.LastIndex(path, "/")
+	if idx < 0 {
+		return path
+	}
+	return path[idx+1:]
+}
+
+// JenkinsWebHookPath returns the path of the Team Foundation Server plugin webhook
+func (a *AzureDevOpsProvider) JenkinsWebHookPath(gitURL string, secret string) string {
+	return "/team-events/"
+}
+
+// Label returns the git service label
+func (a *AzureDevOpsProvider) Label() string {
+	return a.Server.Label()
+}
+
+// ServerURL returns the git server URL
+func (a *AzureDevOpsProvider) ServerURL() string {
+	return a.Server.URL
+}
+
+// BranchArchiveURL returns a URL to the ZIP archive for the git branch
+func (a *AzureDevOpsProvider) BranchArchiveURL(org string, name string, branch string) string {
+	query := url.Values{}
+	query.Set("path", "/")
+	query.Set("versionDescriptor.version", branch)
+	query.Set("$format", "zip")
+	query.Set("download", "true")
+	return a.repoURL(org, name, query, "items")
+}
+
+// CurrentUsername returns the current username
+func (a *AzureDevOpsProvider) CurrentUsername() string {
+	return a.Username
+}
+
+// UserAuth returns the current user auth
+func (a *AzureDevOpsProvider) UserAuth() auth.UserAuth {
+	return a.User
+}
+
+// UserInfo returns the details of the authenticated user if it matches the username
+func (a *AzureDevOpsProvider) UserInfo(username string) *GitUser {
+	data := &azureDevOpsConnectionData{}
+	err := a.do(http.MethodGet, a.apiURL(nil, "_apis", "connectionData"), nil, data)
+	if err != nil {
+		log.Logger().Errorf("Unable to fetch user info for %s due to %s", username, err.Error())
+		return nil
+	}
+	user := data.AuthenticatedUser
+	if username != "" && user.UniqueName != "" && !strings.EqualFold(username, user.UniqueName) && username != a.Username {
+		return &GitUser{Login: username}
+	}
+	return &GitUser{
+		Login: username,
+		Name:  user.DisplayName,
+		Email: user.UniqueName,
+		URL:   user.URL,
+	}
+}
+
+// AddCollaborator is not supported as Azure DevOps permissions are defined on projects
+func (a *AzureDevOpsProvider) AddCollaborator(user string, organisation string, repo string) error {
+	log.Logger().Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for Azure DevOps. Please add user: %v as a contributor to the project %s.", user, organisation)
+	return nil
+}
+
+// ListInvitations is not supported as Azure DevOps permissions are defined on projects
+func (a *AzureDevOpsProvider) ListInvitations() ([]*github.RepositoryInvitation, *github.Response, error) {
+	log.Logger().Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for Azure DevOps.")
+	return []*github.RepositoryInvitation{}, &github.Response{}, nil
+}
+
+// AcceptInvitation is not supported as Azure DevOps permissions are defined on projects
+func (a *AzureDevOpsProvider) AcceptInvitation(ID int64) (*github.Response, error) {
+	log.Logger().Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for Azure DevOps.")
+	return &github.Response{}, nil
+}
+
+// ShouldForkForPullRequest returns false as pull requests are created from branches of the same repository
+func (a *AzureDevOpsProvider) ShouldForkForPullRequest(originalOwner string, repoName string, username string) bool {
+	return false
+}
+
+// GetBranch returns the branch information for an owner/repo, including the commit at the tip
+func (a *AzureDevOpsProvider) GetBranch(owner string, repo string, branch string) (*GitBranch, error) {
+	query := url.Values{}
+	query.Set("filter", "heads/"+branch)
+	page := azureDevOpsRefsPage{}
+	err := a.do(http.MethodGet, a.repoURL(owner, repo, query, "refs"), nil, &page)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get branch %s on %s/%s", branch, owner, repo)
+	}
+	for _, ref := range page.Value {
+		if ref.Name == azureDevOpsBranchPrefix+branch {
+			return &GitBranch{
+				Name: branch,
+				Commit: &GitCommit{
+					SHA:    ref.ObjectID,
+					Branch: branch,
+				},
+				Protected: ref.IsLocked,
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+// AzureDevOpsAccessTokenURL returns the URL to create personal access tokens
+func AzureDevOpsAccessTokenURL(url string) string {
+	return util.UrlJoin(url, "_usersSettings/tokens")
+}
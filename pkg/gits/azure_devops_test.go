@@ -0,0 +1,401 @@
+package gits_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	azureProject = "test-project"
+	azureRepoID  = "5febef5a-833d-4e14-b9c0-14cb638f91e6"
+)
+
+type AzureDevOpsProviderTestSuite struct {
+	suite.Suite
+	mux           *http.ServeMux
+	server        *httptest.Server
+	provider      *gits.AzureDevOpsProvider
+	subscriptions []map[string]interface{}
+}
+
+var azureDevOpsRouter = util.Router{
+	"/_apis/projects": util.MethodMap{
+		"GET": "projects.json",
+	},
+	"/_apis/projects/test-project": util.MethodMap{
+		"GET": "project.json",
+	},
+	"/_apis/connectionData": util.MethodMap{
+		"GET": "connection-data.json",
+	},
+	"/_apis/hooks/subscriptions": util.MethodMap{
+		"GET":  "subscriptions.json",
+		"POST": "subscription.json",
+	},
+	"/_apis/hooks/subscriptions/6f3d8f39-2a42-4d7d-b7a1-3a2b5b7c2f01": util.MethodMap{
+		"PUT": "subscription.json",
+	},
+	"/_apis/hooks/subscriptions/8e0b7b7e-0c57-4c3e-a8e8-5f0c0b4b8d02": util.MethodMap{
+		"PUT": "subscription.json",
+	},
+	"/test-project/_apis/git/repositories": util.MethodMap{
+		"GET":  "repos.json",
+		"POST": "repo-created.json",
+	},
+	"/test-project/_apis/git/repositories/test-repo": util.MethodMap{
+		"GET": "repo.json",
+	},
+	"/test-project/_apis/git/repositories/" + azureRepoID: util.MethodMap{
+		"DELETE": "empty.json",
+		"PATCH":  "repo-renamed.json",
+	},
+	"/test-project/_apis/git/repositories/test-repo/pullrequests": util.MethodMap{
+		"GET":  "prs.json",
+		"POST": "pr.json",
+	},
+	"/test-project/_apis/git/repositories/test-repo/pullrequests/1": util.MethodMap{
+		"GET":   "pr.json",
+		"PATCH": "pr-completed.json",
+	},
+	"/test-project/_apis/git/repositories/test-repo/pullrequests/1/commits": util.MethodMap{
+		"GET": "pr-commits.json",
+	},
+	"/test-project/_apis/git/repositories/test-repo/pullrequests/1/threads": util.MethodMap{
+		"POST": "empty.json",
+	},
+	"/test-project/_apis/git/repositories/test-repo/pullrequests/1/labels": util.MethodMap{
+		"POST": "empty.json",
+	},
+	"/test-project/_apis/git/repositories/test-repo/commits": util.MethodMap{
+		"GET": "pr-commits.json",
+	},
+	"/test-project/_apis/git/repositories/test-repo/commits/d6f24ee03d76a2caf0a4e1975fb43e8f61759b9c/statuses": util.MethodMap{
+		"GET":  "statuses.json",
+		"POST": "status.json",
+	},
+	"/test-project/_apis/git/repositories/test-repo/refs": util.MethodMap{
+		"GET": "tags.json",
+	},
+	"/test-project/_apis/git/repositories/test-repo/items": util.MethodMap{
+		"GET": "item.json",
+	},
+}
+
+func (suite *AzureDevOpsProviderTestSuite) SetupSuite() {
+	suite.mux = http.NewServeMux()
+
+	for path, methodMap := range azureDevOpsRouter {
+		handler := util.GetMockAPIResponseFromFile("test_data/azure_devops", methodMap)
+		if strings.HasPrefix(path, "/_apis/hooks/subscriptions") {
+			handler = suite.recordSubscriptions(handler)
+		}
+		suite.mux.HandleFunc(path, handler)
+	}
+
+	suite.server = httptest.NewServer(suite.mux)
+	suite.Require().NotNil(suite.server)
+
+	as := auth.AuthServer{
+		URL:         suite.server.URL,
+		Name:        "Test Azure DevOps",
+		Kind:        gits.KindAzureDevOps,
+		CurrentUser: "test-user",
+	}
+	ua := auth.UserAuth{
+		Username: "test-user",
+		ApiToken: "0123456789abdef",
+	}
+
+	git := gits.NewGitCLI()
+	p, err := gits.CreateProvider(&as, &ua, git)
+	suite.Require().Nil(err)
+	suite.Require().NotNil(p)
+
+	var ok bool
+	suite.provider, ok = p.(*gits.AzureDevOpsProvider)
+	suite.Require().True(ok)
+	suite.Require().NotNil(suite.provider)
+}
+
+// recordSubscriptions records the bodies of the requests creating or updating service hook subscriptions
+func (suite *AzureDevOpsProviderTestSuite) recordSubscriptions(handler func(http.ResponseWriter, *http.Request)) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost || r.Method == http.MethodPut {
+			data, err := ioutil.ReadAll(r.Body)
+			suite.Require().Nil(err)
+			subscription := map[string]interface{}{}
+			suite.Require().Nil(json.Unmarshal(data, &subscription))
+			suite.subscriptions = append(suite.subscriptions, subscription)
+		}
+		handler(w, r)
+	}
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestListOrganisations() {
+	orgs, err := suite.provider.ListOrganisations()
+	suite.Require().Nil(err)
+	suite.Require().Len(orgs, 2)
+	suite.Require().Equal("test-project", orgs[0].Login)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestListRepositories() {
+	repos, err := suite.provider.ListRepositories(azureProject)
+	suite.Require().Nil(err)
+	suite.Require().Len(repos, 2)
+
+	for _, repo := range repos {
+		suite.Require().NotNil(repo)
+		suite.Require().Equal(azureProject, repo.Organisation)
+	}
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestGetRepository() {
+	repo, err := suite.provider.GetRepository(azureProject, "test-repo")
+	suite.Require().Nil(err)
+	suite.Require().NotNil(repo)
+	suite.Require().Equal("test-repo", repo.Name)
+	suite.Require().Equal("https://test-org@dev.azure.com/test-org/test-project/_git/test-repo", repo.CloneURL)
+	suite.Require().True(repo.Private)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestCreateRepository() {
+	repo, err := suite.provider.CreateRepository(azureProject, "test-repo123", true)
+	suite.Require().Nil(err)
+	suite.Require().NotNil(repo)
+	suite.Require().Equal("test-repo123", repo.Name)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestDeleteRepository() {
+	err := suite.provider.DeleteRepository(azureProject, "test-repo")
+	suite.Require().Nil(err)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestRenameRepository() {
+	repo, err := suite.provider.RenameRepository(azureProject, "test-repo", "test-repo-renamed")
+	suite.Require().Nil(err)
+	suite.Require().NotNil(repo)
+	suite.Require().Equal("test-repo-renamed", repo.Name)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestValidateRepositoryName() {
+	err := suite.provider.ValidateRepositoryName(azureProject, "test-repo")
+	suite.Require().NotNil(err)
+
+	err = suite.provider.ValidateRepositoryName(azureProject, "foo-repo")
+	suite.Require().Nil(err)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestCreatePullRequest() {
+	args := gits.GitPullRequestArguments{
+		GitRepository: &gits.GitRepository{
+			Name:         "test-repo",
+			Organisation: azureProject,
+		},
+		Head:   "feat/world",
+		Base:   "master",
+		Title:  "Test Pull Request",
+		Body:   "Test Pull request description",
+		Labels: []string{"updatebot"},
+	}
+
+	pr, err := suite.provider.CreatePullRequest(&args)
+	suite.Require().Nil(err)
+	suite.Require().NotNil(pr)
+	suite.Require().Equal(1, *pr.Number)
+	suite.Require().Equal("open", *pr.State)
+	suite.Require().Equal("feat/world", *pr.HeadRef)
+	suite.Require().Equal("d6f24ee03d76a2caf0a4e1975fb43e8f61759b9c", pr.LastCommitSha)
+	suite.Require().Equal("test-user@example.com", pr.Author.Login)
+	suite.Require().Len(pr.Labels, 1)
+	suite.Require().Equal(suite.server.URL+"/test-project/_git/test-repo/pullrequest/1", pr.URL)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestUpdatePullRequestStatus() {
+	number := 1
+	state := "closed"
+	pr := &gits.GitPullRequest{
+		Owner:  azureProject,
+		Repo:   "test-repo",
+		Number: &number,
+		State:  &state,
+	}
+
+	err := suite.provider.UpdatePullRequestStatus(pr)
+	suite.Require().Nil(err)
+	suite.Require().Equal("open", *pr.State)
+	suite.Require().True(*pr.Mergeable)
+	suite.Require().False(*pr.Merged)
+	suite.Require().Equal("d6f24ee03d76a2caf0a4e1975fb43e8f61759b9c", pr.LastCommitSha)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestListOpenPullRequests() {
+	prs, err := suite.provider.ListOpenPullRequests(azureProject, "test-repo")
+	suite.Require().Nil(err)
+	suite.Require().Len(prs, 1)
+	suite.Require().Equal("open", *prs[0].State)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestGetPullRequestCommits() {
+	commits, err := suite.provider.GetPullRequestCommits(azureProject, &gits.GitRepository{Name: "test-repo"}, 1)
+	suite.Require().Nil(err)
+	suite.Require().Len(commits, 2)
+	suite.Require().Equal("Test User", commits[0].Author.Name)
+	suite.Require().Equal("feat: say hello world", commits[0].Subject())
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestListCommitStatus() {
+	statuses, err := suite.provider.ListCommitStatus(azureProject, "test-repo", "d6f24ee03d76a2caf0a4e1975fb43e8f61759b9c")
+	suite.Require().Nil(err)
+	suite.Require().Len(statuses, 2)
+	suite.Require().Equal("success", statuses[0].State)
+	suite.Require().Equal("jenkins-x/pr-build", statuses[0].Context)
+	suite.Require().Equal("pending", statuses[1].State)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestPullRequestLastCommitStatus() {
+	number := 1
+	pr := &gits.GitPullRequest{
+		Owner:         azureProject,
+		Repo:          "test-repo",
+		Number:        &number,
+		LastCommitSha: "d6f24ee03d76a2caf0a4e1975fb43e8f61759b9c",
+	}
+	status, err := suite.provider.PullRequestLastCommitStatus(pr)
+	suite.Require().Nil(err)
+	suite.Require().Equal("success", status)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestUpdateCommitStatus() {
+	status, err := suite.provider.UpdateCommitStatus(azureProject, "test-repo", "d6f24ee03d76a2caf0a4e1975fb43e8f61759b9c", &gits.GitRepoStatus{
+		State:       "failure",
+		Context:     "jenkins-x/pr-build",
+		Description: "Pipeline failed",
+	})
+	suite.Require().Nil(err)
+	suite.Require().Equal("failure", status.State)
+	suite.Require().Equal("jenkins-x/pr-build", status.Context)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestMergePullRequest() {
+	number := 1
+	pr := &gits.GitPullRequest{
+		Owner:  azureProject,
+		Repo:   "test-repo",
+		Number: &number,
+	}
+	err := suite.provider.MergePullRequest(pr, "Merged by Jenkins X")
+	suite.Require().Nil(err)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestAddPRComment() {
+	number := 1
+	pr := &gits.GitPullRequest{
+		Owner:  azureProject,
+		Repo:   "test-repo",
+		Number: &number,
+	}
+	err := suite.provider.AddPRComment(pr, "/approve")
+	suite.Require().Nil(err)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestAddLabelsToIssue() {
+	err := suite.provider.AddLabelsToIssue(azureProject, "test-repo", 1, []string{"updatebot"})
+	suite.Require().Nil(err)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestWebHooks() {
+	repo := &gits.GitRepository{Name: "test-repo", Organisation: azureProject}
+	suite.subscriptions = nil
+
+	hooks, err := suite.provider.ListWebHooks(azureProject, "test-repo")
+	suite.Require().Nil(err)
+	suite.Require().Len(hooks, 1)
+	suite.Require().Equal("http://hook.jx.example.com/hook", hooks[0].URL)
+
+	err = suite.provider.CreateWebHook(&gits.GitWebHookArguments{
+		Owner:  azureProject,
+		Repo:   repo,
+		URL:    "http://hook.jx.example.com/hook",
+		Secret: "s3cr3t",
+	})
+	suite.Require().Nil(err)
+
+	// the secret is the basic auth password of the service hooks as Azure DevOps cannot sign the payloads
+	suite.Require().NotEmpty(suite.subscriptions)
+	for _, subscription := range suite.subscriptions {
+		inputs := subscription["consumerInputs"].(map[string]interface{})
+		suite.Equal(gits.AzureDevOpsWebHookUsername, inputs["basicAuthUsername"])
+		suite.Equal("s3cr3t", inputs["basicAuthPassword"])
+		suite.NotContains(inputs, "httpHeaders")
+	}
+
+	err = suite.provider.UpdateWebHook(&gits.GitWebHookArguments{
+		Owner:       azureProject,
+		Repo:        repo,
+		URL:         "http://hook.jx.example.com/new-hook",
+		ExistingURL: "http://hook.jx.example.com/hook",
+	})
+	suite.Require().Nil(err)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestReleases() {
+	releases, err := suite.provider.ListReleases(azureProject, "test-repo")
+	suite.Require().Nil(err)
+	suite.Require().Len(releases, 3)
+
+	release, err := suite.provider.GetRelease(azureProject, "test-repo", "v0.0.9")
+	suite.Require().Nil(err)
+	suite.Require().NotNil(release)
+	suite.Require().Equal("v0.0.9", release.TagName)
+
+	latest, err := suite.provider.GetLatestRelease(azureProject, "test-repo")
+	suite.Require().Nil(err)
+	suite.Require().NotNil(latest)
+	suite.Require().Equal("v0.0.10", latest.TagName)
+
+	err = suite.provider.UpdateRelease(azureProject, "test-repo", "v0.0.10", &gits.GitRelease{})
+	suite.Require().Nil(err)
+
+	err = suite.provider.UpdateRelease(azureProject, "test-repo", "v1.0.0", &gits.GitRelease{})
+	suite.Require().NotNil(err)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestGetContent() {
+	content, err := suite.provider.GetContent(azureProject, "test-repo", "jenkins-x.yml", "master")
+	suite.Require().Nil(err)
+	suite.Require().NotNil(content)
+	suite.Require().Equal("jenkins-x.yml", content.Name)
+	suite.Require().Equal("base64", content.Encoding)
+
+	data, err := base64.StdEncoding.DecodeString(content.Content)
+	suite.Require().Nil(err)
+	suite.Require().Equal("buildPack: go\n", string(data))
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestUserInfo() {
+	user := suite.provider.UserInfo("test-user")
+	suite.Require().NotNil(user)
+	suite.Require().Equal("Test User", user.Name)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TearDownSuite() {
+	suite.server.Close()
+}
+
+func TestAzureDevOpsProviderTestSuite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping AzureDevOpsProviderTestSuite in short mode")
+	} else {
+		suite.Run(t, new(AzureDevOpsProviderTestSuite))
+	}
+}
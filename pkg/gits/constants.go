@@ -11,6 +11,8 @@ const (
 	KindGitlab = "gitlab"
 	// KindGitHub git kind for github
 	KindGitHub = "github"
+	// KindAzureDevOps git kind for Azure DevOps Repos
+	KindAzureDevOps = "azuredevops"
 	// KindGitFake git kind for fake git
 	KindGitFake = "fakegit"
 	// KindUnknown git kind for unknown git
@@ -19,10 +21,17 @@ const (
 	// BitbucketCloudURL the default URL for BitBucket Cloud
 	BitbucketCloudURL = "https://bitbucket.org"
 
+	// AzureDevOpsHost the host of Azure DevOps Services
+	AzureDevOpsHost = "dev.azure.com"
+	// AzureDevOpsURL the URL of Azure DevOps Services; the server URL of a provider is this URL followed by the organisation
+	AzureDevOpsURL = "https://dev.azure.com"
+	// AzureDevOpsLegacyHostSuffix the host suffix of legacy Azure DevOps Services URLs of the form https://myorg.visualstudio.com
+	AzureDevOpsLegacyHostSuffix = ".visualstudio.com"
+
 	// FakeGitURL the default URL for the fake git provider
 	FakeGitURL = "https://fake.git"
 )
 
 var (
-	KindGits = []string{KindBitBucketCloud, KindBitBucketServer, KindGitea, KindGitHub, KindGitlab}
+	KindGits = []string{KindAzureDevOps, KindBitBucketCloud, KindBitBucketServer, KindGitea, KindGitHub, KindGitlab}
 )
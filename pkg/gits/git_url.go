@@ -13,6 +13,11 @@ const (
 	GitHubURL  = "https://github.com"
 
 	gitPrefix = "git@"
+
+	// azureDevOpsGitPath the path segment separating the project from the repository name in Azure DevOps URLs
+	azureDevOpsGitPath = "/_git/"
+	// azureDevOpsSSHHost the host used by Azure DevOps SSH URLs of the form git@ssh.dev.azure.com:v3/org/project/repo
+	azureDevOpsSSHHost = "ssh.dev.azure.com"
 )
 
 func (i *GitRepository) IsGitHub() bool {
@@ -74,6 +79,9 @@ func (i *GitRepository) HostURL() string {
 
 func (i *GitRepository) HostURLWithoutUser() string {
 	u := i.URL
+	if azureURL := azureDevOpsHostURL(u); azureURL != "" {
+		return azureURL
+	}
 	if u != "" {
 		u2, err := url.Parse(u)
 		if err == nil {
@@ -126,6 +134,14 @@ func ParseGitURL(text string) (*GitRepository, error) {
 		t = strings.TrimSuffix(t, ".git")
 
 		arr := util.RegexpSplit(t, ":|/")
+		if len(arr) >= 5 && arr[0] == azureDevOpsSSHHost && arr[1] == "v3" {
+			answer.Scheme = "git"
+			answer.Host = AzureDevOpsHost
+			answer.Organisation = arr[3]
+			answer.Project = arr[3]
+			answer.Name = arr[4]
+			return &answer, nil
+		}
 		if len(arr) >= 3 {
 			answer.Scheme = "git"
 			answer.Host = arr[0]
@@ -139,6 +155,20 @@ func ParseGitURL(text string) (*GitRepository, error) {
 
 func parsePath(path string, info *GitRepository) (*GitRepository, error) {
 
+	// Azure DevOps paths are of the form [/<organisation>]/<project>/_git/<repo>
+	if idx := strings.Index(path, azureDevOpsGitPath); idx >= 0 {
+		projectPath := strings.Split(strings.Trim(path[:idx], "/"), "/")
+		repoPath := strings.Split(strings.Trim(path[idx+len(azureDevOpsGitPath):], "/"), "/")
+		project := projectPath[len(projectPath)-1]
+		name := strings.TrimSuffix(repoPath[0], ".git")
+		if project != "" && name != "" {
+			info.Organisation = project
+			info.Project = project
+			info.Name = name
+			return info, nil
+		}
+	}
+
 	// This is necessary for Bitbucket Server in some cases.
 	trimPath := strings.TrimPrefix(path, "/scm")
 
@@ -186,6 +216,41 @@ func SaasGitKind(gitServiceUrl string) string {
 		if strings.HasPrefix(gitServiceUrl, "https://github") {
 			return KindGitHub
 		}
+		if strings.HasPrefix(gitServiceUrl, AzureDevOpsURL) || strings.Contains(gitServiceUrl, AzureDevOpsLegacyHostSuffix) {
+			return KindAzureDevOps
+		}
+		return ""
+	}
+}
+
+// azureDevOpsHostURL returns the Azure DevOps server URL including the organisation
+// (e.g. https://dev.azure.com/myorg) for the given repository URL or "" if its not an Azure DevOps URL
+func azureDevOpsHostURL(text string) string {
+	if strings.HasPrefix(text, gitPrefix+azureDevOpsSSHHost+":v3/") {
+		arr := strings.Split(strings.TrimPrefix(text, gitPrefix+azureDevOpsSSHHost+":v3/"), "/")
+		if len(arr) > 0 && arr[0] != "" {
+			return util.UrlJoin(AzureDevOpsURL, arr[0])
+		}
+		return ""
+	}
+	idx := strings.Index(text, azureDevOpsGitPath)
+	if idx < 0 {
+		return ""
+	}
+	u, err := url.Parse(text[:idx])
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	u.User = nil
+	u.RawQuery = ""
+	u.Fragment = ""
+
+	// lets strip the project from the path
+	path := strings.TrimSuffix(u.Path, "/")
+	i := strings.LastIndex(path, "/")
+	if i < 0 {
 		return ""
 	}
+	u.Path = path[:i]
+	return strings.TrimSuffix(u.String(), "/")
 }
@@ -54,6 +54,15 @@ func TestParseGitURL(t *testing.T) {
 		{
 			"https://bitbucketserver.com/projects/myproject/repos/foo/pull-requests/1", "bitbucketserver.com", "myproject", "foo",
 		},
+		{
+			"https://myorg@dev.azure.com/myorg/myproject/_git/foo", "dev.azure.com", "myproject", "foo",
+		},
+		{
+			"https://myorg.visualstudio.com/myproject/_git/foo", "myorg.visualstudio.com", "myproject", "foo",
+		},
+		{
+			"git@ssh.dev.azure.com:v3/myorg/myproject/foo", "dev.azure.com", "myproject", "foo",
+		},
 	}
 	for _, data := range testCases {
 		info, err := gits.ParseGitURL(data.url)
@@ -79,6 +88,14 @@ func TestSaasKind(t *testing.T) {
 			gitURL: "https://github.test.com",
 			kind:   gits.KindGitHub,
 		},
+		"Azure DevOps": {
+			gitURL: "https://dev.azure.com/myorg",
+			kind:   gits.KindAzureDevOps,
+		},
+		"Azure DevOps legacy": {
+			gitURL: "https://myorg.visualstudio.com",
+			kind:   gits.KindAzureDevOps,
+		},
 	}
 
 	for name, tc := range tests {
@@ -96,3 +113,19 @@ func TestGitInfoProviderURL(t *testing.T) {
 		assert.Equal(t, "https://github.com", info.ProviderURL(), "ProviderURL() for %s", u)
 	}
 }
+
+func TestAzureDevOpsProviderURL(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]string{
+		"https://myorg@dev.azure.com/myorg/myproject/_git/foo":          "https://dev.azure.com/myorg",
+		"git@ssh.dev.azure.com:v3/myorg/myproject/foo":                  "https://dev.azure.com/myorg",
+		"https://myorg.visualstudio.com/myproject/_git/foo":             "https://myorg.visualstudio.com",
+		"https://tfs.acme.com/tfs/DefaultCollection/myproject/_git/foo": "https://tfs.acme.com/tfs/DefaultCollection",
+	}
+	for u, expected := range testCases {
+		info, err := gits.ParseGitURL(u)
+		require.NoError(t, err, "for URL %s", u)
+		assert.Equal(t, expected, info.ProviderURL(), "ProviderURL() for %s", u)
+		assert.Equal(t, expected, info.HostURLWithoutUser(), "HostURLWithoutUser() for %s", u)
+	}
+}
@@ -252,6 +252,8 @@ func CreateProvider(server *auth.AuthServer, user *auth.UserAuth, git Gitter) (G
 		return NewGiteaProvider(server, user, git)
 	} else if server.Kind == KindGitlab {
 		return NewGitlabProvider(server, user, git)
+	} else if server.Kind == KindAzureDevOps {
+		return NewAzureDevOpsProvider(server, user, git)
 	} else if server.Kind == KindGitFake {
 		return NewFakeProvider(), nil
 	} else {
@@ -286,6 +288,8 @@ func ProviderAccessTokenURL(kind string, url string, username string) string {
 		return GiteaAccessTokenURL(url)
 	case KindGitlab:
 		return GitlabAccessTokenURL(url)
+	case KindAzureDevOps:
+		return AzureDevOpsAccessTokenURL(url)
 	default:
 		return GitHubAccessTokenURL(url)
 	}
@@ -432,6 +436,9 @@ func (i *GitRepository) CreateProvider(inCluster bool, authConfigSvc auth.Config
 
 // ProviderURL returns the git provider URL
 func (i *GitRepository) ProviderURL() string {
+	if azureURL := azureDevOpsHostURL(i.URL); azureURL != "" {
+		return azureURL
+	}
 	scheme := i.Scheme
 	if !strings.HasPrefix(scheme, "http") {
 		scheme = "https"
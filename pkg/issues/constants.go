@@ -3,10 +3,12 @@ package issues
 const (
 	Bugzilla = "bugzilla"
 	Jira     = "jira"
+	Linear   = "linear"
 	Trello   = "trello"
 	Git      = "git"
+	YouTrack = "youtrack"
 )
 
 var (
-	IssueTrackerKinds = []string{Bugzilla, Jira, Trello}
+	IssueTrackerKinds = []string{Bugzilla, Jira, Linear, Trello, YouTrack}
 )
@@ -0,0 +1,352 @@
+package issues
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	// LinearHost the host name of the Linear web application
+	LinearHost = "linear.app"
+	// LinearAPIURL the URL of the Linear GraphQL API
+	LinearAPIURL = "https://api.linear.app/graphql"
+
+	linearIssueFields = `id identifier title description url createdAt updatedAt completedAt canceledAt
+		state { name type }
+		creator { name displayName email avatarUrl }
+		assignee { name displayName email avatarUrl }
+		labels { nodes { name color } }`
+)
+
+// LinearService is an issue provider for Linear using its GraphQL API.
+//
+// The server URL is the workspace URL such as https://linear.app/myworkspace and the project is the team key such as ENG
+type LinearService struct {
+	Client   *http.Client
+	Server   *auth.AuthServer
+	UserAuth *auth.UserAuth
+	Project  string
+	APIURL   string
+}
+
+type linearUser struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+	Email       string `json:"email"`
+	AvatarURL   string `json:"avatarUrl"`
+}
+
+type linearLabel struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type linearIssue struct {
+	ID          string      `json:"id"`
+	Identifier  string      `json:"identifier"`
+	Title       string      `json:"title"`
+	Description string      `json:"description"`
+	URL         string      `json:"url"`
+	CreatedAt   *time.Time  `json:"createdAt"`
+	UpdatedAt   *time.Time  `json:"updatedAt"`
+	CompletedAt *time.Time  `json:"completedAt"`
+	CanceledAt  *time.Time  `json:"canceledAt"`
+	Creator     *linearUser `json:"creator"`
+	Assignee    *linearUser `json:"assignee"`
+	State       struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	} `json:"state"`
+	Labels struct {
+		Nodes []linearLabel `json:"nodes"`
+	} `json:"labels"`
+}
+
+type linearIssueConnection struct {
+	Nodes []linearIssue `json:"nodes"`
+}
+
+type linearRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+type linearError struct {
+	Message string `json:"message"`
+}
+
+type linearResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []linearError   `json:"errors"`
+}
+
+// CreateLinearIssueProvider creates a new Linear issue provider
+func CreateLinearIssueProvider(server *auth.AuthServer, userAuth *auth.UserAuth, project string, batchMode bool) (IssueProvider, error) {
+	u := server.URL
+	if u == "" {
+		return nil, fmt.Errorf("No base URL for server!")
+	}
+	if userAuth == nil || userAuth.ApiToken == "" {
+		return nil, fmt.Errorf("No API key found for Linear server %s", u)
+	}
+	if batchMode {
+		log.Logger().Infof("Using Linear workspace %s and API key %s", u, strings.Repeat("*", len(userAuth.ApiToken)))
+	}
+	return &LinearService{
+		Client:   &http.Client{Timeout: 30 * time.Second},
+		Server:   server,
+		UserAuth: userAuth,
+		Project:  project,
+		APIURL:   linearAPIURL(u),
+	}, nil
+}
+
+// linearAPIURL returns the GraphQL endpoint; workspaces on linear.app use the public API
+// otherwise the server is assumed to be a proxy of the API
+func linearAPIURL(serverURL string) string {
+	u, err := url.Parse(serverURL)
+	if err == nil && (u.Host == LinearHost || strings.HasSuffix(u.Host, "."+LinearHost)) {
+		return LinearAPIURL
+	}
+	return util.UrlJoin(serverURL, "graphql")
+}
+
+// GetIssue returns the issue of the given identifier such as ENG-123
+func (i *LinearService) GetIssue(key string) (*gits.GitIssue, error) {
+	data := struct {
+		Issue *linearIssue `json:"issue"`
+	}{}
+	query := `query ($id: String!) { issue(id: $id) { ` + linearIssueFields + ` } }`
+	err := i.query(query, map[string]interface{}{"id": key}, &data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get Linear issue %s", key)
+	}
+	if data.Issue == nil {
+		return nil, nil
+	}
+	return i.linearToGitIssue(data.Issue), nil
+}
+
+// SearchIssues searches the open issues of the team
+func (i *LinearService) SearchIssues(query string) ([]*gits.GitIssue, error) {
+	filter := i.teamFilter()
+	filter["state"] = map[string]interface{}{
+		"type": map[string]interface{}{"nin": []string{"completed", "canceled"}},
+	}
+	if query != "" {
+		filter["title"] = map[string]interface{}{"containsIgnoreCase": query}
+	}
+	return i.searchIssues(filter)
+}
+
+// SearchIssuesClosedSince searches the issues of the team completed since the given time
+func (i *LinearService) SearchIssuesClosedSince(t time.Time) ([]*gits.GitIssue, error) {
+	filter := i.teamFilter()
+	filter["completedAt"] = map[string]interface{}{"gt": t.UTC().Format(time.RFC3339)}
+	return i.searchIssues(filter)
+}
+
+// CreateIssue creates a new issue in the team
+func (i *LinearService) CreateIssue(issue *gits.GitIssue) (*gits.GitIssue, error) {
+	teamID, err := i.teamID()
+	if err != nil {
+		return nil, err
+	}
+	data := struct {
+		IssueCreate struct {
+			Success bool         `json:"success"`
+			Issue   *linearIssue `json:"issue"`
+		} `json:"issueCreate"`
+	}{}
+	query := `mutation ($input: IssueCreateInput!) { issueCreate(input: $input) { success issue { ` + linearIssueFields + ` } } }`
+	input := map[string]interface{}{
+		"teamId":      teamID,
+		"title":       issue.Title,
+		"description": issue.Body,
+	}
+	err = i.query(query, map[string]interface{}{"input": input}, &data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create Linear issue in team %s", i.Project)
+	}
+	if !data.IssueCreate.Success || data.IssueCreate.Issue == nil {
+		return nil, fmt.Errorf("failed to create Linear issue in team %s", i.Project)
+	}
+	return i.linearToGitIssue(data.IssueCreate.Issue), nil
+}
+
+// CreateIssueComment creates a comment on the given issue
+func (i *LinearService) CreateIssueComment(key string, comment string) error {
+	data := struct {
+		CommentCreate struct {
+			Success bool `json:"success"`
+		} `json:"commentCreate"`
+	}{}
+	query := `mutation ($input: CommentCreateInput!) { commentCreate(input: $input) { success } }`
+	input := map[string]interface{}{
+		"issueId": key,
+		"body":    comment,
+	}
+	err := i.query(query, map[string]interface{}{"input": input}, &data)
+	if err != nil {
+		return errors.Wrapf(err, "failed to comment on Linear issue %s", key)
+	}
+	if !data.CommentCreate.Success {
+		return fmt.Errorf("failed to comment on Linear issue %s", key)
+	}
+	return nil
+}
+
+// IssueURL returns the URL of the given issue
+func (i *LinearService) IssueURL(key string) string {
+	return util.UrlJoin(i.Server.URL, "issue", key)
+}
+
+// HomeURL returns the URL of the team
+func (i *LinearService) HomeURL() string {
+	return util.UrlJoin(i.Server.URL, "team", i.Project)
+}
+
+func (i *LinearService) teamFilter() map[string]interface{} {
+	return map[string]interface{}{
+		"team": map[string]interface{}{
+			"key": map[string]interface{}{"eq": i.Project},
+		},
+	}
+}
+
+func (i *LinearService) teamID() (string, error) {
+	data := struct {
+		Teams struct {
+			Nodes []struct {
+				ID string `json:"id"`
+			} `json:"nodes"`
+		} `json:"teams"`
+	}{}
+	query := `query ($filter: TeamFilter) { teams(filter: $filter) { nodes { id } } }`
+	filter := map[string]interface{}{
+		"key": map[string]interface{}{"eq": i.Project},
+	}
+	err := i.query(query, map[string]interface{}{"filter": filter}, &data)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to find Linear team %s", i.Project)
+	}
+	if len(data.Teams.Nodes) == 0 {
+		return "", fmt.Errorf("could not find Linear team %s", i.Project)
+	}
+	return data.Teams.Nodes[0].ID, nil
+}
+
+func (i *LinearService) searchIssues(filter map[string]interface{}) ([]*gits.GitIssue, error) {
+	data := struct {
+		Issues linearIssueConnection `json:"issues"`
+	}{}
+	query := `query ($filter: IssueFilter) { issues(filter: $filter, first: 100) { nodes { ` + linearIssueFields + ` } } }`
+	answer := []*gits.GitIssue{}
+	err := i.query(query, map[string]interface{}{"filter": filter}, &data)
+	if err != nil {
+		return answer, errors.Wrapf(err, "failed to search Linear issues in team %s", i.Project)
+	}
+	for _, issue := range data.Issues.Nodes {
+		answer = append(answer, i.linearToGitIssue(&issue))
+	}
+	return answer, nil
+}
+
+// query invokes a GraphQL query unmarshalling the data of the response into result
+func (i *LinearService) query(query string, variables map[string]interface{}, result interface{}) error {
+	body, err := json.Marshal(&linearRequest{Query: query, Variables: variables})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, i.APIURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", i.UserAuth.ApiToken)
+	resp, err := i.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("status %d from %s: %s", resp.StatusCode, i.APIURL, string(data))
+	}
+	response := &linearResponse{}
+	err = json.Unmarshal(data, response)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse response from %s", i.APIURL)
+	}
+	if len(response.Errors) > 0 {
+		messages := []string{}
+		for _, e := range response.Errors {
+			messages = append(messages, e.Message)
+		}
+		return fmt.Errorf("%s", strings.Join(messages, ", "))
+	}
+	return json.Unmarshal(response.Data, result)
+}
+
+func (i *LinearService) linearToGitIssue(issue *linearIssue) *gits.GitIssue {
+	answer := &gits.GitIssue{
+		Key:       issue.Identifier,
+		URL:       issue.URL,
+		Title:     issue.Title,
+		Body:      issue.Description,
+		CreatedAt: issue.CreatedAt,
+		UpdatedAt: issue.UpdatedAt,
+		User:      linearUserToGitUser(issue.Creator),
+	}
+	if answer.URL == "" {
+		answer.URL = i.IssueURL(issue.Identifier)
+	}
+	state := "open"
+	switch issue.State.Type {
+	case "completed":
+		state = "closed"
+		answer.ClosedAt = issue.CompletedAt
+	case "canceled":
+		state = "closed"
+		answer.ClosedAt = issue.CanceledAt
+	}
+	answer.State = &state
+	for _, label := range issue.Labels.Nodes {
+		answer.Labels = append(answer.Labels, gits.GitLabel{
+			Name:  label.Name,
+			Color: strings.TrimPrefix(label.Color, "#"),
+		})
+	}
+	assignee := linearUserToGitUser(issue.Assignee)
+	if assignee != nil {
+		answer.Assignees = []gits.GitUser{*assignee}
+	}
+	return answer
+}
+
+func linearUserToGitUser(user *linearUser) *gits.GitUser {
+	if user == nil {
+		return nil
+	}
+	return &gits.GitUser{
+		Login:     user.DisplayName,
+		Name:      user.Name,
+		Email:     user.Email,
+		AvatarURL: user.AvatarURL,
+	}
+}
@@ -0,0 +1,88 @@
+package issues_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/issues"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const linearIssueResponse = `{
+  "data": {
+    "issue": {
+      "id": "9cfb482a-81e3-4154-b5b9-2c805e70a02d",
+      "identifier": "ENG-123",
+      "title": "Handle empty values",
+      "description": "Empty values cause a panic",
+      "url": "https://linear.app/myworkspace/issue/ENG-123/handle-empty-values",
+      "createdAt": "2019-09-02T10:15:30.000Z",
+      "completedAt": "2019-09-04T08:00:00.000Z",
+      "state": { "name": "Done", "type": "completed" },
+      "creator": { "name": "James Strachan", "displayName": "jstrachan", "email": "james@example.com" },
+      "assignee": { "name": "James Rawlings", "displayName": "rawlingsj", "email": "rawlingsj@example.com" },
+      "labels": { "nodes": [ { "name": "bug", "color": "#eb5757" } ] }
+    }
+  }
+}`
+
+func TestLinearGetIssue(t *testing.T) {
+	t.Parallel()
+
+	var request struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/graphql", r.URL.Path)
+		assert.Equal(t, "test-api-key", r.Header.Get("Authorization"))
+		err := json.NewDecoder(r.Body).Decode(&request)
+		assert.NoError(t, err)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(linearIssueResponse))
+	}))
+	defer server.Close()
+
+	tracker, err := issues.CreateIssueProvider(issues.Linear, &auth.AuthServer{URL: server.URL}, &auth.UserAuth{ApiToken: "test-api-key"}, "ENG", true, nil)
+	require.NoError(t, err)
+	assert.Equal(t, issues.Linear, issues.GetIssueProvider(tracker))
+
+	issue, err := tracker.GetIssue("ENG-123")
+	require.NoError(t, err)
+	require.NotNil(t, issue)
+
+	assert.Equal(t, "ENG-123", request.Variables["id"])
+	assert.Equal(t, "ENG-123", issue.Key)
+	assert.Equal(t, "Handle empty values", issue.Title)
+	assert.Equal(t, "https://linear.app/myworkspace/issue/ENG-123/handle-empty-values", issue.URL)
+	require.NotNil(t, issue.State)
+	assert.Equal(t, "closed", *issue.State)
+	require.NotNil(t, issue.ClosedAt)
+	require.NotNil(t, issue.User)
+	assert.Equal(t, "jstrachan", issue.User.Login)
+	require.Len(t, issue.Assignees, 1)
+	assert.Equal(t, "rawlingsj", issue.Assignees[0].Login)
+	require.Len(t, issue.Labels, 1)
+	assert.Equal(t, "eb5757", issue.Labels[0].Color)
+}
+
+func TestLinearGraphQLErrors(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": null, "errors": [{"message": "Entity not found"}]}`))
+	}))
+	defer server.Close()
+
+	tracker, err := issues.CreateLinearIssueProvider(&auth.AuthServer{URL: server.URL}, &auth.UserAuth{ApiToken: "test-api-key"}, "ENG", true)
+	require.NoError(t, err)
+
+	_, err = tracker.GetIssue("ENG-999")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Entity not found")
+}
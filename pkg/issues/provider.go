@@ -6,6 +6,7 @@ import (
 
 	"github.com/jenkins-x/jx/pkg/auth"
 	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/util"
 )
 
 type IssueProvider interface {
@@ -35,6 +36,10 @@ func CreateIssueProvider(kind string, server *auth.AuthServer, userAuth *auth.Us
 	switch kind {
 	case Jira:
 		return CreateJiraIssueProvider(server, userAuth, project, batchMode, git)
+	case Linear:
+		return CreateLinearIssueProvider(server, userAuth, project, batchMode)
+	case YouTrack:
+		return CreateYouTrackIssueProvider(server, userAuth, project, batchMode)
 	default:
 		return nil, fmt.Errorf("Unsupported issue provider kind: %s", kind)
 	}
@@ -45,6 +50,10 @@ func ProviderAccessTokenURL(kind string, url string) string {
 	case Jira:
 		// TODO handle on premise servers too by detecting the URL is at atlassian.com
 		return "https://id.atlassian.com/manage/api-tokens"
+	case Linear:
+		return util.UrlJoin(url, "settings", "api")
+	case YouTrack:
+		return util.UrlJoin(url, "users", "me") + "?tab=account-security"
 	default:
 		return ""
 	}
@@ -52,9 +61,20 @@ func ProviderAccessTokenURL(kind string, url string) string {
 
 // GetIssueProvider returns the kind of issue provider
 func GetIssueProvider(tracker IssueProvider) string {
-	_, ok := tracker.(*JiraService)
-	if ok {
+	switch tracker.(type) {
+	case *JiraService:
 		return Jira
+	case *LinearService:
+		return Linear
+	case *YouTrackService:
+		return YouTrack
+	default:
+		return Git
 	}
-	return Git
+}
+
+// UsesIssueKeys returns true if the kind of issue provider identifies issues with project keys like ENG-123
+// rather than issue numbers like #123
+func UsesIssueKeys(kind string) bool {
+	return kind == Jira || kind == Linear || kind == YouTrack
 }
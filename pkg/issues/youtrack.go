@@ -0,0 +1,305 @@
+package issues
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	youTrackIssueFields = "idReadable,summary,description,created,updated,resolved," +
+		"reporter(login,fullName,email,avatarUrl),tags(name,color(background))," +
+		"customFields(name,value(name,login,fullName,email,avatarUrl))"
+)
+
+// YouTrackService is an issue provider for JetBrains YouTrack using its REST API and a permanent token.
+//
+// The project is the short name of the YouTrack project such as ENG
+type YouTrackService struct {
+	Client   *http.Client
+	Server   *auth.AuthServer
+	UserAuth *auth.UserAuth
+	Project  string
+}
+
+type youTrackUser struct {
+	Login     string `json:"login"`
+	FullName  string `json:"fullName"`
+	Email     string `json:"email"`
+	AvatarURL string `json:"avatarUrl"`
+}
+
+type youTrackTag struct {
+	Name  string `json:"name"`
+	Color *struct {
+		Background string `json:"background"`
+	} `json:"color"`
+}
+
+type youTrackCustomField struct {
+	Name  string          `json:"name"`
+	Value json.RawMessage `json:"value"`
+}
+
+type youTrackFieldValue struct {
+	youTrackUser
+	Name string `json:"name"`
+}
+
+type youTrackIssue struct {
+	IDReadable   string                `json:"idReadable"`
+	Summary      string                `json:"summary"`
+	Description  string                `json:"description"`
+	Created      int64                 `json:"created"`
+	Updated      int64                 `json:"updated"`
+	Resolved     int64                 `json:"resolved"`
+	Reporter     *youTrackUser         `json:"reporter"`
+	Tags         []youTrackTag         `json:"tags"`
+	CustomFields []youTrackCustomField `json:"customFields"`
+}
+
+type youTrackProject struct {
+	ID        string `json:"id"`
+	ShortName string `json:"shortName"`
+}
+
+// CreateYouTrackIssueProvider creates a new YouTrack issue provider
+func CreateYouTrackIssueProvider(server *auth.AuthServer, userAuth *auth.UserAuth, project string, batchMode bool) (IssueProvider, error) {
+	u := server.URL
+	if u == "" {
+		return nil, fmt.Errorf("No base URL for server!")
+	}
+	if userAuth == nil || userAuth.ApiToken == "" {
+		return nil, fmt.Errorf("No permanent token found for YouTrack server %s", u)
+	}
+	if batchMode {
+		log.Logger().Infof("Using YouTrack server %s and token %s", u, strings.Repeat("*", len(userAuth.ApiToken)))
+	}
+	return &YouTrackService{
+		Client:   &http.Client{Timeout: 30 * time.Second},
+		Server:   server,
+		UserAuth: userAuth,
+		Project:  project,
+	}, nil
+}
+
+// GetIssue returns the issue of the given id such as ENG-123
+func (i *YouTrackService) GetIssue(key string) (*gits.GitIssue, error) {
+	issue := &youTrackIssue{}
+	err := i.do(http.MethodGet, i.apiURL(url.Values{"fields": {youTrackIssueFields}}, "issues", key), nil, issue)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get YouTrack issue %s", key)
+	}
+	return i.youTrackToGitIssue(issue), nil
+}
+
+// SearchIssues searches the unresolved issues of the project
+func (i *YouTrackService) SearchIssues(query string) ([]*gits.GitIssue, error) {
+	q := fmt.Sprintf("project: {%s} #Unresolved", i.Project)
+	if query != "" {
+		q += " " + query
+	}
+	return i.searchIssues(q)
+}
+
+// SearchIssuesClosedSince searches the issues of the project resolved since the given time
+func (i *YouTrackService) SearchIssuesClosedSince(t time.Time) ([]*gits.GitIssue, error) {
+	q := fmt.Sprintf("project: {%s} resolved date: %s .. Today", i.Project, t.Format("2006-01-02"))
+	return i.searchIssues(q)
+}
+
+// CreateIssue creates a new issue in the project
+func (i *YouTrackService) CreateIssue(issue *gits.GitIssue) (*gits.GitIssue, error) {
+	project, err := i.findProject()
+	if err != nil {
+		return nil, err
+	}
+	body := map[string]interface{}{
+		"project":     map[string]string{"id": project.ID},
+		"summary":     issue.Title,
+		"description": issue.Body,
+	}
+	created := &youTrackIssue{}
+	err = i.do(http.MethodPost, i.apiURL(url.Values{"fields": {youTrackIssueFields}}, "issues"), body, created)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create YouTrack issue in project %s", i.Project)
+	}
+	return i.youTrackToGitIssue(created), nil
+}
+
+// CreateIssueComment creates a comment on the given issue
+func (i *YouTrackService) CreateIssueComment(key string, comment string) error {
+	body := map[string]string{
+		"text": comment,
+	}
+	err := i.do(http.MethodPost, i.apiURL(nil, "issues", key, "comments"), body, nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to comment on YouTrack issue %s", key)
+	}
+	return nil
+}
+
+// IssueURL returns the URL of the given issue
+func (i *YouTrackService) IssueURL(key string) string {
+	return util.UrlJoin(i.Server.URL, "issue", key)
+}
+
+// HomeURL returns the URL of the issues of the project
+func (i *YouTrackService) HomeURL() string {
+	return util.UrlJoin(i.Server.URL, "issues", strings.ToLower(i.Project))
+}
+
+func (i *YouTrackService) searchIssues(query string) ([]*gits.GitIssue, error) {
+	answer := []*gits.GitIssue{}
+	results := []youTrackIssue{}
+	params := url.Values{
+		"fields": {youTrackIssueFields},
+		"query":  {query},
+		"$top":   {"100"},
+	}
+	err := i.do(http.MethodGet, i.apiURL(params, "issues"), nil, &results)
+	if err != nil {
+		return answer, errors.Wrapf(err, "failed to search YouTrack issues with %s", query)
+	}
+	for _, issue := range results {
+		answer = append(answer, i.youTrackToGitIssue(&issue))
+	}
+	return answer, nil
+}
+
+func (i *YouTrackService) findProject() (*youTrackProject, error) {
+	projects := []youTrackProject{}
+	params := url.Values{
+		"fields": {"id,shortName"},
+		"query":  {i.Project},
+	}
+	err := i.do(http.MethodGet, i.apiURL(params, "admin", "projects"), nil, &projects)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find YouTrack project %s", i.Project)
+	}
+	for _, p := range projects {
+		if strings.EqualFold(p.ShortName, i.Project) {
+			return &p, nil
+		}
+	}
+	return nil, fmt.Errorf("could not find YouTrack project %s", i.Project)
+}
+
+func (i *YouTrackService) apiURL(params url.Values, paths ...string) string {
+	elements := []string{strings.TrimSuffix(i.Server.URL, "/"), "api"}
+	for _, p := range paths {
+		elements = append(elements, url.PathEscape(p))
+	}
+	answer := strings.Join(elements, "/")
+	if len(params) > 0 {
+		answer += "?" + params.Encode()
+	}
+	return answer
+}
+
+func (i *YouTrackService) do(method string, u string, body interface{}, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, u, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+i.UserAuth.ApiToken)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := i.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s returned status %d: %s", method, u, resp.StatusCode, string(data))
+	}
+	if result != nil && len(data) > 0 {
+		return json.Unmarshal(data, result)
+	}
+	return nil
+}
+
+func (i *YouTrackService) youTrackToGitIssue(issue *youTrackIssue) *gits.GitIssue {
+	answer := &gits.GitIssue{
+		Key:       issue.IDReadable,
+		URL:       i.IssueURL(issue.IDReadable),
+		Title:     issue.Summary,
+		Body:      issue.Description,
+		CreatedAt: youTrackTimeToTimeP(issue.Created),
+		UpdatedAt: youTrackTimeToTimeP(issue.Updated),
+		ClosedAt:  youTrackTimeToTimeP(issue.Resolved),
+		User:      youTrackUserToGitUser(issue.Reporter),
+	}
+	state := "open"
+	if issue.Resolved != 0 {
+		state = "closed"
+	}
+	answer.State = &state
+	for _, tag := range issue.Tags {
+		label := gits.GitLabel{
+			Name: tag.Name,
+		}
+		if tag.Color != nil {
+			label.Color = strings.TrimPrefix(tag.Color.Background, "#")
+		}
+		answer.Labels = append(answer.Labels, label)
+	}
+	for _, field := range issue.CustomFields {
+		if field.Name != "Assignee" || len(field.Value) == 0 {
+			continue
+		}
+		value := &youTrackFieldValue{}
+		err := json.Unmarshal(field.Value, value)
+		if err == nil && value.Login != "" {
+			answer.Assignees = []gits.GitUser{*youTrackUserToGitUser(&value.youTrackUser)}
+		}
+	}
+	return answer
+}
+
+func youTrackUserToGitUser(user *youTrackUser) *gits.GitUser {
+	if user == nil {
+		return nil
+	}
+	return &gits.GitUser{
+		Login:     user.Login,
+		Name:      user.FullName,
+		Email:     user.Email,
+		AvatarURL: user.AvatarURL,
+	}
+}
+
+// youTrackTimeToTimeP converts a YouTrack timestamp in milliseconds since the epoch
+func youTrackTimeToTimeP(millis int64) *time.Time {
+	if millis == 0 {
+		return nil
+	}
+	t := time.Unix(0, millis*int64(time.Millisecond)).UTC()
+	return &t
+}
@@ -0,0 +1,64 @@
+package issues_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/issues"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const youTrackIssueResponse = `{
+  "idReadable": "ENG-42",
+  "summary": "Promotion fails on slow clusters",
+  "description": "The rollout times out",
+  "created": 1567419330000,
+  "updated": 1567590000000,
+  "resolved": null,
+  "reporter": { "login": "jstrachan", "fullName": "James Strachan", "email": "james@example.com" },
+  "tags": [ { "name": "regression", "color": { "background": "#ff0000" } } ],
+  "customFields": [
+    { "name": "Priority", "value": { "name": "Major" } },
+    { "name": "Assignee", "value": { "login": "rawlingsj", "fullName": "James Rawlings" } }
+  ]
+}`
+
+func TestYouTrackGetIssue(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/issues/ENG-42", r.URL.Path)
+		assert.NotEmpty(t, r.URL.Query().Get("fields"))
+		assert.Equal(t, "Bearer perm:test-token", r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(youTrackIssueResponse))
+	}))
+	defer server.Close()
+
+	tracker, err := issues.CreateIssueProvider(issues.YouTrack, &auth.AuthServer{URL: server.URL}, &auth.UserAuth{ApiToken: "perm:test-token"}, "ENG", true, nil)
+	require.NoError(t, err)
+	assert.Equal(t, issues.YouTrack, issues.GetIssueProvider(tracker))
+
+	issue, err := tracker.GetIssue("ENG-42")
+	require.NoError(t, err)
+	require.NotNil(t, issue)
+
+	assert.Equal(t, "ENG-42", issue.Key)
+	assert.Equal(t, server.URL+"/issue/ENG-42", issue.URL)
+	assert.Equal(t, "Promotion fails on slow clusters", issue.Title)
+	require.NotNil(t, issue.State)
+	assert.Equal(t, "open", *issue.State)
+	assert.Nil(t, issue.ClosedAt)
+	require.NotNil(t, issue.CreatedAt)
+	assert.Equal(t, 2019, issue.CreatedAt.Year())
+	require.NotNil(t, issue.User)
+	assert.Equal(t, "jstrachan", issue.User.Login)
+	require.Len(t, issue.Assignees, 1)
+	assert.Equal(t, "rawlingsj", issue.Assignees[0].Login)
+	require.Len(t, issue.Labels, 1)
+	assert.Equal(t, "regression", issue.Labels[0].Name)
+	assert.Equal(t, "ff0000", issue.Labels[0].Color)
+}
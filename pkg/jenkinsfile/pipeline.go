@@ -324,7 +324,10 @@ func (l *PipelineLifecycle) CreateStep(mode string, step *syntax.Step) error {
 func removeWhenSteps(prow bool, steps []*syntax.Step) []*syntax.Step {
 	answer := []*syntax.Step{}
 	for _, step := range steps {
-		when := strings.TrimSpace(step.When)
+		when := ""
+		if step.When != nil {
+			when = strings.TrimSpace(step.When.Legacy)
+		}
 		if prow && when == "!prow" {
 			continue
 		}
@@ -33,6 +33,7 @@ type PipelineActivityKey struct {
 	LastCommitURL     string
 	GitInfo           *gits.GitRepository
 	PullRefs          map[string]string
+	Parameters        []v1.PipelineParameter
 }
 
 func (k *PipelineActivityKey) IsValid() bool {
@@ -56,6 +57,10 @@ type PromoteStepActivityKey struct {
 
 type PromotePullRequestFn func(*v1.PipelineActivity, *v1.PipelineActivityStep, *v1.PromoteActivityStep, *v1.PromotePullRequestStep) error
 type PromoteUpdateFn func(*v1.PipelineActivity, *v1.PipelineActivityStep, *v1.PromoteActivityStep, *v1.PromoteUpdateStep) error
+type PromoteFn func(*v1.PipelineActivity, *v1.PipelineActivityStep, *v1.PromoteActivityStep) error
+type PromoteApprovalFn func(*v1.PipelineActivity, *v1.PipelineActivityStep, *v1.PromoteActivityStep, *v1.PromoteApprovalStep) error
+type PromoteCanaryFn func(*v1.PipelineActivity, *v1.PipelineActivityStep, *v1.PromoteActivityStep, *v1.PromoteCanaryStep) error
+type PromoteVerifyFn func(*v1.PipelineActivity, *v1.PipelineActivityStep, *v1.PromoteActivityStep, *v1.PromoteVerifyStep) error
 
 type PipelineDetails struct {
 	GitOwner      string
@@ -483,6 +488,9 @@ func updateActivitySpec(k *PipelineActivityKey, spec *v1.PipelineActivitySpec) {
 	if k.Version != "" && spec.Version == "" {
 		spec.Version = k.Version
 	}
+	if len(k.Parameters) > 0 && len(spec.Parameters) == 0 {
+		spec.Parameters = k.Parameters
+	}
 	gi := k.GitInfo
 	if gi != nil {
 		if gi.URL != "" && spec.GitURL == "" {
@@ -681,6 +689,171 @@ func (k *PromoteStepActivityKey) GetOrCreatePromoteUpdate(jxClient versioned.Int
 	return a, s, p, p.Update, created, err
 }
 
+// OnPromote updates the Promote step of the activity for the key
+func (k *PromoteStepActivityKey) OnPromote(jxClient versioned.Interface, ns string, fn PromoteFn) error {
+	if !k.IsValid() {
+		return nil
+	}
+	activities := jxClient.JenkinsV1().PipelineActivities(ns)
+	if activities == nil {
+		log.Logger().Warn("Warning: no PipelineActivities client available!")
+		return nil
+	}
+	a, s, ps, added, err := k.GetOrCreatePromote(jxClient, ns)
+	if err != nil {
+		return err
+	}
+	p1 := asYaml(a)
+	err = fn(a, s, ps)
+	if err != nil {
+		return err
+	}
+	p2 := asYaml(a)
+
+	if added || p1 == "" || p1 != p2 {
+		_, err = activities.PatchUpdate(a)
+	}
+	return err
+}
+
+// GetOrCreatePromoteApproval gets or creates the PromoteApprovalStep for the key
+func (k *PromoteStepActivityKey) GetOrCreatePromoteApproval(jxClient versioned.Interface, ns string) (*v1.PipelineActivity, *v1.PipelineActivityStep, *v1.PromoteActivityStep, *v1.PromoteApprovalStep, bool, error) {
+	a, s, p, created, err := k.GetOrCreatePromote(jxClient, ns)
+	if err != nil {
+		return nil, nil, nil, nil, created, err
+	}
+	if p.Approval == nil {
+		created = true
+		p.Approval = &v1.PromoteApprovalStep{
+			CoreActivityStep: v1.CoreActivityStep{
+				StartedTimestamp: &metav1.Time{
+					Time: time.Now(),
+				},
+			},
+		}
+	}
+	return a, s, p, p.Approval, created, err
+}
+
+// OnPromoteApproval updates activities while waiting for a promotion to be approved
+func (k *PromoteStepActivityKey) OnPromoteApproval(jxClient versioned.Interface, ns string, fn PromoteApprovalFn) error {
+	if !k.IsValid() {
+		return nil
+	}
+	activities := jxClient.JenkinsV1().PipelineActivities(ns)
+	if activities == nil {
+		log.Logger().Warn("Warning: no PipelineActivities client available!")
+		return nil
+	}
+	a, s, ps, p, added, err := k.GetOrCreatePromoteApproval(jxClient, ns)
+	if err != nil {
+		return err
+	}
+	p1 := asYaml(a)
+	err = fn(a, s, ps, p)
+	if err != nil {
+		return err
+	}
+	p2 := asYaml(a)
+
+	if added || p1 == "" || p1 != p2 {
+		_, err = activities.PatchUpdate(a)
+	}
+	return err
+}
+
+// GetOrCreatePromoteCanary gets or creates the PromoteCanaryStep for the key
+func (k *PromoteStepActivityKey) GetOrCreatePromoteCanary(jxClient versioned.Interface, ns string) (*v1.PipelineActivity, *v1.PipelineActivityStep, *v1.PromoteActivityStep, *v1.PromoteCanaryStep, bool, error) {
+	a, s, p, created, err := k.GetOrCreatePromote(jxClient, ns)
+	if err != nil {
+		return nil, nil, nil, nil, created, err
+	}
+	if p.Canary == nil {
+		created = true
+		p.Canary = &v1.PromoteCanaryStep{
+			CoreActivityStep: v1.CoreActivityStep{
+				StartedTimestamp: &metav1.Time{
+					Time: time.Now(),
+				},
+			},
+		}
+	}
+	return a, s, p, p.Canary, created, err
+}
+
+// OnPromoteCanary updates activities while waiting for the canary analysis of a promotion to complete
+func (k *PromoteStepActivityKey) OnPromoteCanary(jxClient versioned.Interface, ns string, fn PromoteCanaryFn) error {
+	if !k.IsValid() {
+		return nil
+	}
+	activities := jxClient.JenkinsV1().PipelineActivities(ns)
+	if activities == nil {
+		log.Logger().Warn("Warning: no PipelineActivities client available!")
+		return nil
+	}
+	a, s, ps, p, added, err := k.GetOrCreatePromoteCanary(jxClient, ns)
+	if err != nil {
+		return err
+	}
+	p1 := asYaml(a)
+	err = fn(a, s, ps, p)
+	if err != nil {
+		return err
+	}
+	p2 := asYaml(a)
+
+	if added || p1 == "" || p1 != p2 {
+		_, err = activities.PatchUpdate(a)
+	}
+	return err
+}
+
+// GetOrCreatePromoteVerify gets or creates the PromoteVerifyStep for the key
+func (k *PromoteStepActivityKey) GetOrCreatePromoteVerify(jxClient versioned.Interface, ns string) (*v1.PipelineActivity, *v1.PipelineActivityStep, *v1.PromoteActivityStep, *v1.PromoteVerifyStep, bool, error) {
+	a, s, p, created, err := k.GetOrCreatePromote(jxClient, ns)
+	if err != nil {
+		return nil, nil, nil, nil, created, err
+	}
+	if p.Verify == nil {
+		created = true
+		p.Verify = &v1.PromoteVerifyStep{
+			CoreActivityStep: v1.CoreActivityStep{
+				StartedTimestamp: &metav1.Time{
+					Time: time.Now(),
+				},
+			},
+		}
+	}
+	return a, s, p, p.Verify, created, err
+}
+
+// OnPromoteVerify updates activities while verifying the application is healthy after a promotion
+func (k *PromoteStepActivityKey) OnPromoteVerify(jxClient versioned.Interface, ns string, fn PromoteVerifyFn) error {
+	if !k.IsValid() {
+		return nil
+	}
+	activities := jxClient.JenkinsV1().PipelineActivities(ns)
+	if activities == nil {
+		log.Logger().Warn("Warning: no PipelineActivities client available!")
+		return nil
+	}
+	a, s, ps, p, added, err := k.GetOrCreatePromoteVerify(jxClient, ns)
+	if err != nil {
+		return err
+	}
+	p1 := asYaml(a)
+	err = fn(a, s, ps, p)
+	if err != nil {
+		return err
+	}
+	p2 := asYaml(a)
+
+	if added || p1 == "" || p1 != p2 {
+		_, err = activities.PatchUpdate(a)
+	}
+	return err
+}
+
 //OnPromotePullRequest updates activities on a Promote PR
 func (k *PromoteStepActivityKey) OnPromotePullRequest(jxClient versioned.Interface, ns string, fn PromotePullRequestFn) error {
 	if !k.IsValid() {
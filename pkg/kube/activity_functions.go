@@ -152,3 +152,117 @@ func FailedPromotionUpdate(a *v1.PipelineActivity, s *v1.PipelineActivityStep, p
 	p.Status = v1.ActivityStatusTypeFailed
 	return nil
 }
+
+// StartPromotionApproval marks the promotion as waiting for approval
+func StartPromotionApproval(a *v1.PipelineActivity, s *v1.PipelineActivityStep, ps *v1.PromoteActivityStep, p *v1.PromoteApprovalStep) error {
+	StartPromote(ps)
+	if p.StartedTimestamp == nil {
+		p.StartedTimestamp = &metav1.Time{
+			Time: time.Now(),
+		}
+	}
+	ps.Status = v1.ActivityStatusTypeWaitingForApproval
+	p.Status = v1.ActivityStatusTypeWaitingForApproval
+	return nil
+}
+
+// CompletePromotionApproval marks the promotion as approved so that it can continue
+func CompletePromotionApproval(a *v1.PipelineActivity, s *v1.PipelineActivityStep, ps *v1.PromoteActivityStep, p *v1.PromoteApprovalStep) error {
+	if p.CompletedTimestamp == nil {
+		p.CompletedTimestamp = &metav1.Time{
+			Time: time.Now(),
+		}
+	}
+	p.Status = v1.ActivityStatusTypeSucceeded
+	ps.Status = v1.ActivityStatusTypeRunning
+	return nil
+}
+
+// RejectedPromotionApproval marks the promotion as failed as it was rejected or not approved in time
+func RejectedPromotionApproval(a *v1.PipelineActivity, s *v1.PipelineActivityStep, ps *v1.PromoteActivityStep, p *v1.PromoteApprovalStep) error {
+	if p.CompletedTimestamp == nil {
+		p.CompletedTimestamp = &metav1.Time{
+			Time: time.Now(),
+		}
+	}
+	p.Status = v1.ActivityStatusTypeAborted
+	FailedPromote(ps)
+	return nil
+}
+
+// StartPromotionCanary marks the promotion as running while the canary analysis progresses. The promotion is not
+// complete until the canary has been promoted, even if the update of the environment has completed
+func StartPromotionCanary(a *v1.PipelineActivity, s *v1.PipelineActivityStep, ps *v1.PromoteActivityStep, p *v1.PromoteCanaryStep) error {
+	StartPromote(ps)
+	ps.CompletedTimestamp = nil
+	ps.Status = v1.ActivityStatusTypeRunning
+	if p.StartedTimestamp == nil {
+		p.StartedTimestamp = &metav1.Time{
+			Time: time.Now(),
+		}
+	}
+	p.Status = v1.ActivityStatusTypeRunning
+	return nil
+}
+
+// CompletePromotionCanary marks the promotion as succeeded as the canary has been promoted
+func CompletePromotionCanary(a *v1.PipelineActivity, s *v1.PipelineActivityStep, ps *v1.PromoteActivityStep, p *v1.PromoteCanaryStep) error {
+	if p.CompletedTimestamp == nil {
+		p.CompletedTimestamp = &metav1.Time{
+			Time: time.Now(),
+		}
+	}
+	p.Status = v1.ActivityStatusTypeSucceeded
+	CompletePromote(ps)
+	return nil
+}
+
+// FailedPromotionCanary marks the promotion as failed as the canary analysis was aborted or did not complete in time
+func FailedPromotionCanary(a *v1.PipelineActivity, s *v1.PipelineActivityStep, ps *v1.PromoteActivityStep, p *v1.PromoteCanaryStep) error {
+	if p.CompletedTimestamp == nil {
+		p.CompletedTimestamp = &metav1.Time{
+			Time: time.Now(),
+		}
+	}
+	p.Status = v1.ActivityStatusTypeFailed
+	FailedPromote(ps)
+	return nil
+}
+
+// StartPromotionVerify marks the promotion as running while the application is verified to be healthy
+func StartPromotionVerify(a *v1.PipelineActivity, s *v1.PipelineActivityStep, ps *v1.PromoteActivityStep, p *v1.PromoteVerifyStep) error {
+	StartPromote(ps)
+	ps.CompletedTimestamp = nil
+	ps.Status = v1.ActivityStatusTypeRunning
+	if p.StartedTimestamp == nil {
+		p.StartedTimestamp = &metav1.Time{
+			Time: time.Now(),
+		}
+	}
+	p.Status = v1.ActivityStatusTypeRunning
+	return nil
+}
+
+// CompletePromotionVerify marks the promotion as succeeded as all the checks of the application passed
+func CompletePromotionVerify(a *v1.PipelineActivity, s *v1.PipelineActivityStep, ps *v1.PromoteActivityStep, p *v1.PromoteVerifyStep) error {
+	if p.CompletedTimestamp == nil {
+		p.CompletedTimestamp = &metav1.Time{
+			Time: time.Now(),
+		}
+	}
+	p.Status = v1.ActivityStatusTypeSucceeded
+	CompletePromote(ps)
+	return nil
+}
+
+// FailedPromotionVerify marks the promotion as failed as a check of the application failed
+func FailedPromotionVerify(a *v1.PipelineActivity, s *v1.PipelineActivityStep, ps *v1.PromoteActivityStep, p *v1.PromoteVerifyStep) error {
+	if p.CompletedTimestamp == nil {
+		p.CompletedTimestamp = &metav1.Time{
+			Time: time.Now(),
+		}
+	}
+	p.Status = v1.ActivityStatusTypeFailed
+	FailedPromote(ps)
+	return nil
+}
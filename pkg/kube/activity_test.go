@@ -353,6 +353,39 @@ func TestCreateOrUpdatePRActivityWithLastCommitSHA(t *testing.T) {
 	assert.Equal(t, "sha1", a.ObjectMeta.Labels[v1.LabelLastCommitSha])
 }
 
+func TestCreateActivityWithParameters(t *testing.T) {
+	t.Parallel()
+
+	jxClient := jxfake.NewSimpleClientset()
+	parameters := []v1.PipelineParameter{
+		{Name: "TARGET", Value: "production"},
+		{Name: "DRY_RUN", Value: "false"},
+	}
+	key := kube.PipelineActivityKey{
+		Name:     "test-org-demo-master-3",
+		Pipeline: "test-org/demo/master",
+		Build:    "3",
+		GitInfo: &gits.GitRepository{
+			Name:         "demo",
+			Organisation: "test-org",
+			URL:          "https://github.com/test-org/demo",
+		},
+		Parameters: parameters,
+	}
+
+	a, created, err := key.GetOrCreate(jxClient, "jx-testing")
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, parameters, a.Spec.Parameters)
+
+	// the parameters of an existing activity are kept
+	key.Parameters = []v1.PipelineParameter{{Name: "TARGET", Value: "staging"}}
+	a, created, err = key.GetOrCreate(jxClient, "jx-testing")
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, parameters, a.Spec.Parameters)
+}
+
 func TestBatchReconciliationWithTwoPRBuildExecutions(t *testing.T) {
 	t.Parallel()
 
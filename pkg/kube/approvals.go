@@ -0,0 +1,159 @@
+package kube
+
+import (
+	"sort"
+
+	jenkinsio "github.com/jenkins-x/jx/pkg/apis/jenkins.io"
+	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/pkg/kube/naming"
+	"github.com/pkg/errors"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// PromotionApprovalName returns the name of the PromotionApproval for promoting the version of an app to an environment
+func PromotionApprovalName(app string, version string, env string) string {
+	return naming.ToValidName(app + "-" + version + "-" + env)
+}
+
+// GetPendingPromotionApprovals returns the promotion approvals which are still waiting for a decision
+// ordered by creation time
+func GetPendingPromotionApprovals(jxClient versioned.Interface, ns string) ([]v1.PromotionApproval, error) {
+	list, err := jxClient.JenkinsV1().PromotionApprovals(ns).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list PromotionApprovals in namespace %s", ns)
+	}
+	answer := []v1.PromotionApproval{}
+	for _, approval := range list.Items {
+		if approval.IsPending() {
+			answer = append(answer, approval)
+		}
+	}
+	sort.Slice(answer, func(i, j int) bool {
+		return answer[i].CreationTimestamp.Before(&answer[j].CreationTimestamp)
+	})
+	return answer, nil
+}
+
+// IsPromotionApprover returns true if the user can approve the promotion, which is when the user is a subject of
+// the EnvironmentRoleBinding of the approval or no role binding is specified
+func IsPromotionApprover(jxClient versioned.Interface, ns string, approval *v1.PromotionApproval, userName string) (bool, error) {
+	roleBinding := approval.Spec.RoleBinding
+	if roleBinding == "" {
+		return true, nil
+	}
+	binding, err := jxClient.JenkinsV1().EnvironmentRoleBindings(ns).Get(roleBinding, metav1.GetOptions{})
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to find the EnvironmentRoleBinding %s of the approvers", roleBinding)
+	}
+	for _, subject := range binding.Spec.Subjects {
+		if subject.Kind == rbacv1.UserKind && subject.Name == userName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CanManagePromotionApprovers returns true if the current user can update the EnvironmentRoleBindings in the
+// namespace, which lets them make any user an approver
+func CanManagePromotionApprovers(kubeClient kubernetes.Interface, ns string) (bool, error) {
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: ns,
+				Verb:      "update",
+				Group:     jenkinsio.GroupName,
+				Resource:  "environmentrolebindings",
+			},
+		},
+	}
+	answer, err := kubeClient.AuthorizationV1().SelfSubjectAccessReviews().Create(review)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to check if the current user can update the EnvironmentRoleBindings in namespace %s", ns)
+	}
+	return answer.Status.Allowed, nil
+}
+
+// GetPendingStageInputs returns the input stages of the PipelineActivity which are waiting for a decision
+func GetPendingStageInputs(activity *v1.PipelineActivity) []*v1.StageActivityStep {
+	var answer []*v1.StageActivityStep
+	for i := range activity.Spec.Steps {
+		stage := activity.Spec.Steps[i].Stage
+		if stage != nil && stage.Input != nil && stage.Input.IsPending() {
+			answer = append(answer, stage)
+		}
+	}
+	return answer
+}
+
+// GetPipelineActivitiesWaitingForInput returns the PipelineActivities which have an input stage waiting for a
+// decision ordered by creation time
+func GetPipelineActivitiesWaitingForInput(jxClient versioned.Interface, ns string) ([]v1.PipelineActivity, error) {
+	list, err := jxClient.JenkinsV1().PipelineActivities(ns).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list PipelineActivities in namespace %s", ns)
+	}
+	answer := []v1.PipelineActivity{}
+	for _, activity := range list.Items {
+		if !activity.Spec.Status.IsTerminated() && len(GetPendingStageInputs(&activity)) > 0 {
+			answer = append(answer, activity)
+		}
+	}
+	sort.Slice(answer, func(i, j int) bool {
+		return answer[i].CreationTimestamp.Before(&answer[j].CreationTimestamp)
+	})
+	return answer, nil
+}
+
+// GetLatestPipelineActivityWaitingForInput returns the name of the latest build of the pipeline, of the form
+// owner/repo/branch, which has an input stage waiting for a decision
+func GetLatestPipelineActivityWaitingForInput(jxClient versioned.Interface, ns string, pipeline string) (string, error) {
+	waiting, err := GetPipelineActivitiesWaitingForInput(jxClient, ns)
+	if err != nil {
+		return "", err
+	}
+	for i := len(waiting) - 1; i >= 0; i-- {
+		if waiting[i].Spec.Pipeline == pipeline {
+			return waiting[i].Name, nil
+		}
+	}
+	return "", errors.Errorf("the pipeline %s has no builds waiting for approval", pipeline)
+}
+
+// DecideStageInput records the decision of the user on the pending input stage of the PipelineActivity. The stage
+// name can be omitted when the pipeline has a single pending input
+func DecideStageInput(jxClient versioned.Interface, ns string, activityName string, stageName string, userName string, approved bool, comment string) (*v1.StageActivityStep, error) {
+	activities := jxClient.JenkinsV1().PipelineActivities(ns)
+	activity, err := activities.Get(activityName, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find the PipelineActivity %s", activityName)
+	}
+	pending := GetPendingStageInputs(activity)
+	var stage *v1.StageActivityStep
+	for _, s := range pending {
+		if stageName == "" || s.Name == stageName {
+			if stage != nil {
+				return nil, errors.Errorf("the pipeline %s has more than one input waiting for approval so the stage must be specified", activityName)
+			}
+			stage = s
+		}
+	}
+	if stage == nil {
+		if stageName != "" {
+			return nil, errors.Errorf("the stage %s of the pipeline %s is not waiting for approval", stageName, activityName)
+		}
+		return nil, errors.Errorf("the pipeline %s is not waiting for approval", activityName)
+	}
+	if !stage.Input.IsApprover(userName) {
+		return nil, errors.Errorf("user %s is not an approver of the stage %s of the pipeline %s", userName, stage.Name, activityName)
+	}
+	stage.Input.AddDecision(userName, approved, comment)
+	_, err = activities.PatchUpdate(activity)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to update the PipelineActivity %s", activityName)
+	}
+	return stage, nil
+}
@@ -249,6 +249,12 @@ const (
 	// ValueJobKindPostPreview
 	ValueJobKindPostPreview = "post-preview-step"
 
+	// ValueJobKindPreviewSeed for Jobs which seed the dependencies of a preview environment
+	ValueJobKindPreviewSeed = "preview-seed"
+
+	// LabelPreviewEnvironment the name of the preview environment a resource was created for
+	LabelPreviewEnvironment = "jenkins.io/preview"
+
 	// AnnotationURL indicates a service/server's URL
 	AnnotationURL = "jenkins.io/url"
 
@@ -281,6 +287,10 @@ const (
 	// AnnotationReleaseName is the name of the annotation that stores the release name in the preview environment
 	AnnotationReleaseName = "jenkins.io/chart-release"
 
+	// AnnotationFreezeOverrideUser the user given by 'jx promote --override-user' when overriding a freeze window. It is
+	// not verified so the FreezeOverride of the promotion records the current user
+	AnnotationFreezeOverrideUser = "jenkins.io/freeze-override-user"
+
 	// SecretDataUsername the username in a Secret/Credentials
 	SecretDataUsername = "username"
 
@@ -54,6 +54,10 @@ func RegisterAllCRDs(apiClient apiextensionsclientset.Interface) error {
 	if err != nil {
 		return errors.Wrap(err, "failed to register the Fact CRD")
 	}
+	err = RegisterPromotionApprovalCRD(apiClient)
+	if err != nil {
+		return errors.Wrap(err, "failed to register the Promotion Approval CRD")
+	}
 	err = RegisterTeamCRD(apiClient)
 	if err != nil {
 		return errors.Wrap(err, "failed to register the Team CRD")
@@ -248,6 +252,46 @@ func RegisterPipelineStructureCRD(apiClient apiextensionsclientset.Interface) er
 	return RegisterCRD(apiClient, name, names, columns, jenkinsio.GroupName, jenkinsio.Package, jenkinsio.Version)
 }
 
+// RegisterPromotionApprovalCRD ensures that the CRD is registered for PromotionApproval
+func RegisterPromotionApprovalCRD(apiClient apiextensionsclientset.Interface) error {
+	name := "promotionapprovals." + jenkinsio.GroupName
+	names := &v1beta1.CustomResourceDefinitionNames{
+		Kind:       "PromotionApproval",
+		ListKind:   "PromotionApprovalList",
+		Plural:     "promotionapprovals",
+		Singular:   "promotionapproval",
+		ShortNames: []string{"approval", "approvals"},
+		Categories: []string{"all"},
+	}
+	columns := []v1beta1.CustomResourceColumnDefinition{
+		{
+			Name:        "Application",
+			Type:        "string",
+			Description: "The name of the application being promoted",
+			JSONPath:    ".spec.application",
+		},
+		{
+			Name:        "Version",
+			Type:        "string",
+			Description: "The version being promoted",
+			JSONPath:    ".spec.version",
+		},
+		{
+			Name:        "Environment",
+			Type:        "string",
+			Description: "The environment the version is being promoted to",
+			JSONPath:    ".spec.environment",
+		},
+		{
+			Name:        "Status",
+			Type:        "string",
+			Description: "The status of the approval",
+			JSONPath:    ".status.status",
+		},
+	}
+	return RegisterCRD(apiClient, name, names, columns, jenkinsio.GroupName, jenkinsio.Package, jenkinsio.Version)
+}
+
 // RegisterFactCRD ensures that the CRD is registered for Fact
 func RegisterFactCRD(apiClient apiextensionsclientset.Interface) error {
 	name := "facts." + jenkinsio.GroupName
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/jenkins-x/jx/pkg/log"
+	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/api/apps/v1beta1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -151,6 +152,33 @@ func WaitForDeploymentToBeReady(client kubernetes.Interface, name, namespace str
 	return nil
 }
 
+// DeploymentRolloutStatus returns whether the rollout of the latest revision of the deployment has completed along
+// with a message describing its progress. An error is returned if the rollout exceeded its progress deadline
+func DeploymentRolloutStatus(d *appsv1.Deployment) (bool, string, error) {
+	if d.Generation > d.Status.ObservedGeneration {
+		return false, fmt.Sprintf("waiting for the update of deployment %s to be observed", d.Name), nil
+	}
+	for _, c := range d.Status.Conditions {
+		if c.Type == appsv1.DeploymentProgressing && c.Reason == "ProgressDeadlineExceeded" {
+			return false, "", fmt.Errorf("deployment %s exceeded its progress deadline", d.Name)
+		}
+	}
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	if d.Status.UpdatedReplicas < replicas {
+		return false, fmt.Sprintf("%d out of %d new replicas of deployment %s have been updated", d.Status.UpdatedReplicas, replicas, d.Name), nil
+	}
+	if d.Status.Replicas > d.Status.UpdatedReplicas {
+		return false, fmt.Sprintf("%d old replicas of deployment %s are pending termination", d.Status.Replicas-d.Status.UpdatedReplicas, d.Name), nil
+	}
+	if d.Status.AvailableReplicas < d.Status.UpdatedReplicas {
+		return false, fmt.Sprintf("%d of %d updated replicas of deployment %s are available", d.Status.AvailableReplicas, d.Status.UpdatedReplicas, d.Name), nil
+	}
+	return true, fmt.Sprintf("deployment %s successfully rolled out", d.Name), nil
+}
+
 func DeploymentPodCount(client kubernetes.Interface, name, namespace string) (int, error) {
 	pods, err := GetDeploymentPods(client, name, namespace)
 	if err == nil {
@@ -86,3 +86,61 @@ func TestWaitForDeploymentToBeReady(t *testing.T) {
 	assert.NoError(t, err, "Should not error")
 
 }
+
+func TestDeploymentRolloutStatus(t *testing.T) {
+	t.Parallel()
+
+	replicas := int32(2)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:       "jx-myapp",
+			Generation: 2,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+		},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 1,
+			Replicas:           2,
+			UpdatedReplicas:    0,
+			AvailableReplicas:  2,
+		},
+	}
+
+	done, message, err := kube.DeploymentRolloutStatus(deployment)
+	assert.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, "waiting for the update of deployment jx-myapp to be observed", message)
+
+	deployment.Status.ObservedGeneration = 2
+	deployment.Status.Replicas = 3
+	deployment.Status.UpdatedReplicas = 1
+	done, message, err = kube.DeploymentRolloutStatus(deployment)
+	assert.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, "1 out of 2 new replicas of deployment jx-myapp have been updated", message)
+
+	deployment.Status.UpdatedReplicas = 2
+	done, message, err = kube.DeploymentRolloutStatus(deployment)
+	assert.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, "1 old replicas of deployment jx-myapp are pending termination", message)
+
+	deployment.Status.Replicas = 2
+	deployment.Status.AvailableReplicas = 1
+	done, message, err = kube.DeploymentRolloutStatus(deployment)
+	assert.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, "1 of 2 updated replicas of deployment jx-myapp are available", message)
+
+	deployment.Status.AvailableReplicas = 2
+	done, _, err = kube.DeploymentRolloutStatus(deployment)
+	assert.NoError(t, err)
+	assert.True(t, done)
+
+	deployment.Status.Conditions = []appsv1.DeploymentCondition{
+		{Type: appsv1.DeploymentProgressing, Reason: "ProgressDeadlineExceeded"},
+	}
+	_, _, err = kube.DeploymentRolloutStatus(deployment)
+	assert.Error(t, err)
+}
@@ -0,0 +1,323 @@
+package kube
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/kube/naming"
+	"github.com/jenkins-x/jx/pkg/kube/snapshots"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// DefaultPreviewSeedImage is the image of the Jobs which copy SQL dumps into the dependencies of preview environments
+	DefaultPreviewSeedImage = "gcr.io/jenkinsxio/builder-jx"
+
+	defaultSQLDumpSize  = "1Gi"
+	previewSeedVolume   = "seed"
+	previewSeedMountDir = "/seed"
+)
+
+// ValidatePreviewDependencies returns an error if the dependencies of a preview environment are invalid
+func ValidatePreviewDependencies(dependencies []config.PreviewDependency) error {
+	names := map[string]bool{}
+	for _, dep := range dependencies {
+		if dep.Name == "" {
+			return errors.New("previewEnvironments dependency has no name")
+		}
+		if naming.ToValidName(dep.Name) != dep.Name {
+			return fmt.Errorf("previewEnvironments dependency name %s is not a valid PersistentVolumeClaim name", dep.Name)
+		}
+		if names[dep.Name] {
+			return fmt.Errorf("previewEnvironments dependency %s is specified more than once", dep.Name)
+		}
+		names[dep.Name] = true
+		if (dep.VolumeSnapshot == nil) == (dep.SQLDump == nil) {
+			return fmt.Errorf("previewEnvironments dependency %s must have either a volumeSnapshot or a sqlDump", dep.Name)
+		}
+		if dep.VolumeSnapshot != nil && dep.VolumeSnapshot.Name == "" {
+			return fmt.Errorf("previewEnvironments dependency %s has no volumeSnapshot name", dep.Name)
+		}
+		if dep.SQLDump != nil && dep.SQLDump.URL == "" {
+			return fmt.Errorf("previewEnvironments dependency %s has no sqlDump url", dep.Name)
+		}
+		if dep.Size != "" {
+			_, err := resource.ParseQuantity(dep.Size)
+			if err != nil {
+				return errors.Wrapf(err, "invalid size %s of previewEnvironments dependency %s", dep.Size, dep.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// SeedPreviewDependencies creates the PersistentVolumeClaim of each dependency in the namespace of the preview
+// environment which does not exist yet so that redeploying a preview keeps its data. A VolumeSnapshot is cloned from
+// the devNs and a SQL dump is copied by a Job which is returned so that the caller can wait for it to complete
+func SeedPreviewDependencies(kubeClient kubernetes.Interface, devNs string, envName string, previewNs string, dependencies []config.PreviewDependency) ([]*batchv1.Job, error) {
+	var jobs []*batchv1.Job
+	for _, dep := range dependencies {
+		_, err := kubeClient.CoreV1().PersistentVolumeClaims(previewNs).Get(dep.Name, metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return jobs, errors.Wrapf(err, "failed to get PersistentVolumeClaim %s in namespace %s", dep.Name, previewNs)
+		}
+		claimExists := err == nil
+		if claimExists && dep.VolumeSnapshot != nil {
+			log.Logger().Infof("Preview dependency %s already exists in namespace %s", util.ColorInfo(dep.Name), util.ColorInfo(previewNs))
+			continue
+		}
+		labels := map[string]string{
+			LabelCreatedBy:          ValueCreatedByJX,
+			LabelPreviewEnvironment: envName,
+		}
+		if dep.VolumeSnapshot != nil {
+			err = clonePreviewVolumeSnapshot(kubeClient, devNs, previewNs, dep, labels)
+			if err != nil {
+				return jobs, err
+			}
+			continue
+		}
+		job, err := copyPreviewSQLDump(kubeClient, previewNs, dep, labels, claimExists)
+		if err != nil {
+			return jobs, err
+		}
+		if job != nil {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
+// DeletePreviewDependencies deletes the cluster scoped resources created for the dependencies of the preview
+// environment. The snapshots in the storage are retained and the namespaced resources are deleted with the namespace
+func DeletePreviewDependencies(kubeClient kubernetes.Interface, envName string) error {
+	contents, err := snapshots.ListVolumeSnapshotContents(kubeClient, LabelPreviewEnvironment+"="+envName)
+	if err != nil {
+		if apierrors.IsNotFound(errors.Cause(err)) {
+			// the volume snapshot API is not installed so there is nothing to delete
+			return nil
+		}
+		return err
+	}
+	for _, content := range contents {
+		log.Logger().Infof("Deleting VolumeSnapshotContent %s of preview environment %s", util.ColorInfo(content.Name), util.ColorInfo(envName))
+		err = snapshots.DeleteVolumeSnapshotContent(kubeClient, content.Name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// clonePreviewVolumeSnapshot creates a PersistentVolumeClaim from a VolumeSnapshot in another namespace. As a claim
+// can only be restored from a VolumeSnapshot in its own namespace the snapshot is bound to a new VolumeSnapshot in the
+// preview namespace via a pre-provisioned VolumeSnapshotContent
+func clonePreviewVolumeSnapshot(kubeClient kubernetes.Interface, devNs string, previewNs string, dep config.PreviewDependency, labels map[string]string) error {
+	snapshotNs := dep.VolumeSnapshot.Namespace
+	if snapshotNs == "" {
+		snapshotNs = devNs
+	}
+	source, err := snapshots.GetVolumeSnapshot(kubeClient, snapshotNs, dep.VolumeSnapshot.Name)
+	if err != nil {
+		return err
+	}
+	if source.Status == nil || source.Status.BoundVolumeSnapshotContentName == "" {
+		return fmt.Errorf("VolumeSnapshot %s in namespace %s is not bound to a VolumeSnapshotContent yet", dep.VolumeSnapshot.Name, snapshotNs)
+	}
+	sourceContent, err := snapshots.GetVolumeSnapshotContent(kubeClient, source.Status.BoundVolumeSnapshotContentName)
+	if err != nil {
+		return err
+	}
+	contentName := naming.ToValidNameTruncated(previewNs+"-"+dep.Name, 253)
+	content, err := snapshots.NewCloneContent(contentName, sourceContent, previewNs, dep.Name, labels)
+	if err != nil {
+		return err
+	}
+	err = snapshots.CreateVolumeSnapshotContent(kubeClient, content)
+	if err != nil && !apierrors.IsAlreadyExists(errors.Cause(err)) {
+		return err
+	}
+	snapshot := &snapshots.VolumeSnapshot{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: snapshots.APIVersion,
+			Kind:       snapshots.KindVolumeSnapshot,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      dep.Name,
+			Namespace: previewNs,
+			Labels:    labels,
+		},
+		Spec: snapshots.VolumeSnapshotSpec{
+			Source: snapshots.VolumeSnapshotSource{
+				VolumeSnapshotContentName: contentName,
+			},
+			VolumeSnapshotClassName: sourceContent.Spec.VolumeSnapshotClassName,
+		},
+	}
+	err = snapshots.CreateVolumeSnapshot(kubeClient, snapshot)
+	if err != nil && !apierrors.IsAlreadyExists(errors.Cause(err)) {
+		return err
+	}
+
+	size := dep.Size
+	if size == "" && source.Status.RestoreSize != "" {
+		size = source.Status.RestoreSize
+	}
+	if size == "" {
+		return fmt.Errorf("no size specified for previewEnvironments dependency %s and VolumeSnapshot %s has no restore size", dep.Name, dep.VolumeSnapshot.Name)
+	}
+	pvc, err := newPreviewDependencyClaim(previewNs, dep, size, labels)
+	if err != nil {
+		return err
+	}
+	group := snapshots.Group
+	pvc.Spec.DataSource = &corev1.TypedLocalObjectReference{
+		APIGroup: &group,
+		Kind:     snapshots.KindVolumeSnapshot,
+		Name:     dep.Name,
+	}
+	_, err = kubeClient.CoreV1().PersistentVolumeClaims(previewNs).Create(pvc)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create PersistentVolumeClaim %s in namespace %s", dep.Name, previewNs)
+	}
+	log.Logger().Infof("Cloned VolumeSnapshot %s into preview dependency %s", util.ColorInfo(snapshotNs+"/"+dep.VolumeSnapshot.Name), util.ColorInfo(dep.Name))
+	return nil
+}
+
+// copyPreviewSQLDump creates an empty PersistentVolumeClaim and a Job which copies the SQL dump into it. If the claim
+// already exists the Job is only created again if it failed and nil is returned if it already succeeded
+func copyPreviewSQLDump(kubeClient kubernetes.Interface, previewNs string, dep config.PreviewDependency, labels map[string]string, claimExists bool) (*batchv1.Job, error) {
+	jobName := naming.ToValidNameTruncated("seed-"+dep.Name, 63)
+	jobs := kubeClient.BatchV1().Jobs(previewNs)
+	if claimExists {
+		existing, err := jobs.Get(jobName, metav1.GetOptions{})
+		if err == nil {
+			if !IsJobFinished(existing) {
+				return existing, nil
+			}
+			if IsJobSucceeded(existing) {
+				log.Logger().Infof("Preview dependency %s already exists in namespace %s", util.ColorInfo(dep.Name), util.ColorInfo(previewNs))
+				return nil, nil
+			}
+			propagation := metav1.DeletePropagationBackground
+			err = jobs.Delete(jobName, &metav1.DeleteOptions{PropagationPolicy: &propagation})
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to delete failed Job %s in namespace %s", jobName, previewNs)
+			}
+		} else if !apierrors.IsNotFound(err) {
+			return nil, errors.Wrapf(err, "failed to get Job %s in namespace %s", jobName, previewNs)
+		}
+	} else {
+		size := dep.Size
+		if size == "" {
+			size = defaultSQLDumpSize
+		}
+		pvc, err := newPreviewDependencyClaim(previewNs, dep, size, labels)
+		if err != nil {
+			return nil, err
+		}
+		_, err = kubeClient.CoreV1().PersistentVolumeClaims(previewNs).Create(pvc)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create PersistentVolumeClaim %s in namespace %s", dep.Name, previewNs)
+		}
+	}
+
+	dump := dep.SQLDump
+	image := dump.Image
+	if image == "" {
+		image = DefaultPreviewSeedImage
+	}
+	fileName := path.Base(strings.TrimSuffix(dump.URL, "/"))
+	jobLabels := map[string]string{
+		LabelJobKind: ValueJobKindPreviewSeed,
+	}
+	for k, v := range labels {
+		jobLabels[k] = v
+	}
+	backoffLimit := int32(2)
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jobName,
+			Namespace: previewNs,
+			Labels:    jobLabels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: jobLabels,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy:      corev1.RestartPolicyNever,
+					ServiceAccountName: dump.ServiceAccount,
+					Containers: []corev1.Container{
+						{
+							Name:    "seed",
+							Image:   image,
+							Command: []string{"jx", "step", "unstash", "--url", dump.URL, "--output", path.Join(previewSeedMountDir, fileName), "--timeout", "30m"},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      previewSeedVolume,
+									MountPath: previewSeedMountDir,
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: previewSeedVolume,
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: dep.Name,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	answer, err := jobs.Create(job)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create Job %s in namespace %s", job.Name, previewNs)
+	}
+	log.Logger().Infof("Copying SQL dump %s into preview dependency %s", util.ColorInfo(dump.URL), util.ColorInfo(dep.Name))
+	return answer, nil
+}
+
+func newPreviewDependencyClaim(previewNs string, dep config.PreviewDependency, size string, labels map[string]string) (*corev1.PersistentVolumeClaim, error) {
+	quantity, err := resource.ParseQuantity(size)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid size %s of previewEnvironments dependency %s", size, dep.Name)
+	}
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      dep.Name,
+			Namespace: previewNs,
+			Labels:    labels,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: quantity,
+				},
+			},
+		},
+	}
+	if dep.StorageClass != "" {
+		storageClass := dep.StorageClass
+		pvc.Spec.StorageClassName = &storageClass
+	}
+	return pvc, nil
+}
@@ -0,0 +1,116 @@
+package kube_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kube_mocks "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestValidatePreviewDependencies(t *testing.T) {
+	t.Parallel()
+
+	snapshot := &config.PreviewVolumeSnapshot{Name: "orders-nightly"}
+	dump := &config.PreviewSQLDump{URL: "gs://my-bucket/dumps/orders.sql.gz"}
+
+	assert.NoError(t, kube.ValidatePreviewDependencies(nil))
+	assert.NoError(t, kube.ValidatePreviewDependencies([]config.PreviewDependency{
+		{Name: "orders-data", VolumeSnapshot: snapshot},
+		{Name: "users-data", SQLDump: dump, Size: "5Gi"},
+	}))
+
+	invalid := map[string][]config.PreviewDependency{
+		"no name":        {{SQLDump: dump}},
+		"invalid name":   {{Name: "Orders_Data", SQLDump: dump}},
+		"duplicate name": {{Name: "orders-data", SQLDump: dump}, {Name: "orders-data", VolumeSnapshot: snapshot}},
+		"no source":      {{Name: "orders-data"}},
+		"both sources":   {{Name: "orders-data", SQLDump: dump, VolumeSnapshot: snapshot}},
+		"no snapshot":    {{Name: "orders-data", VolumeSnapshot: &config.PreviewVolumeSnapshot{}}},
+		"no dump url":    {{Name: "orders-data", SQLDump: &config.PreviewSQLDump{}}},
+		"invalid size":   {{Name: "orders-data", SQLDump: dump, Size: "lots"}},
+	}
+	for name, deps := range invalid {
+		assert.Error(t, kube.ValidatePreviewDependencies(deps), name)
+	}
+}
+
+func TestSeedPreviewDependenciesFromSQLDump(t *testing.T) {
+	t.Parallel()
+
+	previewNs := "jx-myorg-myapp-pr-1"
+	deps := []config.PreviewDependency{
+		{
+			Name:         "orders-data",
+			StorageClass: "fast",
+			SQLDump: &config.PreviewSQLDump{
+				URL:            "gs://my-bucket/dumps/orders.sql.gz",
+				ServiceAccount: "seeder",
+			},
+		},
+	}
+	kubeClient := kube_mocks.NewSimpleClientset()
+
+	jobs, err := kube.SeedPreviewDependencies(kubeClient, "jx", "myorg-myapp-pr-1", previewNs, deps)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+
+	pvc, err := kubeClient.CoreV1().PersistentVolumeClaims(previewNs).Get("orders-data", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "myorg-myapp-pr-1", pvc.Labels[kube.LabelPreviewEnvironment])
+	assert.Equal(t, "fast", *pvc.Spec.StorageClassName)
+	storage := pvc.Spec.Resources.Requests["storage"]
+	assert.Equal(t, "1Gi", storage.String())
+
+	job := jobs[0]
+	assert.Equal(t, kube.ValueJobKindPreviewSeed, job.Labels[kube.LabelJobKind])
+	podSpec := job.Spec.Template.Spec
+	assert.Equal(t, "seeder", podSpec.ServiceAccountName)
+	require.Len(t, podSpec.Containers, 1)
+	assert.Equal(t, kube.DefaultPreviewSeedImage, podSpec.Containers[0].Image)
+	assert.Equal(t, []string{"jx", "step", "unstash", "--url", "gs://my-bucket/dumps/orders.sql.gz", "--output", "/seed/orders.sql.gz", "--timeout", "30m"}, podSpec.Containers[0].Command)
+	require.Len(t, podSpec.Volumes, 1)
+	assert.Equal(t, "orders-data", podSpec.Volumes[0].PersistentVolumeClaim.ClaimName)
+
+	// a running seed Job is waited for again
+	jobs, err = kube.SeedPreviewDependencies(kubeClient, "jx", "myorg-myapp-pr-1", previewNs, deps)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+
+	// a succeeded seed Job is not run again
+	now := metav1.Now()
+	job.Status.CompletionTime = &now
+	job.Status.Succeeded = 1
+	_, err = kubeClient.BatchV1().Jobs(previewNs).Update(job)
+	require.NoError(t, err)
+	jobs, err = kube.SeedPreviewDependencies(kubeClient, "jx", "myorg-myapp-pr-1", previewNs, deps)
+	require.NoError(t, err)
+	assert.Empty(t, jobs)
+}
+
+func TestSeedPreviewDependenciesRetriesFailedSQLDump(t *testing.T) {
+	t.Parallel()
+
+	previewNs := "jx-myorg-myapp-pr-2"
+	deps := []config.PreviewDependency{
+		{Name: "orders-data", SQLDump: &config.PreviewSQLDump{URL: "gs://my-bucket/dumps/orders.sql"}},
+	}
+	kubeClient := kube_mocks.NewSimpleClientset()
+
+	jobs, err := kube.SeedPreviewDependencies(kubeClient, "jx", "myorg-myapp-pr-2", previewNs, deps)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+
+	job := jobs[0]
+	job.Status.Failed = *job.Spec.BackoffLimit
+	_, err = kubeClient.BatchV1().Jobs(previewNs).Update(job)
+	require.NoError(t, err)
+
+	jobs, err = kube.SeedPreviewDependencies(kubeClient, "jx", "myorg-myapp-pr-2", previewNs, deps)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, int32(0), jobs[0].Status.Failed, "the failed Job should be created again")
+}
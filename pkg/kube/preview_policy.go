@@ -0,0 +1,207 @@
+package kube
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// PreviewPolicyFromConfig returns the lifecycle policy of a Preview Environment from the previewEnvironments
+// configuration of a project or nil if it has neither a TTL nor hibernation
+func PreviewPolicyFromConfig(c *config.PreviewEnvironmentConfig) *v1.PreviewPolicy {
+	if c == nil || (c.TTL == "" && c.HibernateAfter == "") {
+		return nil
+	}
+	return &v1.PreviewPolicy{
+		TTL:            c.TTL,
+		HibernateAfter: c.HibernateAfter,
+	}
+}
+
+// ValidatePreviewPolicy returns an error if the durations of the policy are invalid
+func ValidatePreviewPolicy(policy *v1.PreviewPolicy) error {
+	if policy == nil {
+		return nil
+	}
+	_, err := parsePreviewDuration("ttl", policy.TTL)
+	if err != nil {
+		return err
+	}
+	_, err = parsePreviewDuration("hibernateAfter", policy.HibernateAfter)
+	return err
+}
+
+func parsePreviewDuration(name string, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid previewEnvironments %s duration %s", name, value)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("invalid previewEnvironments %s duration %s as it must be positive", name, value)
+	}
+	return d, nil
+}
+
+// PreviewLastDeployed returns when the Preview Environment was last deployed, defaulting to when it was created
+func PreviewLastDeployed(env *v1.Environment) time.Time {
+	if env.Status.LastDeployed != nil {
+		return env.Status.LastDeployed.Time
+	}
+	return env.CreationTimestamp.Time
+}
+
+// PreviewLastActive returns when the Preview Environment was last deployed, woken or seen serving traffic
+func PreviewLastActive(env *v1.Environment) time.Time {
+	answer := PreviewLastDeployed(env)
+	if env.Status.LastActive != nil && env.Status.LastActive.Time.After(answer) {
+		answer = env.Status.LastActive.Time
+	}
+	return answer
+}
+
+// IsPreviewHibernated returns true if the Deployments of the Preview Environment have been scaled to zero
+func IsPreviewHibernated(env *v1.Environment) bool {
+	return env.Status.Hibernated != nil
+}
+
+// IsPreviewExpired returns true if the Preview Environment has a TTL which has passed since it was last deployed
+func IsPreviewExpired(env *v1.Environment, now time.Time) (bool, error) {
+	policy := env.Spec.PreviewPolicy
+	if policy == nil {
+		return false, nil
+	}
+	ttl, err := parsePreviewDuration("ttl", policy.TTL)
+	if err != nil || ttl == 0 {
+		return false, err
+	}
+	return now.Sub(PreviewLastDeployed(env)) > ttl, nil
+}
+
+// IsPreviewIdle returns true if the Preview Environment is not hibernated and has been inactive for longer than
+// its policy allows
+func IsPreviewIdle(env *v1.Environment, now time.Time) (bool, error) {
+	policy := env.Spec.PreviewPolicy
+	if policy == nil || IsPreviewHibernated(env) {
+		return false, nil
+	}
+	idle, err := parsePreviewDuration("hibernateAfter", policy.HibernateAfter)
+	if err != nil || idle == 0 {
+		return false, err
+	}
+	return now.Sub(PreviewLastActive(env)) > idle, nil
+}
+
+// HibernatePreview scales the Deployments in the namespace of the Preview Environment to zero, recording their
+// replicas on the Environment before scaling them so that they are restored when it wakes, even if scaling fails
+func HibernatePreview(kubeClient kubernetes.Interface, jxClient versioned.Interface, ns string, env *v1.Environment) (*v1.Environment, error) {
+	previewNs := env.Spec.Namespace
+	deployments, err := kubeClient.AppsV1().Deployments(previewNs).List(metav1.ListOptions{})
+	if err != nil {
+		return env, errors.Wrapf(err, "failed to list Deployments in namespace %s", previewNs)
+	}
+	// keep the replicas recorded by an earlier attempt which failed part way as those Deployments are already scaled
+	// to zero
+	replicas := map[string]int32{}
+	for name, count := range env.Status.HibernatedReplicas {
+		replicas[name] = count
+	}
+	var scaled []*appsv1.Deployment
+	for i := range deployments.Items {
+		d := &deployments.Items[i]
+		if d.Spec.Replicas != nil && *d.Spec.Replicas == 0 {
+			continue
+		}
+		count := int32(1)
+		if d.Spec.Replicas != nil {
+			count = *d.Spec.Replicas
+		}
+		replicas[d.Name] = count
+		scaled = append(scaled, d)
+	}
+	env.Status.HibernatedReplicas = replicas
+	updated, err := jxClient.JenkinsV1().Environments(ns).PatchUpdate(env)
+	if err != nil {
+		return env, errors.Wrapf(err, "failed to update Environment %s", env.Name)
+	}
+	env = updated
+	for _, d := range scaled {
+		zero := int32(0)
+		d.Spec.Replicas = &zero
+		_, err = kubeClient.AppsV1().Deployments(previewNs).Update(d)
+		if err != nil {
+			return env, errors.Wrapf(err, "failed to scale Deployment %s in namespace %s to zero", d.Name, previewNs)
+		}
+	}
+	now := metav1.Now()
+	env.Status.Hibernated = &now
+	answer, err := jxClient.JenkinsV1().Environments(ns).PatchUpdate(env)
+	if err != nil {
+		return env, errors.Wrapf(err, "failed to update Environment %s", env.Name)
+	}
+	return answer, nil
+}
+
+// WakePreview restores the replicas of the Deployments of a hibernated Preview Environment and marks it as active
+// so that it is not hibernated again until it is next idle
+func WakePreview(kubeClient kubernetes.Interface, jxClient versioned.Interface, ns string, env *v1.Environment) (*v1.Environment, error) {
+	previewNs := env.Spec.Namespace
+	for name, count := range env.Status.HibernatedReplicas {
+		d, err := kubeClient.AppsV1().Deployments(previewNs).Get(name, metav1.GetOptions{})
+		if err != nil {
+			log.Logger().Warnf("Failed to find Deployment %s in namespace %s to wake: %s", name, previewNs, err)
+			continue
+		}
+		replicas := count
+		d.Spec.Replicas = &replicas
+		_, err = kubeClient.AppsV1().Deployments(previewNs).Update(d)
+		if err != nil {
+			return env, errors.Wrapf(err, "failed to scale Deployment %s in namespace %s to %d", name, previewNs, count)
+		}
+	}
+	now := metav1.Now()
+	env.Status.Hibernated = nil
+	env.Status.HibernatedReplicas = nil
+	env.Status.LastActive = &now
+	answer, err := jxClient.JenkinsV1().Environments(ns).PatchUpdate(env)
+	if err != nil {
+		return env, errors.Wrapf(err, "failed to update Environment %s", env.Name)
+	}
+	return answer, nil
+}
+
+// FindPreviewForPullRequest returns the Preview Environment of the Pull Request of the given repository or nil if
+// there is none
+func FindPreviewForPullRequest(jxClient versioned.Interface, ns string, owner string, repository string, pullRequest string) (*v1.Environment, error) {
+	envs, err := jxClient.JenkinsV1().Environments(ns).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list Environments in namespace %s", ns)
+	}
+	prName := strings.TrimPrefix(pullRequest, "PR-")
+	for i := range envs.Items {
+		env := &envs.Items[i]
+		if !IsPreviewEnvironment(env) || env.Spec.PreviewGitSpec.Name != prName {
+			continue
+		}
+		gitInfo, err := gits.ParseGitURL(env.Spec.Source.URL)
+		if err != nil {
+			continue
+		}
+		if strings.EqualFold(gitInfo.Organisation, owner) && strings.EqualFold(gitInfo.Name, repository) {
+			return env, nil
+		}
+	}
+	return nil, nil
+}
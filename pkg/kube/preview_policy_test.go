@@ -0,0 +1,179 @@
+package kube_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	jxfake "github.com/jenkins-x/jx/pkg/client/clientset/versioned/fake"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kube_mocks "k8s.io/client-go/kubernetes/fake"
+	k8sTesting "k8s.io/client-go/testing"
+)
+
+func TestPreviewPolicyFromConfig(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, kube.PreviewPolicyFromConfig(nil))
+	assert.Nil(t, kube.PreviewPolicyFromConfig(&config.PreviewEnvironmentConfig{MaximumInstances: 3}))
+
+	policy := kube.PreviewPolicyFromConfig(&config.PreviewEnvironmentConfig{TTL: "72h", HibernateAfter: "8h"})
+	require.NotNil(t, policy)
+	assert.Equal(t, "72h", policy.TTL)
+	assert.Equal(t, "8h", policy.HibernateAfter)
+
+	assert.NoError(t, kube.ValidatePreviewPolicy(policy))
+	assert.Error(t, kube.ValidatePreviewPolicy(&v1.PreviewPolicy{TTL: "3 days"}))
+	assert.Error(t, kube.ValidatePreviewPolicy(&v1.PreviewPolicy{HibernateAfter: "-1h"}))
+}
+
+func TestPreviewExpiredAndIdle(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	deployed := metav1.NewTime(now.Add(-10 * time.Hour))
+	env := kube.NewPreviewEnvironment("pr-1")
+	env.CreationTimestamp = metav1.NewTime(now.Add(-100 * time.Hour))
+	env.Spec.PreviewPolicy = &v1.PreviewPolicy{TTL: "72h", HibernateAfter: "8h"}
+
+	expired, err := kube.IsPreviewExpired(env, now)
+	require.NoError(t, err)
+	assert.True(t, expired, "should expire without a deployment since it was created")
+
+	env.Status.LastDeployed = &deployed
+	expired, err = kube.IsPreviewExpired(env, now)
+	require.NoError(t, err)
+	assert.False(t, expired, "should not expire as recently deployed")
+
+	idle, err := kube.IsPreviewIdle(env, now)
+	require.NoError(t, err)
+	assert.True(t, idle)
+
+	active := metav1.NewTime(now.Add(-time.Hour))
+	env.Status.LastActive = &active
+	idle, err = kube.IsPreviewIdle(env, now)
+	require.NoError(t, err)
+	assert.False(t, idle, "should not be idle as recently active")
+
+	env.Status.LastActive = nil
+	env.Status.Hibernated = &active
+	idle, err = kube.IsPreviewIdle(env, now)
+	require.NoError(t, err)
+	assert.False(t, idle, "should not be idle as already hibernated")
+
+	env.Spec.PreviewPolicy = nil
+	expired, err = kube.IsPreviewExpired(env, now)
+	require.NoError(t, err)
+	assert.False(t, expired, "should not expire without a policy")
+}
+
+func TestHibernateAndWakePreview(t *testing.T) {
+	t.Parallel()
+
+	env := kube.NewPreviewEnvironment("pr-1")
+	env.Spec.PreviewPolicy = &v1.PreviewPolicy{HibernateAfter: "8h"}
+	previewNs := env.Spec.Namespace
+	deployment := func(name string, replicas int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: previewNs},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		}
+	}
+	kubeClient := kube_mocks.NewSimpleClientset(deployment("app", 2), deployment("db", 1), deployment("stopped", 0))
+	jxClient := jxfake.NewSimpleClientset(env)
+
+	env, err := kube.HibernatePreview(kubeClient, jxClient, "jx", env)
+	require.NoError(t, err)
+	assert.True(t, kube.IsPreviewHibernated(env))
+	assert.Equal(t, map[string]int32{"app": 2, "db": 1}, env.Status.HibernatedReplicas)
+	for _, name := range []string{"app", "db", "stopped"} {
+		d, err := kubeClient.AppsV1().Deployments(previewNs).Get(name, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, int32(0), *d.Spec.Replicas, "replicas of %s", name)
+	}
+
+	env, err = kube.WakePreview(kubeClient, jxClient, "jx", env)
+	require.NoError(t, err)
+	assert.False(t, kube.IsPreviewHibernated(env))
+	assert.NotNil(t, env.Status.LastActive)
+	expected := map[string]int32{"app": 2, "db": 1, "stopped": 0}
+	for name, replicas := range expected {
+		d, err := kubeClient.AppsV1().Deployments(previewNs).Get(name, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, replicas, *d.Spec.Replicas, "replicas of %s", name)
+	}
+}
+
+func TestHibernatePreviewRecordsReplicasBeforeScaling(t *testing.T) {
+	t.Parallel()
+
+	env := kube.NewPreviewEnvironment("pr-2")
+	env.Spec.PreviewPolicy = &v1.PreviewPolicy{HibernateAfter: "8h"}
+	previewNs := env.Spec.Namespace
+	deployment := func(name string, replicas int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: previewNs},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		}
+	}
+	kubeClient := kube_mocks.NewSimpleClientset(deployment("app", 2), deployment("db", 3))
+	jxClient := jxfake.NewSimpleClientset(env)
+
+	failed := false
+	kubeClient.PrependReactor("update", "deployments", func(action k8sTesting.Action) (handled bool, ret runtime.Object, err error) {
+		d := action.(k8sTesting.UpdateAction).GetObject().(*appsv1.Deployment)
+		if d.Name == "db" && !failed {
+			failed = true
+			return true, nil, errors.New("KABOOM")
+		}
+		return false, nil, nil
+	})
+
+	_, err := kube.HibernatePreview(kubeClient, jxClient, "jx", env)
+	require.Error(t, err)
+	saved, err := jxClient.JenkinsV1().Environments("jx").Get(env.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int32{"app": 2, "db": 3}, saved.Status.HibernatedReplicas)
+
+	// retrying keeps the replicas of the Deployments which were scaled to zero by the failed attempt
+	saved, err = kube.HibernatePreview(kubeClient, jxClient, "jx", saved)
+	require.NoError(t, err)
+	assert.True(t, kube.IsPreviewHibernated(saved))
+	assert.Equal(t, map[string]int32{"app": 2, "db": 3}, saved.Status.HibernatedReplicas)
+
+	_, err = kube.WakePreview(kubeClient, jxClient, "jx", saved)
+	require.NoError(t, err)
+	for name, replicas := range map[string]int32{"app": 2, "db": 3} {
+		d, err := kubeClient.AppsV1().Deployments(previewNs).Get(name, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, replicas, *d.Spec.Replicas, "replicas of %s", name)
+	}
+}
+
+func TestFindPreviewForPullRequest(t *testing.T) {
+	t.Parallel()
+
+	env := kube.NewPreviewEnvironment("myorg-myrepo-pr-23")
+	env.Spec.Source.URL = "https://github.com/myorg/myrepo.git"
+	env.Spec.PreviewGitSpec.Name = "23"
+	other := kube.NewPreviewEnvironment("myorg-other-pr-23")
+	other.Spec.Source.URL = "https://github.com/myorg/other.git"
+	other.Spec.PreviewGitSpec.Name = "23"
+	jxClient := jxfake.NewSimpleClientset(env, other)
+
+	found, err := kube.FindPreviewForPullRequest(jxClient, "jx", "myorg", "myrepo", "PR-23")
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, env.Name, found.Name)
+
+	found, err = kube.FindPreviewForPullRequest(jxClient, "jx", "myorg", "myrepo", "24")
+	require.NoError(t, err)
+	assert.Nil(t, found)
+}
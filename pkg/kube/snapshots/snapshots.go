@@ -0,0 +1,247 @@
+package snapshots
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// Group is the API group of CSI volume snapshots
+	Group = "snapshot.storage.k8s.io"
+	// APIVersion is the API version of CSI volume snapshots
+	APIVersion = Group + "/v1beta1"
+	// APIPath is the path of the CSI volume snapshot API
+	APIPath = "/apis/" + APIVersion
+
+	// KindVolumeSnapshot is the kind of a VolumeSnapshot
+	KindVolumeSnapshot = "VolumeSnapshot"
+	// KindVolumeSnapshotContent is the kind of a VolumeSnapshotContent
+	KindVolumeSnapshotContent = "VolumeSnapshotContent"
+
+	// DeletionPolicyRetain keeps the snapshot in the storage when its VolumeSnapshotContent is deleted
+	DeletionPolicyRetain = "Retain"
+)
+
+// VolumeSnapshot is the subset of the CSI VolumeSnapshot resource used to clone volumes
+type VolumeSnapshot struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VolumeSnapshotSpec    `json:"spec"`
+	Status *VolumeSnapshotStatus `json:"status,omitempty"`
+}
+
+// VolumeSnapshotSpec is the specification of a VolumeSnapshot
+type VolumeSnapshotSpec struct {
+	Source                  VolumeSnapshotSource `json:"source"`
+	VolumeSnapshotClassName string               `json:"volumeSnapshotClassName,omitempty"`
+}
+
+// VolumeSnapshotSource is either the PersistentVolumeClaim to snapshot or the existing VolumeSnapshotContent
+type VolumeSnapshotSource struct {
+	PersistentVolumeClaimName string `json:"persistentVolumeClaimName,omitempty"`
+	VolumeSnapshotContentName string `json:"volumeSnapshotContentName,omitempty"`
+}
+
+// VolumeSnapshotStatus is the status of a VolumeSnapshot
+type VolumeSnapshotStatus struct {
+	BoundVolumeSnapshotContentName string `json:"boundVolumeSnapshotContentName,omitempty"`
+	ReadyToUse                     *bool  `json:"readyToUse,omitempty"`
+	RestoreSize                    string `json:"restoreSize,omitempty"`
+}
+
+// VolumeSnapshotContent is the subset of the CSI VolumeSnapshotContent resource used to clone volumes
+type VolumeSnapshotContent struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VolumeSnapshotContentSpec    `json:"spec"`
+	Status *VolumeSnapshotContentStatus `json:"status,omitempty"`
+}
+
+// VolumeSnapshotContentList is a list of VolumeSnapshotContent resources
+type VolumeSnapshotContentList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []VolumeSnapshotContent `json:"items"`
+}
+
+// VolumeSnapshotContentSpec is the specification of a VolumeSnapshotContent
+type VolumeSnapshotContentSpec struct {
+	VolumeSnapshotRef       VolumeSnapshotReference     `json:"volumeSnapshotRef"`
+	DeletionPolicy          string                      `json:"deletionPolicy"`
+	Driver                  string                      `json:"driver"`
+	VolumeSnapshotClassName string                      `json:"volumeSnapshotClassName,omitempty"`
+	Source                  VolumeSnapshotContentSource `json:"source"`
+}
+
+// VolumeSnapshotReference refers to the VolumeSnapshot bound to a VolumeSnapshotContent
+type VolumeSnapshotReference struct {
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind,omitempty"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace"`
+}
+
+// VolumeSnapshotContentSource is either the volume to snapshot or the handle of an existing snapshot in the storage
+type VolumeSnapshotContentSource struct {
+	VolumeHandle   string `json:"volumeHandle,omitempty"`
+	SnapshotHandle string `json:"snapshotHandle,omitempty"`
+}
+
+// VolumeSnapshotContentStatus is the status of a VolumeSnapshotContent
+type VolumeSnapshotContentStatus struct {
+	SnapshotHandle string `json:"snapshotHandle,omitempty"`
+	ReadyToUse     *bool  `json:"readyToUse,omitempty"`
+	RestoreSize    *int64 `json:"restoreSize,omitempty"`
+}
+
+// SnapshotHandle returns the handle of the snapshot in the storage
+func (c *VolumeSnapshotContent) SnapshotHandle() string {
+	if c.Status != nil && c.Status.SnapshotHandle != "" {
+		return c.Status.SnapshotHandle
+	}
+	return c.Spec.Source.SnapshotHandle
+}
+
+// NewCloneContent returns a pre-provisioned VolumeSnapshotContent which refers to the same snapshot in the storage as
+// the given VolumeSnapshotContent and is bound to a new VolumeSnapshot in another namespace. It retains the snapshot
+// when deleted so that the original VolumeSnapshot is not affected
+func NewCloneContent(name string, source *VolumeSnapshotContent, ns string, snapshotName string, labels map[string]string) (*VolumeSnapshotContent, error) {
+	handle := source.SnapshotHandle()
+	if handle == "" {
+		return nil, fmt.Errorf("VolumeSnapshotContent %s has no snapshot handle yet", source.Name)
+	}
+	return &VolumeSnapshotContent{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: APIVersion,
+			Kind:       KindVolumeSnapshotContent,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+		Spec: VolumeSnapshotContentSpec{
+			VolumeSnapshotRef: VolumeSnapshotReference{
+				APIVersion: APIVersion,
+				Kind:       KindVolumeSnapshot,
+				Name:       snapshotName,
+				Namespace:  ns,
+			},
+			DeletionPolicy:          DeletionPolicyRetain,
+			Driver:                  source.Spec.Driver,
+			VolumeSnapshotClassName: source.Spec.VolumeSnapshotClassName,
+			Source: VolumeSnapshotContentSource{
+				SnapshotHandle: handle,
+			},
+		},
+	}, nil
+}
+
+// GetVolumeSnapshot returns the VolumeSnapshot of the given name in the namespace
+func GetVolumeSnapshot(kubeClient kubernetes.Interface, ns string, name string) (*VolumeSnapshot, error) {
+	answer := &VolumeSnapshot{}
+	err := getResource(kubeClient, fmt.Sprintf("%s/namespaces/%s/volumesnapshots/%s", APIPath, ns, name), answer)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get VolumeSnapshot %s in namespace %s", name, ns)
+	}
+	return answer, nil
+}
+
+// CreateVolumeSnapshot creates the VolumeSnapshot
+func CreateVolumeSnapshot(kubeClient kubernetes.Interface, snapshot *VolumeSnapshot) error {
+	err := createResource(kubeClient, fmt.Sprintf("%s/namespaces/%s/volumesnapshots", APIPath, snapshot.Namespace), snapshot)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create VolumeSnapshot %s in namespace %s", snapshot.Name, snapshot.Namespace)
+	}
+	return nil
+}
+
+// GetVolumeSnapshotContent returns the VolumeSnapshotContent of the given name
+func GetVolumeSnapshotContent(kubeClient kubernetes.Interface, name string) (*VolumeSnapshotContent, error) {
+	answer := &VolumeSnapshotContent{}
+	err := getResource(kubeClient, fmt.Sprintf("%s/volumesnapshotcontents/%s", APIPath, name), answer)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get VolumeSnapshotContent %s", name)
+	}
+	return answer, nil
+}
+
+// CreateVolumeSnapshotContent creates the VolumeSnapshotContent
+func CreateVolumeSnapshotContent(kubeClient kubernetes.Interface, content *VolumeSnapshotContent) error {
+	err := createResource(kubeClient, fmt.Sprintf("%s/volumesnapshotcontents", APIPath), content)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create VolumeSnapshotContent %s", content.Name)
+	}
+	return nil
+}
+
+// ListVolumeSnapshotContents returns the VolumeSnapshotContents matching the label selector
+func ListVolumeSnapshotContents(kubeClient kubernetes.Interface, selector string) ([]VolumeSnapshotContent, error) {
+	restClient, err := getRESTClient(kubeClient)
+	if err != nil {
+		return nil, err
+	}
+	data, err := restClient.Get().AbsPath(fmt.Sprintf("%s/volumesnapshotcontents", APIPath)).Param("labelSelector", selector).DoRaw()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list VolumeSnapshotContents matching %s", selector)
+	}
+	list := &VolumeSnapshotContentList{}
+	err = json.Unmarshal(data, list)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal VolumeSnapshotContents")
+	}
+	return list.Items, nil
+}
+
+// DeleteVolumeSnapshotContent deletes the VolumeSnapshotContent of the given name
+func DeleteVolumeSnapshotContent(kubeClient kubernetes.Interface, name string) error {
+	restClient, err := getRESTClient(kubeClient)
+	if err != nil {
+		return err
+	}
+	_, err = restClient.Delete().AbsPath(fmt.Sprintf("%s/volumesnapshotcontents/%s", APIPath, name)).DoRaw()
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete VolumeSnapshotContent %s", name)
+	}
+	return nil
+}
+
+func getRESTClient(kubeClient kubernetes.Interface) (rest.Interface, error) {
+	restClient := kubeClient.Discovery().RESTClient()
+	if restClient == nil {
+		return nil, fmt.Errorf("no REST client available to access %s", APIPath)
+	}
+	return restClient, nil
+}
+
+func getResource(kubeClient kubernetes.Interface, path string, result interface{}) error {
+	restClient, err := getRESTClient(kubeClient)
+	if err != nil {
+		return err
+	}
+	data, err := restClient.Get().AbsPath(path).DoRaw()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, result)
+}
+
+func createResource(kubeClient kubernetes.Interface, path string, resource interface{}) error {
+	restClient, err := getRESTClient(kubeClient)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return err
+	}
+	_, err = restClient.Post().AbsPath(path).Body(data).DoRaw()
+	return err
+}
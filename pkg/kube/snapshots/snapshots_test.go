@@ -0,0 +1,45 @@
+package snapshots_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/kube/snapshots"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewCloneContent(t *testing.T) {
+	t.Parallel()
+
+	source := &snapshots.VolumeSnapshotContent{
+		ObjectMeta: metav1.ObjectMeta{Name: "snapcontent-1234"},
+		Spec: snapshots.VolumeSnapshotContentSpec{
+			DeletionPolicy:          "Delete",
+			Driver:                  "pd.csi.storage.gke.io",
+			VolumeSnapshotClassName: "csi-gce-pd",
+			Source:                  snapshots.VolumeSnapshotContentSource{VolumeHandle: "projects/p/zones/z/disks/orders"},
+		},
+		Status: &snapshots.VolumeSnapshotContentStatus{
+			SnapshotHandle: "projects/p/global/snapshots/orders-nightly",
+		},
+	}
+	labels := map[string]string{"jenkins.io/preview": "myorg-myapp-pr-1"}
+
+	content, err := snapshots.NewCloneContent("jx-myorg-myapp-pr-1-orders-data", source, "jx-myorg-myapp-pr-1", "orders-data", labels)
+	require.NoError(t, err)
+	assert.Equal(t, "jx-myorg-myapp-pr-1-orders-data", content.Name)
+	assert.Equal(t, labels, content.Labels)
+	assert.Equal(t, snapshots.KindVolumeSnapshotContent, content.Kind)
+	assert.Equal(t, snapshots.DeletionPolicyRetain, content.Spec.DeletionPolicy, "the original snapshot must be retained")
+	assert.Equal(t, "pd.csi.storage.gke.io", content.Spec.Driver)
+	assert.Equal(t, "csi-gce-pd", content.Spec.VolumeSnapshotClassName)
+	assert.Equal(t, "projects/p/global/snapshots/orders-nightly", content.Spec.Source.SnapshotHandle)
+	assert.Empty(t, content.Spec.Source.VolumeHandle)
+	assert.Equal(t, "orders-data", content.Spec.VolumeSnapshotRef.Name)
+	assert.Equal(t, "jx-myorg-myapp-pr-1", content.Spec.VolumeSnapshotRef.Namespace)
+
+	source.Status = nil
+	_, err = snapshots.NewCloneContent("jx-myorg-myapp-pr-1-orders-data", source, "jx-myorg-myapp-pr-1", "orders-data", labels)
+	assert.Error(t, err, "should fail without a snapshot handle")
+}
@@ -0,0 +1,147 @@
+package notify
+
+import (
+	"strconv"
+	"sync"
+
+	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+)
+
+// Detector works out which notification events are caused by changes to PipelineActivity resources.
+//
+// It remembers the last completed build of each pipeline so that a successful build after a failure can be
+// reported as a recovery
+type Detector struct {
+	lock       sync.Mutex
+	lastBuilds map[string]completedBuild
+}
+
+type completedBuild struct {
+	build  int
+	failed bool
+}
+
+// NewDetector creates a new event detector
+func NewDetector() *Detector {
+	return &Detector{
+		lastBuilds: map[string]completedBuild{},
+	}
+}
+
+// Baseline records the state of an activity which already existed without generating any events
+func (d *Detector) Baseline(activity *v1.PipelineActivity) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.recordCompleted(activity)
+}
+
+// Events returns the events caused by an activity changing from the old to the new state
+func (d *Detector) Events(oldActivity *v1.PipelineActivity, newActivity *v1.PipelineActivity) []*Event {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	activity := newActivity.DeepCopy()
+	events := []*Event{}
+
+	oldStatus := oldActivity.Spec.Status
+	newStatus := activity.Spec.Status
+	if newStatus.IsTerminated() && !oldStatus.IsTerminated() {
+		if isFailed(newStatus) {
+			events = append(events, &Event{
+				Kind:     v1.NotificationEventTypeFailed,
+				Activity: activity,
+			})
+		} else if newStatus == v1.ActivityStatusTypeSucceeded {
+			last, ok := d.lastBuilds[activity.Spec.Pipeline]
+			if ok && last.failed && last.build < buildNumber(activity) {
+				events = append(events, &Event{
+					Kind:     v1.NotificationEventTypeRecovered,
+					Activity: activity,
+				})
+			}
+		}
+	}
+	d.recordCompleted(activity)
+
+	oldSteps := stepStatuses(oldActivity)
+	for _, step := range activity.Spec.Steps {
+		key, status := stepStatus(&step)
+		if key == "" || oldSteps[key] == status {
+			continue
+		}
+		if status == v1.ActivityStatusTypeWaitingForApproval {
+			event := &Event{
+				Kind:     v1.NotificationEventTypeWaitingForApproval,
+				Activity: activity,
+				Step:     key,
+			}
+			if step.Promote != nil {
+				event.Environment = step.Promote.Environment
+				if step.Promote.PullRequest != nil {
+					event.URL = step.Promote.PullRequest.PullRequestURL
+				}
+			}
+			events = append(events, event)
+		} else if status == v1.ActivityStatusTypeSucceeded && step.Promote != nil {
+			events = append(events, &Event{
+				Kind:        v1.NotificationEventTypePromoted,
+				Activity:    activity,
+				Step:        key,
+				Environment: step.Promote.Environment,
+				URL:         step.Promote.ApplicationURL,
+			})
+		}
+	}
+	return events
+}
+
+// recordCompleted remembers the result of the activity if it is the latest completed build of its pipeline
+func (d *Detector) recordCompleted(activity *v1.PipelineActivity) {
+	status := activity.Spec.Status
+	if !status.IsTerminated() || status == v1.ActivityStatusTypeAborted {
+		return
+	}
+	build := buildNumber(activity)
+	last, ok := d.lastBuilds[activity.Spec.Pipeline]
+	if ok && last.build > build {
+		return
+	}
+	d.lastBuilds[activity.Spec.Pipeline] = completedBuild{
+		build:  build,
+		failed: isFailed(status),
+	}
+}
+
+func stepStatuses(activity *v1.PipelineActivity) map[string]v1.ActivityStatusType {
+	answer := map[string]v1.ActivityStatusType{}
+	for _, step := range activity.Spec.Steps {
+		key, status := stepStatus(&step)
+		if key != "" {
+			answer[key] = status
+		}
+	}
+	return answer
+}
+
+// stepStatus returns a key identifying the step and its status
+func stepStatus(step *v1.PipelineActivityStep) (string, v1.ActivityStatusType) {
+	switch {
+	case step.Stage != nil:
+		return "stage:" + step.Stage.Name, step.Stage.Status
+	case step.Promote != nil:
+		return "promote:" + step.Promote.Environment, step.Promote.Status
+	case step.Preview != nil:
+		return "preview:" + step.Preview.Environment, step.Preview.Status
+	}
+	return "", v1.ActivityStatusTypeNone
+}
+
+func isFailed(status v1.ActivityStatusType) bool {
+	return status == v1.ActivityStatusTypeFailed || status == v1.ActivityStatusTypeError
+}
+
+func buildNumber(activity *v1.PipelineActivity) int {
+	n, _ := strconv.Atoi(activity.Spec.Build)
+	return n
+}
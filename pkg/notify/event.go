@@ -0,0 +1,143 @@
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"text/template"
+
+	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/chats"
+	"github.com/pkg/errors"
+)
+
+// DefaultTemplates are the go templates used to render the text of each kind of event unless a channel overrides them
+var DefaultTemplates = map[v1.NotificationEventType]string{
+	v1.NotificationEventTypeFailed:             `Pipeline {{ .Pipeline }} #{{ .Build }} failed`,
+	v1.NotificationEventTypeRecovered:          `Pipeline {{ .Pipeline }} #{{ .Build }} is back to normal`,
+	v1.NotificationEventTypePromoted:           `{{ .Repository }} version {{ .Version }} has been promoted to {{ .Environment }}`,
+	v1.NotificationEventTypeWaitingForApproval: `Pipeline {{ .Pipeline }} #{{ .Build }} is waiting for approval{{ if .Environment }} to promote to {{ .Environment }}{{ end }}`,
+}
+
+// Event is a pipeline event which notifications are sent for
+type Event struct {
+	Kind        v1.NotificationEventType
+	Activity    *v1.PipelineActivity
+	Environment string
+	Step        string
+	URL         string
+	Release     *v1.Release
+}
+
+// Pipeline returns the owner/repository/branch name of the pipeline
+func (e *Event) Pipeline() string {
+	return e.Activity.Spec.Pipeline
+}
+
+// Owner returns the git owner of the pipeline
+func (e *Event) Owner() string {
+	return e.Activity.RepositoryOwner()
+}
+
+// Repository returns the git repository name of the pipeline
+func (e *Event) Repository() string {
+	return e.Activity.RepositoryName()
+}
+
+// Branch returns the branch name of the pipeline
+func (e *Event) Branch() string {
+	return e.Activity.BranchName()
+}
+
+// Build returns the build number of the pipeline
+func (e *Event) Build() string {
+	return e.Activity.Spec.Build
+}
+
+// BuildNumber returns the build number of the pipeline or 0 if it is not numeric
+func (e *Event) BuildNumber() int {
+	n, _ := strconv.Atoi(e.Activity.Spec.Build)
+	return n
+}
+
+// Version returns the version being built or promoted
+func (e *Event) Version() string {
+	if e.Release != nil && e.Release.Spec.Version != "" {
+		return e.Release.Spec.Version
+	}
+	return e.Activity.Spec.Version
+}
+
+// BuildURL returns the URL of the build
+func (e *Event) BuildURL() string {
+	return e.Activity.Spec.BuildURL
+}
+
+// Author returns the author of the last commit
+func (e *Event) Author() string {
+	return e.Activity.Spec.Author
+}
+
+// ReleaseNotesURL returns the URL of the release notes if there are any
+func (e *Event) ReleaseNotesURL() string {
+	if e.Release != nil && e.Release.Spec.ReleaseNotesURL != "" {
+		return e.Release.Spec.ReleaseNotesURL
+	}
+	return e.Activity.Spec.ReleaseNotesURL
+}
+
+// LinkURL returns the most relevant URL for the event
+func (e *Event) LinkURL() string {
+	if e.URL != "" {
+		return e.URL
+	}
+	return e.BuildURL()
+}
+
+// Render renders the text of the event using the given go template or the default template of the event kind
+func (e *Event) Render(text string) (string, error) {
+	if text == "" {
+		text = DefaultTemplates[e.Kind]
+	}
+	if text == "" {
+		return "", fmt.Errorf("no template for notification event %s", e.Kind)
+	}
+	tmpl, err := template.New(string(e.Kind)).Parse(text)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse notification template %s", text)
+	}
+	var buffer bytes.Buffer
+	err = tmpl.Execute(&buffer, e)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to render notification template %s", text)
+	}
+	return buffer.String(), nil
+}
+
+// Status returns the chat message status for the event
+func (e *Event) Status() chats.MessageStatus {
+	switch e.Kind {
+	case v1.NotificationEventTypeFailed:
+		return chats.MessageStatusFailure
+	case v1.NotificationEventTypeWaitingForApproval:
+		return chats.MessageStatusPending
+	default:
+		return chats.MessageStatusSuccess
+	}
+}
+
+// ToMessage converts the event into a chat message with the given rendered title
+func (e *Event) ToMessage(title string) *chats.Message {
+	m := &chats.Message{
+		Title:    title,
+		TitleURL: e.LinkURL(),
+		Status:   e.Status(),
+	}
+	return m.AddField("Repository", e.Owner()+"/"+e.Repository()).
+		AddField("Branch", e.Branch()).
+		AddField("Build", e.Build()).
+		AddField("Version", e.Version()).
+		AddField("Environment", e.Environment).
+		AddField("Author", e.Author()).
+		AddField("Release Notes", e.ReleaseNotesURL())
+}
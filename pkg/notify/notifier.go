@@ -0,0 +1,177 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/chats"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+)
+
+// SendMailFn sends an email; it defaults to smtp.SendMail and is replaceable for testing
+type SendMailFn func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+
+// Notifier sends notifications for events to the channels of the matching rules
+type Notifier struct {
+	// ChatProvider is used for chat channels; chat channels are skipped if it is nil
+	ChatProvider chats.ChatProvider
+	// SMTPServer is the host:port of the SMTP server used for email channels
+	SMTPServer string
+	// From is the sender address of emails
+	From string
+	// HTTPClient is used for webhook channels
+	HTTPClient *http.Client
+	// SendMail sends emails
+	SendMail SendMailFn
+}
+
+// WebhookPayload is the JSON body posted to webhook channels
+type WebhookPayload struct {
+	Kind            v1.NotificationEventType `json:"kind"`
+	Message         string                   `json:"message"`
+	Pipeline        string                   `json:"pipeline"`
+	Build           string                   `json:"build"`
+	Owner           string                   `json:"owner"`
+	Repository      string                   `json:"repository"`
+	Branch          string                   `json:"branch"`
+	Version         string                   `json:"version,omitempty"`
+	Environment     string                   `json:"environment,omitempty"`
+	Status          v1.ActivityStatusType    `json:"status"`
+	URL             string                   `json:"url,omitempty"`
+	BuildURL        string                   `json:"buildUrl,omitempty"`
+	ReleaseNotesURL string                   `json:"releaseNotesUrl,omitempty"`
+}
+
+// NewNotifier creates a new notifier
+func NewNotifier(chatProvider chats.ChatProvider, smtpServer string, from string) *Notifier {
+	return &Notifier{
+		ChatProvider: chatProvider,
+		SMTPServer:   smtpServer,
+		From:         from,
+		HTTPClient:   &http.Client{Timeout: 30 * time.Second},
+		SendMail:     smtp.SendMail,
+	}
+}
+
+// Notify sends the event to the channels of all the rules which match it
+func (n *Notifier) Notify(rules []v1.NotificationRule, event *Event) error {
+	errs := []error{}
+	for _, rule := range MatchingRules(rules, event) {
+		for _, channel := range rule.Channels {
+			err := n.NotifyChannel(&channel, event)
+			if err != nil {
+				errs = append(errs, errors.Wrapf(err, "notification rule %s", rule.Name))
+			}
+		}
+	}
+	return util.CombineErrors(errs...)
+}
+
+// NotifyChannel sends the event to a single channel
+func (n *Notifier) NotifyChannel(channel *v1.NotificationChannel, event *Event) error {
+	text, err := event.Render(channel.Template)
+	if err != nil {
+		return err
+	}
+	switch channel.Kind {
+	case v1.NotificationChannelKindChat:
+		return n.notifyChat(channel, event, text)
+	case v1.NotificationChannelKindEmail:
+		return n.notifyEmail(channel, event, text)
+	case v1.NotificationChannelKindWebhook:
+		return n.notifyWebhook(channel, event, text)
+	default:
+		return fmt.Errorf("unknown notification channel kind %s", channel.Kind)
+	}
+}
+
+func (n *Notifier) notifyChat(channel *v1.NotificationChannel, event *Event, text string) error {
+	if n.ChatProvider == nil {
+		log.Logger().Warnf("no chat provider configured so ignoring notification to chat channel %s", channel.Chat)
+		return nil
+	}
+	if channel.Chat == "" {
+		return fmt.Errorf("no chat channel specified")
+	}
+	_, err := n.ChatProvider.PostMessage(channel.Chat, event.ToMessage(text))
+	if err != nil {
+		return errors.Wrapf(err, "failed to post to chat channel %s", channel.Chat)
+	}
+	return nil
+}
+
+func (n *Notifier) notifyEmail(channel *v1.NotificationChannel, event *Event, text string) error {
+	if len(channel.Email) == 0 {
+		return fmt.Errorf("no email recipients specified")
+	}
+	if n.SMTPServer == "" {
+		log.Logger().Warnf("no SMTP server configured so ignoring notification to %s", strings.Join(channel.Email, ", "))
+		return nil
+	}
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "From: %s\r\n", n.From)
+	fmt.Fprintf(&body, "To: %s\r\n", strings.Join(channel.Email, ", "))
+	fmt.Fprintf(&body, "Subject: %s\r\n", emailSubject(text))
+	fmt.Fprintf(&body, "Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	fmt.Fprintf(&body, "%s\r\n", text)
+	for _, field := range event.ToMessage(text).Fields {
+		fmt.Fprintf(&body, "\r\n%s: %s", field.Title, field.Value)
+	}
+	if u := event.LinkURL(); u != "" {
+		fmt.Fprintf(&body, "\r\n\r\n%s\r\n", u)
+	}
+	err := n.SendMail(n.SMTPServer, nil, n.From, channel.Email, body.Bytes())
+	if err != nil {
+		return errors.Wrapf(err, "failed to send email to %s via %s", strings.Join(channel.Email, ", "), n.SMTPServer)
+	}
+	return nil
+}
+
+func (n *Notifier) notifyWebhook(channel *v1.NotificationChannel, event *Event, text string) error {
+	if channel.WebhookURL == "" {
+		return fmt.Errorf("no webhook URL specified")
+	}
+	payload := &WebhookPayload{
+		Kind:            event.Kind,
+		Message:         text,
+		Pipeline:        event.Pipeline(),
+		Build:           event.Build(),
+		Owner:           event.Owner(),
+		Repository:      event.Repository(),
+		Branch:          event.Branch(),
+		Version:         event.Version(),
+		Environment:     event.Environment,
+		Status:          event.Activity.Spec.Status,
+		URL:             event.LinkURL(),
+		BuildURL:        event.BuildURL(),
+		ReleaseNotesURL: event.ReleaseNotesURL(),
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal webhook payload")
+	}
+	resp, err := n.HTTPClient.Post(channel.WebhookURL, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return errors.Wrapf(err, "failed to post to webhook %s", channel.WebhookURL)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("webhook %s returned status %d: %s", channel.WebhookURL, resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// emailSubject returns the first line of the rendered text as headers cannot span several lines
+func emailSubject(text string) string {
+	return strings.TrimSpace(strings.SplitN(strings.TrimSpace(text), "\n", 2)[0])
+}
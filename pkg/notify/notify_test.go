@@ -0,0 +1,181 @@
+package notify_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"testing"
+
+	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newActivity(build string, status v1.ActivityStatusType) *v1.PipelineActivity {
+	return &v1.PipelineActivity{
+		Spec: v1.PipelineActivitySpec{
+			Pipeline: "myorg/myapp/master",
+			Build:    build,
+			Version:  "1.0." + build,
+			Status:   status,
+			BuildURL: "https://dashboard/myorg/myapp/master/" + build,
+		},
+	}
+}
+
+func eventKinds(events []*notify.Event) []v1.NotificationEventType {
+	answer := []v1.NotificationEventType{}
+	for _, e := range events {
+		answer = append(answer, e.Kind)
+	}
+	return answer
+}
+
+func TestDetectorFailureAndRecovery(t *testing.T) {
+	t.Parallel()
+
+	detector := notify.NewDetector()
+	detector.Baseline(newActivity("1", v1.ActivityStatusTypeSucceeded))
+
+	events := detector.Events(newActivity("2", v1.ActivityStatusTypeRunning), newActivity("2", v1.ActivityStatusTypeFailed))
+	assert.Equal(t, []v1.NotificationEventType{v1.NotificationEventTypeFailed}, eventKinds(events))
+	assert.Equal(t, "myapp", events[0].Repository())
+	assert.Equal(t, "master", events[0].Branch())
+
+	// a resync does not notify again
+	events = detector.Events(newActivity("2", v1.ActivityStatusTypeFailed), newActivity("2", v1.ActivityStatusTypeFailed))
+	assert.Empty(t, events)
+
+	events = detector.Events(newActivity("3", v1.ActivityStatusTypeRunning), newActivity("3", v1.ActivityStatusTypeSucceeded))
+	assert.Equal(t, []v1.NotificationEventType{v1.NotificationEventTypeRecovered}, eventKinds(events))
+
+	events = detector.Events(newActivity("4", v1.ActivityStatusTypeRunning), newActivity("4", v1.ActivityStatusTypeSucceeded))
+	assert.Empty(t, events)
+}
+
+func TestDetectorPromotionAndApproval(t *testing.T) {
+	t.Parallel()
+
+	promoteStep := func(status v1.ActivityStatusType) v1.PipelineActivityStep {
+		return v1.PipelineActivityStep{
+			Kind: v1.ActivityStepKindTypePromote,
+			Promote: &v1.PromoteActivityStep{
+				CoreActivityStep: v1.CoreActivityStep{
+					Name:   "Promote: production",
+					Status: status,
+				},
+				Environment:    "production",
+				ApplicationURL: "http://myapp.production.example.com",
+				PullRequest: &v1.PromotePullRequestStep{
+					PullRequestURL: "https://github.com/myorg/environment-production/pull/7",
+				},
+			},
+		}
+	}
+
+	detector := notify.NewDetector()
+	running := newActivity("5", v1.ActivityStatusTypeRunning)
+	waiting := newActivity("5", v1.ActivityStatusTypeRunning)
+	waiting.Spec.Steps = []v1.PipelineActivityStep{promoteStep(v1.ActivityStatusTypeWaitingForApproval)}
+	promoted := newActivity("5", v1.ActivityStatusTypeRunning)
+	promoted.Spec.Steps = []v1.PipelineActivityStep{promoteStep(v1.ActivityStatusTypeSucceeded)}
+
+	events := detector.Events(running, waiting)
+	require.Equal(t, []v1.NotificationEventType{v1.NotificationEventTypeWaitingForApproval}, eventKinds(events))
+	assert.Equal(t, "production", events[0].Environment)
+	assert.Equal(t, "https://github.com/myorg/environment-production/pull/7", events[0].LinkURL())
+
+	events = detector.Events(waiting, promoted)
+	require.Equal(t, []v1.NotificationEventType{v1.NotificationEventTypePromoted}, eventKinds(events))
+	text, err := events[0].Render("")
+	require.NoError(t, err)
+	assert.Equal(t, "myapp version 1.0.5 has been promoted to production", text)
+}
+
+func TestRuleMatches(t *testing.T) {
+	t.Parallel()
+
+	event := &notify.Event{
+		Kind:        v1.NotificationEventTypePromoted,
+		Activity:    newActivity("1", v1.ActivityStatusTypeSucceeded),
+		Environment: "production",
+	}
+
+	testCases := []struct {
+		name     string
+		rule     v1.NotificationRule
+		expected bool
+	}{
+		{"empty", v1.NotificationRule{}, true},
+		{"event", v1.NotificationRule{Events: []v1.NotificationEventType{v1.NotificationEventTypePromoted}}, true},
+		{"otherEvent", v1.NotificationRule{Events: []v1.NotificationEventType{v1.NotificationEventTypeFailed}}, false},
+		{"repoGlob", v1.NotificationRule{Repositories: []string{"myorg/*"}}, true},
+		{"otherRepo", v1.NotificationRule{Repositories: []string{"otherorg/*"}}, false},
+		{"branch", v1.NotificationRule{Branches: []string{"master"}}, true},
+		{"prBranch", v1.NotificationRule{Branches: []string{"PR-*"}}, false},
+		{"environment", v1.NotificationRule{Environments: []string{"staging", "production"}}, true},
+		{"otherEnvironment", v1.NotificationRule{Environments: []string{"staging"}}, false},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, notify.RuleMatches(&tc.rule, event), "rule %s", tc.name)
+	}
+}
+
+func TestNotifyWebhookAndEmail(t *testing.T) {
+	t.Parallel()
+
+	var payload notify.WebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := json.NewDecoder(r.Body).Decode(&payload)
+		assert.NoError(t, err)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var sentTo []string
+	var sentMessage string
+	notifier := notify.NewNotifier(nil, "localhost:1025", "jx@example.com")
+	notifier.SendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		sentTo = to
+		sentMessage = string(msg)
+		return nil
+	}
+
+	rules := []v1.NotificationRule{
+		{
+			Name:   "failures",
+			Events: []v1.NotificationEventType{v1.NotificationEventTypeFailed},
+			Channels: []v1.NotificationChannel{
+				{
+					Kind:       v1.NotificationChannelKindWebhook,
+					WebhookURL: server.URL,
+				},
+				{
+					Kind:     v1.NotificationChannelKindEmail,
+					Email:    []string{"dev@example.com"},
+					Template: "{{ .Repository }} is broken by {{ .Author }}\nSee {{ .BuildURL }}",
+				},
+			},
+		},
+	}
+	activity := newActivity("2", v1.ActivityStatusTypeFailed)
+	activity.Spec.Author = "jstrachan"
+	event := &notify.Event{
+		Kind:     v1.NotificationEventTypeFailed,
+		Activity: activity,
+	}
+
+	err := notifier.Notify(rules, event)
+	require.NoError(t, err)
+
+	assert.Equal(t, v1.NotificationEventTypeFailed, payload.Kind)
+	assert.Equal(t, "Pipeline myorg/myapp/master #2 failed", payload.Message)
+	assert.Equal(t, "myorg", payload.Owner)
+	assert.Equal(t, "https://dashboard/myorg/myapp/master/2", payload.URL)
+
+	assert.Equal(t, []string{"dev@example.com"}, sentTo)
+	assert.Contains(t, sentMessage, "Subject: myapp is broken by jstrachan\r\nContent-Type:")
+	assert.Contains(t, sentMessage, "See https://dashboard/myorg/myapp/master/2")
+}
@@ -0,0 +1,58 @@
+package notify
+
+import (
+	"path"
+
+	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+)
+
+// MatchingRules returns the notification rules which match the event
+func MatchingRules(rules []v1.NotificationRule, event *Event) []v1.NotificationRule {
+	answer := []v1.NotificationRule{}
+	for _, rule := range rules {
+		if RuleMatches(&rule, event) {
+			answer = append(answer, rule)
+		}
+	}
+	return answer
+}
+
+// RuleMatches returns true if the rule matches the event
+func RuleMatches(rule *v1.NotificationRule, event *Event) bool {
+	if len(rule.Events) > 0 {
+		found := false
+		for _, kind := range rule.Events {
+			if kind == event.Kind {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if !matchesAny(rule.Repositories, event.Owner()+"/"+event.Repository()) {
+		return false
+	}
+	if !matchesAny(rule.Branches, event.Branch()) {
+		return false
+	}
+	if event.Environment != "" && !matchesAny(rule.Environments, event.Environment) {
+		return false
+	}
+	return true
+}
+
+// matchesAny returns true if there are no patterns or the value matches one of the glob patterns
+func matchesAny(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		matched, err := path.Match(pattern, value)
+		if err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,198 @@
+package reports
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+)
+
+// Deployment is a promotion of a version of an application to an environment
+type Deployment struct {
+	Application string
+	Environment string
+	Version     string
+	// Started is when the pipeline which built the version started which is used for the lead time of the change
+	Started   time.Time
+	Completed time.Time
+	Failed    bool
+	// RolledBackVersion is the version which was rolled back if the deployment was a rollback
+	RolledBackVersion string
+}
+
+// DORAMetrics are the four key DevOps Research and Assessment metrics of an application in an environment
+type DORAMetrics struct {
+	Application string `json:"application"`
+	Environment string `json:"environment"`
+	Deployments int    `json:"deployments"`
+	Failures    int    `json:"failures"`
+	// DeploymentFrequency is the number of successful deployments per day
+	DeploymentFrequency float64 `json:"deploymentFrequency"`
+	// LeadTimeSeconds is the mean time from the start of the release pipeline to the deployment completing
+	LeadTimeSeconds float64 `json:"leadTimeSeconds"`
+	// ChangeFailureRate is the ratio of deployments which failed or were rolled back
+	ChangeFailureRate float64 `json:"changeFailureRate"`
+	// MeanTimeToRestoreSeconds is the mean time from a failed deployment to the next successful deployment
+	MeanTimeToRestoreSeconds float64 `json:"meanTimeToRestoreSeconds"`
+}
+
+// DeploymentsFromActivities returns the completed deployments recorded in the promote steps of the activities
+func DeploymentsFromActivities(activities []v1.PipelineActivity) []Deployment {
+	answer := []Deployment{}
+	for _, a := range activities {
+		app := a.Spec.GitRepository
+		if app == "" {
+			paths := strings.Split(a.Spec.Pipeline, "/")
+			if len(paths) > 1 {
+				app = paths[len(paths)-2]
+			}
+		}
+		if app == "" {
+			continue
+		}
+		started := time.Time{}
+		if a.Spec.StartedTimestamp != nil {
+			started = a.Spec.StartedTimestamp.Time
+		}
+		for _, step := range a.Spec.Steps {
+			promote := step.Promote
+			if promote == nil || promote.CompletedTimestamp == nil {
+				continue
+			}
+			status := promote.Status
+			if status != v1.ActivityStatusTypeSucceeded && status != v1.ActivityStatusTypeFailed && status != v1.ActivityStatusTypeError {
+				continue
+			}
+			d := Deployment{
+				Application: app,
+				Environment: promote.Environment,
+				Version:     a.Spec.Version,
+				Started:     started,
+				Completed:   promote.CompletedTimestamp.Time,
+				Failed:      status != v1.ActivityStatusTypeSucceeded,
+			}
+			if promote.Rollback != nil {
+				d.Version = promote.Rollback.ToVersion
+				d.RolledBackVersion = promote.Rollback.FromVersion
+			}
+			answer = append(answer, d)
+		}
+	}
+	return answer
+}
+
+// MarkFailedReleases marks deployments as failed if the Release of the version in the namespace of the
+// environment failed. The envNamespaces map the environment names to their namespaces
+func MarkFailedReleases(deployments []Deployment, releases []v1.Release, envNamespaces map[string]string) {
+	failed := map[string]bool{}
+	for _, r := range releases {
+		if r.Status.Status == v1.ReleaseStatusTypeFailed {
+			failed[r.Namespace+"/"+r.Spec.Name+"/"+r.Spec.Version] = true
+		}
+	}
+	for i := range deployments {
+		d := &deployments[i]
+		ns := envNamespaces[d.Environment]
+		if ns != "" && failed[ns+"/"+d.Application+"/"+d.Version] {
+			d.Failed = true
+		}
+	}
+}
+
+// CalculateDORAMetrics calculates the metrics of each application and environment from the deployments
+// which completed in the time range
+func CalculateDORAMetrics(deployments []Deployment, from time.Time, to time.Time) []*DORAMetrics {
+	groups := map[string][]Deployment{}
+	keys := []string{}
+	for _, d := range deployments {
+		if d.Completed.Before(from) || !d.Completed.Before(to) {
+			continue
+		}
+		key := d.Application + "/" + d.Environment
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], d)
+	}
+	sort.Strings(keys)
+
+	days := to.Sub(from).Hours() / 24
+	answer := []*DORAMetrics{}
+	for _, key := range keys {
+		answer = append(answer, calculateGroupMetrics(groups[key], days))
+	}
+	return answer
+}
+
+func calculateGroupMetrics(deployments []Deployment, days float64) *DORAMetrics {
+	sort.Slice(deployments, func(i, j int) bool {
+		return deployments[i].Completed.Before(deployments[j].Completed)
+	})
+	metrics := &DORAMetrics{
+		Application: deployments[0].Application,
+		Environment: deployments[0].Environment,
+		Deployments: len(deployments),
+	}
+
+	failed := make([]bool, len(deployments))
+	successes := 0
+	leadTimes := []time.Duration{}
+	restoreTimes := []time.Duration{}
+	for i, d := range deployments {
+		if d.Failed {
+			failed[i] = true
+			for _, next := range deployments[i+1:] {
+				if !next.Failed {
+					restoreTimes = append(restoreTimes, next.Completed.Sub(d.Completed))
+					break
+				}
+			}
+			continue
+		}
+		successes++
+		if d.RolledBackVersion != "" {
+			// the deployment of the version which was rolled back was a failed change
+			found := false
+			for j := i - 1; j >= 0; j-- {
+				if deployments[j].Version == d.RolledBackVersion && !failed[j] {
+					failed[j] = true
+					found = true
+					restoreTimes = append(restoreTimes, d.Completed.Sub(deployments[j].Completed))
+					break
+				}
+			}
+			if !found {
+				metrics.Failures++
+			}
+			continue
+		}
+		if !d.Started.IsZero() && d.Started.Before(d.Completed) {
+			leadTimes = append(leadTimes, d.Completed.Sub(d.Started))
+		}
+	}
+	for _, f := range failed {
+		if f {
+			metrics.Failures++
+		}
+	}
+
+	if days > 0 {
+		metrics.DeploymentFrequency = float64(successes) / days
+	}
+	metrics.LeadTimeSeconds = meanSeconds(leadTimes)
+	metrics.ChangeFailureRate = float64(metrics.Failures) / float64(metrics.Deployments)
+	metrics.MeanTimeToRestoreSeconds = meanSeconds(restoreTimes)
+	return metrics
+}
+
+func meanSeconds(durations []time.Duration) float64 {
+	if len(durations) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+	return total.Seconds() / float64(len(durations))
+}
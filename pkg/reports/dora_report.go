@@ -0,0 +1,81 @@
+package reports
+
+import (
+	"strconv"
+	"time"
+)
+
+// DORAMetric describes how one of the DORA metrics of an application in an environment is reported
+type DORAMetric struct {
+	// Title is the human readable name of the metric
+	Title string
+	// Name is the name of the Prometheus gauge of the metric
+	Name string
+	// Help describes the metric
+	Help string
+	// Value returns the raw value of the metric
+	Value func(m *DORAMetrics) float64
+	// Text returns the human readable value of the metric
+	Text func(m *DORAMetrics) string
+}
+
+// DORAMetricTypes are the reported DORA metrics
+var DORAMetricTypes = []DORAMetric{
+	{
+		Title: "Deployments",
+		Name:  "jx_dora_deployments",
+		Help:  "The number of deployments in the time range",
+		Value: func(m *DORAMetrics) float64 { return float64(m.Deployments) },
+		Text:  func(m *DORAMetrics) string { return strconv.Itoa(m.Deployments) },
+	},
+	{
+		Title: "Deployments per day",
+		Name:  "jx_dora_deployment_frequency_per_day",
+		Help:  "The number of successful deployments per day",
+		Value: func(m *DORAMetrics) float64 { return m.DeploymentFrequency },
+		Text:  func(m *DORAMetrics) string { return strconv.FormatFloat(m.DeploymentFrequency, 'f', 2, 64) },
+	},
+	{
+		Title: "Lead time",
+		Name:  "jx_dora_lead_time_seconds",
+		Help:  "The mean lead time of changes from the start of the release pipeline to deployment",
+		Value: func(m *DORAMetrics) float64 { return m.LeadTimeSeconds },
+		Text:  func(m *DORAMetrics) string { return formatSeconds(m.LeadTimeSeconds) },
+	},
+	{
+		Title: "Change failure rate",
+		Name:  "jx_dora_change_failure_rate",
+		Help:  "The ratio of deployments which failed or were rolled back",
+		Value: func(m *DORAMetrics) float64 { return m.ChangeFailureRate },
+		Text:  func(m *DORAMetrics) string { return strconv.FormatFloat(m.ChangeFailureRate*100, 'f', 1, 64) + "%" },
+	},
+	{
+		Title: "Time to restore",
+		Name:  "jx_dora_time_to_restore_seconds",
+		Help:  "The mean time to restore service after a failed deployment",
+		Value: func(m *DORAMetrics) float64 { return m.MeanTimeToRestoreSeconds },
+		Text:  func(m *DORAMetrics) string { return formatSeconds(m.MeanTimeToRestoreSeconds) },
+	},
+}
+
+// AddDORAMetrics adds the human readable values of the DORA metrics of an application in an environment to the report
+func AddDORAMetrics(report BarReport, m *DORAMetrics) {
+	for _, t := range DORAMetricTypes {
+		report.AddText(t.Title, t.Text(m))
+	}
+}
+
+// AddDORAMetric adds the raw value of a DORA metric of each application and environment to the report
+// using the name application/environment
+func AddDORAMetric(report BarReport, metric DORAMetric, metrics []*DORAMetrics) {
+	for _, m := range metrics {
+		report.AddText(m.Application+"/"+m.Environment, strconv.FormatFloat(metric.Value(m), 'g', -1, 64))
+	}
+}
+
+func formatSeconds(seconds float64) string {
+	if seconds <= 0 {
+		return "-"
+	}
+	return time.Duration(seconds * float64(time.Second)).Round(time.Second).String()
+}
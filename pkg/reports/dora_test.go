@@ -0,0 +1,177 @@
+package reports_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/reports"
+	"github.com/jenkins-x/jx/pkg/table"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var doraStart = time.Date(2019, time.December, 1, 0, 0, 0, 0, time.UTC)
+
+func at(hours int) time.Time {
+	return doraStart.Add(time.Duration(hours) * time.Hour)
+}
+
+func promoteActivity(app string, version string, started time.Time, env string, status v1.ActivityStatusType, completed time.Time, rollback *v1.PromoteRollback) v1.PipelineActivity {
+	startedTime := metav1.NewTime(started)
+	completedTime := metav1.NewTime(completed)
+	return v1.PipelineActivity{
+		Spec: v1.PipelineActivitySpec{
+			Pipeline:         "myorg/" + app + "/master",
+			Version:          version,
+			StartedTimestamp: &startedTime,
+			Steps: []v1.PipelineActivityStep{
+				{
+					Kind: v1.ActivityStepKindTypePromote,
+					Promote: &v1.PromoteActivityStep{
+						CoreActivityStep: v1.CoreActivityStep{
+							Status:             status,
+							CompletedTimestamp: &completedTime,
+						},
+						Environment: env,
+						Rollback:    rollback,
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestCalculateDORAMetrics(t *testing.T) {
+	t.Parallel()
+
+	activities := []v1.PipelineActivity{
+		promoteActivity("myapp", "1.0.1", at(0), "production", v1.ActivityStatusTypeSucceeded, at(2), nil),
+		// 1.0.2 fails to deploy and is fixed by 1.0.3
+		promoteActivity("myapp", "1.0.2", at(10), "production", v1.ActivityStatusTypeFailed, at(11), nil),
+		promoteActivity("myapp", "1.0.3", at(12), "production", v1.ActivityStatusTypeSucceeded, at(14), nil),
+		// 1.0.4 is deployed then rolled back to 1.0.3
+		promoteActivity("myapp", "1.0.4", at(20), "production", v1.ActivityStatusTypeSucceeded, at(24), nil),
+		promoteActivity("myapp", "1.0.4", at(20), "production", v1.ActivityStatusTypeSucceeded, at(27), &v1.PromoteRollback{
+			FromVersion: "1.0.4",
+			ToVersion:   "1.0.3",
+		}),
+		promoteActivity("myapp", "1.0.1", at(0), "staging", v1.ActivityStatusTypeSucceeded, at(1), nil),
+		// outside of the time range
+		promoteActivity("myapp", "1.0.0", at(-100), "production", v1.ActivityStatusTypeSucceeded, at(-99), nil),
+		// still running
+		promoteActivity("myapp", "1.0.5", at(30), "production", v1.ActivityStatusTypeRunning, at(31), nil),
+	}
+	deployments := reports.DeploymentsFromActivities(activities)
+
+	metrics := reports.CalculateDORAMetrics(deployments, doraStart, doraStart.Add(48*time.Hour))
+	require.Len(t, metrics, 2)
+
+	production := metrics[0]
+	assert.Equal(t, "myapp", production.Application)
+	assert.Equal(t, "production", production.Environment)
+	assert.Equal(t, 5, production.Deployments)
+	assert.Equal(t, 2, production.Failures)
+	assert.Equal(t, 2.0, production.DeploymentFrequency)
+	assert.Equal(t, 0.4, production.ChangeFailureRate)
+	// lead times of 1.0.1, 1.0.3 and 1.0.4 are 2h, 2h and 4h
+	assert.Equal(t, (8 * time.Hour / 3).Seconds(), production.LeadTimeSeconds)
+	// restored after 3 hours from both failures
+	assert.Equal(t, (3 * time.Hour).Seconds(), production.MeanTimeToRestoreSeconds)
+
+	staging := metrics[1]
+	assert.Equal(t, "staging", staging.Environment)
+	assert.Equal(t, 1, staging.Deployments)
+	assert.Equal(t, 0, staging.Failures)
+	assert.Equal(t, 0.0, staging.ChangeFailureRate)
+}
+
+func TestMarkFailedReleases(t *testing.T) {
+	t.Parallel()
+
+	activities := []v1.PipelineActivity{
+		promoteActivity("myapp", "1.0.1", at(0), "production", v1.ActivityStatusTypeSucceeded, at(2), nil),
+		promoteActivity("myapp", "1.0.2", at(4), "production", v1.ActivityStatusTypeSucceeded, at(6), nil),
+	}
+	releases := []v1.Release{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "myapp-1.0.1",
+				Namespace: "jx-production",
+			},
+			Spec: v1.ReleaseSpec{
+				Name:    "myapp",
+				Version: "1.0.1",
+			},
+			Status: v1.ReleaseStatus{
+				Status: v1.ReleaseStatusTypeFailed,
+			},
+		},
+	}
+	deployments := reports.DeploymentsFromActivities(activities)
+	reports.MarkFailedReleases(deployments, releases, map[string]string{"production": "jx-production"})
+
+	metrics := reports.CalculateDORAMetrics(deployments, doraStart, doraStart.Add(24*time.Hour))
+	require.Len(t, metrics, 1)
+	assert.Equal(t, 1, metrics[0].Failures)
+	assert.Equal(t, (4 * time.Hour).Seconds(), metrics[0].MeanTimeToRestoreSeconds)
+}
+
+func TestDORAMetricsPrometheusBarReport(t *testing.T) {
+	t.Parallel()
+
+	metrics := []*reports.DORAMetrics{
+		{
+			Application:              "myapp",
+			Environment:              "production",
+			Deployments:              4,
+			DeploymentFrequency:      0.5,
+			LeadTimeSeconds:          3600,
+			ChangeFailureRate:        0.25,
+			MeanTimeToRestoreSeconds: 600,
+		},
+	}
+	var buf bytes.Buffer
+	for _, m := range reports.DORAMetricTypes {
+		report := reports.NewPrometheusBarReport(&buf, m.Name, m.Help, "application", "environment")
+		reports.AddDORAMetric(report, m, metrics)
+		err := report.Render()
+		require.NoError(t, err)
+	}
+
+	text := buf.String()
+	assert.Contains(t, text, "# TYPE jx_dora_deployments gauge\n")
+	assert.Contains(t, text, `jx_dora_deployments{application="myapp",environment="production"} 4`+"\n")
+	assert.Contains(t, text, `jx_dora_deployment_frequency_per_day{application="myapp",environment="production"} 0.5`+"\n")
+	assert.Contains(t, text, `jx_dora_lead_time_seconds{application="myapp",environment="production"} 3600`+"\n")
+	assert.Contains(t, text, `jx_dora_change_failure_rate{application="myapp",environment="production"} 0.25`+"\n")
+	assert.Contains(t, text, `jx_dora_time_to_restore_seconds{application="myapp",environment="production"} 600`+"\n")
+}
+
+func TestDORAMetricsTableBarReport(t *testing.T) {
+	t.Parallel()
+
+	m := &reports.DORAMetrics{
+		Application:              "myapp",
+		Environment:              "production",
+		Deployments:              4,
+		DeploymentFrequency:      0.5,
+		LeadTimeSeconds:          3600,
+		ChangeFailureRate:        0.25,
+		MeanTimeToRestoreSeconds: 0,
+	}
+	var buf bytes.Buffer
+	report := reports.NewTableBarReport(table.CreateTable(&buf), m.Application, m.Environment)
+	reports.AddDORAMetrics(report, m)
+	err := report.Render()
+	require.NoError(t, err)
+
+	text := buf.String()
+	assert.Contains(t, text, "myapp")
+	assert.Contains(t, text, "Deployments per day 0.50")
+	assert.Contains(t, text, "Lead time           1h0m0s")
+	assert.Contains(t, text, "Change failure rate 25.0%")
+	assert.Contains(t, text, "Time to restore     -")
+}
@@ -0,0 +1,294 @@
+package reports
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultPipelineBuckets are the histogram buckets in seconds used for pipeline durations
+var DefaultPipelineBuckets = []float64{30, 60, 120, 300, 600, 900, 1200, 1800, 3600, 7200}
+
+// DefaultStepBuckets are the histogram buckets in seconds used for stage, step and queue durations
+var DefaultStepBuckets = []float64{1, 5, 10, 30, 60, 120, 300, 600, 1200, 3600}
+
+// PipelineMetrics aggregates the durations and outcomes of completed pipelines and promotions from PipelineActivity
+// resources so that they can be exposed to Prometheus. Each pipeline and promotion is only counted once
+type PipelineMetrics struct {
+	lock      sync.Mutex
+	pipelines map[string]bool
+	promotes  map[string]bool
+
+	pipelineDurations *histogramFamily
+	queueDurations    *histogramFamily
+	stageDurations    *histogramFamily
+	stepDurations     *histogramFamily
+	pipelineCounts    *counterFamily
+	promotionCounts   *counterFamily
+}
+
+// NewPipelineMetrics creates a new empty set of pipeline metrics
+func NewPipelineMetrics() *PipelineMetrics {
+	return &PipelineMetrics{
+		pipelines:         map[string]bool{},
+		promotes:          map[string]bool{},
+		pipelineDurations: newHistogramFamily(DefaultPipelineBuckets),
+		queueDurations:    newHistogramFamily(DefaultStepBuckets),
+		stageDurations:    newHistogramFamily(DefaultStepBuckets),
+		stepDurations:     newHistogramFamily(DefaultStepBuckets),
+		pipelineCounts:    newCounterFamily(),
+		promotionCounts:   newCounterFamily(),
+	}
+}
+
+// Observe records the metrics of the activity once its pipeline and each of its promotions have completed
+func (m *PipelineMetrics) Observe(activity *v1.PipelineActivity) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	spec := &activity.Spec
+	owner, repo, branch := activityGitLabels(activity)
+	labels := map[string]string{
+		"owner":  owner,
+		"repo":   repo,
+		"branch": branch,
+	}
+
+	for _, step := range spec.Steps {
+		promote := step.Promote
+		if promote == nil || !promote.Status.IsTerminated() {
+			continue
+		}
+		key := activity.Name + "/" + promote.Environment
+		if m.promotes[key] {
+			continue
+		}
+		m.promotes[key] = true
+		m.promotionCounts.inc(withLabels(labels, "environment", promote.Environment, "status", string(promote.Status)))
+	}
+
+	if !spec.Status.IsTerminated() || m.pipelines[activity.Name] {
+		return
+	}
+	m.pipelines[activity.Name] = true
+	m.pipelineCounts.inc(withLabels(labels, "status", string(spec.Status)))
+
+	if duration, ok := stepDuration(spec.StartedTimestamp, spec.CompletedTimestamp); ok {
+		m.pipelineDurations.observe(labels, duration)
+	}
+	firstStageStarted := time.Time{}
+	for _, step := range spec.Steps {
+		stage := step.Stage
+		if stage == nil {
+			continue
+		}
+		if stage.StartedTimestamp != nil && (firstStageStarted.IsZero() || stage.StartedTimestamp.Time.Before(firstStageStarted)) {
+			firstStageStarted = stage.StartedTimestamp.Time
+		}
+		stageLabels := withLabels(labels, "stage", stage.Name)
+		if duration, ok := stepDuration(stage.StartedTimestamp, stage.CompletedTimestamp); ok {
+			m.stageDurations.observe(stageLabels, duration)
+		}
+		for _, s := range stage.Steps {
+			if duration, ok := stepDuration(s.StartedTimestamp, s.CompletedTimestamp); ok {
+				m.stepDurations.observe(withLabels(stageLabels, "step", s.Name), duration)
+			}
+		}
+	}
+	if !firstStageStarted.IsZero() {
+		queued := activity.CreationTimestamp.Time
+		if queued.IsZero() && spec.StartedTimestamp != nil {
+			queued = spec.StartedTimestamp.Time
+		}
+		if !queued.IsZero() && !firstStageStarted.Before(queued) {
+			m.queueDurations.observe(labels, firstStageStarted.Sub(queued).Seconds())
+		}
+	}
+}
+
+// Forget stops tracking whether the pipeline and promotions of a deleted activity have been counted
+func (m *PipelineMetrics) Forget(activity *v1.PipelineActivity) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.pipelines, activity.Name)
+	prefix := activity.Name + "/"
+	for key := range m.promotes {
+		if strings.HasPrefix(key, prefix) {
+			delete(m.promotes, key)
+		}
+	}
+}
+
+// Write writes the metrics in the Prometheus text exposition format
+func (m *PipelineMetrics) Write(w io.Writer) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	histograms := []struct {
+		name   string
+		help   string
+		family *histogramFamily
+	}{
+		{"jx_pipeline_duration_seconds", "The duration of completed pipelines", m.pipelineDurations},
+		{"jx_pipeline_queue_seconds", "The time pipelines waited before their first stage started", m.queueDurations},
+		{"jx_pipeline_stage_duration_seconds", "The duration of completed pipeline stages", m.stageDurations},
+		{"jx_pipeline_step_duration_seconds", "The duration of completed pipeline steps", m.stepDurations},
+	}
+	for _, h := range histograms {
+		err := h.family.write(w, h.name, h.help)
+		if err != nil {
+			return err
+		}
+	}
+	err := WritePrometheusMetric(w, "jx_pipelines_total", "The number of completed pipelines by status", "counter", m.pipelineCounts.samples())
+	if err != nil {
+		return err
+	}
+	return WritePrometheusMetric(w, "jx_promotions_total", "The number of completed promotions by environment and status", "counter", m.promotionCounts.samples())
+}
+
+func activityGitLabels(activity *v1.PipelineActivity) (string, string, string) {
+	spec := &activity.Spec
+	owner, repo, branch := spec.GitOwner, spec.GitRepository, spec.GitBranch
+	paths := strings.Split(spec.Pipeline, "/")
+	if len(paths) == 3 {
+		if owner == "" {
+			owner = paths[0]
+		}
+		if repo == "" {
+			repo = paths[1]
+		}
+		if branch == "" {
+			branch = paths[2]
+		}
+	}
+	return owner, repo, branch
+}
+
+func stepDuration(started *metav1.Time, completed *metav1.Time) (float64, bool) {
+	if started == nil || completed == nil || completed.Time.Before(started.Time) {
+		return 0, false
+	}
+	return completed.Time.Sub(started.Time).Seconds(), true
+}
+
+func withLabels(labels map[string]string, keyValues ...string) map[string]string {
+	answer := map[string]string{}
+	for k, v := range labels {
+		answer[k] = v
+	}
+	for i := 0; i+1 < len(keyValues); i += 2 {
+		answer[keyValues[i]] = keyValues[i+1]
+	}
+	return answer
+}
+
+type histogram struct {
+	labels map[string]string
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+type histogramFamily struct {
+	buckets    []float64
+	histograms map[string]*histogram
+}
+
+func newHistogramFamily(buckets []float64) *histogramFamily {
+	return &histogramFamily{
+		buckets:    buckets,
+		histograms: map[string]*histogram{},
+	}
+}
+
+func (f *histogramFamily) observe(labels map[string]string, value float64) {
+	key := prometheusLabels(labels)
+	h := f.histograms[key]
+	if h == nil {
+		h = &histogram{
+			labels: labels,
+			counts: make([]uint64, len(f.buckets)),
+		}
+		f.histograms[key] = h
+	}
+	for i, b := range f.buckets {
+		if value <= b {
+			h.counts[i]++
+		}
+	}
+	h.sum += value
+	h.count++
+}
+
+func (f *histogramFamily) write(w io.Writer, name string, help string) error {
+	_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", name, help, name)
+	if err != nil {
+		return err
+	}
+	keys := []string{}
+	for k := range f.histograms {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		h := f.histograms[key]
+		for i, b := range f.buckets {
+			_, err = fmt.Fprintf(w, "%s_bucket%s %d\n", name, prometheusLabels(withLabels(h.labels, "le", strconv.FormatFloat(b, 'g', -1, 64))), h.counts[i])
+			if err != nil {
+				return err
+			}
+		}
+		_, err = fmt.Fprintf(w, "%s_bucket%s %d\n%s_sum%s %s\n%s_count%s %d\n",
+			name, prometheusLabels(withLabels(h.labels, "le", "+Inf")), h.count,
+			name, key, strconv.FormatFloat(h.sum, 'g', -1, 64),
+			name, key, h.count)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type counterFamily struct {
+	counters map[string]*PrometheusSample
+}
+
+func newCounterFamily() *counterFamily {
+	return &counterFamily{
+		counters: map[string]*PrometheusSample{},
+	}
+}
+
+func (f *counterFamily) inc(labels map[string]string) {
+	key := prometheusLabels(labels)
+	c := f.counters[key]
+	if c == nil {
+		c = &PrometheusSample{
+			Labels: labels,
+		}
+		f.counters[key] = c
+	}
+	c.Value++
+}
+
+func (f *counterFamily) samples() []PrometheusSample {
+	keys := []string{}
+	for k := range f.counters {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	answer := []PrometheusSample{}
+	for _, key := range keys {
+		answer = append(answer, *f.counters[key])
+	}
+	return answer
+}
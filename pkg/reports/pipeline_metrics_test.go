@@ -0,0 +1,136 @@
+package reports_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/reports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func timeAt(seconds int) *metav1.Time {
+	t := metav1.NewTime(doraStart.Add(time.Duration(seconds) * time.Second))
+	return &t
+}
+
+func completedActivity(status v1.ActivityStatusType) *v1.PipelineActivity {
+	return &v1.PipelineActivity{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "myorg-myapp-master-1",
+			CreationTimestamp: *timeAt(0),
+		},
+		Spec: v1.PipelineActivitySpec{
+			Pipeline:           "myorg/myapp/master",
+			Status:             status,
+			StartedTimestamp:   timeAt(0),
+			CompletedTimestamp: timeAt(200),
+			Steps: []v1.PipelineActivityStep{
+				{
+					Kind: v1.ActivityStepKindTypeStage,
+					Stage: &v1.StageActivityStep{
+						CoreActivityStep: v1.CoreActivityStep{
+							Name:               "build",
+							Status:             v1.ActivityStatusTypeSucceeded,
+							StartedTimestamp:   timeAt(20),
+							CompletedTimestamp: timeAt(140),
+						},
+						Steps: []v1.CoreActivityStep{
+							{
+								Name:               "test",
+								Status:             v1.ActivityStatusTypeSucceeded,
+								StartedTimestamp:   timeAt(20),
+								CompletedTimestamp: timeAt(28),
+							},
+						},
+					},
+				},
+				{
+					Kind: v1.ActivityStepKindTypePromote,
+					Promote: &v1.PromoteActivityStep{
+						CoreActivityStep: v1.CoreActivityStep{
+							Status:             v1.ActivityStatusTypeSucceeded,
+							StartedTimestamp:   timeAt(140),
+							CompletedTimestamp: timeAt(200),
+						},
+						Environment: "staging",
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestPipelineMetrics(t *testing.T) {
+	t.Parallel()
+
+	metrics := reports.NewPipelineMetrics()
+
+	running := completedActivity(v1.ActivityStatusTypeRunning)
+	running.Spec.Steps[1].Promote.Status = v1.ActivityStatusTypeRunning
+	metrics.Observe(running)
+
+	activity := completedActivity(v1.ActivityStatusTypeSucceeded)
+	metrics.Observe(activity)
+	// the same activity is only counted once
+	metrics.Observe(activity)
+
+	var buf bytes.Buffer
+	err := metrics.Write(&buf)
+	require.NoError(t, err)
+	text := buf.String()
+
+	labels := `branch="master",owner="myorg",repo="myapp"`
+	assert.Contains(t, text, "# TYPE jx_pipeline_duration_seconds histogram\n")
+	assert.Contains(t, text, `jx_pipeline_duration_seconds_bucket{`+labels+`,le="120"} 0`+"\n")
+	assert.Contains(t, text, `jx_pipeline_duration_seconds_bucket{`+labels+`,le="300"} 1`+"\n")
+	assert.Contains(t, text, `jx_pipeline_duration_seconds_bucket{`+labels+`,le="+Inf"} 1`+"\n")
+	assert.Contains(t, text, `jx_pipeline_duration_seconds_sum{`+labels+`} 200`+"\n")
+	assert.Contains(t, text, `jx_pipeline_duration_seconds_count{`+labels+`} 1`+"\n")
+	assert.Contains(t, text, `jx_pipeline_queue_seconds_sum{`+labels+`} 20`+"\n")
+	assert.Contains(t, text, `jx_pipeline_stage_duration_seconds_sum{`+labels+`,stage="build"} 120`+"\n")
+	assert.Contains(t, text, `jx_pipeline_step_duration_seconds_sum{`+labels+`,stage="build",step="test"} 8`+"\n")
+	assert.Contains(t, text, `jx_pipelines_total{`+labels+`,status="Succeeded"} 1`+"\n")
+	assert.Contains(t, text, `jx_promotions_total{`+labels+`,environment="staging",status="Succeeded"} 1`+"\n")
+	assert.NotContains(t, text, `status="Running"`)
+}
+
+func TestPipelineMetricsForgetDeletedActivity(t *testing.T) {
+	t.Parallel()
+
+	metrics := reports.NewPipelineMetrics()
+
+	activity := completedActivity(v1.ActivityStatusTypeSucceeded)
+	metrics.Observe(activity)
+	metrics.Forget(activity)
+	// an activity recreated with the same name is counted again
+	metrics.Observe(activity)
+
+	var buf bytes.Buffer
+	err := metrics.Write(&buf)
+	require.NoError(t, err)
+	text := buf.String()
+
+	labels := `branch="master",owner="myorg",repo="myapp"`
+	assert.Contains(t, text, `jx_pipelines_total{`+labels+`,status="Succeeded"} 2`+"\n")
+	assert.Contains(t, text, `jx_promotions_total{`+labels+`,environment="staging",status="Succeeded"} 2`+"\n")
+}
+
+func TestPipelineMetricsEscapeLabelValues(t *testing.T) {
+	t.Parallel()
+
+	metrics := reports.NewPipelineMetrics()
+
+	activity := completedActivity(v1.ActivityStatusTypeSucceeded)
+	activity.Spec.GitBranch = "fix/\"größe\"\\\n"
+	metrics.Observe(activity)
+
+	var buf bytes.Buffer
+	err := metrics.Write(&buf)
+	require.NoError(t, err)
+
+	assert.Contains(t, buf.String(), `jx_pipelines_total{branch="fix/\"größe\"\\\n",owner="myorg",repo="myapp",status="Succeeded"} 1`+"\n")
+}
@@ -0,0 +1,106 @@
+package reports
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// PrometheusSample is a single value of a Prometheus metric with its labels
+type PrometheusSample struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// PrometheusBarReport renders the bars of a report as the samples of a Prometheus gauge. The name of each bar
+// holds the values of the label names separated by '/'
+type PrometheusBarReport struct {
+	Out        io.Writer
+	Name       string
+	Help       string
+	LabelNames []string
+
+	samples []PrometheusSample
+	err     error
+}
+
+// NewPrometheusBarReport creates a report which renders the gauge with the given name and label names
+func NewPrometheusBarReport(out io.Writer, name string, help string, labelNames ...string) *PrometheusBarReport {
+	return &PrometheusBarReport{
+		Out:        out,
+		Name:       name,
+		Help:       help,
+		LabelNames: labelNames,
+	}
+}
+
+func (r *PrometheusBarReport) AddText(name string, value string) {
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		if r.err == nil {
+			r.err = errors.Wrapf(err, "invalid value %s of %s for metric %s", value, name, r.Name)
+		}
+		return
+	}
+	labels := map[string]string{}
+	values := strings.SplitN(name, "/", len(r.LabelNames))
+	for i, labelName := range r.LabelNames {
+		if i < len(values) {
+			labels[labelName] = values[i]
+		}
+	}
+	r.samples = append(r.samples, PrometheusSample{
+		Labels: labels,
+		Value:  v,
+	})
+}
+
+func (r *PrometheusBarReport) AddNumber(name string, value int) {
+	ReportAddNumber(r, name, value)
+}
+
+func (r *PrometheusBarReport) Render() error {
+	if r.err != nil {
+		return r.err
+	}
+	return WritePrometheusMetric(r.Out, r.Name, r.Help, "gauge", r.samples)
+}
+
+// WritePrometheusMetric writes the samples of a metric in the Prometheus text exposition format
+func WritePrometheusMetric(w io.Writer, name string, help string, metricType string, samples []PrometheusSample) error {
+	_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+	if err != nil {
+		return err
+	}
+	for _, s := range samples {
+		_, err = fmt.Fprintf(w, "%s%s %s\n", name, prometheusLabels(s.Labels), strconv.FormatFloat(s.Value, 'g', -1, 64))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prometheusLabelValueEscaper escapes the characters which the Prometheus text exposition format requires to be escaped
+// in label values
+var prometheusLabelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func prometheusLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	keys := []string{}
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	values := []string{}
+	for _, k := range keys {
+		values = append(values, k+"=\""+prometheusLabelValueEscaper.Replace(labels[k])+"\"")
+	}
+	return "{" + strings.Join(values, ",") + "}"
+}
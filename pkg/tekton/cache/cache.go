@@ -0,0 +1,245 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"gocloud.dev/blob"
+
+	// lets import all the blob providers we need
+	_ "gocloud.dev/blob/azureblob"
+	_ "gocloud.dev/blob/fileblob"
+	_ "gocloud.dev/blob/gcsblob"
+	_ "gocloud.dev/blob/s3blob"
+)
+
+const (
+	// StoragePrefix the path in the storage under which all the caches are stored
+	StoragePrefix = "jenkins-x/cache"
+
+	// Classifier the classifier of the team storage location used for caches
+	Classifier = "cache"
+
+	archiveSuffix = ".tar.gz"
+	hashLength    = 16
+)
+
+// Entry is a cache archive in the storage
+type Entry struct {
+	Name    string
+	ModTime time.Time
+	Size    int64
+}
+
+// OpenBucket opens the bucket for the given URL such as 'gs://mybucket' or 'file:///jx-cache' for a mounted volume
+func OpenBucket(ctx context.Context, bucketURL string) (*blob.Bucket, error) {
+	bucket, err := blob.Open(ctx, bucketURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open bucket %s", bucketURL)
+	}
+	return bucket, nil
+}
+
+// Key returns the key of a cache which is the prefix followed by a hash of the contents of the given files, such as
+// lock files, relative to the directory. Missing files are ignored so that the key is stable until they are created.
+// Caches without files always have the same hash so that every key of a prefix has the same form
+func Key(prefix string, files []string, dir string) (string, error) {
+	hash := sha256.New()
+	for _, f := range files {
+		fileName := f
+		if !filepath.IsAbs(fileName) {
+			fileName = filepath.Join(dir, fileName)
+		}
+		exists, err := util.FileExists(fileName)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to check if file exists %s", fileName)
+		}
+		io.WriteString(hash, f+"\n")
+		if !exists {
+			log.Logger().Warnf("the cache key file %s does not exist", fileName)
+			continue
+		}
+		data, err := ioutil.ReadFile(fileName)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to read file %s", fileName)
+		}
+		hash.Write(data)
+	}
+	return prefix + "-" + hex.EncodeToString(hash.Sum(nil))[0:hashLength], nil
+}
+
+// ObjectName returns the name of the archive of the cache for the key of the given repository
+func ObjectName(owner string, repository string, key string) string {
+	return path.Join(StoragePrefix, owner, repository, key+archiveSuffix)
+}
+
+// ObjectPrefix returns the prefix of the names of the archives of the caches of the given repository whose keys were
+// created with the given key prefix
+func ObjectPrefix(owner string, repository string, prefix string) string {
+	return path.Join(StoragePrefix, owner, repository, prefix) + "-"
+}
+
+// Save stores the files in the given paths as an archive for the key unless the key is already stored. The file names
+// in the archive are relative to the root so that they can be restored to the same place. Returns true if the cache
+// was saved
+func Save(ctx context.Context, bucket *blob.Bucket, name string, paths []string, root string) (bool, error) {
+	entries, err := list(ctx, bucket, name)
+	if err != nil {
+		return false, err
+	}
+	for _, e := range entries {
+		if e.Name == name {
+			log.Logger().Infof("the cache %s already exists so not saving it", util.ColorInfo(name))
+			return false, nil
+		}
+	}
+	var files []string
+	for _, p := range paths {
+		exists, err := util.FileExists(p)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to check if %s exists", p)
+		}
+		if !exists {
+			log.Logger().Warnf("the cache path %s does not exist", p)
+			continue
+		}
+		err = util.GlobAllFiles("", p, func(name string) error {
+			files = append(files, name)
+			return nil
+		})
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to find the files in %s", p)
+		}
+	}
+	if len(files) == 0 {
+		log.Logger().Warnf("no files found in the cache paths %s so not saving the cache", strings.Join(paths, ", "))
+		return false, nil
+	}
+	buffer := &bytes.Buffer{}
+	err = util.TarGzFiles(buffer, files, root)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to create the archive of the cache %s", name)
+	}
+	err = bucket.WriteAll(ctx, name, buffer.Bytes(), &blob.WriterOptions{
+		ContentType: "application/gzip",
+		Metadata: map[string]string{
+			"classification": Classifier,
+		},
+	})
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to write the cache %s", name)
+	}
+	return true, nil
+}
+
+// Restore extracts the archive with the given name into the root directory. If it does not exist the most recent
+// archive whose name is the fallback prefix followed by a hash is restored instead, so that the caches of keys which
+// only start with the same prefix are ignored. Returns the name of the restored archive or an empty string if there
+// was no archive to restore
+func Restore(ctx context.Context, bucket *blob.Bucket, name string, fallbackPrefix string, root string) (string, error) {
+	entries, err := list(ctx, bucket, fallbackPrefix)
+	if err != nil {
+		return "", err
+	}
+	restored := ""
+	var latest time.Time
+	for _, e := range entries {
+		if e.Name == name {
+			restored = name
+			break
+		}
+		if isArchiveOfPrefix(e.Name, fallbackPrefix) && e.ModTime.After(latest) {
+			restored = e.Name
+			latest = e.ModTime
+		}
+	}
+	if restored == "" {
+		return "", nil
+	}
+	data, err := bucket.ReadAll(ctx, restored)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read the cache %s", restored)
+	}
+	tmpFile, err := ioutil.TempFile("", "jx-cache-")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create a temporary file")
+	}
+	defer os.Remove(tmpFile.Name())
+	_, err = tmpFile.Write(data)
+	tmpFile.Close()
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to write file %s", tmpFile.Name())
+	}
+	err = util.UnTargzAll(tmpFile.Name(), root)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to extract the cache %s", restored)
+	}
+	return restored, nil
+}
+
+// Expired returns the cache archives which have not been modified since the given time, oldest first
+func Expired(ctx context.Context, bucket *blob.Bucket, before time.Time) ([]Entry, error) {
+	entries, err := list(ctx, bucket, StoragePrefix+"/")
+	if err != nil {
+		return nil, err
+	}
+	var answer []Entry
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name, archiveSuffix) && e.ModTime.Before(before) {
+			answer = append(answer, e)
+		}
+	}
+	sort.Slice(answer, func(i, j int) bool {
+		return answer[i].ModTime.Before(answer[j].ModTime)
+	})
+	return answer, nil
+}
+
+// isArchiveOfPrefix returns true if the name is the prefix followed by the hash of a key and the archive suffix
+func isArchiveOfPrefix(name string, prefix string) bool {
+	if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, archiveSuffix) {
+		return false
+	}
+	hash := strings.TrimSuffix(strings.TrimPrefix(name, prefix), archiveSuffix)
+	if len(hash) != hashLength {
+		return false
+	}
+	_, err := hex.DecodeString(hash)
+	return err == nil
+}
+
+func list(ctx context.Context, bucket *blob.Bucket, prefix string) ([]Entry, error) {
+	var answer []Entry
+	iter := bucket.List(&blob.ListOptions{Prefix: prefix})
+	for {
+		obj, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list the caches with prefix %s", prefix)
+		}
+		if obj.IsDir {
+			continue
+		}
+		answer = append(answer, Entry{
+			Name:    obj.Key,
+			ModTime: obj.ModTime,
+			Size:    obj.Size,
+		})
+	}
+	return answer, nil
+}
@@ -0,0 +1,145 @@
+package cache_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/tekton/cache"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gocloud.dev/blob/fileblob"
+)
+
+func TestKey(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "test-cache-key-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	key, err := cache.Key("maven", nil, dir)
+	require.NoError(t, err)
+	assert.Equal(t, "maven-e3b0c44298fc1c14", key, "a cache without files has a fixed hash")
+
+	err = ioutil.WriteFile(filepath.Join(dir, "pom.xml"), []byte("<project/>"), util.DefaultWritePermissions)
+	require.NoError(t, err)
+	key, err = cache.Key("maven", []string{"pom.xml"}, dir)
+	require.NoError(t, err)
+	assert.Len(t, key, len("maven-")+16)
+
+	err = ioutil.WriteFile(filepath.Join(dir, "pom.xml"), []byte("<project><dependencies/></project>"), util.DefaultWritePermissions)
+	require.NoError(t, err)
+	changed, err := cache.Key("maven", []string{"pom.xml"}, dir)
+	require.NoError(t, err)
+	assert.NotEqual(t, key, changed)
+}
+
+func TestSaveRestoreAndExpire(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "test-cache-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	storageDir := filepath.Join(dir, "storage")
+	err = os.MkdirAll(storageDir, util.DefaultWritePermissions)
+	require.NoError(t, err)
+	bucket, err := fileblob.OpenBucket(storageDir, nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	root := filepath.Join(dir, "root")
+	repository := filepath.Join(root, "m2", "repository")
+	err = os.MkdirAll(filepath.Join(repository, "junit"), util.DefaultWritePermissions)
+	require.NoError(t, err)
+	err = ioutil.WriteFile(filepath.Join(repository, "junit", "junit.jar"), []byte("jar"), util.DefaultWritePermissions)
+	require.NoError(t, err)
+
+	name := cache.ObjectName("myorg", "myrepo", "maven-00000000000f1234")
+	saved, err := cache.Save(ctx, bucket, name, []string{repository}, root)
+	require.NoError(t, err)
+	assert.True(t, saved)
+
+	saved, err = cache.Save(ctx, bucket, name, []string{repository}, root)
+	require.NoError(t, err)
+	assert.False(t, saved, "should not save an existing cache again")
+
+	err = os.RemoveAll(root)
+	require.NoError(t, err)
+
+	restored, err := cache.Restore(ctx, bucket, cache.ObjectName("myorg", "myrepo", "maven-00000000000f5678"), cache.ObjectPrefix("myorg", "myrepo", "maven"), root)
+	require.NoError(t, err)
+	assert.Equal(t, name, restored, "should fall back to the latest cache with the same prefix")
+
+	data, err := ioutil.ReadFile(filepath.Join(repository, "junit", "junit.jar"))
+	require.NoError(t, err)
+	assert.Equal(t, "jar", string(data))
+
+	restored, err = cache.Restore(ctx, bucket, cache.ObjectName("myorg", "myrepo", "npm-00000000000f1234"), cache.ObjectPrefix("myorg", "myrepo", "npm"), root)
+	require.NoError(t, err)
+	assert.Equal(t, "", restored)
+
+	expired, err := cache.Expired(ctx, bucket, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, expired)
+
+	expired, err = cache.Expired(ctx, bucket, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, expired, 1)
+	assert.Equal(t, name, expired[0].Name)
+}
+
+func TestRestoreCacheWithoutFiles(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "test-cache-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	bucket, err := fileblob.OpenBucket(dir, nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	root := filepath.Join(dir, "root")
+	repository := filepath.Join(root, "m2", "repository")
+	err = os.MkdirAll(repository, util.DefaultWritePermissions)
+	require.NoError(t, err)
+	err = ioutil.WriteFile(filepath.Join(repository, "junit.jar"), []byte("junit"), util.DefaultWritePermissions)
+	require.NoError(t, err)
+
+	extraKey, err := cache.Key("maven-extra", []string{"pom.xml"}, dir)
+	require.NoError(t, err)
+	extraName := cache.ObjectName("myorg", "myrepo", extraKey)
+	saved, err := cache.Save(ctx, bucket, extraName, []string{repository}, root)
+	require.NoError(t, err)
+	assert.True(t, saved)
+
+	restored, err := cache.Restore(ctx, bucket, cache.ObjectName("myorg", "myrepo", "maven-00000000000f1234"), cache.ObjectPrefix("myorg", "myrepo", "maven"), root)
+	require.NoError(t, err)
+	assert.Equal(t, "", restored, "should not fall back to the cache of another key which starts with the same prefix")
+
+	key, err := cache.Key("maven", nil, dir)
+	require.NoError(t, err)
+	name := cache.ObjectName("myorg", "myrepo", key)
+	saved, err = cache.Save(ctx, bucket, name, []string{repository}, root)
+	require.NoError(t, err)
+	assert.True(t, saved)
+
+	err = os.RemoveAll(root)
+	require.NoError(t, err)
+
+	key, err = cache.Key("maven", nil, dir)
+	require.NoError(t, err)
+	restored, err = cache.Restore(ctx, bucket, cache.ObjectName("myorg", "myrepo", key), cache.ObjectPrefix("myorg", "myrepo", "maven"), root)
+	require.NoError(t, err)
+	assert.Equal(t, name, restored)
+
+	data, err := ioutil.ReadFile(filepath.Join(repository, "junit.jar"))
+	require.NoError(t, err)
+	assert.Equal(t, "junit", string(data))
+}
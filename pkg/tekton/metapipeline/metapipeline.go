@@ -50,6 +50,7 @@ type CRDCreationParameters struct {
 	ServiceAccount   string
 	Labels           []string
 	EnvVars          []string
+	Parameters       []string
 	DefaultImage     string
 	Apps             []jenkinsv1.App
 	VersionsDir      string
@@ -70,7 +71,7 @@ func CreateMetaPipelineCRDs(params CRDCreationParameters) (*tekton.CRDWrapper, e
 	if err != nil {
 		return nil, err
 	}
-	pipeline, tasks, structure, err := parsedPipeline.GenerateCRDs(params.PipelineName, params.BuildNumber, params.Namespace, params.PodTemplates, params.VersionsDir, nil, params.SourceDir, labels, params.DefaultImage)
+	pipeline, tasks, structure, err := parsedPipeline.GenerateCRDs(params.PipelineName, params.BuildNumber, params.Namespace, params.PodTemplates, params.VersionsDir, nil, params.SourceDir, labels, params.DefaultImage, "")
 	if err != nil {
 		return nil, err
 	}
@@ -224,6 +225,10 @@ func stepCreateTektonCRDs(params CRDCreationParameters) syntax.Step {
 	for _, e := range params.EnvVars {
 		args = append(args, "--env", e)
 	}
+	for _, p := range params.Parameters {
+		// the step is run by a shell so quote the values which may contain spaces
+		args = append(args, "--param", syntax.ShellQuote(p))
+	}
 	step := syntax.Step{
 		Name:      createTektonCRDsStepName,
 		Comment:   "Pipeline step to create the Tekton CRDs for the actual pipeline run",
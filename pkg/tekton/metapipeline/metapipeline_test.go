@@ -92,6 +92,19 @@ var _ = Describe("Meta pipeline", func() {
 			})
 		})
 
+		Context("with parameters", func() {
+			JustBeforeEach(func() {
+				testParams.Parameters = []string{"TARGET=production", "MESSAGE=it's a hotfix"}
+				actualCRDs, actualStdout, actualError = createMetaPipeline(testParams)
+			})
+
+			It("should pass the quoted parameters to step create task", func() {
+				Expect(actualError).Should(BeNil())
+				step := actualCRDs.Tasks()[0].Spec.Steps[3]
+				Expect(step.Args[0]).Should(HaveSuffix(`--env SOME_VAR=SOME_VAL --param 'TARGET=production' --param 'MESSAGE=it'\''s a hotfix'`))
+			})
+		})
+
 		Context("with extending App missing required metadata", func() {
 			JustBeforeEach(func() {
 				testApp := jenkinsv1.App{
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/tekton/syntax"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Start creates a Pod and a headless Service for each of the services of the group. The Service has the host name of
+// the service so that the steps can reach it. If an owner is given, such as the Pod of the Task, the resources are
+// garbage collected when it is deleted. Returns the names of the Pods
+func Start(kubeClient kubernetes.Interface, ns string, group string, owner *metav1.OwnerReference, services []syntax.Service) ([]string, error) {
+	var names []string
+	for _, s := range services {
+		name := syntax.ServiceHostName(group, s.Name)
+		labels := map[string]string{
+			syntax.LabelServiceGroup: group,
+			syntax.LabelServiceName:  s.Name,
+		}
+		meta := metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ns,
+			Labels:    labels,
+		}
+		if owner != nil {
+			meta.OwnerReferences = []metav1.OwnerReference{*owner}
+		}
+		container := corev1.Container{
+			Name:           s.Name,
+			Image:          s.Image,
+			Env:            s.Env,
+			ReadinessProbe: s.ReadinessProbe,
+		}
+		svc := &corev1.Service{
+			ObjectMeta: meta,
+			Spec: corev1.ServiceSpec{
+				ClusterIP: corev1.ClusterIPNone,
+				Selector:  labels,
+			},
+		}
+		for _, p := range s.Ports {
+			container.Ports = append(container.Ports, corev1.ContainerPort{
+				ContainerPort: p,
+			})
+			svc.Spec.Ports = append(svc.Spec.Ports, corev1.ServicePort{
+				Name:       fmt.Sprintf("port-%d", p),
+				Port:       p,
+				TargetPort: intstr.FromInt(int(p)),
+			})
+		}
+		pod := &corev1.Pod{
+			ObjectMeta: meta,
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{container},
+			},
+		}
+		_, err := kubeClient.CoreV1().Pods(ns).Create(pod)
+		if err != nil {
+			return names, errors.Wrapf(err, "failed to create Pod %s for service %s", name, s.Name)
+		}
+		names = append(names, name)
+		_, err = kubeClient.CoreV1().Services(ns).Create(svc)
+		if err != nil {
+			return names, errors.Wrapf(err, "failed to create Service %s for service %s", name, s.Name)
+		}
+		log.Logger().Infof("started service %s as %s", util.ColorInfo(s.Name), util.ColorInfo(name))
+	}
+	return names, nil
+}
+
+// WaitForReady waits for the Pods of the services to be ready
+func WaitForReady(kubeClient kubernetes.Interface, ns string, names []string, timeout time.Duration) error {
+	for _, name := range names {
+		err := kube.WaitForPodNameToBeReady(kubeClient, ns, name, timeout)
+		if err != nil {
+			return errors.Wrapf(err, "service Pod %s was not ready within %s", name, timeout.String())
+		}
+	}
+	return nil
+}
+
+// Stop deletes the Pods and Services of the services of the group
+func Stop(kubeClient kubernetes.Interface, ns string, group string) error {
+	selector := syntax.LabelServiceGroup + "=" + group
+	listOptions := metav1.ListOptions{LabelSelector: selector}
+	svcList, err := kubeClient.CoreV1().Services(ns).List(listOptions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list Services with selector %s", selector)
+	}
+	for _, svc := range svcList.Items {
+		err = kubeClient.CoreV1().Services(ns).Delete(svc.Name, &metav1.DeleteOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to delete Service %s", svc.Name)
+		}
+	}
+	podList, err := kubeClient.CoreV1().Pods(ns).List(listOptions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list Pods with selector %s", selector)
+	}
+	for _, pod := range podList.Items {
+		err = kubeClient.CoreV1().Pods(ns).Delete(pod.Name, &metav1.DeleteOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to delete Pod %s", pod.Name)
+		}
+		log.Logger().Infof("stopped service %s", util.ColorInfo(pod.Labels[syntax.LabelServiceName]))
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/tekton/services"
+	"github.com/jenkins-x/jx/pkg/tekton/syntax"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestStartAndStopServices(t *testing.T) {
+	t.Parallel()
+
+	ns := "jx"
+	group := "jenkins-x-myapp-master-build-1"
+	kubeClient := fake.NewSimpleClientset()
+	owner := &metav1.OwnerReference{
+		APIVersion: "v1",
+		Kind:       "Pod",
+		Name:       "build-pod",
+		UID:        "1234",
+	}
+
+	names, err := services.Start(kubeClient, ns, group, owner, []syntax.Service{
+		{
+			Name:  "postgres",
+			Image: "postgres:11",
+			Env:   []corev1.EnvVar{{Name: "POSTGRES_PASSWORD", Value: "secret"}},
+			Ports: []int32{5432},
+		},
+		{
+			Name:  "redis",
+			Image: "redis",
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, names, 2)
+	assert.Equal(t, syntax.ServiceHostName(group, "postgres"), names[0])
+
+	pod, err := kubeClient.CoreV1().Pods(ns).Get(names[0], metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "postgres:11", pod.Spec.Containers[0].Image)
+	assert.Equal(t, int32(5432), pod.Spec.Containers[0].Ports[0].ContainerPort)
+	assert.Equal(t, []metav1.OwnerReference{*owner}, pod.OwnerReferences)
+
+	svc, err := kubeClient.CoreV1().Services(ns).Get(names[0], metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, corev1.ClusterIPNone, svc.Spec.ClusterIP)
+	assert.Equal(t, pod.Labels, svc.Spec.Selector)
+	assert.Equal(t, int32(5432), svc.Spec.Ports[0].Port)
+
+	_, err = services.Start(kubeClient, ns, "another-build", nil, []syntax.Service{{Name: "postgres", Image: "postgres"}})
+	require.NoError(t, err)
+
+	err = services.Stop(kubeClient, ns, group)
+	require.NoError(t, err)
+
+	pods, err := kubeClient.CoreV1().Pods(ns).List(metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, pods.Items, 1)
+	assert.Equal(t, "another-build", pods.Items[0].Labels[syntax.LabelServiceGroup])
+
+	svcs, err := kubeClient.CoreV1().Services(ns).List(metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, svcs.Items, 1)
+}
@@ -0,0 +1,148 @@
+package syntax
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/knative/pkg/apis"
+	tektonv1alpha1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// CacheVolumeMountPath is where the PersistentVolumeClaim of a cache is mounted in the cache steps
+	CacheVolumeMountPath = "/jx-cache"
+
+	cacheStorageVolumeName = "jx-cache-storage"
+)
+
+var validCacheKey = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`).MatchString
+
+// Cache defines files, such as a Maven repository or Go modules, which are restored before the steps of each stage and
+// saved after them if they succeed. Caches are stored in the team's storage location for "cache" or a
+// PersistentVolumeClaim
+type Cache struct {
+	// Key is the prefix of the key of the cache such as "maven"
+	Key string `json:"key"`
+	// Files are lock files, such as "pom.xml" or "go.sum", whose contents are hashed into the key so that the cache
+	// is saved again when they change
+	Files []string `json:"files,omitempty"`
+	// Paths are the directories to save and restore, either absolute or relative to the working directory
+	Paths []string `json:"paths"`
+	// ClaimName is the name of a PersistentVolumeClaim to store the cache in instead of the team's storage location
+	ClaimName string `json:"claimName,omitempty"`
+}
+
+func validateCache(c *Cache) *apis.FieldError {
+	if c == nil {
+		return nil
+	}
+	if c.Key == "" {
+		return apis.ErrMissingField("key")
+	}
+	if !validCacheKey(c.Key) {
+		return &apis.FieldError{
+			Message: fmt.Sprintf("invalid key %s, must only contain letters, digits, '.', '_' or '-'", c.Key),
+			Paths:   []string{"key"},
+		}
+	}
+	if len(c.Paths) == 0 {
+		return apis.ErrMissingField("paths")
+	}
+	for i, p := range c.Paths {
+		if strings.TrimSpace(p) == "" {
+			return apis.ErrMissingField(fmt.Sprintf("paths[%d]", i))
+		}
+	}
+	for i, f := range c.Files {
+		if strings.TrimSpace(f) == "" {
+			return apis.ErrMissingField(fmt.Sprintf("files[%d]", i))
+		}
+	}
+	return nil
+}
+
+func cacheArgs(c *Cache, command string) []string {
+	args := []string{"step", "cache", command, "--key", c.Key}
+	for _, f := range c.Files {
+		args = append(args, "--file", f)
+	}
+	if c.ClaimName != "" {
+		args = append(args, "--bucket-url", "file://"+CacheVolumeMountPath)
+	}
+	return args
+}
+
+// addRestoreCacheStep appends a step which restores the cache
+func addRestoreCacheStep(t *tektonv1alpha1.Task, c *Cache, image string, workingDir string, env []corev1.EnvVar) {
+	t.Spec.Steps = append(t.Spec.Steps, corev1.Container{
+		Name:       uniqueStepName(t, MangleToRfc1035Label("restore-cache-"+c.Key, "")),
+		Image:      image,
+		Command:    []string{"jx"},
+		Args:       cacheArgs(c, "restore"),
+		WorkingDir: workingDir,
+		Env:        env,
+	})
+}
+
+// addSaveCacheStep appends a step which saves the cache
+func addSaveCacheStep(t *tektonv1alpha1.Task, c *Cache, image string, workingDir string, env []corev1.EnvVar) {
+	args := cacheArgs(c, "save")
+	for _, p := range c.Paths {
+		args = append(args, "--path", p)
+	}
+	t.Spec.Steps = append(t.Spec.Steps, corev1.Container{
+		Name:       uniqueStepName(t, MangleToRfc1035Label("save-cache-"+c.Key, "")),
+		Image:      image,
+		Command:    []string{"jx"},
+		Args:       args,
+		WorkingDir: workingDir,
+		Env:        env,
+	})
+}
+
+// addCacheVolumes shares the absolute cache paths outside of the workspace between all the steps of the Task via
+// emptyDir volumes so that the cache steps can restore and save them, and mounts the PersistentVolumeClaim of the cache
+// in the cache steps
+func addCacheVolumes(t *tektonv1alpha1.Task, c *Cache) {
+	for _, p := range c.Paths {
+		if !filepath.IsAbs(p) || strings.HasPrefix(filepath.Clean(p)+"/", WorkingDirRoot+"/") {
+			continue
+		}
+		name := MangleToRfc1035Label("cache-"+p, "")
+		t.Spec.Volumes = append(t.Spec.Volumes, corev1.Volume{
+			Name: name,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		})
+		for i := range t.Spec.Steps {
+			t.Spec.Steps[i].VolumeMounts = append(t.Spec.Steps[i].VolumeMounts, corev1.VolumeMount{
+				Name:      name,
+				MountPath: p,
+			})
+		}
+	}
+	if c.ClaimName == "" {
+		return
+	}
+	t.Spec.Volumes = append(t.Spec.Volumes, corev1.Volume{
+		Name: cacheStorageVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: c.ClaimName,
+			},
+		},
+	})
+	for i := range t.Spec.Steps {
+		s := &t.Spec.Steps[i]
+		if len(s.Args) > 2 && s.Args[0] == "step" && s.Args[1] == "cache" {
+			s.VolumeMounts = append(s.VolumeMounts, corev1.VolumeMount{
+				Name:      cacheStorageVolumeName,
+				MountPath: CacheVolumeMountPath,
+			})
+		}
+	}
+}
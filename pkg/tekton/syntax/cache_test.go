@@ -0,0 +1,79 @@
+package syntax_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/tekton/syntax"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestCacheSteps(t *testing.T) {
+	t.Parallel()
+
+	pipeline := &syntax.ParsedPipeline{
+		Agent: &syntax.Agent{Image: "some-image"},
+		Options: &syntax.RootOptions{
+			Cache: &syntax.Cache{
+				Key:   "maven",
+				Files: []string{"pom.xml"},
+				Paths: []string{"/root/.m2/repository"},
+			},
+		},
+		Stages: []syntax.Stage{
+			{
+				Name:  "Build",
+				Steps: []syntax.Step{{Command: "mvn install"}},
+			},
+			{
+				Name: "Frontend",
+				Options: &syntax.StageOptions{
+					RootOptions: &syntax.RootOptions{
+						Cache: &syntax.Cache{
+							Key:       "npm",
+							Files:     []string{"package-lock.json"},
+							Paths:     []string{"node_modules"},
+							ClaimName: "npm-cache",
+						},
+					},
+				},
+				Steps: []syntax.Step{{Command: "npm ci"}},
+			},
+		},
+	}
+	require.Nil(t, pipeline.Validate(context.Background()))
+
+	_, tasks, _, err := pipeline.GenerateCRDs("somepipeline", "1", "jx", nil, filepath.Join("test_data", "stable_versions"), nil, "source", nil, "", "")
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+
+	build := tasks[0]
+	var names []string
+	for _, s := range build.Spec.Steps {
+		names = append(names, s.Name)
+	}
+	assert.Equal(t, []string{"git-merge", "restore-cache-maven", "step2", "save-cache-maven"}, names)
+	assert.Equal(t, []string{"step", "cache", "restore", "--key", "maven", "--file", "pom.xml"}, build.Spec.Steps[1].Args)
+	assert.Equal(t, []string{"step", "cache", "save", "--key", "maven", "--file", "pom.xml", "--path", "/root/.m2/repository"}, build.Spec.Steps[3].Args)
+	require.Len(t, build.Spec.Volumes, 1)
+	assert.NotNil(t, build.Spec.Volumes[0].EmptyDir)
+	for _, s := range build.Spec.Steps {
+		assert.Contains(t, s.VolumeMounts, corev1.VolumeMount{Name: build.Spec.Volumes[0].Name, MountPath: "/root/.m2/repository"}, "step %s", s.Name)
+	}
+
+	frontend := tasks[1]
+	names = nil
+	for _, s := range frontend.Spec.Steps {
+		names = append(names, s.Name)
+	}
+	assert.Equal(t, []string{"restore-cache-npm", "step2", "save-cache-npm"}, names)
+	assert.Equal(t, []string{"step", "cache", "restore", "--key", "npm", "--file", "package-lock.json", "--bucket-url", "file://" + syntax.CacheVolumeMountPath}, frontend.Spec.Steps[0].Args)
+	require.Len(t, frontend.Spec.Volumes, 1)
+	require.NotNil(t, frontend.Spec.Volumes[0].PersistentVolumeClaim)
+	assert.Equal(t, "npm-cache", frontend.Spec.Volumes[0].PersistentVolumeClaim.ClaimName)
+	assert.Len(t, frontend.Spec.Steps[0].VolumeMounts, 1)
+	assert.Empty(t, frontend.Spec.Steps[1].VolumeMounts, "relative cache paths are already shared via the workspace")
+}
@@ -0,0 +1,134 @@
+package syntax
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/knative/pkg/apis"
+	tektonv1alpha1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// approverNameRegex matches the user names which can be approvers. Users are compared as valid Kubernetes names so
+// longer names or other characters could match different users
+var approverNameRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9._-]{0,62}$`)
+
+// Input is a stage which pauses the pipeline until a user approves it continuing, either via
+// 'jx approve pipeline' or a ChatOps comment. Rejecting the input or it timing out aborts the pipeline
+type Input struct {
+	// Message is displayed to the approvers
+	Message string `json:"message,omitempty"`
+	// Approvers are the user names of the users who can approve or reject the input, ignoring case. If empty any user can
+	Approvers []string `json:"approvers,omitempty"`
+	// Timeout is how long to wait for a decision before aborting the pipeline
+	Timeout *Timeout `json:"timeout,omitempty"`
+}
+
+func validateInput(s Stage) *apis.FieldError {
+	i := s.Input
+	if i == nil {
+		return nil
+	}
+	if len(s.Services) > 0 {
+		return &apis.FieldError{
+			Message: "services are not supported on input stages",
+			Paths:   []string{"services"},
+		}
+	}
+	if s.Options != nil && (s.Options.Stash != nil || s.Options.Unstash != nil || s.Options.Cache != nil) {
+		return &apis.FieldError{
+			Message: "stash, unstash and cache are not supported on input stages",
+			Paths:   []string{"options"},
+		}
+	}
+	for _, approver := range i.Approvers {
+		if strings.TrimSpace(approver) == "" {
+			return (&apis.FieldError{
+				Message: "approvers cannot be blank",
+				Paths:   []string{"approvers"},
+			}).ViaField("input")
+		}
+		if !approverNameRegex.MatchString(approver) {
+			return (&apis.FieldError{
+				Message: fmt.Sprintf("approver %s is not a valid user name. User names start with a letter, contain only letters, digits, '.', '_' or '-' and are at most 63 characters", approver),
+				Paths:   []string{"approvers"},
+			}).ViaField("input")
+		}
+	}
+	return validateTimeout(i.Timeout).ViaField("timeout").ViaField("input")
+}
+
+// inputStageToTask creates the Task of an input stage, which has a single step waiting for the decision
+func inputStageToTask(s Stage, stageName string, taskName string, namespace string, sourceDir string, jxImage string, env []corev1.EnvVar, defaultTaskSpec *tektonv1alpha1.TaskSpec, labels map[string]string) (*tektonv1alpha1.Task, error) {
+	t := &tektonv1alpha1.Task{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: TektonAPIVersion,
+			Kind:       "Task",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      taskName,
+			Labels:    util.MergeMaps(labels, map[string]string{LabelStageName: s.stageLabelName()}),
+		},
+	}
+	if defaultTaskSpec != nil {
+		t.Spec = *defaultTaskSpec
+	}
+	t.SetDefaults(context.Background())
+
+	t.Spec.Inputs = &tektonv1alpha1.Inputs{
+		Resources: []tektonv1alpha1.TaskResource{
+			{
+				Name:       "workspace",
+				TargetPath: sourceDir,
+				Type:       tektonv1alpha1.PipelineResourceTypeGit,
+			},
+		},
+	}
+	t.Spec.Outputs = &tektonv1alpha1.Outputs{
+		Resources: []tektonv1alpha1.TaskResource{
+			{
+				Name: "workspace",
+				Type: tektonv1alpha1.PipelineResourceTypeGit,
+			},
+		},
+	}
+
+	args := []string{"step", "input", "--stage", stageName}
+	if s.Input.Message != "" {
+		args = append(args, "--message", s.Input.Message)
+	}
+	for _, approver := range s.Input.Approvers {
+		args = append(args, "--approver", approver)
+	}
+	if s.Input.Timeout != nil {
+		d, err := s.Input.Timeout.ToDuration()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "--timeout", d.Duration.String())
+	}
+	t.Spec.Steps = append(t.Spec.Steps, corev1.Container{
+		Name:       uniqueStepName(t, "input"),
+		Image:      jxImage,
+		Command:    []string{"jx"},
+		Args:       args,
+		WorkingDir: stageWorkingDir(sourceDir, nil),
+		Env:        env,
+	})
+	return t, nil
+}
+
+// activityStageName returns the name of the stage in the PipelineActivity, which includes the names of its
+// enclosing stages
+func activityStageName(s Stage, enclosingStage *transformedStage) string {
+	names := []string{s.Name}
+	for e := enclosingStage; e != nil; e = e.EnclosingStage {
+		names = append([]string{e.Stage.Name}, names...)
+	}
+	return strings.NewReplacer("-", " ").Replace(strings.Join(names, " / "))
+}
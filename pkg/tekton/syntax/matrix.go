@@ -0,0 +1,211 @@
+package syntax
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/knative/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Matrix defines axes of values for which a stage is run in parallel, once for each combination of values which is
+// not excluded. The value of each axis is available to the steps as an environment variable named after the axis and
+// can be used in the images of the stage and its steps, e.g. "golang:${GO_VERSION}"
+type Matrix struct {
+	Axes []MatrixAxis `json:"axes"`
+	// Exclude lists the combinations of axis values which should not be run. A combination is excluded if all the
+	// values of one of the excludes match
+	Exclude []map[string]string `json:"exclude,omitempty"`
+}
+
+// MatrixAxis is a variable name and the values the stage is run with
+type MatrixAxis struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+var validMatrixAxisName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`).MatchString
+
+// Combinations returns the combinations of axis values which are not excluded, in the order of the axes
+func (m *Matrix) Combinations() [][]corev1.EnvVar {
+	if m == nil || len(m.Axes) == 0 {
+		return nil
+	}
+	combinations := [][]corev1.EnvVar{{}}
+	for _, axis := range m.Axes {
+		var expanded [][]corev1.EnvVar
+		for _, c := range combinations {
+			for _, v := range axis.Values {
+				combination := append(append([]corev1.EnvVar{}, c...), corev1.EnvVar{Name: axis.Name, Value: v})
+				expanded = append(expanded, combination)
+			}
+		}
+		combinations = expanded
+	}
+	var answer [][]corev1.EnvVar
+	for _, c := range combinations {
+		if !m.isExcluded(c) {
+			answer = append(answer, c)
+		}
+	}
+	return answer
+}
+
+func (m *Matrix) isExcluded(combination []corev1.EnvVar) bool {
+	values := map[string]string{}
+	for _, e := range combination {
+		values[e.Name] = e.Value
+	}
+	for _, exclude := range m.Exclude {
+		matches := len(exclude) > 0
+		for k, v := range exclude {
+			if values[k] != v {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+// matrixStageName returns the name of the stage for a combination of axis values such as "Test (1.12, alpine)"
+func matrixStageName(name string, combination []corev1.EnvVar) string {
+	var values []string
+	for _, e := range combination {
+		values = append(values, e.Value)
+	}
+	return fmt.Sprintf("%s (%s)", name, strings.Join(values, ", "))
+}
+
+// expandMatrixStages replaces any stages with a matrix with a stage of the same name which runs a copy of the stage
+// in parallel for each combination of axis values
+func expandMatrixStages(stages []Stage) []Stage {
+	var answer []Stage
+	for _, s := range stages {
+		if len(s.Stages) > 0 {
+			s.Stages = expandMatrixStages(s.Stages)
+		}
+		if len(s.Parallel) > 0 {
+			s.Parallel = expandMatrixStages(s.Parallel)
+		}
+		if s.Matrix == nil {
+			answer = append(answer, s)
+			continue
+		}
+		parent := Stage{
+			Name: s.Name,
+			When: s.When,
+		}
+		for _, combination := range s.Matrix.Combinations() {
+			parent.Parallel = append(parent.Parallel, matrixStage(s, combination))
+		}
+		answer = append(answer, parent)
+	}
+	return answer
+}
+
+func matrixStage(s Stage, combination []corev1.EnvVar) Stage {
+	child := *s.DeepCopy()
+	child.Name = matrixStageName(s.Name, combination)
+	child.Matrix = nil
+	child.When = nil
+	child.Env = append(append([]corev1.EnvVar{}, child.GetEnv()...), combination...)
+	child.Environment = nil
+	if child.Agent != nil {
+		child.Agent.Image = replaceMatrixVariables(child.Agent.Image, combination)
+	}
+	child.Steps = replaceMatrixVariablesInSteps(child.Steps, combination)
+	return child
+}
+
+func replaceMatrixVariablesInSteps(steps []Step, combination []corev1.EnvVar) []Step {
+	for i := range steps {
+		step := &steps[i]
+		step.Image = replaceMatrixVariables(step.Image, combination)
+		if step.Agent != nil {
+			step.Agent.Image = replaceMatrixVariables(step.Agent.Image, combination)
+		}
+		if step.Loop != nil {
+			step.Loop.Steps = replaceMatrixVariablesInSteps(step.Loop.Steps, combination)
+		}
+	}
+	return steps
+}
+
+func replaceMatrixVariables(text string, combination []corev1.EnvVar) string {
+	for _, e := range combination {
+		text = strings.Replace(text, "${"+e.Name+"}", e.Value, -1)
+	}
+	return text
+}
+
+func validateMatrix(s Stage, inParallel bool) *apis.FieldError {
+	m := s.Matrix
+	if m == nil {
+		return nil
+	}
+	if inParallel {
+		return &apis.FieldError{
+			Message: "matrix is not supported on stages within a parallel stage",
+			Paths:   []string{"matrix"},
+		}
+	}
+	if len(s.Steps) == 0 {
+		return &apis.FieldError{
+			Message: "matrix is only supported on stages with steps",
+			Paths:   []string{"matrix"},
+		}
+	}
+	if s.Options != nil && s.Options.Stash != nil {
+		return &apis.FieldError{
+			Message: "stash is not supported on stages with a matrix as each combination would create the same stash",
+			Paths:   []string{"options.stash"},
+		}
+	}
+	if len(m.Axes) == 0 {
+		return apis.ErrMissingField("axes").ViaField("matrix")
+	}
+	axes := map[string][]string{}
+	for i, axis := range m.Axes {
+		if !validMatrixAxisName(axis.Name) {
+			return (&apis.FieldError{
+				Message: fmt.Sprintf("invalid axis name %s, must be a valid environment variable name", axis.Name),
+				Paths:   []string{"name"},
+			}).ViaFieldIndex("axes", i).ViaField("matrix")
+		}
+		if _, exists := axes[axis.Name]; exists {
+			return (&apis.FieldError{
+				Message: fmt.Sprintf("axis %s is defined more than once", axis.Name),
+				Paths:   []string{"name"},
+			}).ViaFieldIndex("axes", i).ViaField("matrix")
+		}
+		if len(axis.Values) == 0 {
+			return apis.ErrMissingField("values").ViaFieldIndex("axes", i).ViaField("matrix")
+		}
+		axes[axis.Name] = axis.Values
+	}
+	for i, exclude := range m.Exclude {
+		if len(exclude) == 0 {
+			return apis.ErrMissingField(fmt.Sprintf("exclude[%d]", i)).ViaField("matrix")
+		}
+		for k := range exclude {
+			if _, exists := axes[k]; !exists {
+				return (&apis.FieldError{
+					Message: fmt.Sprintf("unknown axis %s", k),
+					Paths:   []string{k},
+				}).ViaFieldIndex("exclude", i).ViaField("matrix")
+			}
+		}
+	}
+	if len(m.Combinations()) == 0 {
+		return &apis.FieldError{
+			Message: "all the combinations of the matrix are excluded",
+			Paths:   []string{"matrix"},
+		}
+	}
+	return nil
+}
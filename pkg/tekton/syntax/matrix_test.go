@@ -0,0 +1,92 @@
+package syntax_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/tekton/syntax"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestMatrixCombinations(t *testing.T) {
+	t.Parallel()
+
+	matrix := &syntax.Matrix{
+		Axes: []syntax.MatrixAxis{
+			{Name: "GO_VERSION", Values: []string{"1.11", "1.12"}},
+			{Name: "DB", Values: []string{"postgres", "mysql"}},
+		},
+		Exclude: []map[string]string{
+			{"GO_VERSION": "1.11", "DB": "mysql"},
+		},
+	}
+	assert.Equal(t, [][]corev1.EnvVar{
+		{{Name: "GO_VERSION", Value: "1.11"}, {Name: "DB", Value: "postgres"}},
+		{{Name: "GO_VERSION", Value: "1.12"}, {Name: "DB", Value: "postgres"}},
+		{{Name: "GO_VERSION", Value: "1.12"}, {Name: "DB", Value: "mysql"}},
+	}, matrix.Combinations())
+
+	var noMatrix *syntax.Matrix
+	assert.Empty(t, noMatrix.Combinations())
+}
+
+func TestMatrixStagesGenerateParallelTasks(t *testing.T) {
+	t.Parallel()
+
+	pipeline := &syntax.ParsedPipeline{
+		Agent: &syntax.Agent{Image: "some-image"},
+		Stages: []syntax.Stage{
+			{
+				Name:  "Build",
+				Steps: []syntax.Step{{Command: "make build"}},
+			},
+			{
+				Name:  "Test",
+				Agent: &syntax.Agent{Image: "golang:${GO_VERSION}"},
+				Matrix: &syntax.Matrix{
+					Axes: []syntax.MatrixAxis{
+						{Name: "GO_VERSION", Values: []string{"1.11", "1.12"}},
+						{Name: "DB", Values: []string{"postgres", "mysql"}},
+					},
+					Exclude: []map[string]string{
+						{"GO_VERSION": "1.11", "DB": "mysql"},
+					},
+				},
+				Steps: []syntax.Step{{Command: "make test"}},
+			},
+		},
+	}
+	require.Nil(t, pipeline.Validate(context.Background()))
+
+	_, tasks, structure, err := pipeline.GenerateCRDs("somepipeline", "1", "jx", nil, filepath.Join("test_data", "stable_versions"), nil, "source", nil, "", "")
+	require.NoError(t, err)
+
+	var taskNames []string
+	for _, task := range tasks {
+		taskNames = append(taskNames, task.Name)
+	}
+	assert.Equal(t, []string{
+		"somepipeline-build-1",
+		"somepipeline-test-1-11-postgres-1",
+		"somepipeline-test-1-12-postgres-1",
+		"somepipeline-test-1-12-mysql-1",
+	}, taskNames)
+
+	test := tasks[3]
+	require.Len(t, test.Spec.Steps, 1)
+	assert.Equal(t, "golang:1.12", test.Spec.Steps[0].Image)
+	assert.Contains(t, test.Spec.Steps[0].Env, corev1.EnvVar{Name: "GO_VERSION", Value: "1.12"})
+	assert.Contains(t, test.Spec.Steps[0].Env, corev1.EnvVar{Name: "DB", Value: "mysql"})
+
+	parent := structure.GetStage("Test")
+	require.NotNil(t, parent)
+	assert.Equal(t, []string{"Test (1.11, postgres)", "Test (1.12, postgres)", "Test (1.12, mysql)"}, parent.Parallel)
+	child := structure.GetStage("Test (1.12, mysql)")
+	require.NotNil(t, child)
+	require.NotNil(t, child.Parent)
+	assert.Equal(t, "Test", *child.Parent)
+	assert.Equal(t, "somepipeline-test-1-12-mysql-1", *child.TaskRef)
+}
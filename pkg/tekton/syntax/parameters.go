@@ -0,0 +1,193 @@
+package syntax
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/knative/pkg/apis"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ParameterType is the type of the value of a pipeline parameter
+type ParameterType string
+
+const (
+	// ParameterTypeString a parameter which can be any string
+	ParameterTypeString ParameterType = "string"
+	// ParameterTypeBool a parameter which is either true or false
+	ParameterTypeBool ParameterType = "bool"
+	// ParameterTypeChoice a parameter which is one of a list of choices
+	ParameterTypeChoice ParameterType = "choice"
+)
+
+// ParameterTypes the types of pipeline parameters
+var ParameterTypes = []string{string(ParameterTypeString), string(ParameterTypeBool), string(ParameterTypeChoice)}
+
+var parameterNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Parameter is a value which can be set when a pipeline is triggered, such as via 'jx start pipeline --param k=v'.
+// The value of the parameter is exposed to all the steps of the pipeline as an environment variable of the same name
+type Parameter struct {
+	// Name is the name of the environment variable the value is exposed as
+	Name string `json:"name"`
+	// Type is one of string, bool or choice. Defaults to string
+	Type ParameterType `json:"type,omitempty"`
+	// Description is displayed to the users triggering the pipeline
+	Description string `json:"description,omitempty"`
+	// Default is the value if none is given. Choice parameters default to their first choice
+	Default string `json:"default,omitempty"`
+	// Choices are the allowed values of a choice parameter
+	Choices []string `json:"choices,omitempty"`
+}
+
+// GetType returns the type of the parameter, defaulting to string
+func (p Parameter) GetType() ParameterType {
+	if p.Type == "" {
+		return ParameterTypeString
+	}
+	return p.Type
+}
+
+// DefaultValue returns the value of the parameter when none is given
+func (p Parameter) DefaultValue() string {
+	if p.Default != "" {
+		return p.Default
+	}
+	switch p.GetType() {
+	case ParameterTypeBool:
+		return "false"
+	case ParameterTypeChoice:
+		if len(p.Choices) > 0 {
+			return p.Choices[0]
+		}
+	}
+	return ""
+}
+
+// ValidateValue returns the normalised value of the parameter or an error if it is not valid for its type
+func (p Parameter) ValidateValue(value string) (string, error) {
+	switch p.GetType() {
+	case ParameterTypeBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", fmt.Errorf("the value %s of parameter %s is not a bool", value, p.Name)
+		}
+		return strconv.FormatBool(b), nil
+	case ParameterTypeChoice:
+		if util.StringArrayIndex(p.Choices, value) < 0 {
+			return "", fmt.Errorf("the value %s of parameter %s is not one of %s", value, p.Name, strings.Join(p.Choices, ", "))
+		}
+	}
+	return value, nil
+}
+
+// ParseParameterValues parses the values of parameters of the form 'name=value', the value may contain '='
+func ParseParameterValues(values []string) (map[string]string, error) {
+	answer := map[string]string{}
+	for _, v := range values {
+		parts := strings.SplitN(v, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("the parameter value %s is not of the form name=value", v)
+		}
+		answer[parts[0]] = parts[1]
+	}
+	return answer, nil
+}
+
+func validateParameters(parameters []Parameter) *apis.FieldError {
+	names := map[string]bool{}
+	for i, p := range parameters {
+		if err := validateParameter(p); err != nil {
+			return err.ViaFieldIndex("parameters", i)
+		}
+		if names[p.Name] {
+			return (&apis.FieldError{
+				Message: fmt.Sprintf("parameter %s is defined more than once", p.Name),
+				Paths:   []string{"name"},
+			}).ViaFieldIndex("parameters", i)
+		}
+		names[p.Name] = true
+	}
+	return nil
+}
+
+func validateParameter(p Parameter) *apis.FieldError {
+	if p.Name == "" {
+		return apis.ErrMissingField("name")
+	}
+	if !parameterNameRegex.MatchString(p.Name) {
+		return &apis.FieldError{
+			Message: fmt.Sprintf("%s is not a valid environment variable name", p.Name),
+			Paths:   []string{"name"},
+		}
+	}
+	if util.StringArrayIndex(ParameterTypes, string(p.GetType())) < 0 {
+		return &apis.FieldError{
+			Message: fmt.Sprintf("%s is not a valid parameter type. Valid types are %s", string(p.Type), strings.Join(ParameterTypes, ", ")),
+			Paths:   []string{"type"},
+		}
+	}
+	if p.GetType() == ParameterTypeChoice && len(p.Choices) == 0 {
+		return apis.ErrMissingField("choices")
+	}
+	if p.GetType() != ParameterTypeChoice && len(p.Choices) > 0 {
+		return &apis.FieldError{
+			Message: "choices can only be set on choice parameters",
+			Paths:   []string{"choices"},
+		}
+	}
+	if p.Default != "" {
+		if _, err := p.ValidateValue(p.Default); err != nil {
+			return &apis.FieldError{
+				Message: err.Error(),
+				Paths:   []string{"default"},
+			}
+		}
+	}
+	return nil
+}
+
+// ApplyParameters resolves the parameters of the pipeline from the given values and their defaults, adding them to
+// the environment of the pipeline. The resolved values are returned in the order the parameters are defined
+func (j *ParsedPipeline) ApplyParameters(values map[string]string) ([]corev1.EnvVar, error) {
+	var unknown []string
+	for name := range values {
+		found := false
+		for _, p := range j.Parameters {
+			if p.Name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("the pipeline has no parameters named %s", strings.Join(unknown, ", "))
+	}
+
+	var resolved []corev1.EnvVar
+	for _, p := range j.Parameters {
+		value, ok := values[p.Name]
+		if !ok {
+			value = p.DefaultValue()
+		}
+		value, err := p.ValidateValue(value)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid pipeline parameter")
+		}
+		resolved = append(resolved, corev1.EnvVar{Name: p.Name, Value: value})
+	}
+	if len(resolved) > 0 {
+		j.Env = scopedEnv(resolved, j.GetEnv())
+		j.Environment = nil
+	}
+	return resolved, nil
+}
@@ -0,0 +1,85 @@
+package syntax_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/tekton/syntax"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func parameterisedPipeline() *syntax.ParsedPipeline {
+	return &syntax.ParsedPipeline{
+		Agent: &syntax.Agent{Image: "some-image"},
+		Env:   []corev1.EnvVar{{Name: "DRY_RUN", Value: "not-a-parameter"}, {Name: "OTHER", Value: "kept"}},
+		Parameters: []syntax.Parameter{
+			{Name: "TARGET", Type: syntax.ParameterTypeChoice, Choices: []string{"staging", "production"}},
+			{Name: "DRY_RUN", Type: syntax.ParameterTypeBool, Default: "true"},
+			{Name: "MESSAGE", Description: "A message for the release notes"},
+		},
+		Stages: []syntax.Stage{{
+			Name:  "Release",
+			Steps: []syntax.Step{{Command: "echo ${TARGET}"}},
+		}},
+	}
+}
+
+func TestApplyParametersDefaults(t *testing.T) {
+	t.Parallel()
+
+	pipeline := parameterisedPipeline()
+	require.Nil(t, pipeline.Validate(context.Background()))
+
+	resolved, err := pipeline.ApplyParameters(nil)
+	require.NoError(t, err)
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: "TARGET", Value: "staging"},
+		{Name: "DRY_RUN", Value: "true"},
+		{Name: "MESSAGE", Value: ""},
+	}, resolved)
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: "DRY_RUN", Value: "true"},
+		{Name: "MESSAGE", Value: ""},
+		{Name: "OTHER", Value: "kept"},
+		{Name: "TARGET", Value: "staging"},
+	}, pipeline.Env)
+}
+
+func TestApplyParametersValues(t *testing.T) {
+	t.Parallel()
+
+	pipeline := parameterisedPipeline()
+	resolved, err := pipeline.ApplyParameters(map[string]string{"TARGET": "production", "DRY_RUN": "0", "MESSAGE": "hotfix"})
+	require.NoError(t, err)
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: "TARGET", Value: "production"},
+		{Name: "DRY_RUN", Value: "false"},
+		{Name: "MESSAGE", Value: "hotfix"},
+	}, resolved)
+}
+
+func TestApplyParametersInvalidValues(t *testing.T) {
+	t.Parallel()
+
+	_, err := parameterisedPipeline().ApplyParameters(map[string]string{"TARGET": "qa"})
+	assert.EqualError(t, err, "invalid pipeline parameter: the value qa of parameter TARGET is not one of staging, production")
+
+	_, err = parameterisedPipeline().ApplyParameters(map[string]string{"DRY_RUN": "maybe"})
+	assert.EqualError(t, err, "invalid pipeline parameter: the value maybe of parameter DRY_RUN is not a bool")
+
+	_, err = parameterisedPipeline().ApplyParameters(map[string]string{"VERSION": "1.0.0", "ANSWER": "42"})
+	assert.EqualError(t, err, "the pipeline has no parameters named ANSWER, VERSION")
+}
+
+func TestParseParameterValues(t *testing.T) {
+	t.Parallel()
+
+	values, err := syntax.ParseParameterValues([]string{"TARGET=production", "QUERY=a=b", "EMPTY="})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"TARGET": "production", "QUERY": "a=b", "EMPTY": ""}, values)
+
+	_, err = syntax.ParseParameterValues([]string{"TARGET"})
+	assert.Error(t, err)
+}
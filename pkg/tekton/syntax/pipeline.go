@@ -39,6 +39,7 @@ const (
 type ParsedPipeline struct {
 	Agent      *Agent          `json:"agent,omitempty"`
 	Env        []corev1.EnvVar `json:"env,omitempty"`
+	Parameters []Parameter     `json:"parameters,omitempty"`
 	Options    *RootOptions    `json:"options,omitempty"`
 	Stages     []Stage         `json:"stages"`
 	Post       []Post          `json:"post,omitempty"`
@@ -116,6 +117,11 @@ type RootOptions struct {
 	// like CPU/RAM requests/limits, secrets, ports, etc. Some of these things will end up with native syntax approaches
 	// down the road.
 	ContainerOptions *corev1.Container `json:"containerOptions,omitempty"`
+	// Cache is restored before and saved after the steps of each stage unless the stage has its own cache
+	Cache *Cache `json:"cache,omitempty"`
+	// Services are started before and stopped after the steps of each stage, in addition to the services of the stage.
+	// They can only be set in the options of the pipeline as stages have their own services
+	Services []Service `json:"services,omitempty"`
 }
 
 // Stash defines files to be saved for use in a later stage, marked with a name
@@ -136,8 +142,9 @@ type Unstash struct {
 type StageOptions struct {
 	*RootOptions `json:",inline"`
 
-	// TODO: Not yet implemented in build-pipeline
-	Stash   *Stash   `json:"stash,omitempty"`
+	// Stash stores the matching files in the team's storage after the steps of the stage so later stages can use them
+	Stash *Stash `json:"stash,omitempty"`
+	// Unstash fetches the files of a stash from an earlier stage before the steps of the stage
 	Unstash *Unstash `json:"unstash,omitempty"`
 
 	Workspace *string `json:"workspace,omitempty"`
@@ -172,11 +179,21 @@ type Step struct {
 	// env allows defining per-step environment variables
 	Env []corev1.EnvVar `json:"env,omitempty"`
 
+	// when allows the step to be skipped unless its conditions are met
+	When *When `json:"when,omitempty"`
+
+	// timeout fails the step if it runs for longer, each attempt of the step has its own timeout. The image of the step
+	// must contain the timeout command, as provided by coreutils or busybox
+	Timeout *Timeout `json:"timeout,omitempty"`
+	// retry is the number of times to retry the step if it fails
+	Retry int8 `json:"retry,omitempty"`
+	// backoff is the delay before the first retry of the step, which doubles for each further retry. Defaults to 10 seconds
+	Backoff *Timeout `json:"backoff,omitempty"`
+
 	// Legacy fields from jenkinsfile.PipelineStep before it was eliminated.
 	Comment   string  `json:"comment,omitempty"`
 	Groovy    string  `json:"groovy,omitempty"`
 	Steps     []*Step `json:"steps,omitempty"`
-	When      string  `json:"when,omitempty"`
 	Container string  `json:"container,omitempty"`
 	Sh        string  `json:"sh,omitempty"`
 }
@@ -205,6 +222,10 @@ type Stage struct {
 	Parallel   []Stage         `json:"parallel,omitempty"`
 	Post       []Post          `json:"post,omitempty"`
 	WorkingDir *string         `json:"dir,omitempty"`
+	When       *When           `json:"when,omitempty"`
+	Matrix     *Matrix         `json:"matrix,omitempty"`
+	Services   []Service       `json:"services,omitempty"`
+	Input      *Input          `json:"input,omitempty"`
 
 	// Replaced by Env, retained for backwards compatibility
 	Environment []corev1.EnvVar `json:"environment,omitempty"`
@@ -222,14 +243,13 @@ const (
 
 // Post contains a PostCondition and one more actions to be executed after a pipeline or stage if the condition is met.
 type Post struct {
-	// TODO: Conditional execution of something after a Task or Pipeline completes is not yet implemented
 	Condition PostCondition `json:"condition"`
 	Actions   []PostAction  `json:"actions"`
 }
 
 // PostAction contains the name of a built-in post action and options to pass to that action.
 type PostAction struct {
-	// TODO: Notifications are not yet supported in Build Pipeline per se.
+	// Name is one of the built-in actions: notify, collect-test-reports or mark-activity
 	Name string `json:"name"`
 	// Also, we'll need to do some magic to do type verification during translation - i.e., this action wants a number
 	// for this option, so translate the string value for that option to a number.
@@ -532,10 +552,22 @@ func (j *ParsedPipeline) Validate(context context.Context) *apis.FieldError {
 		return err
 	}
 
+	if err := validateStashes(j.Stages); err != nil {
+		return err
+	}
+
 	if err := validateRootOptions(j.Options).ViaField("options"); err != nil {
 		return err
 	}
 
+	if err := validateParameters(j.Parameters); err != nil {
+		return err
+	}
+
+	if err := validatePost(j.Post); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -571,8 +603,8 @@ func validateAgent(a *Agent) *apis.FieldError {
 var containsASCIILetter = regexp.MustCompile(`[a-zA-Z]`).MatchString
 
 func validateStage(s Stage, parentAgent *Agent) *apis.FieldError {
-	if len(s.Steps) == 0 && len(s.Stages) == 0 && len(s.Parallel) == 0 {
-		return apis.ErrMissingOneOf("steps", "stages", "parallel")
+	if len(s.Steps) == 0 && len(s.Stages) == 0 && len(s.Parallel) == 0 && s.Input == nil {
+		return apis.ErrMissingOneOf("steps", "stages", "parallel", "input")
 	}
 
 	if !containsASCIILetter(s.Name) {
@@ -582,6 +614,33 @@ func validateStage(s Stage, parentAgent *Agent) *apis.FieldError {
 		}
 	}
 
+	if err := validateWhen(s.When); err != nil {
+		return err
+	}
+
+	if len(s.Post) > 0 && len(s.Steps) == 0 {
+		return &apis.FieldError{
+			Message: "post is only supported on stages with steps",
+			Paths:   []string{"post"},
+		}
+	}
+
+	if err := validatePost(s.Post); err != nil {
+		return err
+	}
+
+	if err := validateServices(s.Services); err != nil {
+		return err
+	}
+
+	if err := validateMatrix(s, false); err != nil {
+		return err
+	}
+
+	if err := validateInput(s); err != nil {
+		return err
+	}
+
 	stageAgent := s.Agent.DeepCopy()
 	if stageAgent == nil {
 		stageAgent = parentAgent.DeepCopy()
@@ -594,6 +653,10 @@ func validateStage(s Stage, parentAgent *Agent) *apis.FieldError {
 		}
 	}
 
+	if s.Input != nil && (len(s.Steps) > 0 || len(s.Stages) > 0 || len(s.Parallel) > 0) {
+		return apis.ErrMultipleOneOf("steps", "stages", "parallel", "input")
+	}
+
 	if len(s.Steps) > 0 {
 		if len(s.Stages) > 0 || len(s.Parallel) > 0 {
 			return apis.ErrMultipleOneOf("steps", "stages", "parallel")
@@ -641,8 +704,11 @@ func validateStage(s Stage, parentAgent *Agent) *apis.FieldError {
 
 	if len(s.Parallel) > 0 {
 		for i, stage := range s.Parallel {
+			if err := validateMatrix(stage, true).ViaFieldIndex("parallel", i); err != nil {
+				return err
+			}
 			if err := validateStage(stage, parentAgent).ViaFieldIndex("parallel", i); err != nil {
-				return nil
+				return err
 			}
 		}
 	}
@@ -682,11 +748,8 @@ func validateStep(s Step) *apis.FieldError {
 			Paths:   []string{"comment"},
 		}
 	}
-	if s.When != "" {
-		return &apis.FieldError{
-			Message: "the when field is only valid in legacy build packs, not in jenkins-x.yml. Please remove it.",
-			Paths:   []string{"when"},
-		}
+	if err := validateWhen(s.When); err != nil {
+		return err
 	}
 	if len(s.Steps) > 0 {
 		return &apis.FieldError{
@@ -717,10 +780,18 @@ func validateStep(s Step) *apis.FieldError {
 		}
 	}
 
+	if err := validateStepTemplateName(s); err != nil {
+		return err
+	}
+
 	if err := validateLoop(s.Loop); err != nil {
 		return err.ViaField("loop")
 	}
 
+	if err := validateStepTimeoutAndRetry(s); err != nil {
+		return err
+	}
+
 	if s.Agent != nil {
 		return validateAgent(s.Agent).ViaField("agent")
 	}
@@ -781,6 +852,14 @@ func validateRootOptions(o *RootOptions) *apis.FieldError {
 			}
 		}
 
+		if err := validateCache(o.Cache); err != nil {
+			return err.ViaField("cache")
+		}
+
+		if err := validateServices(o.Services); err != nil {
+			return err
+		}
+
 		return validateContainerOptions(o.ContainerOptions).ViaField("containerOptions")
 	}
 
@@ -854,6 +933,13 @@ func validateStageOptions(o *StageOptions) *apis.FieldError {
 			}
 		}
 
+		if o.RootOptions != nil && len(o.Services) > 0 {
+			return &apis.FieldError{
+				Message: "services cannot be set in the options of a stage, set them on the stage instead",
+				Paths:   []string{"services"},
+			}
+		}
+
 		return validateRootOptions(o.RootOptions)
 	}
 
@@ -890,7 +976,6 @@ func validateTimeout(t *Timeout) *apis.FieldError {
 
 func validateUnstash(u *Unstash) *apis.FieldError {
 	if u != nil {
-		// TODO: Check to make sure the corresponding stash is defined somewhere
 		if u.Name == "" {
 			return &apis.FieldError{
 				Message: "The unstash name must be provided",
@@ -1115,12 +1200,10 @@ func (ts *transformedStage) computeWorkspace(parentWorkspace string) {
 	}
 }
 
-func stageToTask(s Stage, pipelineIdentifier string, buildIdentifier string, namespace string, sourceDir string, baseWorkingDir *string, parentEnv []corev1.EnvVar, parentAgent *Agent, parentWorkspace string, parentContainer *corev1.Container, depth int8, enclosingStage *transformedStage, previousSiblingStage *transformedStage, podTemplates map[string]*corev1.Pod, versionsDir string, labels map[string]string, defaultImage string) (*transformedStage, error) {
-	if len(s.Post) != 0 {
-		return nil, errors.New("post on stages not yet supported")
-	}
-
+func stageToTask(s Stage, pipelineIdentifier string, buildIdentifier string, namespace string, sourceDir string, baseWorkingDir *string, parentEnv []corev1.EnvVar, parentAgent *Agent, parentWorkspace string, parentContainer *corev1.Container, parentCache *Cache, parentServices []Service, depth int8, enclosingStage *transformedStage, previousSiblingStage *transformedStage, podTemplates map[string]*corev1.Pod, versionsDir string, teamStepsDir string, labels map[string]string, defaultImage string) (*transformedStage, error) {
 	stageContainer := &corev1.Container{}
+	stageCache := parentCache
+	stageServices := parentServices
 
 	if s.Options != nil {
 		o := s.Options
@@ -1133,14 +1216,12 @@ func stageToTask(s Stage, pipelineIdentifier string, buildIdentifier string, nam
 			if o.ContainerOptions != nil {
 				stageContainer = o.ContainerOptions
 			}
-		}
-		if o.Stash != nil {
-			return nil, errors.New("Stash on stage not yet supported")
-		}
-		if o.Unstash != nil {
-			return nil, errors.New("Unstash on stage not yet supported")
+			if o.Cache != nil {
+				stageCache = o.Cache
+			}
 		}
 	}
+	stageServices = mergeServices(stageServices, s.Services)
 
 	// Don't overwrite the inherited working dir if we don't have one specified here.
 	if s.WorkingDir != nil {
@@ -1208,10 +1289,35 @@ func stageToTask(s Stage, pipelineIdentifier string, buildIdentifier string, nam
 			},
 		}
 
+		if len(stageServices) > 0 {
+			env = scopedEnv(env, servicesEnv(stageServices, t.Name))
+		}
+
+		var jxImage string
+		if len(s.Post) > 0 || stageCache != nil || len(stageServices) > 0 || (s.Options != nil && (s.Options.Stash != nil || s.Options.Unstash != nil)) {
+			jxImage, err = getJxImage(defaultImage, versionsDir)
+			if err != nil {
+				return nil, err
+			}
+		}
+		stageDir := stageWorkingDir(sourceDir, baseWorkingDir)
+		if len(stageServices) > 0 {
+			err = addStartServicesStep(t, stageServices, jxImage, stageDir, env)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if s.Options != nil && s.Options.Unstash != nil {
+			addUnstashStep(t, s.Options.Unstash, jxImage, stageDir, env)
+		}
+		if stageCache != nil {
+			addRestoreCacheStep(t, stageCache, jxImage, stageDir, env)
+		}
+
 		// We don't want to dupe volumes for the Task if there are multiple steps
 		volumes := make(map[string]corev1.Volume)
 		for _, step := range s.Steps {
-			actualSteps, stepVolumes, newCounter, err := generateSteps(step, agent.Image, sourceDir, baseWorkingDir, env, stageContainer, podTemplates, versionsDir, stepCounter)
+			actualSteps, stepVolumes, newCounter, err := generateSteps(step, agent.Image, sourceDir, baseWorkingDir, env, stageContainer, podTemplates, versionsDir, teamStepsDir, stepCounter)
 			if err != nil {
 				return nil, err
 			}
@@ -1235,6 +1341,41 @@ func stageToTask(s Stage, pipelineIdentifier string, buildIdentifier string, nam
 			t.Spec.Volumes = append(t.Spec.Volumes, volumes[v])
 		}
 
+		if stageCache != nil {
+			addSaveCacheStep(t, stageCache, jxImage, stageDir, env)
+		}
+		if s.Options != nil && s.Options.Stash != nil {
+			addStashStep(t, s.Options.Stash, jxImage, stageDir, env)
+		}
+		if len(stageServices) > 0 {
+			addStopServicesStep(t, jxImage, stageDir, env)
+		}
+		if stageCache != nil {
+			addCacheVolumes(t, stageCache)
+		}
+
+		if len(s.Post) > 0 {
+			addPostSteps(t, s.Post, s.Name, true, jxImage, filepath.Join(WorkingDirRoot, sourceDir), env)
+		}
+
+		ts := transformedStage{Stage: s, Task: t, Depth: depth, EnclosingStage: enclosingStage, PreviousSiblingStage: previousSiblingStage}
+		ts.computeWorkspace(parentWorkspace)
+		return &ts, nil
+	}
+	if s.Input != nil {
+		jxImage, err := getJxImage(defaultImage, versionsDir)
+		if err != nil {
+			return nil, err
+		}
+		var taskSpec *tektonv1alpha1.TaskSpec
+		if previousSiblingStage == nil && isNestedFirstStepsStage(enclosingStage) {
+			taskSpec = &defaultTaskSpec
+		}
+		taskName := MangleToRfc1035Label(fmt.Sprintf("%s-%s", pipelineIdentifier, s.Name), buildIdentifier)
+		t, err := inputStageToTask(s, activityStageName(s, enclosingStage), taskName, namespace, sourceDir, jxImage, env, taskSpec, labels)
+		if err != nil {
+			return nil, err
+		}
 		ts := transformedStage{Stage: s, Task: t, Depth: depth, EnclosingStage: enclosingStage, PreviousSiblingStage: previousSiblingStage}
 		ts.computeWorkspace(parentWorkspace)
 		return &ts, nil
@@ -1249,7 +1390,7 @@ func stageToTask(s Stage, pipelineIdentifier string, buildIdentifier string, nam
 			if i > 0 {
 				nestedPreviousSibling = tasks[i-1]
 			}
-			nestedTask, err := stageToTask(nested, pipelineIdentifier, buildIdentifier, namespace, sourceDir, baseWorkingDir, env, agent, *ts.Stage.Options.Workspace, stageContainer, depth+1, &ts, nestedPreviousSibling, podTemplates, versionsDir, labels, defaultImage)
+			nestedTask, err := stageToTask(nested, pipelineIdentifier, buildIdentifier, namespace, sourceDir, baseWorkingDir, env, agent, *ts.Stage.Options.Workspace, stageContainer, stageCache, stageServices, depth+1, &ts, nestedPreviousSibling, podTemplates, versionsDir, teamStepsDir, labels, defaultImage)
 			if err != nil {
 				return nil, err
 			}
@@ -1266,7 +1407,7 @@ func stageToTask(s Stage, pipelineIdentifier string, buildIdentifier string, nam
 		ts.computeWorkspace(parentWorkspace)
 
 		for _, nested := range s.Parallel {
-			nestedTask, err := stageToTask(nested, pipelineIdentifier, buildIdentifier, namespace, sourceDir, baseWorkingDir, env, agent, *ts.Stage.Options.Workspace, stageContainer, depth+1, &ts, nil, podTemplates, versionsDir, labels, defaultImage)
+			nestedTask, err := stageToTask(nested, pipelineIdentifier, buildIdentifier, namespace, sourceDir, baseWorkingDir, env, agent, *ts.Stage.Options.Workspace, stageContainer, stageCache, stageServices, depth+1, &ts, nil, podTemplates, versionsDir, teamStepsDir, labels, defaultImage)
 			if err != nil {
 				return nil, err
 			}
@@ -1276,7 +1417,7 @@ func stageToTask(s Stage, pipelineIdentifier string, buildIdentifier string, nam
 
 		return &ts, nil
 	}
-	return nil, errors.New("no steps, sequential stages, or parallel stages")
+	return nil, errors.New("no steps, sequential stages, parallel stages or input")
 }
 
 // MergeContainers combines parent and child container structs, with the child overriding the parent.
@@ -1345,7 +1486,7 @@ func isNestedFirstStepsStage(enclosingStage *transformedStage) bool {
 	return true
 }
 
-func generateSteps(step Step, inheritedAgent, sourceDir string, baseWorkingDir *string, env []corev1.EnvVar, parentContainer *corev1.Container, podTemplates map[string]*corev1.Pod, versionsDir string, stepCounter int) ([]corev1.Container, map[string]corev1.Volume, int, error) {
+func generateSteps(step Step, inheritedAgent, sourceDir string, baseWorkingDir *string, env []corev1.EnvVar, parentContainer *corev1.Container, podTemplates map[string]*corev1.Pod, versionsDir string, teamStepsDir string, stepCounter int) ([]corev1.Container, map[string]corev1.Volume, int, error) {
 	volumes := make(map[string]corev1.Volume)
 	var steps []corev1.Container
 
@@ -1421,6 +1562,9 @@ func generateSteps(step Step, inheritedAgent, sourceDir string, baseWorkingDir *
 		c.Stdin = false
 		c.TTY = false
 		c.Env = scopedEnv(step.Env, scopedEnv(env, c.Env))
+		if err := applyStepTimeoutAndRetry(step, c); err != nil {
+			return nil, nil, stepCounter, err
+		}
 
 		steps = append(steps, *c)
 	} else if step.Loop != nil {
@@ -1431,7 +1575,7 @@ func generateSteps(step Step, inheritedAgent, sourceDir string, baseWorkingDir *
 				if s.Name != "" {
 					s.Name = s.Name + strconv.Itoa(1+i)
 				}
-				loopSteps, loopVolumes, loopCounter, loopErr := generateSteps(s, stepImage, sourceDir, baseWorkingDir, loopEnv, parentContainer, podTemplates, versionsDir, stepCounter)
+				loopSteps, loopVolumes, loopCounter, loopErr := generateSteps(s, stepImage, sourceDir, baseWorkingDir, loopEnv, parentContainer, podTemplates, versionsDir, teamStepsDir, stepCounter)
 				if loopErr != nil {
 					return nil, nil, loopCounter, loopErr
 				}
@@ -1448,8 +1592,29 @@ func generateSteps(step Step, inheritedAgent, sourceDir string, baseWorkingDir *
 				}
 			}
 		}
+	} else if step.Step != "" {
+		template, err := LoadStepTemplate(StepTemplateDirs(versionsDir, teamStepsDir), step.Step)
+		if err != nil {
+			return nil, nil, stepCounter, err
+		}
+		expandedSteps, templateEnv, err := templateSteps(step, template)
+		if err != nil {
+			return nil, nil, stepCounter, err
+		}
+		templateEnv = scopedEnv(templateEnv, env)
+		for _, s := range expandedSteps {
+			templateContainers, templateVolumes, templateCounter, templateErr := generateSteps(s, stepImage, sourceDir, baseWorkingDir, templateEnv, parentContainer, podTemplates, versionsDir, teamStepsDir, stepCounter)
+			if templateErr != nil {
+				return nil, nil, templateCounter, errors.Wrapf(templateErr, "failed to generate the steps of step template %s", template.Name)
+			}
+			stepCounter = templateCounter
+			steps = append(steps, templateContainers...)
+			for k, v := range templateVolumes {
+				volumes[k] = v
+			}
+		}
 	} else {
-		return nil, nil, stepCounter, errors.New("syntactic sugar steps not yet supported")
+		return nil, nil, stepCounter, errors.New("one of command, step or loop is required")
 	}
 
 	return steps, volumes, stepCounter, nil
@@ -1460,13 +1625,12 @@ func PipelineRunName(pipelineIdentifier string, buildIdentifier string) string {
 	return MangleToRfc1035Label(fmt.Sprintf("%s", pipelineIdentifier), buildIdentifier)
 }
 
-// GenerateCRDs translates the Pipeline structure into the corresponding Pipeline and Task CRDs
-func (j *ParsedPipeline) GenerateCRDs(pipelineIdentifier string, buildIdentifier string, namespace string, podTemplates map[string]*corev1.Pod, versionsDir string, taskParams []tektonv1alpha1.ParamSpec, sourceDir string, labels map[string]string, defaultImage string) (*tektonv1alpha1.Pipeline, []*tektonv1alpha1.Task, *v1.PipelineStructure, error) {
-	if len(j.Post) != 0 {
-		return nil, nil, nil, errors.New("Post at top level not yet supported")
-	}
-
+// GenerateCRDs translates the Pipeline structure into the corresponding Pipeline and Task CRDs. Step templates are
+// resolved from the team's steps directory, if any, and then the version stream
+func (j *ParsedPipeline) GenerateCRDs(pipelineIdentifier string, buildIdentifier string, namespace string, podTemplates map[string]*corev1.Pod, versionsDir string, taskParams []tektonv1alpha1.ParamSpec, sourceDir string, labels map[string]string, defaultImage string, teamStepsDir string) (*tektonv1alpha1.Pipeline, []*tektonv1alpha1.Task, *v1.PipelineStructure, error) {
 	var parentContainer *corev1.Container
+	var parentCache *Cache
+	var parentServices []Service
 	baseWorkingDir := j.WorkingDir
 
 	if j.Options != nil {
@@ -1475,6 +1639,8 @@ func (j *ParsedPipeline) GenerateCRDs(pipelineIdentifier string, buildIdentifier
 			return nil, nil, nil, errors.New("Retry at top level not yet supported")
 		}
 		parentContainer = o.ContainerOptions
+		parentCache = o.Cache
+		parentServices = o.Services
 	}
 
 	p := &tektonv1alpha1.Pipeline{
@@ -1515,10 +1681,16 @@ func (j *ParsedPipeline) GenerateCRDs(pipelineIdentifier string, buildIdentifier
 
 	baseEnv := j.GetEnv()
 
-	for i, s := range j.Stages {
-		isLastStage := i == len(j.Stages)-1
+	jxImage, err := getJxImage(defaultImage, versionsDir)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
-		stage, err := stageToTask(s, pipelineIdentifier, buildIdentifier, namespace, sourceDir, baseWorkingDir, baseEnv, j.Agent, "default", parentContainer, 0, nil, previousStage, podTemplates, versionsDir, labels, defaultImage)
+	stages := expandMatrixStages(j.Stages)
+	for i, s := range stages {
+		isLastStage := i == len(stages)-1
+
+		stage, err := stageToTask(s, pipelineIdentifier, buildIdentifier, namespace, sourceDir, baseWorkingDir, baseEnv, j.Agent, "default", parentContainer, parentCache, parentServices, 0, nil, previousStage, podTemplates, versionsDir, teamStepsDir, labels, defaultImage)
 		if err != nil {
 			return nil, nil, nil, err
 		}
@@ -1536,7 +1708,18 @@ func (j *ParsedPipeline) GenerateCRDs(pipelineIdentifier string, buildIdentifier
 
 		linearTasks := stage.getLinearTasks()
 
+		// the success and always post actions of the pipeline only run in a single end Task, so if the last stage has
+		// parallel stages they run in the end Task of the last of them rather than once per parallel stage
+		var endTask *tektonv1alpha1.Task
+		if isLastStage {
+			endStages := findEndStages(*stage)
+			endTask = endStages[len(endStages)-1].Task
+		}
+
 		for index, lt := range linearTasks {
+			addPostSteps(lt, j.Post, "", lt == endTask, jxImage, filepath.Join(WorkingDirRoot, sourceDir), baseEnv)
+			addPostStatusStep(lt, jxImage)
+
 			if shouldRemoveWorkspaceOutput(stage, lt.Name, index, len(linearTasks), isLastStage) {
 				pipelineTasks[index].Resources.Outputs = nil
 				lt.Spec.Outputs = nil
@@ -1753,6 +1936,9 @@ func validateStageNames(j *ParsedPipeline) (err *apis.FieldError) {
 
 		for _, stage := range stages {
 			*stageNames = append(*stageNames, stage.Name)
+			for _, combination := range stage.Matrix.Combinations() {
+				*stageNames = append(*stageNames, matrixStageName(stage.Name, combination))
+			}
 			if len(stage.Stages) > 0 {
 				validate(stage.Stages, stageNames)
 			}
@@ -1769,17 +1955,22 @@ func validateStageNames(j *ParsedPipeline) (err *apis.FieldError) {
 }
 
 // todo JR lets remove this when we switch tekton to using git merge type pipelineresources
+// getJxImage returns the image used for steps which run jx commands such as the git merge and post action steps
+func getJxImage(defaultImage string, versionsDir string) (string, error) {
+	if defaultImage != "" {
+		return defaultImage, nil
+	}
+	image := os.Getenv("BUILDER_JX_IMAGE")
+	if image != "" {
+		return image, nil
+	}
+	return version.ResolveDockerImage(versionsDir, GitMergeImage)
+}
+
 func getDefaultTaskSpec(envs []corev1.EnvVar, parentContainer *corev1.Container, defaultImage string, versionsDir string) (tektonv1alpha1.TaskSpec, error) {
-	var err error
-	image := defaultImage
-	if image == "" {
-		image = os.Getenv("BUILDER_JX_IMAGE")
-		if image == "" {
-			image, err = version.ResolveDockerImage(versionsDir, GitMergeImage)
-			if err != nil {
-				return tektonv1alpha1.TaskSpec{}, err
-			}
-		}
+	image, err := getJxImage(defaultImage, versionsDir)
+	if err != nil {
+		return tektonv1alpha1.TaskSpec{}, err
 	}
 
 	childContainer := &corev1.Container{
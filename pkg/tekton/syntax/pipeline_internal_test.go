@@ -3,6 +3,8 @@ package syntax
 import (
 	"strings"
 	"testing"
+
+	corev1 "k8s.io/api/core/v1"
 )
 
 func TestFindDuplicates(t *testing.T) {
@@ -57,3 +59,22 @@ func TestFindDuplicates(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyStepTimeoutAndRetryUsesTheShellOfTheStep(t *testing.T) {
+	step := Step{
+		Timeout: &Timeout{Time: 5, Unit: TimeoutUnitMinutes},
+	}
+	c := &corev1.Container{
+		Name:    "step2",
+		Command: []string{"/bin/bash", "-c"},
+		Args:    []string{"shopt -s globstar && ls **/*.go"},
+	}
+	err := applyStepTimeoutAndRetry(step, c)
+	if err != nil {
+		t.Fatalf("failed to apply the timeout: %s", err)
+	}
+	expected := "timeout 300 /bin/bash -c 'shopt -s globstar && ls **/*.go'"
+	if len(c.Args) != 1 || c.Args[0] != expected {
+		t.Errorf("expected args %s but got %s", expected, strings.Join(c.Args, " "))
+	}
+}
@@ -19,6 +19,7 @@ import (
 	tb "github.com/tektoncd/pipeline/test/builder"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 var (
@@ -74,6 +75,53 @@ func TestParseJenkinsfileYaml(t *testing.T) {
 				syntax_helpers_test.StructureStage("A Working Stage", syntax_helpers_test.StructureStageTaskRef("somepipeline-a-working-stage-1")),
 			),
 		},
+		{
+			name: "step_timeout_and_retry",
+			expected: syntax_helpers_test.ParsedPipeline(
+				syntax_helpers_test.PipelineAgent("some-image"),
+				syntax_helpers_test.PipelineStage("Build",
+					syntax_helpers_test.StageStep(
+						syntax_helpers_test.StepCmd("npm"), syntax_helpers_test.StepArg("install"),
+						syntax_helpers_test.StepTimeout(&syntax.Timeout{Time: 5, Unit: syntax.TimeoutUnitMinutes}),
+						syntax_helpers_test.StepRetry(2, &syntax.Timeout{Time: 30, Unit: syntax.TimeoutUnitSeconds}),
+					),
+					syntax_helpers_test.StageStep(
+						syntax_helpers_test.StepCmd(`echo "don't panic" && npm test`),
+						syntax_helpers_test.StepRetry(1, nil),
+					),
+					syntax_helpers_test.StageStep(
+						syntax_helpers_test.StepCmd("npm"), syntax_helpers_test.StepArg("run"), syntax_helpers_test.StepArg("lint"),
+						syntax_helpers_test.StepTimeout(&syntax.Timeout{Time: 1, Unit: syntax.TimeoutUnitHours}),
+					),
+				),
+			),
+			pipeline: tb.Pipeline("somepipeline-1", "jx", tb.PipelineSpec(
+				tb.PipelineTask("build", "somepipeline-build-1",
+					tb.PipelineTaskInputResource("workspace", "somepipeline"),
+				),
+				tb.PipelineDeclaredResource("somepipeline", tektonv1alpha1.PipelineResourceTypeGit))),
+			tasks: []*tektonv1alpha1.Task{
+				tb.Task("somepipeline-build-1", "jx", syntax_helpers_test.TaskStageLabel("Build"),
+					tb.TaskSpec(
+						tb.TaskInputs(
+							tb.InputsResource("workspace", tektonv1alpha1.PipelineResourceTypeGit,
+								tb.ResourceTargetPath("source"))),
+						tb.Step("git-merge", resolvedGitMergeImage, tb.Command("jx"), tb.Args("step", "git", "merge", "--verbose"), workingDir("/workspace/source")),
+						tb.Step("step2", "some-image:0.0.1", tb.Command("/bin/sh", "-c"),
+							tb.Args(`attempt=1; delay=30; until timeout 300 /bin/sh -c 'npm install'; do rc=$?; if [ $attempt -gt 2 ]; then exit $rc; fi; `+
+								`echo "step failed with exit code $rc, retrying in $delay seconds"; sleep $delay; attempt=$((attempt+1)); delay=$((delay*2)); done`),
+							workingDir("/workspace/source")),
+						tb.Step("step3", "some-image:0.0.1", tb.Command("/bin/sh", "-c"),
+							tb.Args(`attempt=1; delay=10; until /bin/sh -c 'echo "don'\''t panic" && npm test'; do rc=$?; if [ $attempt -gt 1 ]; then exit $rc; fi; `+
+								`echo "step failed with exit code $rc, retrying in $delay seconds"; sleep $delay; attempt=$((attempt+1)); delay=$((delay*2)); done`),
+							workingDir("/workspace/source")),
+						tb.Step("step4", "some-image:0.0.1", tb.Command("/bin/sh", "-c"), tb.Args("timeout 3600 /bin/sh -c 'npm run lint'"), workingDir("/workspace/source")),
+					)),
+			},
+			structure: syntax_helpers_test.PipelineStructure("somepipeline-1",
+				syntax_helpers_test.StructureStage("Build", syntax_helpers_test.StructureStageTaskRef("somepipeline-build-1")),
+			),
+		},
 		{
 			name: "multiple_stages",
 			expected: syntax_helpers_test.ParsedPipeline(
@@ -244,6 +292,135 @@ func TestParseJenkinsfileYaml(t *testing.T) {
 					syntax_helpers_test.StructureStagePrevious("Parent Stage")),
 			),
 		},
+		{
+			name: "stash_and_unstash",
+			expected: syntax_helpers_test.ParsedPipeline(
+				syntax_helpers_test.PipelineAgent("some-image"),
+				syntax_helpers_test.PipelineStage("First Stage",
+					syntax_helpers_test.StageOptions(
+						syntax_helpers_test.StageOptionsStash("binaries", "bin/*"),
+					),
+					syntax_helpers_test.StageStep(syntax_helpers_test.StepCmd("echo"), syntax_helpers_test.StepArg("first"))),
+				syntax_helpers_test.PipelineStage("Parent Stage",
+					syntax_helpers_test.StageParallel("A Working Stage",
+						syntax_helpers_test.StageOptions(
+							syntax_helpers_test.StageOptionsUnstash("binaries", "target"),
+						),
+						syntax_helpers_test.StageStep(syntax_helpers_test.StepCmd("echo"), syntax_helpers_test.StepArg("hello"), syntax_helpers_test.StepArg("world"))),
+					syntax_helpers_test.StageParallel("Another stage",
+						syntax_helpers_test.StageOptions(
+							syntax_helpers_test.StageOptionsUnstash("binaries", ""),
+						),
+						syntax_helpers_test.StageStep(syntax_helpers_test.StepCmd("echo"), syntax_helpers_test.StepArg("again"))),
+				),
+				syntax_helpers_test.PipelineStage("Last Stage",
+					syntax_helpers_test.StageStep(syntax_helpers_test.StepCmd("echo"), syntax_helpers_test.StepArg("last"))),
+			),
+			pipeline: tb.Pipeline("somepipeline-1", "jx", tb.PipelineSpec(
+				tb.PipelineTask("first-stage", "somepipeline-first-stage-1",
+					tb.PipelineTaskInputResource("workspace", "somepipeline"),
+					tb.PipelineTaskOutputResource("workspace", "somepipeline")),
+				tb.PipelineTask("a-working-stage", "somepipeline-a-working-stage-1",
+					tb.PipelineTaskInputResource("workspace", "somepipeline", tb.From("first-stage")),
+					tb.RunAfter("first-stage")),
+				tb.PipelineTask("another-stage", "somepipeline-another-stage-1",
+					tb.PipelineTaskInputResource("workspace", "somepipeline",
+						tb.From("first-stage")),
+					tb.RunAfter("first-stage")),
+				tb.PipelineTask("last-stage", "somepipeline-last-stage-1",
+					tb.PipelineTaskInputResource("workspace", "somepipeline", tb.From("first-stage")),
+					tb.RunAfter("a-working-stage", "another-stage")),
+				tb.PipelineDeclaredResource("somepipeline", tektonv1alpha1.PipelineResourceTypeGit))),
+			tasks: []*tektonv1alpha1.Task{
+				tb.Task("somepipeline-first-stage-1", "jx", syntax_helpers_test.TaskStageLabel("First Stage"), tb.TaskSpec(
+					tb.TaskInputs(
+						tb.InputsResource("workspace", tektonv1alpha1.PipelineResourceTypeGit,
+							tb.ResourceTargetPath("source"))),
+					tb.TaskOutputs(tb.OutputsResource("workspace", tektonv1alpha1.PipelineResourceTypeGit)),
+					tb.Step("git-merge", resolvedGitMergeImage, tb.Command("jx"), tb.Args("step", "git", "merge", "--verbose"), workingDir("/workspace/source")),
+					tb.Step("step2", "some-image:0.0.1", tb.Command("/bin/sh", "-c"), tb.Args("echo first"), workingDir("/workspace/source")),
+					tb.Step("stash-binaries", resolvedGitMergeImage, tb.Command("jx"), tb.Args("step", "stash", "--name", "binaries", "--pattern", "bin/*"), workingDir("/workspace/source")),
+				)),
+				tb.Task("somepipeline-a-working-stage-1", "jx", syntax_helpers_test.TaskStageLabel("A Working Stage"),
+					tb.TaskSpec(
+						tb.TaskInputs(
+							tb.InputsResource("workspace", tektonv1alpha1.PipelineResourceTypeGit,
+								tb.ResourceTargetPath("source"))),
+						tb.Step("unstash-binaries", resolvedGitMergeImage, tb.Command("jx"), tb.Args("step", "unstash", "--name", "binaries", "--output", "target"), workingDir("/workspace/source")),
+						tb.Step("step2", "some-image:0.0.1", tb.Command("/bin/sh", "-c"), tb.Args("echo hello world"), workingDir("/workspace/source")),
+					)),
+				tb.Task("somepipeline-another-stage-1", "jx", syntax_helpers_test.TaskStageLabel("Another stage"), tb.TaskSpec(
+					tb.TaskInputs(
+						tb.InputsResource("workspace", tektonv1alpha1.PipelineResourceTypeGit,
+							tb.ResourceTargetPath("source"))),
+					tb.Step("unstash-binaries", resolvedGitMergeImage, tb.Command("jx"), tb.Args("step", "unstash", "--name", "binaries", "--output", "."), workingDir("/workspace/source")),
+					tb.Step("step2", "some-image:0.0.1", tb.Command("/bin/sh", "-c"), tb.Args("echo again"), workingDir("/workspace/source")),
+				)),
+				tb.Task("somepipeline-last-stage-1", "jx", syntax_helpers_test.TaskStageLabel("Last Stage"), tb.TaskSpec(
+					tb.TaskInputs(
+						tb.InputsResource("workspace", tektonv1alpha1.PipelineResourceTypeGit,
+							tb.ResourceTargetPath("source"))),
+					tb.Step("step2", "some-image:0.0.1", tb.Command("/bin/sh", "-c"), tb.Args("echo last"), workingDir("/workspace/source")),
+				)),
+			},
+		},
+		{
+			name: "input_stage",
+			expected: syntax_helpers_test.ParsedPipeline(
+				syntax_helpers_test.PipelineAgent("some-image"),
+				syntax_helpers_test.PipelineStage("Build",
+					syntax_helpers_test.StageStep(syntax_helpers_test.StepCmd("echo"), syntax_helpers_test.StepArg("build"))),
+				syntax_helpers_test.PipelineStage("Approve",
+					syntax_helpers_test.StageInput("Deploy to production?", &syntax.Timeout{Time: 2, Unit: syntax.TimeoutUnitHours}, "alice", "bob")),
+				syntax_helpers_test.PipelineStage("Deploy",
+					syntax_helpers_test.StageStep(syntax_helpers_test.StepCmd("echo"), syntax_helpers_test.StepArg("deploy"))),
+			),
+			pipeline: tb.Pipeline("somepipeline-1", "jx", tb.PipelineSpec(
+				tb.PipelineTask("build", "somepipeline-build-1",
+					tb.PipelineTaskInputResource("workspace", "somepipeline"),
+					tb.PipelineTaskOutputResource("workspace", "somepipeline")),
+				tb.PipelineTask("approve", "somepipeline-approve-1",
+					tb.PipelineTaskInputResource("workspace", "somepipeline",
+						tb.From("build")),
+					tb.PipelineTaskOutputResource("workspace", "somepipeline"),
+					tb.RunAfter("build")),
+				tb.PipelineTask("deploy", "somepipeline-deploy-1",
+					tb.PipelineTaskInputResource("workspace", "somepipeline",
+						tb.From("approve")),
+					tb.RunAfter("approve")),
+				tb.PipelineDeclaredResource("somepipeline", tektonv1alpha1.PipelineResourceTypeGit))),
+			tasks: []*tektonv1alpha1.Task{
+				tb.Task("somepipeline-build-1", "jx", syntax_helpers_test.TaskStageLabel("Build"), tb.TaskSpec(
+					tb.TaskInputs(
+						tb.InputsResource("workspace", tektonv1alpha1.PipelineResourceTypeGit,
+							tb.ResourceTargetPath("source"))),
+					tb.TaskOutputs(tb.OutputsResource("workspace", tektonv1alpha1.PipelineResourceTypeGit)),
+					tb.Step("git-merge", resolvedGitMergeImage, tb.Command("jx"), tb.Args("step", "git", "merge", "--verbose"), workingDir("/workspace/source")),
+					tb.Step("step2", "some-image:0.0.1", tb.Command("/bin/sh", "-c"), tb.Args("echo build"), workingDir("/workspace/source")),
+				)),
+				tb.Task("somepipeline-approve-1", "jx", syntax_helpers_test.TaskStageLabel("Approve"), tb.TaskSpec(
+					tb.TaskInputs(
+						tb.InputsResource("workspace", tektonv1alpha1.PipelineResourceTypeGit,
+							tb.ResourceTargetPath("source"))),
+					tb.TaskOutputs(tb.OutputsResource("workspace", tektonv1alpha1.PipelineResourceTypeGit)),
+					tb.Step("input", resolvedGitMergeImage, tb.Command("jx"), tb.Args("step", "input", "--stage", "Approve", "--message", "Deploy to production?",
+						"--approver", "alice", "--approver", "bob", "--timeout", "2h0m0s"), workingDir("/workspace/source")),
+				)),
+				tb.Task("somepipeline-deploy-1", "jx", syntax_helpers_test.TaskStageLabel("Deploy"), tb.TaskSpec(
+					tb.TaskInputs(
+						tb.InputsResource("workspace", tektonv1alpha1.PipelineResourceTypeGit,
+							tb.ResourceTargetPath("source"))),
+					tb.Step("step2", "some-image:0.0.1", tb.Command("/bin/sh", "-c"), tb.Args("echo deploy"), workingDir("/workspace/source")),
+				)),
+			},
+			structure: syntax_helpers_test.PipelineStructure("somepipeline-1",
+				syntax_helpers_test.StructureStage("Build", syntax_helpers_test.StructureStageTaskRef("somepipeline-build-1")),
+				syntax_helpers_test.StructureStage("Approve", syntax_helpers_test.StructureStageTaskRef("somepipeline-approve-1"),
+					syntax_helpers_test.StructureStagePrevious("Build")),
+				syntax_helpers_test.StructureStage("Deploy", syntax_helpers_test.StructureStageTaskRef("somepipeline-deploy-1"),
+					syntax_helpers_test.StructureStagePrevious("Approve")),
+			),
+		},
 		{
 			name: "parallel_and_nested_stages",
 			expected: syntax_helpers_test.ParsedPipeline(
@@ -573,7 +750,26 @@ func TestParseJenkinsfileYaml(t *testing.T) {
 						syntax_helpers_test.StepOptions(map[string]string{"firstParam": "some value", "secondParam": "some other value"})),
 				),
 			),
-			expectedErrorMsg: "syntactic sugar steps not yet supported",
+			pipeline: tb.Pipeline("somepipeline-1", "jx", tb.PipelineSpec(
+				tb.PipelineTask("a-working-stage", "somepipeline-a-working-stage-1",
+					tb.PipelineTaskInputResource("workspace", "somepipeline"),
+				),
+				tb.PipelineDeclaredResource("somepipeline", tektonv1alpha1.PipelineResourceTypeGit))),
+			tasks: []*tektonv1alpha1.Task{
+				tb.Task("somepipeline-a-working-stage-1", "jx", syntax_helpers_test.TaskStageLabel("A Working Stage"),
+					tb.TaskSpec(
+						tb.TaskInputs(
+							tb.InputsResource("workspace", tektonv1alpha1.PipelineResourceTypeGit,
+								tb.ResourceTargetPath("source"))),
+						tb.Step("git-merge", resolvedGitMergeImage, tb.Command("jx"), tb.Args("step", "git", "merge", "--verbose"), workingDir("/workspace/source")),
+						tb.Step("step2", "some-image:0.0.1", tb.Command("/bin/sh", "-c"), tb.Args("echo hello world"), workingDir("/workspace/source")),
+						tb.Step("step3", "some-image:0.0.1", tb.Command("/bin/sh", "-c"), tb.Args("echo ${FIRST_PARAM} ${SECOND_PARAM}"), workingDir("/workspace/source"),
+							tb.EnvVar("FIRST_PARAM", "some value"), tb.EnvVar("SECOND_PARAM", "some other value")),
+					)),
+			},
+			structure: syntax_helpers_test.PipelineStructure("somepipeline-1",
+				syntax_helpers_test.StructureStage("A Working Stage", syntax_helpers_test.StructureStageTaskRef("somepipeline-a-working-stage-1")),
+			),
 		},
 		{
 			name: "post",
@@ -582,24 +778,56 @@ func TestParseJenkinsfileYaml(t *testing.T) {
 				syntax_helpers_test.PipelineStage("A Working Stage",
 					syntax_helpers_test.StageStep(syntax_helpers_test.StepCmd("echo"), syntax_helpers_test.StepArg("hello"), syntax_helpers_test.StepArg("world")),
 					syntax_helpers_test.StagePost(syntax.PostConditionSuccess,
-						syntax_helpers_test.PostAction("mail", map[string]string{
-							"to":      "foo@bar.com",
-							"subject": "Yay, it passed",
+						syntax_helpers_test.PostAction("notify", map[string]string{
+							"channel": "#builds",
 						})),
 					syntax_helpers_test.StagePost(syntax.PostConditionFailure,
-						syntax_helpers_test.PostAction("slack", map[string]string{
-							"whatever": "the",
-							"slack":    "config",
-							"actually": "is. =)",
+						syntax_helpers_test.PostAction("mark-activity", map[string]string{
+							"quarantine": "true",
 						})),
 					syntax_helpers_test.StagePost(syntax.PostConditionAlways,
-						syntax_helpers_test.PostAction("junit", map[string]string{
-							"pattern": "target/surefire-reports/**/*.xml",
+						syntax_helpers_test.PostAction("collect-test-reports", map[string]string{
+							"pattern": "target/surefire-reports/*.xml",
 						}),
 					),
 				),
+				syntax_helpers_test.PipelinePost(syntax.PostConditionFailure,
+					syntax_helpers_test.PostAction("notify", nil)),
 			),
-			expectedErrorMsg: "post on stages not yet supported",
+			pipeline: tb.Pipeline("somepipeline-1", "jx", tb.PipelineSpec(
+				tb.PipelineTask("a-working-stage", "somepipeline-a-working-stage-1",
+					tb.PipelineTaskInputResource("workspace", "somepipeline"),
+				),
+				tb.PipelineDeclaredResource("somepipeline", tektonv1alpha1.PipelineResourceTypeGit))),
+			tasks: []*tektonv1alpha1.Task{
+				tb.Task("somepipeline-a-working-stage-1", "jx", syntax_helpers_test.TaskStageLabel("A Working Stage"),
+					tb.TaskSpec(
+						tb.TaskInputs(
+							tb.InputsResource("workspace", tektonv1alpha1.PipelineResourceTypeGit,
+								tb.ResourceTargetPath("source"))),
+						tb.Step("post-setup", resolvedGitMergeImage, tb.Command("/bin/sh", "-c"),
+							tb.Args(`mkdir -p /workspace/.jx-post/bin && cp "$(command -v jx)" /workspace/.jx-post/bin/jx`)),
+						tb.Step("git-merge", resolvedGitMergeImage, tb.Command("/workspace/.jx-post/bin/jx"),
+							tb.Args("step", "post", "exec", "--", "jx", "step", "git", "merge", "--verbose"), workingDir("/workspace/source")),
+						tb.Step("step2", "some-image:0.0.1", tb.Command("/bin/sh", "-c"),
+							tb.Args("if [ -f /workspace/.jx-post/failed ]; then echo 'skipping as an earlier step failed'; exit 0; fi; ( echo hello world ) || { mkdir -p /workspace/.jx-post && touch /workspace/.jx-post/failed; }"),
+							workingDir("/workspace/source")),
+						tb.Step("post-success-notify", resolvedGitMergeImage, tb.Command("jx"),
+							tb.Args("step", "post", "action", "--name", "notify", "--condition", "success", "--stage", "A Working Stage", "--option", "channel=#builds"),
+							workingDir("/workspace/source")),
+						tb.Step("post-failure-mark-activity", resolvedGitMergeImage, tb.Command("jx"),
+							tb.Args("step", "post", "action", "--name", "mark-activity", "--condition", "failure", "--stage", "A Working Stage", "--option", "quarantine=true"),
+							workingDir("/workspace/source")),
+						tb.Step("post-always-collect-test-reports", resolvedGitMergeImage, tb.Command("jx"),
+							tb.Args("step", "post", "action", "--name", "collect-test-reports", "--condition", "always", "--stage", "A Working Stage", "--option", "pattern=target/surefire-reports/*.xml"),
+							workingDir("/workspace/source")),
+						tb.Step("post-failure-notify", resolvedGitMergeImage, tb.Command("jx"),
+							tb.Args("step", "post", "action", "--name", "notify", "--condition", "failure"),
+							workingDir("/workspace/source")),
+						tb.Step("post-status", resolvedGitMergeImage, tb.Command("/bin/sh", "-c"),
+							tb.Args("if [ -f /workspace/.jx-post/failed ]; then echo 'failing as a step failed'; exit 1; fi")),
+					)),
+			},
 		},
 		{
 			name: "top_level_and_stage_options",
@@ -609,6 +837,12 @@ func TestParseJenkinsfileYaml(t *testing.T) {
 					syntax_helpers_test.PipelineOptionsTimeout(50, "minutes"),
 					syntax_helpers_test.PipelineOptionsRetry(3),
 				),
+				syntax_helpers_test.PipelineStage("An Earlier Stage",
+					syntax_helpers_test.StageOptions(
+						syntax_helpers_test.StageOptionsStash("Earlier Files", "build/*"),
+					),
+					syntax_helpers_test.StageStep(syntax_helpers_test.StepCmd("echo"), syntax_helpers_test.StepArg("hello")),
+				),
 				syntax_helpers_test.PipelineStage("A Working Stage",
 					syntax_helpers_test.StageOptions(
 						syntax_helpers_test.StageOptionsTimeout(5, "seconds"),
@@ -880,7 +1114,35 @@ func TestParseJenkinsfileYaml(t *testing.T) {
 					),
 				),
 			),
-			expectedErrorMsg: "syntactic sugar steps not yet supported",
+			pipeline: tb.Pipeline("somepipeline-1", "jx", tb.PipelineSpec(
+				tb.PipelineTask("a-working-stage", "somepipeline-a-working-stage-1",
+					tb.PipelineTaskInputResource("workspace", "somepipeline"),
+				),
+				tb.PipelineDeclaredResource("somepipeline", tektonv1alpha1.PipelineResourceTypeGit))),
+			tasks: []*tektonv1alpha1.Task{
+				tb.Task("somepipeline-a-working-stage-1", "jx", syntax_helpers_test.TaskStageLabel("A Working Stage"),
+					tb.TaskSpec(
+						tb.TaskInputs(
+							tb.InputsResource("workspace", tektonv1alpha1.PipelineResourceTypeGit,
+								tb.ResourceTargetPath("source"))),
+						tb.Step("git-merge", resolvedGitMergeImage, tb.Command("jx"), tb.Args("step", "git", "merge", "--verbose"), workingDir("/workspace/source")),
+						tb.Step("step2", "some-image:0.0.1", tb.Command("/bin/sh", "-c"), tb.Args("echo hello ${LANGUAGE}"), workingDir("/workspace/source"),
+							tb.EnvVar("LANGUAGE", "maven")),
+						tb.Step("step3", "some-image:0.0.1", tb.Command("/bin/sh", "-c"), tb.Args("echo ${FIRST_PARAM} ${SECOND_PARAM}"), workingDir("/workspace/source"),
+							tb.EnvVar("FIRST_PARAM", "some value"), tb.EnvVar("LANGUAGE", "maven"), tb.EnvVar("SECOND_PARAM", "some other value")),
+						tb.Step("step4", "some-image:0.0.1", tb.Command("/bin/sh", "-c"), tb.Args("echo hello ${LANGUAGE}"), workingDir("/workspace/source"),
+							tb.EnvVar("LANGUAGE", "gradle")),
+						tb.Step("step5", "some-image:0.0.1", tb.Command("/bin/sh", "-c"), tb.Args("echo ${FIRST_PARAM} ${SECOND_PARAM}"), workingDir("/workspace/source"),
+							tb.EnvVar("FIRST_PARAM", "some value"), tb.EnvVar("LANGUAGE", "gradle"), tb.EnvVar("SECOND_PARAM", "some other value")),
+						tb.Step("step6", "some-image:0.0.1", tb.Command("/bin/sh", "-c"), tb.Args("echo hello ${LANGUAGE}"), workingDir("/workspace/source"),
+							tb.EnvVar("LANGUAGE", "nodejs")),
+						tb.Step("step7", "some-image:0.0.1", tb.Command("/bin/sh", "-c"), tb.Args("echo ${FIRST_PARAM} ${SECOND_PARAM}"), workingDir("/workspace/source"),
+							tb.EnvVar("FIRST_PARAM", "some value"), tb.EnvVar("LANGUAGE", "nodejs"), tb.EnvVar("SECOND_PARAM", "some other value")),
+					)),
+			},
+			structure: syntax_helpers_test.PipelineStructure("somepipeline-1",
+				syntax_helpers_test.StructureStage("A Working Stage", syntax_helpers_test.StructureStageTaskRef("somepipeline-a-working-stage-1")),
+			),
 		},
 		{
 			name: "top_level_container_options",
@@ -1208,7 +1470,7 @@ func TestParseJenkinsfileYaml(t *testing.T) {
 				}
 			}
 
-			pipeline, tasks, structure, err := parsed.GenerateCRDs("somepipeline", "1", "jx", nil, testVersionsDir, nil, "source", nil, "")
+			pipeline, tasks, structure, err := parsed.GenerateCRDs("somepipeline", "1", "jx", nil, testVersionsDir, nil, "source", nil, "", "")
 
 			if err != nil {
 				if tt.expectedErrorMsg != "" {
@@ -1273,13 +1535,70 @@ func TestFailedValidation(t *testing.T) {
 			expectedError: apis.ErrMultipleOneOf("label", "image").
 				ViaField("agent"),
 		},
+		{
+			name: "unstash_without_stash",
+			expectedError: (&apis.FieldError{
+				Message: "no stash named binaries is created by an earlier stage",
+				Paths:   []string{"name"},
+			}).ViaField("unstash").ViaField("options").ViaFieldIndex("parallel", 1).ViaFieldIndex("stages", 0),
+		},
+		{
+			name:          "input_and_steps",
+			expectedError: apis.ErrMultipleOneOf("steps", "stages", "parallel", "input").ViaFieldIndex("stages", 0),
+		},
+		{
+			name: "input_with_invalid_timeout_unit",
+			expectedError: (&apis.FieldError{
+				Message: "weeks is not a valid time unit. Valid time units are seconds, minutes, hours, days",
+				Paths:   []string{"unit"},
+			}).ViaField("timeout").ViaField("input").ViaFieldIndex("stages", 0),
+		},
+		{
+			name: "input_with_invalid_approver",
+			expectedError: (&apis.FieldError{
+				Message: "approver bob@example.com is not a valid user name. User names start with a letter, contain only letters, digits, '.', '_' or '-' and are at most 63 characters",
+				Paths:   []string{"approvers"},
+			}).ViaField("input").ViaFieldIndex("stages", 0),
+		},
+		{
+			name: "matrix_with_unknown_exclude_axis",
+			expectedError: (&apis.FieldError{
+				Message: "unknown axis OS",
+				Paths:   []string{"OS"},
+			}).ViaFieldIndex("exclude", 0).ViaField("matrix").ViaFieldIndex("stages", 0),
+		},
+		{
+			name:          "cache_without_paths",
+			expectedError: apis.ErrMissingField("paths").ViaField("cache").ViaField("options"),
+		},
+		{
+			name: "service_readiness_probe_without_handler",
+			expectedError: (&apis.FieldError{
+				Message: "readinessProbe must specify one of exec, httpGet or tcpSocket",
+				Paths:   []string{"readinessProbe"},
+			}).ViaFieldIndex("services", 0).ViaFieldIndex("stages", 0),
+		},
+		{
+			name: "stage_options_with_services",
+			expectedError: (&apis.FieldError{
+				Message: "services cannot be set in the options of a stage, set them on the stage instead",
+				Paths:   []string{"services"},
+			}).ViaField("options").ViaFieldIndex("stages", 0),
+		},
+		{
+			name: "step_with_invalid_template_name",
+			expectedError: (&apis.FieldError{
+				Message: "invalid step template name ../some-step, must only contain lowercase letters, digits or '-'",
+				Paths:   []string{"step"},
+			}).ViaFieldIndex("steps", 0).ViaFieldIndex("stages", 0),
+		},
 		{
 			name:          "no_stages",
 			expectedError: apis.ErrMissingField("stages"),
 		},
 		{
 			name:          "no_steps_stages_or_parallel",
-			expectedError: apis.ErrMissingOneOf("steps", "stages", "parallel").ViaFieldIndex("stages", 0),
+			expectedError: apis.ErrMissingOneOf("steps", "stages", "parallel", "input").ViaFieldIndex("stages", 0),
 		},
 		{
 			name:          "steps_and_stages",
@@ -1293,6 +1612,10 @@ func TestFailedValidation(t *testing.T) {
 			name:          "stages_and_parallel",
 			expectedError: apis.ErrMultipleOneOf("steps", "stages", "parallel").ViaFieldIndex("stages", 0),
 		},
+		{
+			name:          "parallel_stage_without_steps",
+			expectedError: apis.ErrMissingOneOf("steps", "stages", "parallel").ViaFieldIndex("parallel", 1).ViaFieldIndex("stages", 0),
+		},
 		{
 			name:          "step_without_command_step_or_loop",
 			expectedError: apis.ErrMissingOneOf("command", "step", "loop").ViaFieldIndex("steps", 0).ViaFieldIndex("stages", 0),
@@ -1396,6 +1719,48 @@ func TestFailedValidation(t *testing.T) {
 				Paths:   []string{"retry"},
 			}).ViaField("options").ViaFieldIndex("stages", 0),
 		},
+		{
+			name: "step_retry_with_invalid_count",
+			expectedError: (&apis.FieldError{
+				Message: "Retry count cannot be negative",
+				Paths:   []string{"retry"},
+			}).ViaFieldIndex("steps", 0).ViaFieldIndex("stages", 0),
+		},
+		{
+			name: "step_timeout_with_invalid_unit",
+			expectedError: (&apis.FieldError{
+				Message: "years is not a valid time unit. Valid time units are seconds, minutes, hours, days",
+				Paths:   []string{"unit"},
+			}).ViaField("timeout").ViaFieldIndex("steps", 0).ViaFieldIndex("stages", 0),
+		},
+		{
+			name: "step_backoff_without_retry",
+			expectedError: (&apis.FieldError{
+				Message: "backoff can only be set when retry is greater than zero",
+				Paths:   []string{"backoff"},
+			}).ViaFieldIndex("steps", 0).ViaFieldIndex("stages", 0),
+		},
+		{
+			name: "loop_with_retry",
+			expectedError: (&apis.FieldError{
+				Message: "timeout, retry and backoff cannot be set on a loop, set them on the steps of the loop instead",
+				Paths:   []string{"loop"},
+			}).ViaFieldIndex("steps", 0).ViaFieldIndex("stages", 0),
+		},
+		{
+			name: "parameter_with_invalid_type",
+			expectedError: (&apis.FieldError{
+				Message: "number is not a valid parameter type. Valid types are string, bool, choice",
+				Paths:   []string{"type"},
+			}).ViaFieldIndex("parameters", 1),
+		},
+		{
+			name: "parameter_default_not_in_choices",
+			expectedError: (&apis.FieldError{
+				Message: "the value qa of parameter TARGET is not one of staging, production",
+				Paths:   []string{"default"},
+			}).ViaFieldIndex("parameters", 0),
+		},
 		{
 			name: "stash_without_name",
 			expectedError: (&apis.FieldError{
@@ -1503,6 +1868,27 @@ func TestFailedValidation(t *testing.T) {
 				Paths:   []string{"when"},
 			}).ViaFieldIndex("steps", 0).ViaFieldIndex("stages", 0),
 		},
+		{
+			name: "post_with_unknown_action",
+			expectedError: (&apis.FieldError{
+				Message: "unknown post action mail, must be one of: notify, collect-test-reports, mark-activity",
+				Paths:   []string{"name"},
+			}).ViaFieldIndex("actions", 0).ViaFieldIndex("post", 0).ViaFieldIndex("stages", 0),
+		},
+		{
+			name: "post_with_invalid_activity_label",
+			expectedError: (&apis.FieldError{
+				Message: "invalid label value tests failed: " + validation.IsValidLabelValue("tests failed")[0],
+				Paths:   []string{"reason"},
+			}).ViaField("options").ViaFieldIndex("actions", 0).ViaFieldIndex("post", 0).ViaFieldIndex("stages", 0),
+		},
+		{
+			name: "when_with_invalid_kind",
+			expectedError: (&apis.FieldError{
+				Message: "invalid kind nightly, must be one of: release, pullrequest, feature",
+				Paths:   []string{"kind[0]"},
+			}).ViaField("when").ViaFieldIndex("stages", 0),
+		},
 		{
 			name: "container_field",
 			expectedError: (&apis.FieldError{
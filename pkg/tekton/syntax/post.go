@@ -0,0 +1,243 @@
+package syntax
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/knative/pkg/apis"
+	tektonv1alpha1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// PostActionNotify posts a message about the outcome to the chat channel of the project
+	PostActionNotify = "notify"
+	// PostActionCollectTestReports stashes the test reports matching the pattern option into the team's storage
+	PostActionCollectTestReports = "collect-test-reports"
+	// PostActionMarkActivity adds the options as labels on the PipelineActivity
+	PostActionMarkActivity = "mark-activity"
+
+	// PostFailedMarkerFile is the file created when a step of a Task fails so that the post actions of the Task know
+	// the outcome of the steps
+	PostFailedMarkerFile = WorkingDirRoot + "/.jx-post/failed"
+	// PostJxBinary is where the jx binary is copied so that steps which are not run by a shell can be run via
+	// jx step post exec whatever their image
+	PostJxBinary = WorkingDirRoot + "/.jx-post/bin/jx"
+
+	postStepPrefix = "post-"
+)
+
+// PostActions the built-in post actions
+var PostActions = []string{PostActionNotify, PostActionCollectTestReports, PostActionMarkActivity}
+
+// postSkipIfFailed is the start of the script of the wrapped shell steps which skips them if an earlier step failed
+var postSkipIfFailed = fmt.Sprintf("if [ -f %s ]; then echo 'skipping as an earlier step failed'; exit 0; fi; ", PostFailedMarkerFile)
+
+// IsMet returns true if the condition is met for the outcome of the steps
+func (c PostCondition) IsMet(failed bool) bool {
+	switch c {
+	case PostConditionSuccess:
+		return !failed
+	case PostConditionFailure:
+		return failed
+	default:
+		return c == PostConditionAlways
+	}
+}
+
+func validatePost(posts []Post) *apis.FieldError {
+	for i, p := range posts {
+		if p.Condition != PostConditionSuccess && p.Condition != PostConditionFailure && p.Condition != PostConditionAlways {
+			return (&apis.FieldError{
+				Message: fmt.Sprintf("invalid condition %s, must be one of: %s, %s, %s", p.Condition, PostConditionSuccess, PostConditionFailure, PostConditionAlways),
+				Paths:   []string{"condition"},
+			}).ViaFieldIndex("post", i)
+		}
+		if len(p.Actions) == 0 {
+			return apis.ErrMissingField("actions").ViaFieldIndex("post", i)
+		}
+		for j, a := range p.Actions {
+			if err := validatePostAction(a).ViaFieldIndex("actions", j); err != nil {
+				return err.ViaFieldIndex("post", i)
+			}
+		}
+	}
+	return nil
+}
+
+func validatePostAction(a PostAction) *apis.FieldError {
+	switch a.Name {
+	case PostActionNotify:
+		return nil
+	case PostActionCollectTestReports:
+		if a.Options["pattern"] == "" {
+			return apis.ErrMissingField("pattern").ViaField("options")
+		}
+		return nil
+	case PostActionMarkActivity:
+		if len(a.Options) == 0 {
+			return apis.ErrMissingField("options")
+		}
+		return validateActivityLabels(a.Options).ViaField("options")
+	default:
+		return &apis.FieldError{
+			Message: fmt.Sprintf("unknown post action %s, must be one of: %s", a.Name, strings.Join(PostActions, ", ")),
+			Paths:   []string{"name"},
+		}
+	}
+}
+
+// ValidateActivityLabel returns the reasons why the key and value cannot be used as a label of the PipelineActivity
+func ValidateActivityLabel(key string, value string) []string {
+	var answer []string
+	for _, msg := range validation.IsQualifiedName(key) {
+		answer = append(answer, fmt.Sprintf("invalid label key %s: %s", key, msg))
+	}
+	for _, msg := range validation.IsValidLabelValue(value) {
+		answer = append(answer, fmt.Sprintf("invalid label value %s: %s", value, msg))
+	}
+	return answer
+}
+
+func validateActivityLabels(labels map[string]string) *apis.FieldError {
+	var keys []string
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if msgs := ValidateActivityLabel(k, labels[k]); len(msgs) > 0 {
+			return &apis.FieldError{
+				Message: strings.Join(msgs, ", "),
+				Paths:   []string{k},
+			}
+		}
+	}
+	return nil
+}
+
+// addPostSteps makes the steps of the Task record a failure rather than failing the Task and appends the steps which
+// run the post actions. If the Task is not the single final Task of the posts then success conditions are left to the
+// final Task and always conditions only run on failure so that each action runs at most once per pipeline
+func addPostSteps(t *tektonv1alpha1.Task, posts []Post, stageName string, final bool, image string, workingDir string, env []corev1.EnvVar) {
+	var steps []corev1.Container
+	for _, p := range posts {
+		condition := p.Condition
+		if !final {
+			if condition == PostConditionSuccess {
+				continue
+			}
+			if condition == PostConditionAlways {
+				condition = PostConditionFailure
+			}
+		}
+		for _, a := range p.Actions {
+			args := []string{"step", "post", "action", "--name", a.Name, "--condition", string(condition)}
+			if stageName != "" {
+				args = append(args, "--stage", stageName)
+			}
+			var keys []string
+			for k := range a.Options {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				args = append(args, "--option", k+"="+a.Options[k])
+			}
+			steps = append(steps, corev1.Container{
+				Name:       MangleToRfc1035Label(fmt.Sprintf("%s%s-%s", postStepPrefix, condition, a.Name), ""),
+				Image:      image,
+				Command:    []string{"jx"},
+				Args:       args,
+				WorkingDir: workingDir,
+				Env:        env,
+			})
+		}
+	}
+	if len(steps) == 0 {
+		return
+	}
+	wrapStepsForPost(t, image)
+	for _, step := range steps {
+		step.Name = uniqueStepName(t, step.Name)
+		t.Spec.Steps = append(t.Spec.Steps, step)
+	}
+}
+
+// addPostStatusStep adds a final step which fails the Task if any step failed once the post actions and any other
+// steps which run whatever the outcome, such as stopping the services, have run
+func addPostStatusStep(t *tektonv1alpha1.Task, image string) {
+	if !isWrappedForPost(t) {
+		return
+	}
+	t.Spec.Steps = append(t.Spec.Steps, corev1.Container{
+		Name:    uniqueStepName(t, postStepPrefix+"status"),
+		Image:   image,
+		Command: []string{"/bin/sh", "-c"},
+		Args:    []string{fmt.Sprintf("if [ -f %s ]; then echo 'failing as a step failed'; exit 1; fi", PostFailedMarkerFile)},
+	})
+}
+
+// wrapStepsForPost changes the steps of the Task so that a failure is recorded in the marker file and any following
+// steps are skipped. Shell steps are wrapped in the shell. Other steps, such as kaniko, are run via jx step post exec
+// using a copy of the jx binary as their image may not contain a shell
+func wrapStepsForPost(t *tektonv1alpha1.Task, image string) {
+	if isWrappedForPost(t) {
+		return
+	}
+	copyJx := false
+	for i := range t.Spec.Steps {
+		c := &t.Spec.Steps[i]
+		if len(c.Command) == 2 && c.Command[0] == "/bin/sh" && c.Command[1] == "-c" && len(c.Args) == 1 {
+			c.Args = []string{fmt.Sprintf("%s( %s ) || { mkdir -p %s && touch %s; }",
+				postSkipIfFailed, c.Args[0], filepath.Dir(PostFailedMarkerFile), PostFailedMarkerFile)}
+			continue
+		}
+		if len(c.Command) == 0 {
+			continue
+		}
+		args := append([]string{"step", "post", "exec", "--"}, c.Command...)
+		c.Args = append(args, c.Args...)
+		c.Command = []string{PostJxBinary}
+		copyJx = true
+	}
+	if copyJx {
+		setup := corev1.Container{
+			Name:    uniqueStepName(t, postStepPrefix+"setup"),
+			Image:   image,
+			Command: []string{"/bin/sh", "-c"},
+			Args:    []string{fmt.Sprintf("mkdir -p %s && cp \"$(command -v jx)\" %s", filepath.Dir(PostJxBinary), PostJxBinary)},
+		}
+		t.Spec.Steps = append([]corev1.Container{setup}, t.Spec.Steps...)
+	}
+}
+
+// isWrappedForPost returns true if the steps of the Task have already been wrapped so that a failure is recorded in
+// the marker file
+func isWrappedForPost(t *tektonv1alpha1.Task) bool {
+	for _, s := range t.Spec.Steps {
+		if len(s.Command) == 1 && s.Command[0] == PostJxBinary {
+			return true
+		}
+		if len(s.Args) == 1 && strings.HasPrefix(s.Args[0], postSkipIfFailed) {
+			return true
+		}
+	}
+	return false
+}
+
+func uniqueStepName(t *tektonv1alpha1.Task, name string) string {
+	names := []string{}
+	for _, s := range t.Spec.Steps {
+		names = append(names, s.Name)
+	}
+	answer := name
+	for i := 2; util.StringArrayIndex(names, answer) >= 0; i++ {
+		answer = fmt.Sprintf("%s-%d", name, i)
+	}
+	return answer
+}
@@ -0,0 +1,110 @@
+package syntax_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/tekton/syntax"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tektonv1alpha1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
+)
+
+func TestPostActionsOfParallelStagesRunOnce(t *testing.T) {
+	t.Parallel()
+
+	pipeline := &syntax.ParsedPipeline{
+		Agent: &syntax.Agent{Image: "some-image"},
+		Stages: []syntax.Stage{
+			{
+				Name: "Tests",
+				Parallel: []syntax.Stage{
+					{
+						Name:  "Unit",
+						Steps: []syntax.Step{{Command: "make test"}},
+					},
+					{
+						Name:  "Lint",
+						Steps: []syntax.Step{{Command: "make lint"}},
+					},
+				},
+			},
+		},
+		Post: []syntax.Post{
+			{
+				Condition: syntax.PostConditionSuccess,
+				Actions:   []syntax.PostAction{{Name: syntax.PostActionNotify}},
+			},
+			{
+				Condition: syntax.PostConditionAlways,
+				Actions:   []syntax.PostAction{{Name: syntax.PostActionMarkActivity, Options: map[string]string{"tested": "true"}}},
+			},
+		},
+	}
+	require.Nil(t, pipeline.Validate(context.Background()))
+
+	_, tasks, _, err := pipeline.GenerateCRDs("somepipeline", "1", "jx", nil, filepath.Join("test_data", "stable_versions"), nil, "source", nil, "", "")
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+
+	assert.Equal(t, []string{"post-failure-mark-activity"}, postActionStepNames(tasks[0]))
+	assert.Equal(t, []string{"post-success-notify", "post-always-mark-activity"}, postActionStepNames(tasks[1]))
+}
+
+func TestPostActionsWrapStepsWithoutShell(t *testing.T) {
+	t.Parallel()
+
+	pipeline := &syntax.ParsedPipeline{
+		Agent: &syntax.Agent{Image: "some-image"},
+		Stages: []syntax.Stage{
+			{
+				Name: "Build",
+				Steps: []syntax.Step{
+					{Command: "make build"},
+					{Command: "/kaniko/executor", Arguments: []string{"--destination=myorg/myapp:1.0.1"}},
+				},
+				Post: []syntax.Post{
+					{
+						Condition: syntax.PostConditionFailure,
+						Actions:   []syntax.PostAction{{Name: syntax.PostActionNotify}},
+					},
+				},
+			},
+		},
+	}
+	require.Nil(t, pipeline.Validate(context.Background()))
+
+	_, tasks, _, err := pipeline.GenerateCRDs("somepipeline", "1", "jx", nil, filepath.Join("test_data", "stable_versions"), nil, "source", nil, "", "")
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+
+	steps := tasks[0].Spec.Steps
+	require.Len(t, steps, 6)
+	assert.Equal(t, "post-setup", steps[0].Name)
+	assert.Equal(t, []string{"/bin/sh", "-c"}, steps[0].Command)
+	assert.Equal(t, []string{`mkdir -p /workspace/.jx-post/bin && cp "$(command -v jx)" /workspace/.jx-post/bin/jx`}, steps[0].Args)
+
+	assert.Equal(t, "git-merge", steps[1].Name)
+	assert.Equal(t, []string{syntax.PostJxBinary}, steps[1].Command)
+	assert.Equal(t, []string{"step", "post", "exec", "--", "jx", "step", "git", "merge", "--verbose"}, steps[1].Args)
+
+	assert.Equal(t, []string{"/bin/sh", "-c"}, steps[2].Command)
+	assert.Contains(t, steps[2].Args[0], "( make build ) || { mkdir -p /workspace/.jx-post && touch /workspace/.jx-post/failed; }")
+
+	assert.Equal(t, []string{syntax.PostJxBinary}, steps[3].Command)
+	assert.Equal(t, []string{"step", "post", "exec", "--", "/kaniko/executor", "--destination=myorg/myapp:1.0.1"}, steps[3].Args)
+
+	assert.Equal(t, "post-failure-notify", steps[4].Name)
+	assert.Equal(t, "post-status", steps[5].Name)
+}
+
+func postActionStepNames(task *tektonv1alpha1.Task) []string {
+	var names []string
+	for _, s := range task.Spec.Steps {
+		if len(s.Args) > 2 && s.Args[0] == "step" && s.Args[1] == "post" && s.Args[2] == "action" {
+			names = append(names, s.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,109 @@
+package syntax
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/knative/pkg/apis"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// defaultStepBackoff is the delay before the first retry of a step if it has no backoff
+var defaultStepBackoff = Timeout{Time: 10, Unit: TimeoutUnitSeconds}
+
+func validateStepTimeoutAndRetry(s Step) *apis.FieldError {
+	if s.Timeout == nil && s.Retry == 0 && s.Backoff == nil {
+		return nil
+	}
+	if s.Loop != nil {
+		return &apis.FieldError{
+			Message: "timeout, retry and backoff cannot be set on a loop, set them on the steps of the loop instead",
+			Paths:   []string{"loop"},
+		}
+	}
+	if strings.HasPrefix(s.GetCommand(), "/kaniko") {
+		return &apis.FieldError{
+			Message: "timeout, retry and backoff are not supported for kaniko commands",
+			Paths:   []string{"command"},
+		}
+	}
+	if err := validateTimeout(s.Timeout); err != nil {
+		return err.ViaField("timeout")
+	}
+	if s.Retry < 0 {
+		return &apis.FieldError{
+			Message: "Retry count cannot be negative",
+			Paths:   []string{"retry"},
+		}
+	}
+	if s.Backoff != nil && s.Retry == 0 {
+		return &apis.FieldError{
+			Message: "backoff can only be set when retry is greater than zero",
+			Paths:   []string{"backoff"},
+		}
+	}
+	if err := validateTimeout(s.Backoff); err != nil {
+		return err.ViaField("backoff")
+	}
+	return nil
+}
+
+// applyStepTimeoutAndRetry wraps the shell command of the container so that each attempt of the step is run by the
+// same shell, killed after its timeout and retried with an exponential backoff if it fails
+func applyStepTimeoutAndRetry(step Step, c *corev1.Container) error {
+	if step.Timeout == nil && step.Retry <= 0 {
+		return nil
+	}
+	if len(c.Command) != 2 || !strings.HasSuffix(c.Command[0], "sh") || c.Command[1] != "-c" || len(c.Args) != 1 {
+		return fmt.Errorf("timeout and retry are only supported for steps run by a shell but step %s has command %s",
+			c.Name, strings.Join(c.Command, " "))
+	}
+	script, err := retryStepCommand(c.Command[0], c.Args[0], step.Timeout, step.Retry, step.Backoff)
+	if err != nil {
+		return errors.Wrapf(err, "failed to apply the timeout and retry of step %s", c.Name)
+	}
+	c.Args = []string{script}
+	return nil
+}
+
+// retryStepCommand returns a POSIX shell script which runs the command in the given shell with the given timeout per
+// attempt, retrying it up to retry times if it fails. The delay between attempts starts at the backoff and doubles for
+// each retry. The timeout is applied with the timeout command so the image of the step must contain it
+func retryStepCommand(shell string, command string, timeout *Timeout, retry int8, backoff *Timeout) (string, error) {
+	script := shell + " -c " + ShellQuote(command)
+	if timeout != nil {
+		seconds, err := timeoutSeconds(timeout)
+		if err != nil {
+			return "", err
+		}
+		script = fmt.Sprintf("timeout %d %s", seconds, script)
+	}
+	if retry <= 0 {
+		return script, nil
+	}
+	if backoff == nil {
+		backoff = &defaultStepBackoff
+	}
+	delay, err := timeoutSeconds(backoff)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("attempt=1; delay=%d; until %s; do rc=$?; if [ $attempt -gt %d ]; then exit $rc; fi; "+
+		"echo \"step failed with exit code $rc, retrying in $delay seconds\"; sleep $delay; attempt=$((attempt+1)); delay=$((delay*2)); done",
+		delay, script, retry), nil
+}
+
+func timeoutSeconds(t *Timeout) (int64, error) {
+	d, err := t.ToDuration()
+	if err != nil {
+		return 0, err
+	}
+	return int64(d.Duration / time.Second), nil
+}
+
+// ShellQuote quotes the string so it is passed as a single word to a POSIX shell
+func ShellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
@@ -0,0 +1,173 @@
+package syntax
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/knative/pkg/apis"
+	"github.com/pkg/errors"
+	tektonv1alpha1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// LabelServiceGroup is the label on the Pods and Services of the services of a Task, whose value is the name of
+	// the Task
+	LabelServiceGroup = "jenkins.io/service-group"
+
+	// LabelServiceName is the label on the Pod and Service of a service with the name of the service
+	LabelServiceName = "jenkins.io/service-name"
+)
+
+var validServiceName = regexp.MustCompile(`^[a-z]([-a-z0-9]*[a-z0-9])?$`).MatchString
+
+// Service is a container, such as a database, which is started before the steps of a stage and stopped after them.
+// The steps do not start until the service is ready. As Tasks cannot have sidecars, each service runs in its own Pod,
+// owned by the Pod of the Task, and the steps reach it via the host name in the environment variable named after the
+// service, e.g. POSTGRES_HOST for a service named "postgres"
+type Service struct {
+	Name  string          `json:"name"`
+	Image string          `json:"image"`
+	Env   []corev1.EnvVar `json:"env,omitempty"`
+	Ports []int32         `json:"ports,omitempty"`
+	// ReadinessProbe is used to wait for the service to be ready before running the steps. If there is no probe the
+	// steps start as soon as the container of the service is running
+	ReadinessProbe *corev1.Probe `json:"readinessProbe,omitempty"`
+}
+
+// ServiceHostName returns the name of the Kubernetes Service, and so the host name, of a service of a Task. The name
+// contains a hash of the group so that concurrent builds do not share services
+func ServiceHostName(group string, name string) string {
+	hash := sha256.Sum256([]byte(group))
+	return MangleToRfc1035Label(name, hex.EncodeToString(hash[:])[0:10])
+}
+
+// ServiceHostEnvVarName returns the name of the environment variable containing the host name of the service
+func ServiceHostEnvVarName(name string) string {
+	return strings.ToUpper(strings.Replace(name, "-", "_", -1)) + "_HOST"
+}
+
+// mergeServices returns the parent services with any services of the same name replaced by the child services and
+// the other child services appended
+func mergeServices(parent []Service, child []Service) []Service {
+	if len(child) == 0 {
+		return parent
+	}
+	var answer []Service
+	for _, p := range parent {
+		overridden := false
+		for _, c := range child {
+			if c.Name == p.Name {
+				overridden = true
+				break
+			}
+		}
+		if !overridden {
+			answer = append(answer, p)
+		}
+	}
+	return append(answer, child...)
+}
+
+func validateServices(services []Service) *apis.FieldError {
+	names := map[string]bool{}
+	for i, s := range services {
+		if err := validateService(s).ViaFieldIndex("services", i); err != nil {
+			return err
+		}
+		if names[s.Name] {
+			return (&apis.FieldError{
+				Message: fmt.Sprintf("a service named %s is defined more than once", s.Name),
+				Paths:   []string{"name"},
+			}).ViaFieldIndex("services", i)
+		}
+		names[s.Name] = true
+	}
+	return nil
+}
+
+func validateService(s Service) *apis.FieldError {
+	if s.Name == "" {
+		return apis.ErrMissingField("name")
+	}
+	if !validServiceName(s.Name) || len(s.Name) > 40 {
+		return &apis.FieldError{
+			Message: fmt.Sprintf("invalid service name %s, must be at most 40 lowercase letters, digits or '-' and start with a letter", s.Name),
+			Paths:   []string{"name"},
+		}
+	}
+	if s.Image == "" {
+		return apis.ErrMissingField("image")
+	}
+	for i, e := range s.Env {
+		if e.Name == "" {
+			return apis.ErrMissingField("name").ViaFieldIndex("env", i)
+		}
+	}
+	for i, p := range s.Ports {
+		if p < 1 || p > 65535 {
+			return &apis.FieldError{
+				Message: fmt.Sprintf("invalid port %d, must be between 1 and 65535", p),
+				Paths:   []string{fmt.Sprintf("ports[%d]", i)},
+			}
+		}
+	}
+	if probe := s.ReadinessProbe; probe != nil && probe.Exec == nil && probe.HTTPGet == nil && probe.TCPSocket == nil {
+		return &apis.FieldError{
+			Message: "readinessProbe must specify one of exec, httpGet or tcpSocket",
+			Paths:   []string{"readinessProbe"},
+		}
+	}
+	return nil
+}
+
+// servicesEnv returns the environment variables with the host names of the services of the Task
+func servicesEnv(services []Service, group string) []corev1.EnvVar {
+	var answer []corev1.EnvVar
+	for _, s := range services {
+		answer = append(answer, corev1.EnvVar{
+			Name:  ServiceHostEnvVarName(s.Name),
+			Value: ServiceHostName(group, s.Name),
+		})
+	}
+	return answer
+}
+
+// addStartServicesStep appends a step which starts the services and waits for them to be ready
+func addStartServicesStep(t *tektonv1alpha1.Task, services []Service, image string, workingDir string, env []corev1.EnvVar) error {
+	args := []string{"step", "services", "start", "--group", t.Name}
+	for _, s := range services {
+		data, err := json.Marshal(&s)
+		if err != nil {
+			return errors.Wrapf(err, "failed to marshal service %s", s.Name)
+		}
+		args = append(args, "--service", string(data))
+	}
+	t.Spec.Steps = append(t.Spec.Steps, corev1.Container{
+		Name:       uniqueStepName(t, "start-services"),
+		Image:      image,
+		Command:    []string{"jx"},
+		Args:       args,
+		WorkingDir: workingDir,
+		Env:        env,
+	})
+	return nil
+}
+
+// addStopServicesStep appends a step which deletes the Pods and Services of the services. The steps of the Task are
+// wrapped in the same way as for post actions so that the services are stopped even if a step fails
+func addStopServicesStep(t *tektonv1alpha1.Task, image string, workingDir string, env []corev1.EnvVar) {
+	wrapStepsForPost(t, image)
+	t.Spec.Steps = append(t.Spec.Steps, corev1.Container{
+		Name:       uniqueStepName(t, "stop-services"),
+		Image:      image,
+		Command:    []string{"jx"},
+		Args:       []string{"step", "services", "stop", "--group", t.Name},
+		WorkingDir: workingDir,
+		Env:        env,
+	})
+}
@@ -0,0 +1,83 @@
+package syntax_test
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/tekton/syntax"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestServicesSteps(t *testing.T) {
+	t.Parallel()
+
+	postgres := syntax.Service{
+		Name:  "postgres",
+		Image: "postgres:11",
+		Ports: []int32{5432},
+		ReadinessProbe: &corev1.Probe{
+			Handler: corev1.Handler{
+				TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(5432)},
+			},
+		},
+	}
+	pipeline := &syntax.ParsedPipeline{
+		Agent: &syntax.Agent{Image: "some-image"},
+		Options: &syntax.RootOptions{
+			Services: []syntax.Service{postgres},
+		},
+		Stages: []syntax.Stage{
+			{
+				Name:  "Build",
+				Steps: []syntax.Step{{Command: "make build"}},
+			},
+			{
+				Name: "Integration",
+				Services: []syntax.Service{
+					{
+						Name:  "postgres",
+						Image: "postgres:12",
+					},
+					{
+						Name:  "redis-cache",
+						Image: "redis",
+					},
+				},
+				Env:   []corev1.EnvVar{{Name: "REDIS_CACHE_HOST", Value: "my-redis"}},
+				Steps: []syntax.Step{{Command: "make integration"}},
+			},
+		},
+	}
+	require.Nil(t, pipeline.Validate(context.Background()))
+
+	_, tasks, _, err := pipeline.GenerateCRDs("somepipeline", "1", "jx", nil, filepath.Join("test_data", "stable_versions"), nil, "source", nil, "", "")
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+
+	build := tasks[0]
+	var names []string
+	for _, s := range build.Spec.Steps {
+		names = append(names, s.Name)
+	}
+	assert.Equal(t, []string{"post-setup", "git-merge", "start-services", "step2", "stop-services", "post-status"}, names)
+	data, err := json.Marshal(&postgres)
+	require.NoError(t, err)
+	assert.Equal(t, []string{syntax.PostJxBinary}, build.Spec.Steps[2].Command, "the steps record a failure so that the services are still stopped")
+	assert.Equal(t, []string{"step", "post", "exec", "--", "jx", "step", "services", "start", "--group", build.Name, "--service", string(data)}, build.Spec.Steps[2].Args)
+	assert.Contains(t, build.Spec.Steps[3].Args[0], "( make build ) || { mkdir -p /workspace/.jx-post && touch /workspace/.jx-post/failed; }")
+	assert.Equal(t, []string{"jx"}, build.Spec.Steps[4].Command)
+	assert.Equal(t, []string{"step", "services", "stop", "--group", build.Name}, build.Spec.Steps[4].Args)
+	assert.Contains(t, build.Spec.Steps[3].Env, corev1.EnvVar{Name: "POSTGRES_HOST", Value: syntax.ServiceHostName(build.Name, "postgres")})
+
+	integration := tasks[1]
+	require.Len(t, integration.Spec.Steps[2].Args, 14)
+	assert.Contains(t, integration.Spec.Steps[2].Args[11], "postgres:12", "the stage service replaces the pipeline service")
+	assert.Contains(t, integration.Spec.Steps[2].Args[13], "redis")
+	assert.Contains(t, integration.Spec.Steps[3].Env, corev1.EnvVar{Name: "REDIS_CACHE_HOST", Value: "my-redis"}, "the stage env overrides the host name")
+	assert.NotEqual(t, syntax.ServiceHostName(build.Name, "postgres"), syntax.ServiceHostName(integration.Name, "postgres"))
+}
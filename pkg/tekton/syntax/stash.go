@@ -0,0 +1,116 @@
+package syntax
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/knative/pkg/apis"
+	tektonv1alpha1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// validateStashes checks that every unstash refers to a stash of a stage which runs before it. Stages in the same
+// parallel group cannot unstash each other's files as they run at the same time
+func validateStashes(stages []Stage) *apis.FieldError {
+	_, err := validateStashesInStages(stages, nil, false)
+	return err
+}
+
+// validateStashesInStages returns the names of the stashes created by the stages
+func validateStashesInStages(stages []Stage, available []string, parallel bool) ([]string, *apis.FieldError) {
+	var created []string
+	for i, s := range stages {
+		visible := append(append([]string{}, available...), created...)
+		field := "stages"
+		if parallel {
+			visible = available
+			field = "parallel"
+		}
+		names, err := validateStashesInStage(s, visible)
+		if err != nil {
+			return nil, err.ViaFieldIndex(field, i)
+		}
+		created = append(created, names...)
+	}
+	return created, nil
+}
+
+// validateStashesInStage returns the names of the stashes created by the stage and any nested stages
+func validateStashesInStage(s Stage, available []string) ([]string, *apis.FieldError) {
+	var created []string
+	if s.Options != nil {
+		if u := s.Options.Unstash; u != nil && u.Name != "" && util.StringArrayIndex(available, u.Name) < 0 {
+			return nil, (&apis.FieldError{
+				Message: fmt.Sprintf("no stash named %s is created by an earlier stage", u.Name),
+				Paths:   []string{"name"},
+			}).ViaField("unstash").ViaField("options")
+		}
+		if st := s.Options.Stash; st != nil && st.Name != "" {
+			if util.StringArrayIndex(available, st.Name) >= 0 {
+				return nil, (&apis.FieldError{
+					Message: fmt.Sprintf("a stash named %s is already created by an earlier stage", st.Name),
+					Paths:   []string{"name"},
+				}).ViaField("stash").ViaField("options")
+			}
+			if len(s.Steps) == 0 {
+				return nil, (&apis.FieldError{
+					Message: "stash is only supported on stages with steps",
+					Paths:   []string{"stash"},
+				}).ViaField("options")
+			}
+			created = append(created, st.Name)
+		}
+	}
+	nested := append(append([]string{}, available...), created...)
+	if len(s.Stages) > 0 {
+		names, err := validateStashesInStages(s.Stages, nested, false)
+		return append(created, names...), err
+	}
+	if len(s.Parallel) > 0 {
+		names, err := validateStashesInStages(s.Parallel, nested, true)
+		return append(created, names...), err
+	}
+	return created, nil
+}
+
+// addUnstashStep appends a step which fetches the files of the named stash from the team's storage into the
+// directory of the unstash, relative to the working directory of the stage
+func addUnstashStep(t *tektonv1alpha1.Task, u *Unstash, image string, workingDir string, env []corev1.EnvVar) {
+	dir := u.Dir
+	if dir == "" {
+		dir = "."
+	}
+	t.Spec.Steps = append(t.Spec.Steps, corev1.Container{
+		Name:       uniqueStepName(t, MangleToRfc1035Label("unstash-"+u.Name, "")),
+		Image:      image,
+		Command:    []string{"jx"},
+		Args:       []string{"step", "unstash", "--name", u.Name, "--output", dir},
+		WorkingDir: workingDir,
+		Env:        env,
+	})
+}
+
+// addStashStep appends a step which stores the files matching the stash in the team's storage so that later stages
+// can unstash them
+func addStashStep(t *tektonv1alpha1.Task, s *Stash, image string, workingDir string, env []corev1.EnvVar) {
+	t.Spec.Steps = append(t.Spec.Steps, corev1.Container{
+		Name:       uniqueStepName(t, MangleToRfc1035Label("stash-"+s.Name, "")),
+		Image:      image,
+		Command:    []string{"jx"},
+		Args:       []string{"step", "stash", "--name", s.Name, "--pattern", s.Files},
+		WorkingDir: workingDir,
+		Env:        env,
+	})
+}
+
+// stageWorkingDir returns the working directory of the steps of a stage which do not specify their own directory
+func stageWorkingDir(sourceDir string, baseWorkingDir *string) string {
+	if baseWorkingDir == nil {
+		return filepath.Join(WorkingDirRoot, sourceDir)
+	}
+	if filepath.IsAbs(*baseWorkingDir) {
+		return *baseWorkingDir
+	}
+	return filepath.Join(WorkingDirRoot, sourceDir, *baseWorkingDir)
+}
@@ -0,0 +1,238 @@
+package syntax
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/knative/pkg/apis"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// StepTemplatesDirName is the directory of the version stream and of the team's build pack repository which
+	// contains the step templates, one <name>.yml file per template
+	StepTemplatesDirName = "steps"
+)
+
+var validStepTemplateName = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`).MatchString
+
+// StepTemplate is a reusable definition of one or more steps which a pipeline runs via "step: <name>", passing the
+// options of the template. The value of each option is available to the steps as an environment variable named
+// after the option in upper case, e.g. PROJECT_KEY for the option "projectKey"
+type StepTemplate struct {
+	// Name is the name of the template, taken from its file name
+	Name        string `json:"-"`
+	Description string `json:"description,omitempty"`
+	// Version is the version of the template for reporting purposes
+	Version string `json:"version,omitempty"`
+	// Image is the default image of the steps of the template, otherwise the image of the step or stage is used
+	Image   string               `json:"image,omitempty"`
+	Options []StepTemplateOption `json:"options,omitempty"`
+	Env     []corev1.EnvVar      `json:"env,omitempty"`
+	Steps   []Step               `json:"steps"`
+
+	// Dir is the directory the template was loaded from
+	Dir string `json:"-"`
+}
+
+// StepTemplateOption is an option of a step template
+type StepTemplateOption struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Default     string `json:"default,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// StepTemplateDirs returns the directories to load step templates from, the team's build pack repository taking
+// precedence over the version stream. Either directory can be empty
+func StepTemplateDirs(versionsDir string, teamStepsDir string) []string {
+	var answer []string
+	if teamStepsDir != "" {
+		answer = append(answer, teamStepsDir)
+	}
+	if versionsDir != "" {
+		answer = append(answer, filepath.Join(versionsDir, StepTemplatesDirName))
+	}
+	return answer
+}
+
+// LoadStepTemplate loads the step template with the given name from the first of the directories which contains it
+func LoadStepTemplate(dirs []string, name string) (*StepTemplate, error) {
+	if !validStepTemplateName(name) {
+		return nil, errors.Errorf("invalid step template name %s", name)
+	}
+	for _, dir := range dirs {
+		fileName := filepath.Join(dir, name+".yml")
+		exists, err := util.FileExists(fileName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to check if file exists %s", fileName)
+		}
+		if exists {
+			return loadStepTemplateFile(fileName)
+		}
+	}
+	return nil, errors.Errorf("no step template named %s found in %s", name, strings.Join(dirs, ", "))
+}
+
+// LoadStepTemplates loads all of the step templates in the directories, sorted by name. If a template is in more than
+// one directory the first one is used
+func LoadStepTemplates(dirs []string) ([]*StepTemplate, error) {
+	templates := map[string]*StepTemplate{}
+	for _, dir := range dirs {
+		paths, err := filepath.Glob(filepath.Join(dir, "*.yml"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to find the step templates in %s", dir)
+		}
+		for _, path := range paths {
+			template, err := loadStepTemplateFile(path)
+			if err != nil {
+				return nil, err
+			}
+			if templates[template.Name] == nil {
+				templates[template.Name] = template
+			}
+		}
+	}
+	var answer []*StepTemplate
+	for _, t := range templates {
+		answer = append(answer, t)
+	}
+	sort.Slice(answer, func(i, j int) bool {
+		return answer[i].Name < answer[j].Name
+	})
+	return answer, nil
+}
+
+func loadStepTemplateFile(fileName string) (*StepTemplate, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load step template %s", fileName)
+	}
+	template := &StepTemplate{}
+	err = yaml.Unmarshal(data, template)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal step template %s", fileName)
+	}
+	template.Name = strings.TrimSuffix(filepath.Base(fileName), filepath.Ext(fileName))
+	template.Dir = filepath.Dir(fileName)
+	if len(template.Steps) == 0 {
+		return nil, errors.Errorf("step template %s has no steps", fileName)
+	}
+	for i, s := range template.Steps {
+		if s.Step != "" {
+			return nil, errors.Errorf("step %d of step template %s cannot use another step template", i+1, fileName)
+		}
+		if err := validateStep(s); err != nil {
+			return nil, errors.Wrapf(err, "invalid step %d of step template %s", i+1, fileName)
+		}
+	}
+	return template, nil
+}
+
+// OptionsEnv returns the environment variables for the options of a step using the template, falling back to the
+// defaults of the options
+func (t *StepTemplate) OptionsEnv(options map[string]string) ([]corev1.EnvVar, error) {
+	var answer []corev1.EnvVar
+	for k := range options {
+		found := false
+		for _, o := range t.Options {
+			if o.Name == k {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.Errorf("unknown option %s for step template %s", k, t.Name)
+		}
+	}
+	for _, o := range t.Options {
+		value, ok := options[o.Name]
+		if !ok {
+			if o.Required {
+				return nil, errors.Errorf("missing required option %s for step template %s", o.Name, t.Name)
+			}
+			value = o.Default
+		}
+		answer = append(answer, corev1.EnvVar{
+			Name:  StepTemplateOptionEnvVarName(o.Name),
+			Value: value,
+		})
+	}
+	return answer, nil
+}
+
+// StepTemplateOptionEnvVarName returns the name of the environment variable for an option of a step template, e.g.
+// PROJECT_KEY for "projectKey" or "project-key"
+func StepTemplateOptionEnvVarName(name string) string {
+	var sb strings.Builder
+	var previous rune
+	for _, r := range name {
+		if r == '-' || r == '.' {
+			r = '_'
+		}
+		if unicode.IsUpper(r) && (unicode.IsLower(previous) || unicode.IsDigit(previous)) {
+			sb.WriteRune('_')
+		}
+		sb.WriteRune(unicode.ToUpper(r))
+		previous = r
+	}
+	return sb.String()
+}
+
+// templateSteps returns the steps of the template for a step which uses it, with the environment, directory and name
+// of the step applied to them
+func templateSteps(step Step, template *StepTemplate) ([]Step, []corev1.EnvVar, error) {
+	optionsEnv, err := template.OptionsEnv(step.Options)
+	if err != nil {
+		return nil, nil, err
+	}
+	var steps []Step
+	for i, s := range template.Steps {
+		s = *s.DeepCopy()
+		if s.Dir == "" {
+			s.Dir = step.Dir
+		}
+		if s.Image == "" && s.Agent == nil && step.GetImage() == "" {
+			s.Image = template.Image
+		}
+		if s.Timeout == nil {
+			s.Timeout = step.Timeout
+		}
+		if s.Retry == 0 {
+			s.Retry = step.Retry
+		}
+		if s.Backoff == nil {
+			s.Backoff = step.Backoff
+		}
+		if step.Name != "" {
+			if s.Name != "" {
+				s.Name = step.Name + "-" + s.Name
+			} else if len(template.Steps) > 1 {
+				s.Name = fmt.Sprintf("%s%d", step.Name, i+1)
+			} else {
+				s.Name = step.Name
+			}
+		}
+		steps = append(steps, s)
+	}
+	env := scopedEnv(step.Env, scopedEnv(optionsEnv, template.Env))
+	return steps, env, nil
+}
+
+func validateStepTemplateName(s Step) *apis.FieldError {
+	if s.Step != "" && !validStepTemplateName(s.Step) {
+		return &apis.FieldError{
+			Message: fmt.Sprintf("invalid step template name %s, must only contain lowercase letters, digits or '-'", s.Step),
+			Paths:   []string{"step"},
+		}
+	}
+	return nil
+}
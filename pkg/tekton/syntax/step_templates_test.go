@@ -0,0 +1,69 @@
+package syntax_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/tekton/syntax"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestStepTemplateOptionEnvVarName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "PROJECT_KEY", syntax.StepTemplateOptionEnvVarName("projectKey"))
+	assert.Equal(t, "PROJECT_KEY", syntax.StepTemplateOptionEnvVarName("project-key"))
+	assert.Equal(t, "SONAR_URL", syntax.StepTemplateOptionEnvVarName("sonar.url"))
+	assert.Equal(t, "URL", syntax.StepTemplateOptionEnvVarName("URL"))
+	assert.Equal(t, "JAVA11_HOME", syntax.StepTemplateOptionEnvVarName("java11Home"))
+}
+
+func TestLoadStepTemplates(t *testing.T) {
+	t.Parallel()
+
+	teamDir, err := ioutil.TempDir("", "test-step-templates")
+	require.NoError(t, err)
+	defer os.RemoveAll(teamDir)
+
+	err = ioutil.WriteFile(filepath.Join(teamDir, "some-step.yml"), []byte(`description: The team's version
+steps:
+  - command: echo team
+`), 0644)
+	require.NoError(t, err)
+	err = ioutil.WriteFile(filepath.Join(teamDir, "another-step.yml"), []byte(`image: maven
+steps:
+  - command: mvn
+    args: ['sonar:sonar']
+`), 0644)
+	require.NoError(t, err)
+
+	dirs := syntax.StepTemplateDirs(filepath.Join("test_data", "stable_versions"), teamDir)
+
+	templates, err := syntax.LoadStepTemplates(dirs)
+	require.NoError(t, err)
+	require.Len(t, templates, 2)
+	assert.Equal(t, "another-step", templates[0].Name)
+	assert.Equal(t, "some-step", templates[1].Name)
+	assert.Equal(t, "The team's version", templates[1].Description, "the team's templates take precedence")
+
+	template, err := syntax.LoadStepTemplate(dirs[1:], "some-step")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", template.Version)
+
+	env, err := template.OptionsEnv(map[string]string{"firstParam": "hello"})
+	require.NoError(t, err)
+	assert.Equal(t, []corev1.EnvVar{{Name: "FIRST_PARAM", Value: "hello"}, {Name: "SECOND_PARAM", Value: "a default value"}}, env)
+
+	_, err = template.OptionsEnv(map[string]string{"secondParam": "hello"})
+	assert.EqualError(t, err, "missing required option firstParam for step template some-step")
+
+	_, err = template.OptionsEnv(map[string]string{"firstParam": "hello", "thirdParam": "hello"})
+	assert.EqualError(t, err, "unknown option thirdParam for step template some-step")
+
+	_, err = syntax.LoadStepTemplate(dirs, "missing-step")
+	assert.Error(t, err)
+}
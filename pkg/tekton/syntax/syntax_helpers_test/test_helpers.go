@@ -389,6 +389,17 @@ func StageEnvVar(name, value string) StageOp {
 	}
 }
 
+// StageInput makes the stage an input stage
+func StageInput(message string, timeout *syntax.Timeout, approvers ...string) StageOp {
+	return func(stage *syntax.Stage) {
+		stage.Input = &syntax.Input{
+			Message:   message,
+			Approvers: approvers,
+			Timeout:   timeout,
+		}
+	}
+}
+
 // StagePost adds a post condition to the stage
 func StagePost(condition syntax.PostCondition, ops ...PipelinePostOp) StageOp {
 	return func(stage *syntax.Stage) {
@@ -462,6 +473,21 @@ func StepDir(dir string) StepOp {
 	}
 }
 
+// StepTimeout sets the timeout of each attempt of a step
+func StepTimeout(timeout *syntax.Timeout) StepOp {
+	return func(step *syntax.Step) {
+		step.Timeout = timeout
+	}
+}
+
+// StepRetry sets the retry count and backoff for a step
+func StepRetry(retry int8, backoff *syntax.Timeout) StepOp {
+	return func(step *syntax.Step) {
+		step.Retry = retry
+		step.Backoff = backoff
+	}
+}
+
 // StepLoop adds a loop to the step
 func StepLoop(variable string, values []string, ops ...LoopOp) StepOp {
 	return func(step *syntax.Step) {
@@ -0,0 +1,343 @@
+package syntax
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/knative/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// WhenKindRelease the kind of release pipelines
+	WhenKindRelease = "release"
+	// WhenKindPullRequest the kind of Pull Request pipelines
+	WhenKindPullRequest = "pullrequest"
+	// WhenKindFeature the kind of feature branch pipelines
+	WhenKindFeature = "feature"
+)
+
+// WhenKinds the pipeline kinds which can be used in a when condition
+var WhenKinds = []string{WhenKindRelease, WhenKindPullRequest, WhenKindFeature}
+
+// When defines the conditions which must all be met for a stage or step to be executed. If a stage or step is skipped
+// no Task or container is generated for it.
+type When struct {
+	// Branch a glob the branch of the pipeline must match, such as "master" or "release/*"
+	Branch string `json:"branch,omitempty"`
+	// ChangeSet globs of which at least one must match a file changed by the commit or Pull Request, such as "docs/**"
+	ChangeSet []string `json:"changeset,omitempty"`
+	// Environment variables which must be equal to the given values
+	Environment map[string]string `json:"environment,omitempty"`
+	// Labels of which at least one must be on the Pull Request
+	Labels []string `json:"labels,omitempty"`
+	// Kind the pipeline kinds of which one must match: release, pullrequest or feature
+	Kind []string `json:"kind,omitempty"`
+
+	// Legacy is the string expression from legacy build packs such as "prow" or "!prow"
+	Legacy string `json:"-"`
+}
+
+// WhenContext is the information about the pipeline being generated which when conditions are evaluated against
+type WhenContext struct {
+	Branch      string
+	Kind        string
+	Environment map[string]string
+	Labels      []string
+	// ChangedFiles are the files changed by the commit or Pull Request. If nil the changes are unknown and all
+	// changeset conditions match
+	ChangedFiles []string
+}
+
+// UnmarshalJSON supports both the legacy build pack string expressions and structured when conditions
+func (w *When) UnmarshalJSON(data []byte) error {
+	var legacy string
+	if err := json.Unmarshal(data, &legacy); err == nil {
+		*w = When{Legacy: legacy}
+		return nil
+	}
+	type when When
+	answer := when{}
+	if err := json.Unmarshal(data, &answer); err != nil {
+		return err
+	}
+	*w = When(answer)
+	return nil
+}
+
+// MarshalJSON marshals legacy build pack expressions as a string and structured when conditions as an object
+func (w When) MarshalJSON() ([]byte, error) {
+	if w.Legacy != "" {
+		return json.Marshal(w.Legacy)
+	}
+	type when When
+	return json.Marshal(when(w))
+}
+
+// IsLegacy returns true if this is a legacy build pack expression
+func (w *When) IsLegacy() bool {
+	return w != nil && w.Legacy != ""
+}
+
+// Matches returns true if all the conditions are met for the given context. Legacy expressions are handled when
+// build packs are processed so always match here
+func (w *When) Matches(ctx *WhenContext) bool {
+	if w == nil || w.IsLegacy() || ctx == nil {
+		return true
+	}
+	if w.Branch != "" && !MatchesGlob(w.Branch, ctx.Branch) {
+		return false
+	}
+	if len(w.Kind) > 0 && util.StringArrayIndex(w.Kind, ctx.Kind) < 0 {
+		return false
+	}
+	for k, v := range w.Environment {
+		if ctx.Environment[k] != v {
+			return false
+		}
+	}
+	if len(w.Labels) > 0 && !containsAny(w.Labels, ctx.Labels) {
+		return false
+	}
+	if len(w.ChangeSet) > 0 && ctx.ChangedFiles != nil && !matchesAnyFile(w.ChangeSet, ctx.ChangedFiles) {
+		return false
+	}
+	return true
+}
+
+// UsesLabels returns true if any stage or step of the pipeline has a when condition on Pull Request labels
+func (j *ParsedPipeline) UsesLabels() bool {
+	return anyWhen(j.Stages, func(w *When) bool {
+		return len(w.Labels) > 0
+	})
+}
+
+// UsesChangeSet returns true if any stage or step of the pipeline has a when condition on the changed files
+func (j *ParsedPipeline) UsesChangeSet() bool {
+	return anyWhen(j.Stages, func(w *When) bool {
+		return len(w.ChangeSet) > 0
+	})
+}
+
+// ApplyWhen removes any stages and steps whose when conditions do not match the given context, along with any
+// stages which no longer have any steps, and returns the names of the removed stages
+func (j *ParsedPipeline) ApplyWhen(ctx *WhenContext) []string {
+	var skipped []string
+	j.Stages = applyWhenToStages(j.Stages, ctx, scopedEnv(j.GetEnv(), nil), &skipped)
+	return skipped
+}
+
+func applyWhenToStages(stages []Stage, ctx *WhenContext, parentEnv []corev1.EnvVar, skipped *[]string) []Stage {
+	var answer []Stage
+	for _, s := range stages {
+		env := scopedEnv(s.GetEnv(), parentEnv)
+		if !s.When.Matches(whenContextWithEnv(ctx, env)) {
+			*skipped = append(*skipped, s.Name)
+			continue
+		}
+		if len(s.Steps) > 0 {
+			s.Steps = applyWhenToSteps(s.Steps, ctx, env)
+			if len(s.Steps) == 0 {
+				*skipped = append(*skipped, s.Name)
+				continue
+			}
+		}
+		if len(s.Stages) > 0 {
+			s.Stages = applyWhenToStages(s.Stages, ctx, env, skipped)
+			if len(s.Stages) == 0 {
+				*skipped = append(*skipped, s.Name)
+				continue
+			}
+		}
+		if len(s.Parallel) > 0 {
+			s.Parallel = applyWhenToStages(s.Parallel, ctx, env, skipped)
+			if len(s.Parallel) == 0 {
+				*skipped = append(*skipped, s.Name)
+				continue
+			}
+		}
+		answer = append(answer, s)
+	}
+	return answer
+}
+
+func applyWhenToSteps(steps []Step, ctx *WhenContext, parentEnv []corev1.EnvVar) []Step {
+	var answer []Step
+	for _, step := range steps {
+		env := scopedEnv(step.Env, parentEnv)
+		if !step.When.Matches(whenContextWithEnv(ctx, env)) {
+			continue
+		}
+		if step.Loop != nil {
+			loop := *step.Loop
+			loop.Steps = applyWhenToSteps(loop.Steps, ctx, env)
+			if len(loop.Steps) == 0 {
+				continue
+			}
+			step.Loop = &loop
+		}
+		answer = append(answer, step)
+	}
+	return answer
+}
+
+// whenContextWithEnv returns a copy of the context whose environment includes the given pipeline environment
+// variables. Values from the context take precedence as they are known at runtime
+func whenContextWithEnv(ctx *WhenContext, env []corev1.EnvVar) *WhenContext {
+	if ctx == nil {
+		return nil
+	}
+	answer := *ctx
+	answer.Environment = map[string]string{}
+	for _, e := range env {
+		answer.Environment[e.Name] = e.Value
+	}
+	for k, v := range ctx.Environment {
+		answer.Environment[k] = v
+	}
+	return &answer
+}
+
+func anyWhen(stages []Stage, fn func(w *When) bool) bool {
+	for _, s := range stages {
+		if s.When != nil && fn(s.When) {
+			return true
+		}
+		for _, step := range s.Steps {
+			if anyStepWhen(step, fn) {
+				return true
+			}
+		}
+		if anyWhen(s.Stages, fn) || anyWhen(s.Parallel, fn) {
+			return true
+		}
+	}
+	return false
+}
+
+func anyStepWhen(step Step, fn func(w *When) bool) bool {
+	if step.When != nil && fn(step.When) {
+		return true
+	}
+	if step.Loop != nil {
+		for _, s := range step.Loop.Steps {
+			if anyStepWhen(s, fn) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsAny(values []string, actual []string) bool {
+	for _, v := range values {
+		if util.StringArrayIndex(actual, v) >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAnyFile(globs []string, files []string) bool {
+	for _, g := range globs {
+		for _, f := range files {
+			if MatchesGlob(g, f) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MatchesGlob returns true if the text matches the glob where "*" matches any characters other than "/", "**" matches
+// any characters and "?" matches any single character other than "/"
+func MatchesGlob(glob string, text string) bool {
+	r, err := globToRegexp(glob)
+	if err != nil {
+		return false
+	}
+	return r.MatchString(filepath.ToSlash(text))
+}
+
+func globToRegexp(glob string) (*regexp.Regexp, error) {
+	buffer := strings.Builder{}
+	buffer.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+				// "**/" also matches no directories at all
+				if i+1 < len(glob) && glob[i+1] == '/' {
+					i++
+					buffer.WriteString("(.*/)?")
+				} else {
+					buffer.WriteString(".*")
+				}
+			} else {
+				buffer.WriteString("[^/]*")
+			}
+		case '?':
+			buffer.WriteString("[^/]")
+		default:
+			buffer.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	buffer.WriteString("$")
+	return regexp.Compile(buffer.String())
+}
+
+func validateWhen(w *When) *apis.FieldError {
+	if w == nil {
+		return nil
+	}
+	if w.IsLegacy() {
+		return &apis.FieldError{
+			Message: "the when field is only valid in legacy build packs, not in jenkins-x.yml. Please remove it.",
+			Paths:   []string{"when"},
+		}
+	}
+	if w.Branch != "" {
+		if _, err := globToRegexp(w.Branch); err != nil {
+			return (&apis.FieldError{
+				Message: "invalid branch glob",
+				Details: err.Error(),
+				Paths:   []string{"branch"},
+			}).ViaField("when")
+		}
+	}
+	for i, c := range w.ChangeSet {
+		if strings.TrimSpace(c) == "" {
+			return apis.ErrMissingField(fmt.Sprintf("changeset[%d]", i)).ViaField("when")
+		}
+		if _, err := globToRegexp(c); err != nil {
+			return (&apis.FieldError{
+				Message: "invalid changeset glob",
+				Details: err.Error(),
+				Paths:   []string{fmt.Sprintf("changeset[%d]", i)},
+			}).ViaField("when")
+		}
+	}
+	for k := range w.Environment {
+		if strings.TrimSpace(k) == "" {
+			return (&apis.FieldError{
+				Message: "environment variable names must not be empty",
+				Paths:   []string{"environment"},
+			}).ViaField("when")
+		}
+	}
+	for i, k := range w.Kind {
+		if util.StringArrayIndex(WhenKinds, k) < 0 {
+			return (&apis.FieldError{
+				Message: fmt.Sprintf("invalid kind %s, must be one of: %s", k, strings.Join(WhenKinds, ", ")),
+				Paths:   []string{fmt.Sprintf("kind[%d]", i)},
+			}).ViaField("when")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package syntax_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/tekton/syntax"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func TestMatchesGlob(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, syntax.MatchesGlob("master", "master"))
+	assert.True(t, syntax.MatchesGlob("release/*", "release/1.x"))
+	assert.False(t, syntax.MatchesGlob("release/*", "release/1.x/fix"))
+	assert.True(t, syntax.MatchesGlob("services/api/**", "services/api/cmd/main.go"))
+	assert.True(t, syntax.MatchesGlob("**/*.md", "README.md"))
+	assert.True(t, syntax.MatchesGlob("**/*.md", "docs/guide/intro.md"))
+	assert.False(t, syntax.MatchesGlob("services/api/**", "services/web/main.go"))
+	assert.True(t, syntax.MatchesGlob("v?.go", "v1.go"))
+}
+
+func TestWhenMarshalling(t *testing.T) {
+	t.Parallel()
+
+	step := syntax.Step{}
+	err := yaml.Unmarshal([]byte("command: echo\nwhen: '!prow'\n"), &step)
+	require.NoError(t, err)
+	require.NotNil(t, step.When)
+	assert.Equal(t, "!prow", step.When.Legacy)
+	assert.True(t, step.When.IsLegacy())
+
+	data, err := yaml.Marshal(step)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "when: '!prow'")
+
+	stage := syntax.Stage{}
+	err = yaml.Unmarshal([]byte("name: api\nwhen:\n  branch: master\n  changeset:\n  - services/api/**\n"), &stage)
+	require.NoError(t, err)
+	require.NotNil(t, stage.When)
+	assert.False(t, stage.When.IsLegacy())
+	assert.Equal(t, "master", stage.When.Branch)
+	assert.Equal(t, []string{"services/api/**"}, stage.When.ChangeSet)
+
+	data, err = yaml.Marshal(stage)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "branch: master")
+}
+
+func TestWhenMatches(t *testing.T) {
+	t.Parallel()
+
+	ctx := &syntax.WhenContext{
+		Branch:       "master",
+		Kind:         syntax.WhenKindPullRequest,
+		Environment:  map[string]string{"DEPLOY": "true"},
+		Labels:       []string{"run-e2e"},
+		ChangedFiles: []string{"services/api/main.go"},
+	}
+
+	var noConditions *syntax.When
+	assert.True(t, noConditions.Matches(ctx))
+	assert.True(t, (&syntax.When{Branch: "mas*"}).Matches(ctx))
+	assert.False(t, (&syntax.When{Branch: "release/*"}).Matches(ctx))
+	assert.True(t, (&syntax.When{Kind: []string{syntax.WhenKindRelease, syntax.WhenKindPullRequest}}).Matches(ctx))
+	assert.False(t, (&syntax.When{Kind: []string{syntax.WhenKindRelease}}).Matches(ctx))
+	assert.True(t, (&syntax.When{Environment: map[string]string{"DEPLOY": "true"}}).Matches(ctx))
+	assert.False(t, (&syntax.When{Environment: map[string]string{"DEPLOY": "false"}}).Matches(ctx))
+	assert.True(t, (&syntax.When{Labels: []string{"skip-ci", "run-e2e"}}).Matches(ctx))
+	assert.False(t, (&syntax.When{Labels: []string{"skip-ci"}}).Matches(ctx))
+	assert.True(t, (&syntax.When{ChangeSet: []string{"services/api/**"}}).Matches(ctx))
+	assert.False(t, (&syntax.When{ChangeSet: []string{"services/web/**"}}).Matches(ctx))
+	assert.False(t, (&syntax.When{Branch: "master", ChangeSet: []string{"services/web/**"}}).Matches(ctx))
+
+	// unknown changes match every changeset
+	ctx.ChangedFiles = nil
+	assert.True(t, (&syntax.When{ChangeSet: []string{"services/web/**"}}).Matches(ctx))
+}
+
+func TestApplyWhen(t *testing.T) {
+	t.Parallel()
+
+	pipeline := &syntax.ParsedPipeline{
+		Env: []corev1.EnvVar{{Name: "DEPLOY", Value: "false"}},
+		Stages: []syntax.Stage{
+			{
+				Name:  "api",
+				When:  &syntax.When{ChangeSet: []string{"services/api/**"}},
+				Steps: []syntax.Step{{Command: "make api"}},
+			},
+			{
+				Name: "web",
+				When: &syntax.When{ChangeSet: []string{"services/web/**"}},
+				Steps: []syntax.Step{
+					{Command: "make web"},
+				},
+			},
+			{
+				Name: "checks",
+				Parallel: []syntax.Stage{
+					{
+						Name: "lint",
+						Steps: []syntax.Step{
+							{Command: "make lint"},
+							{Command: "make lint-release", When: &syntax.When{Kind: []string{syntax.WhenKindRelease}}},
+						},
+					},
+					{
+						Name:  "deploy",
+						Env:   []corev1.EnvVar{{Name: "DEPLOY", Value: "true"}},
+						When:  &syntax.When{Environment: map[string]string{"DEPLOY": "true"}},
+						Steps: []syntax.Step{{Command: "make deploy"}},
+					},
+					{
+						Name:  "docs",
+						When:  &syntax.When{Environment: map[string]string{"DEPLOY": "true"}},
+						Steps: []syntax.Step{{Command: "make docs"}},
+					},
+				},
+			},
+		},
+	}
+	assert.True(t, pipeline.UsesChangeSet())
+	assert.False(t, pipeline.UsesLabels())
+
+	skipped := pipeline.ApplyWhen(&syntax.WhenContext{
+		Branch:       "PR-1",
+		Kind:         syntax.WhenKindPullRequest,
+		ChangedFiles: []string{"services/api/handler.go"},
+	})
+	assert.Equal(t, []string{"web", "docs"}, skipped)
+
+	require.Len(t, pipeline.Stages, 2)
+	assert.Equal(t, "api", pipeline.Stages[0].Name)
+	checks := pipeline.Stages[1]
+	require.Len(t, checks.Parallel, 2)
+	assert.Equal(t, "lint", checks.Parallel[0].Name)
+	assert.Equal(t, []syntax.Step{{Command: "make lint"}}, checks.Parallel[0].Steps)
+	assert.Equal(t, "deploy", checks.Parallel[1].Name)
+}
@@ -283,11 +283,60 @@ func UnTargzAll(tarball, target string) error {
 		}
 
 		path := filepath.Join(target, header.Name)
+		if err := os.MkdirAll(filepath.Dir(path), DefaultWritePermissions); err != nil {
+			return err
+		}
 		UnTarFile(header, path, tarReader)
 	}
 	return nil
 }
 
+// TarGzFiles writes a gzipped tarball of the given files to the writer using the file names relative to the basedir
+func TarGzFiles(w io.Writer, files []string, basedir string) error {
+	zwriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(zwriter)
+	for _, file := range files {
+		name := file
+		if basedir != "" {
+			var err error
+			name, err = filepath.Rel(basedir, file)
+			if err != nil {
+				return errors.Wrapf(err, "failed to remove basedir %s from %s", basedir, file)
+			}
+		}
+		err := tarFile(tarWriter, file, filepath.ToSlash(name))
+		if err != nil {
+			return errors.Wrapf(err, "failed to add %s to the tarball", file)
+		}
+	}
+	if err := tarWriter.Close(); err != nil {
+		return err
+	}
+	return zwriter.Close()
+}
+
+func tarFile(tarWriter *tar.Writer, file string, name string) error {
+	info, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	if err := tarWriter.WriteHeader(header); err != nil {
+		return err
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(tarWriter, f)
+	return err
+}
+
 // UnTarFile extracts one file from the tar, or creates a directory
 func UnTarFile(header *tar.Header, path string, tarReader *tar.Reader) error {
 	info := header.FileInfo()
@@ -126,3 +126,39 @@ func TestDeleteDirContentsExcept(t *testing.T) {
 		fmt.Sprintf("Expected tmp dir %s to be empty, but contains %v.", tmpDir, remainingFiles))
 
 }
+
+func TestTarGzFilesAndUnTargzAll(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "test-tar-gz-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	sourceDir := filepath.Join(dir, "source")
+	files := []string{filepath.Join(sourceDir, "a.txt"), filepath.Join(sourceDir, "sub", "dir", "b.txt")}
+	for _, f := range files {
+		err = os.MkdirAll(filepath.Dir(f), util.DefaultWritePermissions)
+		require.NoError(t, err)
+		err = ioutil.WriteFile(f, []byte(filepath.Base(f)), util.DefaultWritePermissions)
+		require.NoError(t, err)
+	}
+
+	tarball := filepath.Join(dir, "files.tar.gz")
+	out, err := os.Create(tarball)
+	require.NoError(t, err)
+	err = util.TarGzFiles(out, files, sourceDir)
+	require.NoError(t, err)
+	err = out.Close()
+	require.NoError(t, err)
+
+	targetDir := filepath.Join(dir, "target")
+	err = util.UnTargzAll(tarball, targetDir)
+	require.NoError(t, err)
+
+	data, err := ioutil.ReadFile(filepath.Join(targetDir, "sub", "dir", "b.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "b.txt", string(data))
+	data, err = ioutil.ReadFile(filepath.Join(targetDir, "a.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "a.txt", string(data))
+}
@@ -0,0 +1,128 @@
+package workflow
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxFreezeDuration bounds how far back we look for the start of a scheduled freeze
+const maxFreezeDuration = 31 * 24 * time.Hour
+
+// ValidateFreezeWindow returns an error if the schedule, duration or time zone of the freeze window are invalid
+func ValidateFreezeWindow(w *v1.FreezeWindow) error {
+	if w.Name == "" {
+		return fmt.Errorf("freeze window has no name")
+	}
+	if w.Schedule != "" {
+		_, err := ParseSchedule(w.Schedule)
+		if err != nil {
+			return err
+		}
+	}
+	if w.Duration != "" {
+		d, err := time.ParseDuration(w.Duration)
+		if err != nil {
+			return errors.Wrapf(err, "invalid duration %s of freeze window %s", w.Duration, w.Name)
+		}
+		if d <= 0 || d > maxFreezeDuration {
+			return fmt.Errorf("duration %s of freeze window %s must be positive and no longer than %s", w.Duration, w.Name, maxFreezeDuration.String())
+		}
+		if w.Schedule == "" {
+			return fmt.Errorf("freeze window %s has a duration but no schedule", w.Name)
+		}
+	}
+	if w.TimeZone != "" {
+		_, err := time.LoadLocation(w.TimeZone)
+		if err != nil {
+			return errors.Wrapf(err, "invalid time zone %s of freeze window %s", w.TimeZone, w.Name)
+		}
+	}
+	if w.Start != nil && w.End != nil && !w.Start.Before(w.End) {
+		return fmt.Errorf("freeze window %s ends before it starts", w.Name)
+	}
+	return nil
+}
+
+// IsFreezeWindowActive returns true if promotions are frozen by the window at the given time
+func IsFreezeWindowActive(w *v1.FreezeWindow, t time.Time) (bool, error) {
+	if w.Start != nil && t.Before(w.Start.Time) {
+		return false, nil
+	}
+	if w.End != nil && !t.Before(w.End.Time) {
+		return false, nil
+	}
+	if w.Schedule == "" {
+		return true, nil
+	}
+	err := ValidateFreezeWindow(w)
+	if err != nil {
+		return false, err
+	}
+	schedule, err := ParseSchedule(w.Schedule)
+	if err != nil {
+		return false, err
+	}
+	loc := time.UTC
+	if w.TimeZone != "" {
+		loc, err = time.LoadLocation(w.TimeZone)
+		if err != nil {
+			return false, err
+		}
+	}
+	current := t.In(loc).Truncate(time.Minute)
+	if w.Duration == "" {
+		return schedule.Matches(current), nil
+	}
+	duration, err := time.ParseDuration(w.Duration)
+	if err != nil {
+		return false, err
+	}
+	// lets look for a scheduled start within the duration of the freeze
+	for start := current; current.Sub(start) < duration; start = start.Add(-time.Minute) {
+		if schedule.Matches(start) && t.Sub(start) < duration {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ActiveFreezeWindow returns the first freeze window of the environment which is active at the given time or nil
+func ActiveFreezeWindow(env *v1.Environment, t time.Time) (*v1.FreezeWindow, error) {
+	for i := range env.Spec.FreezeWindows {
+		w := &env.Spec.FreezeWindows[i]
+		active, err := IsFreezeWindowActive(w, t)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to evaluate freeze window %s of Environment %s", w.Name, env.Name)
+		}
+		if active {
+			return w, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetActiveFreezeWindow loads the environment returning its freeze window which is active at the given time or nil
+func GetActiveFreezeWindow(jxClient versioned.Interface, ns string, envName string, t time.Time) (*v1.FreezeWindow, error) {
+	env, err := jxClient.JenkinsV1().Environments(ns).Get(envName, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find Environment %s", envName)
+	}
+	return ActiveFreezeWindow(env, t)
+}
+
+// FreezeDescription returns a description of the freeze window for messages
+func FreezeDescription(w *v1.FreezeWindow) string {
+	answer := w.Name
+	if w.Reason != "" {
+		answer += " (" + w.Reason + ")"
+	}
+	if w.Schedule == "" && w.End != nil {
+		answer += " until " + w.End.Format(time.RFC3339)
+	}
+	return answer
+}
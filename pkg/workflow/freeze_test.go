@@ -0,0 +1,176 @@
+package workflow_test
+
+import (
+	"testing"
+	"time"
+
+	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func parseTime(t *testing.T, text string) time.Time {
+	answer, err := time.Parse(time.RFC3339, text)
+	require.NoError(t, err)
+	return answer
+}
+
+func TestParseSchedule(t *testing.T) {
+	t.Parallel()
+
+	valid := []string{"* * * * *", "0 18 * * 5", "*/15 9-17 * * 1-5", "0 0 1,15 * *", "30 2 * 12 7"}
+	for _, text := range valid {
+		_, err := workflow.ParseSchedule(text)
+		assert.NoError(t, err, "schedule %s", text)
+	}
+
+	invalid := []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "a * * * *"}
+	for _, text := range invalid {
+		_, err := workflow.ParseSchedule(text)
+		assert.Error(t, err, "schedule %s", text)
+	}
+}
+
+func TestScheduleMatches(t *testing.T) {
+	t.Parallel()
+
+	schedule, err := workflow.ParseSchedule("*/15 9-17 * * 1-5")
+	require.NoError(t, err)
+
+	// Monday 2 December 2019
+	assert.True(t, schedule.Matches(parseTime(t, "2019-12-02T09:00:00Z")))
+	assert.True(t, schedule.Matches(parseTime(t, "2019-12-02T17:45:00Z")))
+	assert.False(t, schedule.Matches(parseTime(t, "2019-12-02T09:01:00Z")))
+	assert.False(t, schedule.Matches(parseTime(t, "2019-12-02T18:00:00Z")))
+	// Sunday
+	assert.False(t, schedule.Matches(parseTime(t, "2019-12-01T10:00:00Z")))
+
+	// Sunday can be 0 or 7
+	sunday, err := workflow.ParseSchedule("0 12 * * 7")
+	require.NoError(t, err)
+	assert.True(t, sunday.Matches(parseTime(t, "2019-12-01T12:00:00Z")))
+}
+
+func TestWeekendFreezeWindow(t *testing.T) {
+	t.Parallel()
+
+	w := &v1.FreezeWindow{
+		Name:     "weekends",
+		Schedule: "* * * * 0,6",
+	}
+	require.NoError(t, workflow.ValidateFreezeWindow(w))
+
+	assertFreezeActive(t, w, "2019-11-30T00:00:00Z", true)
+	assertFreezeActive(t, w, "2019-12-01T23:59:30Z", true)
+	assertFreezeActive(t, w, "2019-12-02T00:00:00Z", false)
+	assertFreezeActive(t, w, "2019-11-29T23:59:00Z", false)
+}
+
+func TestScheduledFreezeWindowWithDuration(t *testing.T) {
+	t.Parallel()
+
+	w := &v1.FreezeWindow{
+		Name:     "friday",
+		Schedule: "0 18 * * 5",
+		Duration: "62h",
+	}
+	require.NoError(t, workflow.ValidateFreezeWindow(w))
+
+	// Friday 29 November 2019 at 18:00 until Monday 2 December at 08:00
+	assertFreezeActive(t, w, "2019-11-29T17:59:59Z", false)
+	assertFreezeActive(t, w, "2019-11-29T18:00:00Z", true)
+	assertFreezeActive(t, w, "2019-12-01T12:00:00Z", true)
+	assertFreezeActive(t, w, "2019-12-02T07:59:59Z", true)
+	assertFreezeActive(t, w, "2019-12-02T08:00:00Z", false)
+	assertFreezeActive(t, w, "2019-12-04T12:00:00Z", false)
+}
+
+func TestScheduledFreezeWindowTimeZone(t *testing.T) {
+	t.Parallel()
+
+	w := &v1.FreezeWindow{
+		Name:     "evenings",
+		Schedule: "0 18 * * *",
+		Duration: "1h",
+		TimeZone: "America/New_York",
+	}
+	require.NoError(t, workflow.ValidateFreezeWindow(w))
+
+	// 18:00 in New York is 23:00 UTC in winter
+	assertFreezeActive(t, w, "2019-12-02T18:30:00Z", false)
+	assertFreezeActive(t, w, "2019-12-02T23:30:00Z", true)
+}
+
+func TestAdHocFreezeWindow(t *testing.T) {
+	t.Parallel()
+
+	start := metav1.NewTime(parseTime(t, "2019-12-02T10:00:00Z"))
+	end := metav1.NewTime(parseTime(t, "2019-12-02T14:00:00Z"))
+	w := &v1.FreezeWindow{
+		Name:  "incident",
+		Start: &start,
+		End:   &end,
+	}
+	require.NoError(t, workflow.ValidateFreezeWindow(w))
+
+	assertFreezeActive(t, w, "2019-12-02T09:59:59Z", false)
+	assertFreezeActive(t, w, "2019-12-02T10:00:00Z", true)
+	assertFreezeActive(t, w, "2019-12-02T13:59:59Z", true)
+	assertFreezeActive(t, w, "2019-12-02T14:00:00Z", false)
+
+	// without an end the freeze lasts until it is deleted
+	w.End = nil
+	assertFreezeActive(t, w, "2020-12-02T10:00:00Z", true)
+}
+
+func TestActiveFreezeWindow(t *testing.T) {
+	t.Parallel()
+
+	env := &v1.Environment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "production",
+		},
+		Spec: v1.EnvironmentSpec{
+			FreezeWindows: []v1.FreezeWindow{
+				{
+					Name:     "weekends",
+					Reason:   "no weekend releases",
+					Schedule: "* * * * 0,6",
+				},
+			},
+		},
+	}
+
+	w, err := workflow.ActiveFreezeWindow(env, parseTime(t, "2019-12-01T12:00:00Z"))
+	require.NoError(t, err)
+	require.NotNil(t, w)
+	assert.Equal(t, "weekends (no weekend releases)", workflow.FreezeDescription(w))
+
+	w, err = workflow.ActiveFreezeWindow(env, parseTime(t, "2019-12-02T12:00:00Z"))
+	require.NoError(t, err)
+	assert.Nil(t, w)
+}
+
+func TestValidateFreezeWindowErrors(t *testing.T) {
+	t.Parallel()
+
+	invalid := []v1.FreezeWindow{
+		{Schedule: "* * * * *"},
+		{Name: "bad-schedule", Schedule: "* * *"},
+		{Name: "no-schedule", Duration: "1h"},
+		{Name: "bad-duration", Schedule: "0 18 * * 5", Duration: "forever"},
+		{Name: "too-long", Schedule: "0 18 * * 5", Duration: "1000h"},
+		{Name: "bad-zone", Schedule: "0 18 * * 5", TimeZone: "Nowhere/Special"},
+	}
+	for i := range invalid {
+		assert.Error(t, workflow.ValidateFreezeWindow(&invalid[i]), "freeze window %s", invalid[i].Name)
+	}
+}
+
+func assertFreezeActive(t *testing.T, w *v1.FreezeWindow, text string, expected bool) {
+	actual, err := workflow.IsFreezeWindowActive(w, parseTime(t, text))
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual, "freeze window %s at %s", w.Name, text)
+}
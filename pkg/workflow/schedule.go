@@ -0,0 +1,117 @@
+package workflow
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression of the form 'minute hour day-of-month month day-of-week'
+type Schedule struct {
+	minutes     map[int]bool
+	hours       map[int]bool
+	daysOfMonth map[int]bool
+	months      map[int]bool
+	daysOfWeek  map[int]bool
+
+	anyDayOfMonth bool
+	anyDayOfWeek  bool
+}
+
+type cronField struct {
+	name string
+	min  int
+	max  int
+}
+
+var cronFields = []cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// ParseSchedule parses a cron expression supporting '*', lists, ranges and steps such as '0 18 * * 5' or '*/15 9-17 * * 1-5'
+func ParseSchedule(text string) (*Schedule, error) {
+	fields := strings.Fields(text)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("invalid schedule '%s': expected %d fields for minute, hour, day of month, month and day of week", text, len(cronFields))
+	}
+	values := make([]map[int]bool, len(fields))
+	for i, field := range fields {
+		m, err := parseCronField(field, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule '%s': %s", text, err)
+		}
+		values[i] = m
+	}
+	// Sunday can be either 0 or 7
+	if values[4][7] {
+		values[4][0] = true
+	}
+	return &Schedule{
+		minutes:       values[0],
+		hours:         values[1],
+		daysOfMonth:   values[2],
+		months:        values[3],
+		daysOfWeek:    values[4],
+		anyDayOfMonth: fields[2] == "*",
+		anyDayOfWeek:  fields[4] == "*",
+	}, nil
+}
+
+// Matches returns true if the minute of the time matches the schedule
+func (s *Schedule) Matches(t time.Time) bool {
+	if !s.minutes[t.Minute()] || !s.hours[t.Hour()] || !s.months[int(t.Month())] {
+		return false
+	}
+	dom := s.daysOfMonth[t.Day()]
+	dow := s.daysOfWeek[int(t.Weekday())]
+	// like cron if both days are restricted then either can match
+	if !s.anyDayOfMonth && !s.anyDayOfWeek {
+		return dom || dow
+	}
+	return dom && dow
+}
+
+func parseCronField(text string, field cronField) (map[int]bool, error) {
+	answer := map[int]bool{}
+	for _, part := range strings.Split(text, ",") {
+		step := 1
+		if idx := strings.Index(part, "/"); idx >= 0 {
+			var err error
+			step, err = strconv.Atoi(part[idx+1:])
+			if err != nil || step < 1 {
+				return nil, fmt.Errorf("invalid step in %s field '%s'", field.name, part)
+			}
+			part = part[:idx]
+		}
+		low, high := field.min, field.max
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			low, err = strconv.Atoi(bounds[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s field '%s'", field.name, part)
+			}
+			high = low
+			if len(bounds) == 2 {
+				high, err = strconv.Atoi(bounds[1])
+				if err != nil {
+					return nil, fmt.Errorf("invalid %s field '%s'", field.name, part)
+				}
+			} else if step > 1 {
+				high = field.max
+			}
+		}
+		if low < field.min || high > field.max || low > high {
+			return nil, fmt.Errorf("%s field '%s' must be between %d and %d", field.name, text, field.min, field.max)
+		}
+		for i := low; i <= high; i += step {
+			answer[i] = true
+		}
+	}
+	return answer, nil
+}